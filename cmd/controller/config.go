@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confFile controller configure file, one "key = value" per line, lines
+// start with # are comments. Command line flags override configure keys.
+var confFile = "/etc/sonic/govtep/controller.conf"
+
+// confKeys configure key to command line flag name
+var confKeys = map[string]string{
+	"uplink_file":    "uplink",
+	"uplink_port":    "uplink-port",
+	"driver":         "driver",
+	"shadow_drivers": "shadow",
+	"redis_addr":     "r",
+	"netns":          "netns",
+	"hwvtep_addr":    "hwvtep",
+	"evpn_as":        "evpn-as",
+	"record_file":    "record",
+	"warm_restart":   "warm",
+	"warm_timer":     "warm-timer",
+	"lock":           "lock",
+	"health_addr":    "health",
+	"gateway_hello":  "gw-hello",
+	"gateway_dead":   "gw-dead",
+}
+
+// loadConf set flags not given in command line by configure file, default
+// configure file may not exist
+func loadConf() error {
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	file, err := os.Open(confFile)
+	if err != nil {
+		if os.IsNotExist(err) && !setFlags["conf"] {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("%s:%d: invalid line %q", confFile, lineNo, line)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		flagName, ok := confKeys[key]
+		if !ok {
+			return fmt.Errorf("%s:%d: unknown key %q", confFile, lineNo, key)
+		}
+		if setFlags[flagName] {
+			continue
+		}
+		if err = flag.Set(flagName, value); err != nil {
+			return fmt.Errorf("%s:%d: invalid %s: %v", confFile, lineNo, key, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -4,10 +4,18 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
-	driver "github.com/cn-pmlabs/govtep/driver/uninos"
+	"github.com/cn-pmlabs/govtep/driver/hwvtep"
+	"github.com/cn-pmlabs/govtep/driver/linux"
+	"github.com/cn-pmlabs/govtep/driver/record"
+	"github.com/cn-pmlabs/govtep/driver/sonic"
+	uninos "github.com/cn-pmlabs/govtep/driver/uninos"
 	govtep "github.com/cn-pmlabs/govtep/go_vtep"
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+	ovnnb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnnorthbound"
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
 	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
 	"github.com/cn-pmlabs/govtep/tai"
 )
@@ -15,11 +23,22 @@ import (
 var (
 	version string = "0.0.0"
 	help    bool   = false
+	// driverName TAI driver to program the switch
+	driverName string = "unos"
+	// shadowDrivers TAI drivers mirror calls of driverName, comma separated
+	shadowDrivers string
+	// capability print capability of driver and exit
+	capability bool
 )
 
 func usage() {
 	fmt.Fprintf(os.Stderr, `controller %s
 Usage: controller [-h] [-v vtepdbAddr] [-s ovnsbAddr] [-n ovnnbAddr] [-f switchConfFile]
+                  [-uplink uplinkConfFile] [-uplink-port port] [-conf confFile]
+                  [-driver driver] [-shadow drivers] [-r redisAddr]
+                  [-netns netns] [-hwvtep hwvtepAddr] [-evpn-as as] [-record recordFile]
+                  [-capability] [-warm] [-warm-timer duration] [-lock lockName] [-health addr]
+                  [-gw-hello duration] [-gw-dead duration]
 
 Options:
 `, version)
@@ -32,6 +51,32 @@ func init() {
 	flag.StringVar(&odbc.OvnnbAddr, "n", odbc.OvnnbAddr, "ovnnb database address")
 	flag.StringVar(&odbc.ConfigdbAddr, "c", odbc.ConfigdbAddr, "unos config database address")
 	flag.StringVar(&govtep.SwitchConfFile, "f", govtep.SwitchConfFile, "Switch (group) configure file")
+	flag.StringVar(&govtep.UplinkConfFile, "uplink", govtep.UplinkConfFile, "gateway uplink configure file")
+	flag.StringVar(&govtep.AutoGatewayConfDefaultPhysicalPort, "uplink-port", govtep.AutoGatewayConfDefaultPhysicalPort,
+		"default gateway uplink port (or lag) of vrf")
+	flag.StringVar(&confFile, "conf", confFile, "controller configure file")
+	flag.StringVar(&driverName, "driver", driverName,
+		"TAI driver, one of "+strings.ToLower(strings.Join(tai.RegisteredDrivers(), ", ")))
+	flag.StringVar(&shadowDrivers, "shadow", shadowDrivers,
+		"comma separated TAI drivers mirror every call of driver, result of driver wins")
+	flag.StringVar(&sonic.RedisAddr, "r", sonic.RedisAddr, "SONiC redis address of sonic driver")
+	flag.StringVar(&linux.Netns, "netns", linux.Netns, "network namespace programmed by linux driver")
+	flag.StringVar(&hwvtep.Addr, "hwvtep", hwvtep.Addr, "hardware_vtep database address of hwvtep driver")
+	flag.IntVar(&uninos.EvpnAs, "evpn-as", uninos.EvpnAs,
+		"bgp as of vrfs exporting tenant prefixes as EVPN type-5 routes by unos driver, 0 disables")
+	flag.StringVar(&record.File, "record", record.File, "file TAI calls recorded by record driver")
+	flag.BoolVar(&capability, "capability", false, "print capability of TAI driver and exit")
+	flag.BoolVar(&govtep.WarmRestart, "warm", govtep.WarmRestart,
+		"warm restart, keep dataplane of last run and sweep stale objects after warm-timer")
+	flag.DurationVar(&govtep.WarmRestartTimer, "warm-timer", govtep.WarmRestartTimer,
+		"time to derive objects again from ovn before stale objects swept in warm restart")
+	flag.StringVar(&odbc.ControllerLock, "lock", odbc.ControllerLock,
+		"vtepdb lock electing active instance, standby instances wait for it")
+	flag.StringVar(&healthAddr, "health", healthAddr, "health endpoint listen address, eg :8080")
+	flag.DurationVar(&govtep.GatewayHelloInterval, "gw-hello", govtep.GatewayHelloInterval,
+		"check interval of gateway group members and echo interval of their SB lock session")
+	flag.DurationVar(&govtep.GatewayDeadInterval, "gw-dead", govtep.GatewayDeadInterval,
+		"gateway group SB lock session receiving nothing in this time is closed, releasing member lock")
 	flag.BoolVar(&help, "h", false, "display this help message")
 	flag.Usage = usage
 }
@@ -42,12 +87,47 @@ func main() {
 		flag.Usage()
 		os.Exit(0)
 	}
+	if err := loadConf(); err != nil {
+		fmt.Fprintf(os.Stderr, "controller: load configure failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	tai.WarmRestart = govtep.WarmRestart
+	tai.WarmRestartTimer = govtep.WarmRestartTimer
+
+	// Instance is standby until lock granted, active without lock
+	if odbc.ControllerLock != "" {
+		odbc.LockElection(odbc.VtepdbAddr, nil, odbc.ControllerLock)
+		// db writes assert the lock, writes of instance lost the lock are
+		// refused even if it hasn't noticed yet
+		vtepdb.ControllervtepClient.SetLockTransact(odbc.LockTransactor(vtepdb.CONTROLLERVTEP))
+		ovnsb.OvnsouthboundClient.SetLockTransact(odbc.LockFollow(ovnsb.OVNSOUTHBOUND,
+			func() string { return odbc.OvnsbAddr }))
+		ovnnb.OvnnorthboundClient.SetLockTransact(odbc.LockFollow(ovnnb.OVNNORTHBOUND,
+			func() string { return odbc.OvnnbAddr }))
+	}
+	if healthAddr != "" {
+		healthServe(healthAddr)
+	}
 
 	// Start VTEPDB connection and update Notifier
 	govtep.NewVtepDbClient()
 
-	// TAI driver init
-	driver.Init()
+	// TAI driver init, exit if driver invalid
+	var shadows []string
+	if shadowDrivers != "" {
+		shadows = strings.Split(shadowDrivers, ",")
+	}
+	if err := tai.InitDrivers(driverName, shadows); err != nil {
+		fmt.Fprintf(os.Stderr, "controller: %v\n", err)
+		os.Exit(1)
+	}
+	if capability {
+		fmt.Printf("driver %s capability:\n%s\n", strings.ToLower(driverName), tai.DriverCapability())
+		os.Exit(0)
+	}
+	// Driver notifications are written into vtepdb status
+	tai.RegisterNotificationHandler("vtepdb", tai.VtepdbStatusHandler)
 	// Start TAI vtepDB connection and update Notifier
 	tai.NewTaiDbClient()
 
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+)
+
+// healthAddr listen address of health endpoint, disabled if empty
+var healthAddr string
+
+// healthServe serve role of controller instance. /health answers role on
+// every instance, /health/active answers 503 on standby instance for
+// checks selecting the active one.
+func healthServe(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		healthReply(w, http.StatusOK)
+	})
+	mux.HandleFunc("/health/active", func(w http.ResponseWriter, r *http.Request) {
+		if odbc.IsActive() {
+			healthReply(w, http.StatusOK)
+		} else {
+			healthReply(w, http.StatusServiceUnavailable)
+		}
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Fprintf(os.Stderr, "controller: health endpoint %s: %v\n", addr, err)
+		}
+	}()
+}
+
+func healthReply(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"version": version,
+		"role":    odbc.Role(),
+		"lock":    odbc.ControllerLock,
+	})
+}
@@ -1,15 +0,0 @@
-package dbname
-
-// DB name
-const (
-	DBNAME string = "OVSDB"
-)
-
-// Table name
-const (
-	TABLENAME string = "TableA"
-)
-
-func dbnameFiledsDefaultMapInit() {
-
-}
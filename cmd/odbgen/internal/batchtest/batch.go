@@ -0,0 +1,479 @@
+package batchtest
+
+import (
+	"fmt"
+
+	"github.com/ebay/libovsdb"
+)
+
+// batch write operations of batch client pending until Commit, and rows
+// they changed by table and uuid. Changed row is the full row, rows
+// inserted in batch are keyed by their named uuid, deleted row is nil.
+type batch struct {
+	ops  []libovsdb.Operation
+	rows map[string]map[string]libovsdb.ResultRow
+}
+
+// batchUndo row of batch before a change, restored if the transaction
+// changing it fails
+type batchUndo struct {
+	table   string
+	uuid    string
+	row     libovsdb.ResultRow
+	changed bool
+}
+
+// Begin return batch client of c. Write operations of batch client are
+// pending and sent to db in one transaction by Commit, reads of batch
+// client see pending writes. Rows inserted in batch are referred by their
+// named uuid until Commit. Other users of c are not affected by the
+// batch. Begin of batch client returns itself.
+func (c *Client) Begin() *Client {
+	if c.batch != nil {
+		return c
+	}
+	return &Client{
+		base: c,
+		batch: &batch{
+			rows: make(map[string]map[string]libovsdb.ResultRow),
+		},
+	}
+}
+
+// Commit end batch and send pending write operations in one transaction,
+// db rolls the transaction back if any operation fails
+func (c *Client) Commit() error {
+	c.Tranmutex.Lock()
+	defer c.Tranmutex.Unlock()
+	if c.batch == nil {
+		return fmt.Errorf("%s batch not begun", BATCHTEST)
+	}
+	ops := c.batch.ops
+	c.batch = nil
+	if len(ops) == 0 {
+		return nil
+	}
+	_, err := c.base.Transact(ops...)
+	return err
+}
+
+// Abort end batch and drop pending write operations
+func (c *Client) Abort() {
+	c.Tranmutex.Lock()
+	defer c.Tranmutex.Unlock()
+	c.batch = nil
+}
+
+// InBatch check whether client is batch client not ended yet
+func (c *Client) InBatch() bool {
+	c.Tranmutex.Lock()
+	defer c.Tranmutex.Unlock()
+	return c.batch != nil
+}
+
+// batchTransact do ops on rows of db with pending writes of batch, write
+// ops are pending if all ops succeed. Only selects are sent to db.
+func (c *Client) batchTransact(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	var undo []batchUndo
+	var pending []libovsdb.Operation
+	results := make([]libovsdb.OperationResult, 0, len(ops))
+
+	fail := func(err error, op libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+		for i := len(undo) - 1; i >= 0; i-- {
+			c.batch.restore(undo[i])
+		}
+		return nil, fmt.Errorf("Transaction Failed due to an error : %v in %v", err, op)
+	}
+
+	for _, op := range ops {
+		var result libovsdb.OperationResult
+		switch op.Op {
+		case opInsert:
+			if op.UUIDName == "" {
+				namedUUID, err := newRowUUID()
+				if err != nil {
+					return fail(err, op)
+				}
+				op.UUIDName = namedUUID
+			}
+			row := make(libovsdb.ResultRow, len(op.Row)+1)
+			for column, value := range op.Row {
+				row[column] = batchValue(value)
+			}
+			row["_uuid"] = libovsdb.UUID{GoUUID: op.UUIDName}
+			undo = append(undo, c.batch.change(op.Table, op.UUIDName, row))
+			result.UUID = libovsdb.UUID{GoUUID: op.UUIDName}
+		case opSelect, opUpdate, opMutate, opDelete:
+			rows, err := c.batchSelect(op.Table, op.Where)
+			if err != nil {
+				return fail(err, op)
+			}
+			if op.Op == opSelect {
+				result.Rows = rows
+				break
+			}
+			for _, row := range rows {
+				uuid := row["_uuid"].(libovsdb.UUID).GoUUID
+				switch op.Op {
+				case opUpdate:
+					for column, value := range op.Row {
+						row[column] = batchValue(value)
+					}
+				case opMutate:
+					if err = batchMutate(row, op.Mutations); err != nil {
+						return fail(err, op)
+					}
+				case opDelete:
+					row = nil
+				}
+				undo = append(undo, c.batch.change(op.Table, uuid, row))
+			}
+			result.Count = len(rows)
+		}
+		if op.Op != opSelect {
+			pending = append(pending, op)
+		}
+		results = append(results, result)
+	}
+
+	c.batch.ops = append(c.batch.ops, pending...)
+	return results, nil
+}
+
+// change set row of batch, return undo of the change
+func (b *batch) change(table string, uuid string, row libovsdb.ResultRow) batchUndo {
+	rows, ok := b.rows[table]
+	if !ok {
+		rows = make(map[string]libovsdb.ResultRow)
+		b.rows[table] = rows
+	}
+	old, changed := rows[uuid]
+	rows[uuid] = row
+	return batchUndo{table: table, uuid: uuid, row: old, changed: changed}
+}
+
+func (b *batch) restore(undo batchUndo) {
+	if undo.changed {
+		b.rows[undo.table][undo.uuid] = undo.row
+		return
+	}
+	delete(b.rows[undo.table], undo.uuid)
+}
+
+// batchSelect rows of table matching conditions, rows changed by batch
+// replace their db rows. Conditions referring named uuid are checked
+// locally only. Returned rows are copies.
+func (c *Client) batchSelect(table string, conditions []interface{}) ([]libovsdb.ResultRow, error) {
+	var dbConditions []interface{}
+	for _, condition := range conditions {
+		cond, ok := condition.([]interface{})
+		if ok && len(cond) == 3 && batchNamedUUID(cond[2]) {
+			continue
+		}
+		dbConditions = append(dbConditions, condition)
+	}
+
+	reply, err := c.base.Transact(libovsdb.Operation{
+		Op:    opSelect,
+		Table: table,
+		Where: dbConditions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := c.batch.rows[table]
+	var rows []libovsdb.ResultRow
+	for _, row := range reply[0].Rows {
+		uuid, _ := row["_uuid"].(libovsdb.UUID)
+		if _, ok := changed[uuid.GoUUID]; ok {
+			continue
+		}
+		if len(dbConditions) != len(conditions) {
+			if match, err := batchMatch(row, conditions); err != nil {
+				return nil, err
+			} else if !match {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range changed {
+		if row == nil {
+			continue
+		}
+		if match, err := batchMatch(row, conditions); err != nil {
+			return nil, err
+		} else if match {
+			copied := make(libovsdb.ResultRow, len(row))
+			for column, value := range row {
+				copied[column] = value
+			}
+			rows = append(rows, copied)
+		}
+	}
+	return rows, nil
+}
+
+// batchNamedUUID check whether value has uuid named in transaction, which
+// db can't select before Commit
+func batchNamedUUID(value interface{}) bool {
+	for _, elem := range batchElems(batchValue(value)) {
+		if uuid, ok := elem.(libovsdb.UUID); ok && !batchRealUUID(uuid.GoUUID) {
+			return true
+		}
+	}
+	return false
+}
+
+// batchRealUUID check uuid is in form of uuid generated by db
+func batchRealUUID(uuid string) bool {
+	if len(uuid) != len(InvalidUUID) {
+		return false
+	}
+	for i, ch := range uuid {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if ch != '-' {
+				return false
+			}
+		case (ch < '0' || ch > '9') && (ch < 'a' || ch > 'f'):
+			return false
+		}
+	}
+	return true
+}
+
+// batchValue value in form of db reply, numbers are float64 and set of
+// one element is the element
+func batchValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case *libovsdb.OvsSet:
+		return batchValue(*v)
+	case libovsdb.OvsSet:
+		set := make([]interface{}, 0, len(v.GoSet))
+		for _, elem := range v.GoSet {
+			set = append(set, batchValue(elem))
+		}
+		if len(set) == 1 {
+			return set[0]
+		}
+		return libovsdb.OvsSet{GoSet: set}
+	case *libovsdb.OvsMap:
+		return batchValue(*v)
+	case libovsdb.OvsMap:
+		m := make(map[interface{}]interface{}, len(v.GoMap))
+		for key, elem := range v.GoMap {
+			m[batchValue(key)] = batchValue(elem)
+		}
+		return libovsdb.OvsMap{GoMap: m}
+	}
+	return value
+}
+
+// batchElems elements of set value, atom is set of one element and
+// missing value is empty set
+func batchElems(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case libovsdb.OvsSet:
+		return v.GoSet
+	}
+	return []interface{}{value}
+}
+
+func batchIncludes(elems []interface{}, elem interface{}) bool {
+	for _, e := range elems {
+		if e == elem {
+			return true
+		}
+	}
+	return false
+}
+
+// batchEqual compare values of same column
+func batchEqual(a interface{}, b interface{}) bool {
+	aMap, aIsMap := a.(libovsdb.OvsMap)
+	bMap, bIsMap := b.(libovsdb.OvsMap)
+	if aIsMap || bIsMap {
+		return len(aMap.GoMap) == len(bMap.GoMap) && batchMapIncludes(aMap, bMap)
+	}
+	aElems, bElems := batchElems(a), batchElems(b)
+	if len(aElems) != len(bElems) {
+		return false
+	}
+	for _, elem := range bElems {
+		if !batchIncludes(aElems, elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func batchMapIncludes(m libovsdb.OvsMap, sub libovsdb.OvsMap) bool {
+	for key, value := range sub.GoMap {
+		if v, ok := m.GoMap[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// batchMatch check row with conditions of RFC7047
+func batchMatch(row libovsdb.ResultRow, conditions []interface{}) (bool, error) {
+	for _, condition := range conditions {
+		cond, ok := condition.([]interface{})
+		if !ok || len(cond) != 3 {
+			return false, fmt.Errorf("invalid condition %v", condition)
+		}
+		column, _ := cond[0].(string)
+		function, _ := cond[1].(string)
+		value := batchValue(cond[2])
+		current := row[column]
+
+		var match bool
+		switch function {
+		case "==":
+			match = batchEqual(current, value)
+		case "!=":
+			match = !batchEqual(current, value)
+		case "includes", "excludes":
+			match = true
+			if m, isMap := value.(libovsdb.OvsMap); isMap {
+				currentMap, _ := current.(libovsdb.OvsMap)
+				for key, v := range m.GoMap {
+					cv, ok := currentMap.GoMap[key]
+					if (ok && cv == v) != (function == "includes") {
+						match = false
+					}
+				}
+				break
+			}
+			elems := batchElems(current)
+			for _, elem := range batchElems(value) {
+				if batchIncludes(elems, elem) != (function == "includes") {
+					match = false
+				}
+			}
+		case "<", "<=", ">", ">=":
+			a, aOK := current.(float64)
+			b, bOK := value.(float64)
+			if !aOK || !bOK {
+				return false, fmt.Errorf("condition %v on non number %v", condition, current)
+			}
+			match = (function == "<" && a < b) || (function == "<=" && a <= b) ||
+				(function == ">" && a > b) || (function == ">=" && a >= b)
+		default:
+			return false, fmt.Errorf("unsupported condition %v", condition)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// batchMutate apply mutations of RFC7047 to row
+func batchMutate(row libovsdb.ResultRow, mutations []interface{}) error {
+	for _, mutation := range mutations {
+		mut, ok := mutation.([]interface{})
+		if !ok || len(mut) != 3 {
+			return fmt.Errorf("invalid mutation %v", mutation)
+		}
+		column, _ := mut[0].(string)
+		mutator, _ := mut[1].(string)
+		value := batchValue(mut[2])
+
+		switch mutator {
+		case opInsert, opDelete:
+			current, isMap := row[column].(libovsdb.OvsMap)
+			if _, ok := value.(libovsdb.OvsMap); ok && row[column] == nil {
+				isMap = true
+			}
+			if isMap {
+				row[column] = batchMutateMap(current, mutator, value)
+				break
+			}
+			row[column] = batchMutateSet(batchElems(row[column]), mutator, batchElems(value))
+		case "+=", "-=", "*=", "/=", "%=":
+			a, aOK := row[column].(float64)
+			b, bOK := value.(float64)
+			if !aOK || !bOK {
+				return fmt.Errorf("mutation %v on non number %v", mutation, row[column])
+			}
+			switch mutator {
+			case "+=":
+				a += b
+			case "-=":
+				a -= b
+			case "*=":
+				a *= b
+			case "/=", "%=":
+				if b == 0 {
+					return fmt.Errorf("mutation %v divided by zero", mutation)
+				}
+				if mutator == "/=" {
+					a /= b
+				} else {
+					a = float64(int(a) % int(b))
+				}
+			}
+			row[column] = a
+		default:
+			return fmt.Errorf("unsupported mutation %v", mutation)
+		}
+	}
+	return nil
+}
+
+// batchMutateSet insert or delete elems of set
+func batchMutateSet(set []interface{}, mutator string, elems []interface{}) interface{} {
+	var mutated []interface{}
+	for _, elem := range set {
+		if mutator == opInsert || !batchIncludes(elems, elem) {
+			mutated = append(mutated, elem)
+		}
+	}
+	if mutator == opInsert {
+		for _, elem := range elems {
+			if !batchIncludes(mutated, elem) {
+				mutated = append(mutated, elem)
+			}
+		}
+	}
+	return batchValue(libovsdb.OvsSet{GoSet: mutated})
+}
+
+// batchMutateMap insert pairs of map not having their keys, or delete
+// pairs by map or by set of keys
+func batchMutateMap(m libovsdb.OvsMap, mutator string, value interface{}) libovsdb.OvsMap {
+	mutated := make(map[interface{}]interface{}, len(m.GoMap))
+	for key, v := range m.GoMap {
+		mutated[key] = v
+	}
+	pairs, isMap := value.(libovsdb.OvsMap)
+	switch {
+	case mutator == opInsert:
+		for key, v := range pairs.GoMap {
+			if _, ok := mutated[key]; !ok {
+				mutated[key] = v
+			}
+		}
+	case isMap:
+		for key, v := range pairs.GoMap {
+			if cv, ok := mutated[key]; ok && cv == v {
+				delete(mutated, key)
+			}
+		}
+	default:
+		for _, key := range batchElems(value) {
+			delete(mutated, key)
+		}
+	}
+	return libovsdb.OvsMap{GoMap: mutated}
+}
@@ -0,0 +1,260 @@
+package batchtest
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ebay/libovsdb"
+)
+
+// testSchema schema the package is generated from
+const testSchema = "../../testdata/batch_test.ovsschema"
+
+// testClient client of BATCH_TEST ovsdb-server listening on unix socket
+// of test with Parent p1, test is skipped without ovsdb-server
+func testClient(t *testing.T) *Client {
+	t.Helper()
+	server, err := exec.LookPath("ovsdb-server")
+	if err != nil {
+		t.Skip("ovsdb-server not found")
+	}
+	tool, err := exec.LookPath("ovsdb-tool")
+	if err != nil {
+		t.Skip("ovsdb-tool not found")
+	}
+
+	dir := t.TempDir()
+	db := filepath.Join(dir, "batch.db")
+	socket := filepath.Join(dir, "db.sock")
+	if out, err := exec.Command(tool, "create", db, testSchema).CombinedOutput(); err != nil {
+		t.Skipf("ovsdb-tool create failed %v: %s", err, out)
+	}
+	cmd := exec.Command(server, "--remote=punix:"+socket,
+		"--unixctl="+filepath.Join(dir, "ovsdb-server.ctl"), "--no-chdir", db)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("ovsdb-server start failed %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	var c *Client
+	for i := 0; ; i++ {
+		if c, err = NewClient("unix:"+socket, nil); err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("ovsdb-server %s not ready: %v", socket, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Cleanup(func() { c.Conn().Disconnect() })
+
+	if _, err = c.ParentAdd(TableParent{Name: "p1", Count: 1, Tags: []string{"a"}}); err != nil {
+		t.Fatalf("Parent p1 add failed %v", err)
+	}
+	return c
+}
+
+func sortedTags(tags []string) []string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestBatchInsertSelect(t *testing.T) {
+	c := testClient(t)
+	b := c.Begin()
+	if !b.InBatch() || c.InBatch() {
+		t.Fatalf("batch client in batch %v, base client in batch %v", b.InBatch(), c.InBatch())
+	}
+
+	if _, err := b.ParentAdd(TableParent{Name: "p2", Count: 2}); err != nil {
+		t.Fatalf("Parent p2 add failed %v", err)
+	}
+	parent, err := b.ParentGetByIndex(ParentIndex{Name: "p2"})
+	if err != nil || parent.Count != 2 {
+		t.Fatalf("batch Parent p2 %+v %v, want count 2", parent, err)
+	}
+	if num := b.ParentGetCount(); num != 2 {
+		t.Fatalf("batch Parent count %d, want 2", num)
+	}
+	if _, err = c.ParentGetByIndex(ParentIndex{Name: "p2"}); err == nil {
+		t.Fatal("Parent p2 in db before commit")
+	}
+
+	if err = b.Commit(); err != nil {
+		t.Fatalf("commit failed %v", err)
+	}
+	if b.InBatch() {
+		t.Fatal("batch client in batch after commit")
+	}
+	if parent, err = c.ParentGetByIndex(ParentIndex{Name: "p2"}); err != nil || parent.Count != 2 {
+		t.Fatalf("Parent p2 %+v %v after commit, want count 2", parent, err)
+	}
+}
+
+func TestBatchMutate(t *testing.T) {
+	c := testClient(t)
+	index := ParentIndex{Name: "p1"}
+	b := c.Begin()
+
+	if err := b.ParentUpdateTagsAddvalue(index, []string{"b", "c"}); err != nil {
+		t.Fatalf("add tags failed %v", err)
+	}
+	if err := b.ParentUpdateTagsDelvalue(index, []string{"a"}); err != nil {
+		t.Fatalf("delete tag failed %v", err)
+	}
+	options := map[interface{}]interface{}{"k1": "v1", "k2": "v2"}
+	if err := b.ParentUpdateOptionsSetkey(index, options); err != nil {
+		t.Fatalf("set options failed %v", err)
+	}
+	// insert doesn't replace value of existing key
+	if err := b.ParentUpdateOptionsSetkey(index, map[interface{}]interface{}{"k1": "x"}); err != nil {
+		t.Fatalf("set option k1 failed %v", err)
+	}
+	if err := b.ParentUpdateOptionsDelkey(index, map[interface{}]interface{}{"k2": "v2"}); err != nil {
+		t.Fatalf("delete option k2 failed %v", err)
+	}
+	if err := b.ParentSetField(index, ParentFieldCount, 5); err != nil {
+		t.Fatalf("set count failed %v", err)
+	}
+
+	check := func(c *Client, when string) {
+		t.Helper()
+		parent, err := c.ParentGetByIndex(index)
+		if err != nil {
+			t.Fatalf("Parent p1 %s: %v", when, err)
+		}
+		if tags := sortedTags(parent.Tags); len(tags) != 2 || tags[0] != "b" || tags[1] != "c" {
+			t.Errorf("Parent p1 tags %v %s, want [b c]", tags, when)
+		}
+		if len(parent.Options) != 1 || parent.Options["k1"] != "v1" {
+			t.Errorf("Parent p1 options %v %s, want k1=v1", parent.Options, when)
+		}
+		if parent.Count != 5 {
+			t.Errorf("Parent p1 count %d %s, want 5", parent.Count, when)
+		}
+	}
+	check(b, "in batch")
+
+	// rows are selected by values changed in batch
+	rows, num := b.ParentGet([]interface{}{libovsdb.NewCondition(ParentFieldTags, "includes", "c")})
+	if num != 1 || rows[0][ParentFieldName] != "p1" {
+		t.Errorf("Parent of tag c %v in batch, want p1", rows)
+	}
+	if _, num = b.ParentGet([]interface{}{libovsdb.NewCondition(ParentFieldTags, "includes", "a")}); num != 0 {
+		t.Errorf("Parent of deleted tag a selected in batch")
+	}
+
+	if err := b.Commit(); err != nil {
+		t.Fatalf("commit failed %v", err)
+	}
+	check(c, "after commit")
+}
+
+func TestBatchDelete(t *testing.T) {
+	c := testClient(t)
+	b := c.Begin()
+
+	if err := b.ParentDelByIndex(ParentIndex{Name: "p1"}); err != nil {
+		t.Fatalf("Parent p1 delete failed %v", err)
+	}
+	if _, err := b.ParentGetByIndex(ParentIndex{Name: "p1"}); err == nil {
+		t.Fatal("deleted Parent p1 selected in batch")
+	}
+	if num := b.ParentGetCount(); num != 0 {
+		t.Fatalf("batch Parent count %d after delete, want 0", num)
+	}
+	if _, err := c.ParentGetByIndex(ParentIndex{Name: "p1"}); err != nil {
+		t.Fatalf("Parent p1 removed from db before commit: %v", err)
+	}
+
+	if err := b.Commit(); err != nil {
+		t.Fatalf("commit failed %v", err)
+	}
+	if _, err := c.ParentGetByIndex(ParentIndex{Name: "p1"}); err == nil {
+		t.Fatal("Parent p1 in db after commit")
+	}
+}
+
+func TestBatchNamedUUID(t *testing.T) {
+	c := testClient(t)
+	index := ParentIndex{Name: "p1"}
+	b := c.Begin()
+
+	if err := b.ParentUpdateAddChildren(index, TableChild{Name: "c1", Value: []int{7}}); err != nil {
+		t.Fatalf("add child c1 failed %v", err)
+	}
+	child, err := b.ChildGetByIndex(ChildIndex{Name: "c1"})
+	if err != nil {
+		t.Fatalf("Child c1 in batch: %v", err)
+	}
+	parent, err := b.ParentGetByIndex(index)
+	if err != nil || len(parent.Children) != 1 || parent.Children[0].GoUUID != child.UUID {
+		t.Fatalf("Parent p1 %+v %v in batch, want child %s", parent, err, child.UUID)
+	}
+	// child is referred by its named uuid until commit
+	if _, err = b.ChildGetByUUID(child.UUID); err != nil {
+		t.Fatalf("Child c1 by named uuid %s: %v", child.UUID, err)
+	}
+	rows, num := b.ParentGet([]interface{}{libovsdb.
+		NewCondition(ParentFieldChildren, "includes", libovsdb.UUID{GoUUID: child.UUID})})
+	if num != 1 || rows[0][ParentFieldName] != "p1" {
+		t.Fatalf("Parent of named child %v in batch, want p1", rows)
+	}
+
+	if err = b.Commit(); err != nil {
+		t.Fatalf("commit failed %v", err)
+	}
+	parent, err = c.ParentGetByIndex(index)
+	if err != nil || len(parent.Children) != 1 {
+		t.Fatalf("Parent p1 %+v %v after commit, want one child", parent, err)
+	}
+	child, err = c.ChildGetByUUID(parent.Children[0].GoUUID)
+	if err != nil || child.Name != "c1" || len(child.Value) != 1 || child.Value[0] != 7 {
+		t.Fatalf("child %+v %v of Parent p1 after commit, want c1 of value 7", child, err)
+	}
+}
+
+func TestBatchAbort(t *testing.T) {
+	c := testClient(t)
+	index := ParentIndex{Name: "p1"}
+	b := c.Begin()
+
+	if _, err := b.ParentAdd(TableParent{Name: "p2"}); err != nil {
+		t.Fatalf("Parent p2 add failed %v", err)
+	}
+	if err := b.ParentUpdateTagsAddvalue(index, []string{"b"}); err != nil {
+		t.Fatalf("add tag failed %v", err)
+	}
+	if err := b.ParentUpdateAddChildren(index, TableChild{Name: "c1"}); err != nil {
+		t.Fatalf("add child failed %v", err)
+	}
+	b.Abort()
+	if b.InBatch() {
+		t.Fatal("batch client in batch after abort")
+	}
+
+	if num := c.ParentGetCount(); num != 1 {
+		t.Errorf("Parent count %d after abort, want 1", num)
+	}
+	parent, err := c.ParentGetByIndex(index)
+	if err != nil || len(parent.Tags) != 1 || parent.Tags[0] != "a" || len(parent.Children) != 0 {
+		t.Errorf("Parent p1 %+v %v after abort, want tags [a] without children", parent, err)
+	}
+	if num := c.ChildGetCount(); num != 0 {
+		t.Errorf("Child count %d after abort, want 0", num)
+	}
+	// base client writes db directly after abort
+	if err = c.ParentUpdateTagsAddvalue(index, []string{"d"}); err != nil {
+		t.Fatalf("add tag by base client failed %v", err)
+	}
+	if parent, err = c.ParentGetByIndex(index); err != nil || len(parent.Tags) != 2 {
+		t.Errorf("Parent p1 %+v %v, want tags [a d]", parent, err)
+	}
+}
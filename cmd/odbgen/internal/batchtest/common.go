@@ -1,4 +1,4 @@
-package dbname
+package batchtest
 
 import (
 	"encoding/hex"
@@ -16,6 +16,7 @@ const (
 	opDelete string = "delete"
 	opSelect string = "select"
 	opUpdate string = "update"
+	opWait   string = "wait"
 )
 
 // InvalidUUID used to select all rows in table
@@ -93,9 +94,9 @@ func ConvertTableToRow(table interface{}, fieldMap map[string]string) (map[strin
 				}
 			}
 			row[fieldMap[typ.Field(i).Name]] = val.Field(i).Interface()
-		case string, int, bool:
+		case string, int, float64, bool:
 			row[fieldMap[typ.Field(i).Name]] = val.Field(i).Interface()
-		case []interface{}, []string, []int, []bool, []libovsdb.UUID:
+		case []interface{}, []string, []int, []float64, []bool, []libovsdb.UUID:
 			if val.Field(i).Len() == 0 {
 				continue
 			}
@@ -127,9 +128,9 @@ func convertFieldToRow(fieldName string, field interface{}) (map[string]interfac
 	row := make(map[string]interface{})
 
 	switch field.(type) {
-	case string, int, bool, libovsdb.UUID:
+	case string, int, float64, bool, libovsdb.UUID:
 		row[fieldName] = field
-	case []interface{}, []string, []int, []bool, []libovsdb.UUID:
+	case []interface{}, []string, []int, []float64, []bool, []libovsdb.UUID:
 		oSet, err := libovsdb.NewOvsSet(field)
 		if err != nil {
 			return nil, fmt.Errorf("OvsSet trans error for %s", fieldName)
@@ -192,6 +193,20 @@ func convertOvsSetToIntArray(oset libovsdb.OvsSet) []int {
 	return ret
 }
 
+// convertOvsSetToRealArray get float64 array from OvsSet
+func convertOvsSetToRealArray(oset libovsdb.OvsSet) []float64 {
+	var ret = []float64{}
+	for _, s := range oset.GoSet {
+		switch value := s.(type) {
+		case float64:
+			ret = append(ret, value)
+		case int:
+			ret = append(ret, float64(value))
+		}
+	}
+	return ret
+}
+
 // convertOvsSetToBoolArray get bool array from OvsSet
 func convertOvsSetToBoolArray(oset libovsdb.OvsSet) []bool {
 	var ret = []bool{}
@@ -0,0 +1,30 @@
+package batchtest
+
+// DB name
+const (
+	BATCHTEST string = "BATCH_TEST"
+)
+
+// Table name
+const (
+	Child  string = "Child"
+	Config string = "Config"
+	Parent string = "Parent"
+)
+
+// BatchtestFiledsDefaultMap table fileds default value map
+var BatchtestFiledsDefaultMap = make(map[string]map[string]interface{})
+
+// BatchtestFiledsDefaultMapInit init fields default value mapping
+func BatchtestFiledsDefaultMapInit() {
+}
+
+// TableUUIDColumns mapping
+var TableUUIDColumns = map[string]map[string]int{
+	"Config": {
+		"parent": 1,
+	},
+	"Parent": {
+		"children": 1,
+	},
+}
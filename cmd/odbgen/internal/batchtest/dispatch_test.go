@@ -0,0 +1,93 @@
+package batchtest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ebay/libovsdb"
+)
+
+// recordHandler record typed notifications of Parent and Child
+type recordHandler struct {
+	calls []string
+}
+
+func (h *recordHandler) OnParentInsert(newTable TableParent) {
+	h.calls = append(h.calls, fmt.Sprintf("insert Parent %s %s count %d", newTable.UUID, newTable.Name, newTable.Count))
+}
+
+func (h *recordHandler) OnParentUpdate(oldTable, newTable TableParent, changed []string) {
+	h.calls = append(h.calls, fmt.Sprintf("update Parent %s %s count %d->%d %v",
+		newTable.UUID, newTable.Name, oldTable.Count, newTable.Count, changed))
+}
+
+func (h *recordHandler) OnParentDelete(oldTable TableParent) {
+	h.calls = append(h.calls, fmt.Sprintf("delete Parent %s %s", oldTable.UUID, oldTable.Name))
+}
+
+func (h *recordHandler) OnChildInsert(newTable TableChild) {
+	h.calls = append(h.calls, fmt.Sprintf("insert Child %s %s value %v", newTable.UUID, newTable.Name, newTable.Value))
+}
+
+func (h *recordHandler) OnChildUpdate(oldTable, newTable TableChild, changed []string) {
+	h.calls = append(h.calls, fmt.Sprintf("update Child %s %s", newTable.UUID, newTable.Name))
+}
+
+func (h *recordHandler) OnChildDelete(oldTable TableChild) {
+	h.calls = append(h.calls, fmt.Sprintf("delete Child %s %s", oldTable.UUID, oldTable.Name))
+}
+
+func row(fields map[string]interface{}) libovsdb.Row {
+	return libovsdb.Row{Fields: fields}
+}
+
+// TestDispatch table updates are dispatched to typed handlers in table
+// order then by row uuid, old row of update is merged with new row and
+// changed columns are its columns
+func TestDispatch(t *testing.T) {
+	updates := libovsdb.TableUpdates{Updates: map[string]libovsdb.TableUpdate{
+		Parent: {Rows: map[string]libovsdb.RowUpdate{
+			"p2": {New: row(map[string]interface{}{"name": "p2", "count": float64(2)})},
+			"p1": {
+				New: row(map[string]interface{}{"name": "p1", "count": float64(3)}),
+				Old: row(map[string]interface{}{"count": float64(1)}),
+			},
+			"p3": {Old: row(map[string]interface{}{"name": "p3"})},
+		}},
+		Child: {Rows: map[string]libovsdb.RowUpdate{
+			"c1": {New: row(map[string]interface{}{"name": "c1", "value": float64(7)})},
+		}},
+		Config: {Rows: map[string]libovsdb.RowUpdate{
+			"g1": {New: row(map[string]interface{}{"mode": "active"})},
+		}},
+	}}
+
+	d := NewDispatcher()
+	d.SetOrder(Child, Parent)
+	handler := &recordHandler{}
+	if err := d.Register(handler); err != nil {
+		t.Fatalf("register handler failed %v", err)
+	}
+	if err := d.Register(struct{}{}); err == nil {
+		t.Errorf("handler of no table registered")
+	}
+	d.Update(nil, updates)
+
+	want := []string{
+		"insert Child c1 c1 value [7]",
+		"update Parent p1 p1 count 1->3 [count]",
+		"insert Parent p2 p2 count 2",
+		"delete Parent p3 p3",
+	}
+	if !reflect.DeepEqual(handler.calls, want) {
+		t.Errorf("dispatched\n\t%v\nwant\n\t%v", handler.calls, want)
+	}
+
+	var disconnected bool
+	d.OnDisconnected = func(*libovsdb.OvsdbClient) { disconnected = true }
+	d.Disconnected(nil)
+	if !disconnected {
+		t.Errorf("disconnected not notified")
+	}
+}
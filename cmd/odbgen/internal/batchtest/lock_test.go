@@ -0,0 +1,52 @@
+package batchtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ebay/libovsdb"
+)
+
+// TestLockTransact writes of client and commit of its batch go through
+// lock transact, selects go to connection
+func TestLockTransact(t *testing.T) {
+	c := testClient(t)
+	var writes int
+	locked := true
+	c.SetLockTransact(func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+		writes++
+		if !locked {
+			return nil, fmt.Errorf("lock not owned")
+		}
+		return c.Conn().Transact(BATCHTEST, ops...)
+	})
+
+	if _, err := c.ParentGetByIndex(ParentIndex{Name: "p1"}); err != nil || writes != 0 {
+		t.Fatalf("select Parent p1 %v, %d writes by lock transact, want 0", err, writes)
+	}
+	if err := c.ParentSetField(ParentIndex{Name: "p1"}, ParentFieldCount, 2); err != nil || writes != 1 {
+		t.Fatalf("set Parent p1 count %v, %d writes by lock transact, want 1", err, writes)
+	}
+
+	b := c.Begin()
+	if _, err := b.ParentAdd(TableParent{Name: "p2"}); err != nil {
+		t.Fatalf("Parent p2 add failed %v", err)
+	}
+	if writes != 1 {
+		t.Fatalf("%d writes by lock transact before commit, want 1", writes)
+	}
+	if err := b.Commit(); err != nil || writes != 2 {
+		t.Fatalf("commit %v, %d writes by lock transact, want 2", err, writes)
+	}
+
+	// writes are refused once lock lost
+	locked = false
+	if err := c.ParentSetField(ParentIndex{Name: "p1"}, ParentFieldCount, 3); err == nil {
+		t.Fatal("Parent p1 set without lock")
+	}
+	c.SetLockTransact(nil)
+	parent, err := c.ParentGetByIndex(ParentIndex{Name: "p1"})
+	if err != nil || parent.Count != 2 {
+		t.Fatalf("Parent p1 %+v %v, want count 2", parent, err)
+	}
+}
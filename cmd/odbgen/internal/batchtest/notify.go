@@ -0,0 +1,257 @@
+package batchtest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ebay/libovsdb"
+)
+
+// ChildHandler typed monitor notification handler of Child,
+// changed is the column names updated
+type ChildHandler interface {
+	OnChildInsert(newTable TableChild)
+	OnChildUpdate(oldTable, newTable TableChild, changed []string)
+	OnChildDelete(oldTable TableChild)
+}
+
+// ConfigHandler typed monitor notification handler of Config,
+// changed is the column names updated
+type ConfigHandler interface {
+	OnConfigInsert(newTable TableConfig)
+	OnConfigUpdate(oldTable, newTable TableConfig, changed []string)
+	OnConfigDelete(oldTable TableConfig)
+}
+
+// ParentHandler typed monitor notification handler of Parent,
+// changed is the column names updated
+type ParentHandler interface {
+	OnParentInsert(newTable TableParent)
+	OnParentUpdate(oldTable, newTable TableParent, changed []string)
+	OnParentDelete(oldTable TableParent)
+}
+
+// Dispatcher dispatch monitor updates to typed table handlers, it
+// implements libovsdb.NotificationHandler and can be registered to
+// libovsdb client directly
+type Dispatcher struct {
+	mutex    sync.RWMutex
+	order    []string
+	handlers map[string][]interface{}
+
+	// OnDisconnected called when ovsdb connection lost
+	OnDisconnected func(client *libovsdb.OvsdbClient)
+}
+
+// NewDispatcher create dispatcher without handler
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string][]interface{}),
+	}
+}
+
+// SetOrder set tables dispatch order, eg: referenced table before
+// referencing table, tables not in order are dispatched after by name
+func (d *Dispatcher) SetOrder(tables ...string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.order = append([]string{}, tables...)
+}
+
+// Register add handler implementing one or more table handler interfaces
+func (d *Dispatcher) Register(handler interface{}) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	registered := false
+	if _, ok := handler.(ChildHandler); ok {
+		d.handlers[Child] = append(d.handlers[Child], handler)
+		registered = true
+	}
+	if _, ok := handler.(ConfigHandler); ok {
+		d.handlers[Config] = append(d.handlers[Config], handler)
+		registered = true
+	}
+	if _, ok := handler.(ParentHandler); ok {
+		d.handlers[Parent] = append(d.handlers[Parent], handler)
+		registered = true
+	}
+	if !registered {
+		return fmt.Errorf("handler %T implements no table handler", handler)
+	}
+	return nil
+}
+
+// tableOrder tables of updates in dispatch order
+func (d *Dispatcher) tableOrder(updates libovsdb.TableUpdates) []string {
+	var tables []string
+	ordered := make(map[string]bool)
+	for _, table := range d.order {
+		ordered[table] = true
+		if _, ok := updates.Updates[table]; ok {
+			tables = append(tables, table)
+		}
+	}
+
+	var others []string
+	for table := range updates.Updates {
+		if !ordered[table] {
+			others = append(others, table)
+		}
+	}
+	sort.Strings(others)
+	return append(tables, others...)
+}
+
+// Dispatch call table handlers for all row updates
+func (d *Dispatcher) Dispatch(updates libovsdb.TableUpdates) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	for _, table := range d.tableOrder(updates) {
+		handlers := d.handlers[table]
+		if len(handlers) == 0 {
+			continue
+		}
+
+		rows := updates.Updates[table].Rows
+		uuids := make([]string, 0, len(rows))
+		for uuid := range rows {
+			uuids = append(uuids, uuid)
+		}
+		sort.Strings(uuids)
+
+		for _, uuid := range uuids {
+			dispatchRowUpdate(table, uuid, rows[uuid], handlers)
+		}
+	}
+}
+
+// Update libovsdb.NotificationHandler
+func (d *Dispatcher) Update(context interface{}, updates libovsdb.TableUpdates) {
+	d.Dispatch(updates)
+}
+
+// Locked libovsdb.NotificationHandler
+func (d *Dispatcher) Locked([]interface{}) {
+}
+
+// Stolen libovsdb.NotificationHandler
+func (d *Dispatcher) Stolen([]interface{}) {
+}
+
+// Echo libovsdb.NotificationHandler
+func (d *Dispatcher) Echo([]interface{}) {
+}
+
+// Disconnected libovsdb.NotificationHandler
+func (d *Dispatcher) Disconnected(client *libovsdb.OvsdbClient) {
+	if d.OnDisconnected != nil {
+		d.OnDisconnected(client)
+	}
+}
+
+// splitRowUpdate get op, full old and new rows and changed columns of
+// row update, old row of update only carries changed columns so merge
+// it with new row
+func splitRowUpdate(uuid string, rowUpdate libovsdb.RowUpdate) (string, libovsdb.ResultRow, libovsdb.ResultRow, []string) {
+	var op string
+	var changed []string
+	oldRow := make(libovsdb.ResultRow)
+	newRow := make(libovsdb.ResultRow)
+
+	switch {
+	case rowUpdate.New.Fields != nil && rowUpdate.Old.Fields == nil:
+		op = opInsert
+	case rowUpdate.New.Fields != nil:
+		op = opUpdate
+	case rowUpdate.Old.Fields != nil:
+		op = opDelete
+	default:
+		return "", nil, nil, nil
+	}
+
+	for column, value := range rowUpdate.New.Fields {
+		newRow[column] = value
+		oldRow[column] = value
+	}
+	for column, value := range rowUpdate.Old.Fields {
+		oldRow[column] = value
+		if op == opUpdate && column != "_uuid" {
+			changed = append(changed, column)
+		}
+	}
+	sort.Strings(changed)
+
+	oldRow["_uuid"] = stringToGoUUID(uuid)
+	newRow["_uuid"] = stringToGoUUID(uuid)
+	return op, oldRow, newRow, changed
+}
+
+func dispatchRowUpdate(table string, uuid string, rowUpdate libovsdb.RowUpdate, handlers []interface{}) {
+	op, oldRow, newRow, changed := splitRowUpdate(uuid, rowUpdate)
+	if op == "" {
+		return
+	}
+
+	switch table {
+	case Child:
+		var oldTable, newTable TableChild
+		if op != opInsert {
+			oldTable = ConvertRowToChild(oldRow)
+		}
+		if op != opDelete {
+			newTable = ConvertRowToChild(newRow)
+		}
+		for _, handler := range handlers {
+			h := handler.(ChildHandler)
+			switch op {
+			case opInsert:
+				h.OnChildInsert(newTable)
+			case opUpdate:
+				h.OnChildUpdate(oldTable, newTable, changed)
+			case opDelete:
+				h.OnChildDelete(oldTable)
+			}
+		}
+	case Config:
+		var oldTable, newTable TableConfig
+		if op != opInsert {
+			oldTable = ConvertRowToConfig(oldRow)
+		}
+		if op != opDelete {
+			newTable = ConvertRowToConfig(newRow)
+		}
+		for _, handler := range handlers {
+			h := handler.(ConfigHandler)
+			switch op {
+			case opInsert:
+				h.OnConfigInsert(newTable)
+			case opUpdate:
+				h.OnConfigUpdate(oldTable, newTable, changed)
+			case opDelete:
+				h.OnConfigDelete(oldTable)
+			}
+		}
+	case Parent:
+		var oldTable, newTable TableParent
+		if op != opInsert {
+			oldTable = ConvertRowToParent(oldRow)
+		}
+		if op != opDelete {
+			newTable = ConvertRowToParent(newRow)
+		}
+		for _, handler := range handlers {
+			h := handler.(ParentHandler)
+			switch op {
+			case opInsert:
+				h.OnParentInsert(newTable)
+			case opUpdate:
+				h.OnParentUpdate(oldTable, newTable, changed)
+			case opDelete:
+				h.OnParentDelete(oldTable)
+			}
+		}
+	}
+}
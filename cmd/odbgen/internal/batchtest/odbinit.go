@@ -0,0 +1,104 @@
+package batchtest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/ebay/libovsdb"
+)
+
+// Client db connection and transaction, all table operations
+// are methods of Client, it can be created many times to talk
+// to different db servers at the same time. Connection is guarded
+// by its own mutex, so it can be replaced or disconnected while a
+// transaction holding Tranmutex is hung on it. Batch client got by
+// Begin shares connection and lock transact of its base client.
+type Client struct {
+	Client    *libovsdb.OvsdbClient
+	Tranmutex sync.Mutex
+	connMutex sync.RWMutex
+	base      *Client
+	batch     *batch
+	// lockTransact write transactions with lock asserted, see SetLockTransact
+	lockTransact func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error)
+}
+
+// BatchtestClient default client used by package level operations
+var BatchtestClient = &Client{}
+
+// NewClient connect to addr and return a new db client
+func NewClient(addr string, tlsConfig *tls.Config) (*Client, error) {
+	c, err := libovsdb.Connect(addr, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: Fail to connect %s[%s]: %v", BATCHTEST, addr, err)
+	}
+	return &Client{Client: c}, nil
+}
+
+// NewClientWithConn wrap an existing ovsdb connection as db client
+func NewClientWithConn(c *libovsdb.OvsdbClient) (*Client, error) {
+	if c == nil {
+		return nil, fmt.Errorf("NewClientWithConn: invalid nil client")
+	}
+	return &Client{Client: c}, nil
+}
+
+// SetConn replace the ovsdb connection of client, used after reconnect
+func (c *Client) SetConn(conn *libovsdb.OvsdbClient) error {
+	if conn == nil {
+		return fmt.Errorf("SetConn: invalid nil client")
+	}
+
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	c.Client = conn
+	return nil
+}
+
+// Conn return the ovsdb connection of client
+func (c *Client) Conn() *libovsdb.OvsdbClient {
+	if c.base != nil {
+		return c.base.Conn()
+	}
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	return c.Client
+}
+
+// SetLockTransact write transactions of client are done by fn, which
+// asserts lock of client owner on the session holding it, so db refuses
+// writes once the lock is lost. Nil fn writes on client connection.
+func (c *Client) SetLockTransact(fn func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error)) {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	c.lockTransact = fn
+}
+
+// lockTransactor lock transact of client, nil if not set
+func (c *Client) lockTransactor() func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	if c.base != nil {
+		return c.base.lockTransactor()
+	}
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	return c.lockTransact
+}
+
+// InitBatchtest init db operation of default client
+func InitBatchtest(addr string) error {
+	c, err := libovsdb.Connect(addr, nil)
+	if err != nil {
+		return fmt.Errorf("InitBatchtest: Fail to connect %s", BATCHTEST)
+	}
+	return BatchtestClient.SetConn(c)
+}
+
+// RegisterBatchtestClient init db operation of default client
+func RegisterBatchtestClient(c *libovsdb.OvsdbClient) error {
+	if c == nil {
+		return fmt.Errorf("RegisterBatchtestClient: invalid nil client")
+	}
+
+	return BatchtestClient.SetConn(c)
+}
@@ -0,0 +1,171 @@
+package batchtest
+
+import (
+	"fmt"
+
+	"github.com/ebay/libovsdb"
+)
+
+// UpdateRows update db.table row's field with updates
+// return updated number
+func (c *Client) UpdateRows(table string,
+	updates map[string]interface{}, conditions []interface{}) int {
+	operation := libovsdb.Operation{
+		Op:    opUpdate,
+		Table: table,
+		Row:   updates,
+		Where: conditions,
+	}
+	results, err := c.Transact(operation)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 0
+	}
+	return results[0].Count
+}
+
+// MutateRows mutate db.table row's field with conditions
+// return modified number
+func (c *Client) MutateRows(table string,
+	mutations []interface{}, conditions []interface{}) int {
+	operation := libovsdb.Operation{
+		Op:        opMutate,
+		Table:     table,
+		Mutations: mutations,
+		Where:     conditions,
+	}
+	results, err := c.Transact(operation)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 0
+	}
+	return results[0].Count
+}
+
+// DeleteRows delete db.table rows with conditions
+// return delete number
+func (c *Client) DeleteRows(table string,
+	conditions []interface{}) int {
+	operation := libovsdb.Operation{
+		Op:    opDelete,
+		Table: table,
+		Where: conditions,
+	}
+	results, err := c.Transact(operation)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 0
+	}
+	return results[0].Count
+}
+
+// SelectRows check db.table with conditions existence
+// return ResultRow and selected rows number
+func (c *Client) SelectRows(table string,
+	conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	operation := libovsdb.Operation{
+		Op:    opSelect,
+		Table: table,
+		Where: conditions,
+	}
+	results, err := c.Transact(operation)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return []libovsdb.ResultRow{}, 0
+	}
+
+	if len(results[0].Rows) > 0 {
+		return results[0].Rows, len(results[0].Rows)
+	}
+	return []libovsdb.ResultRow{}, 0
+}
+
+// Transact with mutex and error check, operations of batch client are
+// done in its batch, see Begin
+func (c *Client) Transact(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	// Only support one trans at same time per client now.
+	c.Tranmutex.Lock()
+	defer c.Tranmutex.Unlock()
+	if c.Conn() == nil {
+		return nil, fmt.Errorf("%s client not connected", BATCHTEST)
+	}
+	if c.batch != nil {
+		return c.batchTransact(ops...)
+	}
+	return c.transact(ops...)
+}
+
+// transact ops in one transaction, Tranmutex must be held. Writes are
+// done by lock transact if set.
+func (c *Client) transact(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	conn := c.Conn()
+	if conn == nil {
+		return nil, fmt.Errorf("%s client not connected", BATCHTEST)
+	}
+	var reply []libovsdb.OperationResult
+	var err error
+	if lockTransact := c.lockTransactor(); lockTransact != nil && writeOps(ops) {
+		reply, err = lockTransact(ops...)
+	} else {
+		reply, err = conn.Transact(BATCHTEST, ops...)
+	}
+	if err != nil {
+		return reply, err
+	}
+
+	for i, o := range reply {
+		if o.Error != "" {
+			if i < len(ops) {
+				return nil, fmt.
+					Errorf("Transaction Failed due to an error : %v details: %v in %v", o.Error, o.Details, ops[i])
+			}
+			return nil, fmt.
+				Errorf("Transaction Failed due to an error : %v details: %v", o.Error, o.Details)
+		}
+	}
+	if len(reply) < len(ops) {
+		return reply, fmt.
+			Errorf("Number of Replies should be atleast equal to number of operations")
+	}
+
+	return reply, nil
+}
+
+// writeOps ops have operations other than select and wait
+func writeOps(ops []libovsdb.Operation) bool {
+	for _, op := range ops {
+		if op.Op != opSelect && op.Op != opWait {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateRows update db.table row's field by default client
+func UpdateRows(table string,
+	updates map[string]interface{}, conditions []interface{}) int {
+	return BatchtestClient.UpdateRows(table, updates, conditions)
+}
+
+// MutateRows mutate db.table row's field by default client
+func MutateRows(table string,
+	mutations []interface{}, conditions []interface{}) int {
+	return BatchtestClient.MutateRows(table, mutations, conditions)
+}
+
+// DeleteRows delete db.table rows by default client
+func DeleteRows(table string,
+	conditions []interface{}) int {
+	return BatchtestClient.DeleteRows(table, conditions)
+}
+
+// SelectRows select db.table rows by default client
+func SelectRows(table string,
+	conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	return BatchtestClient.SelectRows(table, conditions)
+}
+
+// Transact by default client
+func Transact(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	return BatchtestClient.Transact(ops...)
+}
@@ -0,0 +1,351 @@
+package batchtest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ebay/libovsdb"
+)
+
+// TableChild definition
+type TableChild struct {
+	UUID  string
+	Name  string
+	Value []int
+}
+
+// ChildIndex definition
+type ChildIndex struct {
+	Name string
+}
+
+// ChildUUIDIndex definition
+type ChildUUIDIndex struct {
+	UUID string
+}
+
+// ChildFields name
+const (
+	ChildFieldUUID  string = "_uuid"
+	ChildFieldName  string = "name"
+	ChildFieldValue string = "value"
+)
+
+// ChildFieldMapToColumn map field name to columns
+var ChildFieldMapToColumn map[string]string = map[string]string{
+	"UUID":  ChildFieldUUID,
+	"Name":  ChildFieldName,
+	"Value": ChildFieldValue,
+}
+
+// constraintsChild schema constraints of Child columns
+var constraintsChild = []columnConstraint{
+	{field: "Value", column: ChildFieldValue, min: 0, max: 1},
+}
+
+// Validate check TableChild against schema constraints before insert
+func (table TableChild) Validate() error {
+	return validateTable(Child, table, constraintsChild, true)
+}
+
+// ChildAddOp create Child
+// return insert Operation for non-root table
+func ChildAddOp(table TableChild) (libovsdb.Operation, error) {
+	if err := table.Validate(); err != nil {
+		return libovsdb.Operation{}, err
+	}
+
+	namedUUID, err := newRowUUID()
+	if err != nil {
+		return libovsdb.Operation{}, err
+	}
+
+	row, err := ConvertTableToRow(table, ChildFieldMapToColumn)
+	if err != nil {
+		return libovsdb.Operation{}, err
+	}
+	insertOp := libovsdb.Operation{
+		Op:       opInsert,
+		Table:    Child,
+		Row:      row,
+		UUIDName: namedUUID,
+	}
+	return insertOp, err
+}
+
+// ChildSet set fields of Child
+func (c *Client) ChildSet(tableIndex interface{}, table TableChild) error {
+	conditions, _ := convertIndexToConditions(tableIndex, ChildFieldMapToColumn)
+	_, num := c.ChildGet(conditions)
+	if num != 1 {
+		return fmt.Errorf("table %v not exist", tableIndex)
+	}
+
+	if err := validateTable(Child, table, constraintsChild, false); err != nil {
+		return err
+	}
+
+	rowsUpdate, err := ConvertTableToRow(table, ChildFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	if c.UpdateRows(Child, rowsUpdate, conditions) == 0 {
+		return fmt.Errorf("Set fields %v failed", table)
+	}
+	return nil
+}
+
+// ChildSet set fields of Child by default client
+func ChildSet(tableIndex interface{}, table TableChild) error {
+	return BatchtestClient.ChildSet(tableIndex, table)
+}
+
+// ChildGet get Child rows
+func (c *Client) ChildGet(conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	return c.SelectRows(Child, conditions)
+}
+
+// ChildGet get Child rows by default client
+func ChildGet(conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	return BatchtestClient.ChildGet(conditions)
+}
+
+// ChildGetByIndex get Child by index
+func (c *Client) ChildGetByIndex(tableIndex interface{}) (TableChild, error) {
+	conditions, _ := convertIndexToConditions(tableIndex, ChildFieldMapToColumn)
+	rows, num := c.ChildGet(conditions)
+	if num != 1 {
+		return TableChild{}, fmt.Errorf("table %v not exist", tableIndex)
+	}
+	table := ConvertRowToChild(rows[0])
+	return table, nil
+}
+
+// ChildGetByIndex get Child by index by default client
+func ChildGetByIndex(tableIndex interface{}) (TableChild, error) {
+	return BatchtestClient.ChildGetByIndex(tableIndex)
+}
+
+// ChildGetByUUID get Child by UUID
+func (c *Client) ChildGetByUUID(uuid string) (TableChild, error) {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "==", stringToGoUUID(uuid)))
+	rows, num := c.ChildGet(conditions)
+	if num != 1 {
+		return TableChild{}, fmt.Errorf("table %v not exist", uuid)
+	}
+	table := ConvertRowToChild(rows[0])
+	return table, nil
+}
+
+// ChildGetByUUID get Child by UUID by default client
+func ChildGetByUUID(uuid string) (TableChild, error) {
+	return BatchtestClient.ChildGetByUUID(uuid)
+}
+
+// ChildGetCount get Child count
+func (c *Client) ChildGetCount() int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	_, num := c.ChildGet(conditions)
+	return num
+}
+
+// ChildGetCount get Child count by default client
+func ChildGetCount() int {
+	return BatchtestClient.ChildGetCount()
+}
+
+// ChildIterator traverse Child and call fn
+// return traversed number
+func (c *Client) ChildIterator(fn func(TableChild)) int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	rows, num := c.ChildGet(conditions)
+	if num > 0 {
+		for _, row := range rows {
+			table := ConvertRowToChild(row)
+			fn(table)
+		}
+	}
+	return num
+}
+
+// ChildIterator traverse Child and call fn by default client
+func ChildIterator(fn func(TableChild)) int {
+	return BatchtestClient.ChildIterator(fn)
+}
+
+// ChildSetField set field of Child
+func (c *Client) ChildSetField(tableIndex interface{}, field string, value interface{}) error {
+	rowUpdate, err := convertFieldToRow(field, value)
+	if err != nil {
+		return err
+	}
+	conditions, _ := convertIndexToConditions(tableIndex, ChildFieldMapToColumn)
+	if c.UpdateRows(Child, rowUpdate, conditions) == 0 {
+		return fmt.Errorf("Set field %v failed", value)
+	}
+	return nil
+}
+
+// ChildSetField set field of Child by default client
+func ChildSetField(tableIndex interface{}, field string, value interface{}) error {
+	return BatchtestClient.ChildSetField(tableIndex, field, value)
+}
+
+// ChildUpdateValueAddvalue add value for array field of Child
+func (c *Client) ChildUpdateValueAddvalue(tableIndex interface{},
+	field []int) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ChildFieldValue, opInsert, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ChildFieldMapToColumn)
+
+	if c.MutateRows(Child, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ChildUpdateValueAddvalue add value for array field of Child by default client
+func ChildUpdateValueAddvalue(tableIndex interface{},
+	field []int) error {
+	return BatchtestClient.ChildUpdateValueAddvalue(tableIndex, field)
+}
+
+// ChildUpdateValueDelvalue del value for array field of Child
+func (c *Client) ChildUpdateValueDelvalue(tableIndex interface{},
+	field []int) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ChildFieldValue, opDelete, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ChildFieldMapToColumn)
+
+	if c.MutateRows(Child, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ChildUpdateValueDelvalue del value for array field of Child by default client
+func ChildUpdateValueDelvalue(tableIndex interface{},
+	field []int) error {
+	return BatchtestClient.ChildUpdateValueDelvalue(tableIndex, field)
+}
+
+// ConvertRowToChild convert map[string]interface{} to table struct
+func ConvertRowToChild(row libovsdb.ResultRow) TableChild {
+	var table TableChild
+	tablePtr := &table
+	typ := reflect.TypeOf(table)
+	val := reflect.ValueOf(table)
+	tableElems := reflect.ValueOf(tablePtr).Elem()
+
+	float64ToInt(row)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Name == "UUID" {
+			if UUID, ok := row["_uuid"].(libovsdb.UUID); ok {
+				tableElems.FieldByName(typ.Field(i).Name).SetString(UUID.GoUUID)
+			}
+			continue
+		}
+		switch val.Field(i).Interface().(type) {
+		case string:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ChildFieldMapToColumn[typ.Field(i).Name]].(string)))
+			}
+		case int:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ChildFieldMapToColumn[typ.Field(i).Name]].(int)))
+			}
+		case float64:
+			switch value := row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(float64(value)))
+			}
+		case bool:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ChildFieldMapToColumn[typ.Field(i).Name]].(bool)))
+			}
+		case libovsdb.UUID:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)))
+			}
+		case []string:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]string{row[ChildFieldMapToColumn[typ.Field(i).Name]].(string)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToStringArray(row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []int:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]int{row[ChildFieldMapToColumn[typ.Field(i).Name]].(int)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToIntArray(row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []float64:
+			switch value := row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{value}))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{float64(value)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(convertOvsSetToRealArray(value)))
+			}
+		case []bool:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]bool{row[ChildFieldMapToColumn[typ.Field(i).Name]].(bool)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToBoolArray(row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []libovsdb.UUID:
+			switch row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]libovsdb.UUID{row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToUUIDArray(row[ChildFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case map[interface{}]interface{}:
+			switch value := row[ChildFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.OvsMap:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value.GoMap))
+			}
+		}
+	}
+
+	return table
+}
@@ -0,0 +1,737 @@
+package batchtest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ebay/libovsdb"
+)
+
+// TableConfig definition
+type TableConfig struct {
+	UUID        string
+	Description []string
+	Enabled     bool
+	Labels      map[interface{}]interface{}
+	Mode        string
+	Parent      []libovsdb.UUID
+	Ports       map[interface{}]interface{}
+	Priority    int
+	Ratio       []float64
+}
+
+// ConfigFields name
+const (
+	ConfigFieldUUID        string = "_uuid"
+	ConfigFieldDescription string = "description"
+	ConfigFieldEnabled     string = "enabled"
+	ConfigFieldLabels      string = "labels"
+	ConfigFieldMode        string = "mode"
+	ConfigFieldParent      string = "parent"
+	ConfigFieldPorts       string = "ports"
+	ConfigFieldPriority    string = "priority"
+	ConfigFieldRatio       string = "ratio"
+)
+
+// ConfigFieldMapToColumn map field name to columns
+var ConfigFieldMapToColumn map[string]string = map[string]string{
+	"UUID":        ConfigFieldUUID,
+	"Description": ConfigFieldDescription,
+	"Enabled":     ConfigFieldEnabled,
+	"Labels":      ConfigFieldLabels,
+	"Mode":        ConfigFieldMode,
+	"Parent":      ConfigFieldParent,
+	"Ports":       ConfigFieldPorts,
+	"Priority":    ConfigFieldPriority,
+	"Ratio":       ConfigFieldRatio,
+}
+
+// Priority range
+const (
+	ConfigPriorityMin int = 0
+	ConfigPriorityMax int = 255
+)
+
+// Ratio range
+const (
+	ConfigRatioMin float64 = 0.0
+	ConfigRatioMax float64 = 1.0
+)
+
+// Mode enum
+const (
+	ConfigModeActive  string = "active"
+	ConfigModeStandby string = "standby"
+)
+
+// constraintsConfig schema constraints of Config columns
+var constraintsConfig = []columnConstraint{
+	{field: "Description", column: ConfigFieldDescription, min: 0, max: 1, key: baseConstraint{atomic: "string", lengthRange: true, minLength: 0, maxLength: 64}},
+	{field: "Mode", column: ConfigFieldMode, min: 1, max: 1, key: baseConstraint{atomic: "string", enum: []interface{}{"active", "standby"}}},
+	{field: "Parent", column: ConfigFieldParent, min: 0, max: 1},
+	{field: "Priority", column: ConfigFieldPriority, min: 1, max: 1, key: baseConstraint{atomic: "integer", intRange: true, minInteger: 0, maxInteger: 255}},
+	{field: "Ratio", column: ConfigFieldRatio, min: 0, max: 1, key: baseConstraint{atomic: "real", realRange: true, minReal: 0.0, maxReal: 1.0}},
+}
+
+// Validate check TableConfig against schema constraints before insert
+func (table TableConfig) Validate() error {
+	return validateTable(Config, table, constraintsConfig, true)
+}
+
+// ConfigAdd create Config
+func (c *Client) ConfigAdd(table TableConfig) (string, error) {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num == 1 {
+		return "", fmt.Errorf("table %v already exist", Config)
+	}
+
+	if err := table.Validate(); err != nil {
+		return "", err
+	}
+
+	namedUUID, err := newRowUUID()
+	if err != nil {
+		return "", err
+	}
+
+	row, err := ConvertTableToRow(table, ConfigFieldMapToColumn)
+	if err != nil {
+		return "", err
+	}
+	insertOp := libovsdb.Operation{
+		Op:       opInsert,
+		Table:    Config,
+		Row:      row,
+		UUIDName: namedUUID,
+	}
+	ops := []libovsdb.Operation{insertOp}
+	reply, err := c.Transact(ops...)
+	if err != nil {
+		return "", err
+	}
+	return reply[0].UUID.GoUUID, err
+}
+
+// ConfigAdd create Config by default client
+func ConfigAdd(table TableConfig) (string, error) {
+	return BatchtestClient.ConfigAdd(table)
+}
+
+// ConfigSet set fields of Config
+func (c *Client) ConfigSet(table TableConfig) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	if err := validateTable(Config, table, constraintsConfig, false); err != nil {
+		return err
+	}
+
+	rowsUpdate, err := ConvertTableToRow(table, ConfigFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	if c.UpdateRows(Config, rowsUpdate, conditions) == 0 {
+		return fmt.Errorf("Set fields %v failed", table)
+	}
+	return nil
+}
+
+// ConfigSet set fields of Config by default client
+func ConfigSet(table TableConfig) error {
+	return BatchtestClient.ConfigSet(table)
+}
+
+// ConfigDel delete Config rows
+func (c *Client) ConfigDel() error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	if c.DeleteRows(Config, conditions) == 0 {
+		return fmt.Errorf("table %v delete failed", conditions)
+	}
+	return nil
+}
+
+// ConfigDel delete Config rows by default client
+func ConfigDel() error {
+	return BatchtestClient.ConfigDel()
+}
+
+// ConfigGet get Config rows
+func (c *Client) ConfigGet() (TableConfig, error) {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	rows, num := c.SelectRows(Config, conditions)
+	if num != 1 {
+		return TableConfig{}, fmt.Errorf("table %v not created yet", Config)
+	}
+	table := ConvertRowToConfig(rows[0])
+	return table, nil
+}
+
+// ConfigGet get Config rows by default client
+func ConfigGet() (TableConfig, error) {
+	return BatchtestClient.ConfigGet()
+}
+
+// ConfigIterator traverse Config and call fn
+// return traversed number
+func (c *Client) ConfigIterator(fn func(TableConfig)) int {
+	table, err := c.ConfigGet()
+	if err != nil {
+		return 0
+	}
+	fn(table)
+	return 1
+}
+
+// ConfigIterator traverse Config and call fn by default client
+func ConfigIterator(fn func(TableConfig)) int {
+	return BatchtestClient.ConfigIterator(fn)
+}
+
+// ConfigClear clear all Config
+// return deleted rows number
+func (c *Client) ConfigClear() int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	return c.DeleteRows(Config, conditions)
+}
+
+// ConfigClear clear all Config by default client
+func ConfigClear() int {
+	return BatchtestClient.ConfigClear()
+}
+
+// ConfigSetField set field of Config
+func (c *Client) ConfigSetField(field string, value interface{}) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	rowUpdate, err := convertFieldToRow(field, value)
+	if err != nil {
+		return err
+	}
+	if c.UpdateRows(Config, rowUpdate, conditions) == 0 {
+		return fmt.Errorf("Set field %v failed", value)
+	}
+	return nil
+}
+
+// ConfigSetField set field of Config by default client
+func ConfigSetField(field string, value interface{}) error {
+	return BatchtestClient.ConfigSetField(field, value)
+}
+
+// ConfigUpdateDescriptionAddvalue add value for array field of Config
+func (c *Client) ConfigUpdateDescriptionAddvalue(field []string) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldDescription, opInsert, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateDescriptionAddvalue add value for array field of Config by default client
+func ConfigUpdateDescriptionAddvalue(field []string) error {
+	return BatchtestClient.ConfigUpdateDescriptionAddvalue(field)
+}
+
+// ConfigUpdateDescriptionDelvalue del value for array field of Config
+func (c *Client) ConfigUpdateDescriptionDelvalue(field []string) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldDescription, opDelete, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateDescriptionDelvalue del value for array field of Config by default client
+func ConfigUpdateDescriptionDelvalue(field []string) error {
+	return BatchtestClient.ConfigUpdateDescriptionDelvalue(field)
+}
+
+// ConfigUpdateModeAddvalue add value for array field of Config
+func (c *Client) ConfigUpdateModeAddvalue(field []string) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldMode, opInsert, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateModeAddvalue add value for array field of Config by default client
+func ConfigUpdateModeAddvalue(field []string) error {
+	return BatchtestClient.ConfigUpdateModeAddvalue(field)
+}
+
+// ConfigUpdateModeDelvalue del value for array field of Config
+func (c *Client) ConfigUpdateModeDelvalue(field []string) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldMode, opDelete, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateModeDelvalue del value for array field of Config by default client
+func ConfigUpdateModeDelvalue(field []string) error {
+	return BatchtestClient.ConfigUpdateModeDelvalue(field)
+}
+
+// ConfigUpdateParentAddvalue add value for array field of Config
+func (c *Client) ConfigUpdateParentAddvalue(field []libovsdb.UUID) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldParent, opInsert, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateParentAddvalue add value for array field of Config by default client
+func ConfigUpdateParentAddvalue(field []libovsdb.UUID) error {
+	return BatchtestClient.ConfigUpdateParentAddvalue(field)
+}
+
+// ConfigUpdateParentDelvalue del value for array field of Config
+func (c *Client) ConfigUpdateParentDelvalue(field []libovsdb.UUID) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldParent, opDelete, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateParentDelvalue del value for array field of Config by default client
+func ConfigUpdateParentDelvalue(field []libovsdb.UUID) error {
+	return BatchtestClient.ConfigUpdateParentDelvalue(field)
+}
+
+// ConfigUpdatePriorityAddvalue add value for array field of Config
+func (c *Client) ConfigUpdatePriorityAddvalue(field []int) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldPriority, opInsert, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdatePriorityAddvalue add value for array field of Config by default client
+func ConfigUpdatePriorityAddvalue(field []int) error {
+	return BatchtestClient.ConfigUpdatePriorityAddvalue(field)
+}
+
+// ConfigUpdatePriorityDelvalue del value for array field of Config
+func (c *Client) ConfigUpdatePriorityDelvalue(field []int) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldPriority, opDelete, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdatePriorityDelvalue del value for array field of Config by default client
+func ConfigUpdatePriorityDelvalue(field []int) error {
+	return BatchtestClient.ConfigUpdatePriorityDelvalue(field)
+}
+
+// ConfigUpdateRatioAddvalue add value for array field of Config
+func (c *Client) ConfigUpdateRatioAddvalue(field []float64) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldRatio, opInsert, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateRatioAddvalue add value for array field of Config by default client
+func ConfigUpdateRatioAddvalue(field []float64) error {
+	return BatchtestClient.ConfigUpdateRatioAddvalue(field)
+}
+
+// ConfigUpdateRatioDelvalue del value for array field of Config
+func (c *Client) ConfigUpdateRatioDelvalue(field []float64) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldRatio, opDelete, oSet))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateRatioDelvalue del value for array field of Config by default client
+func ConfigUpdateRatioDelvalue(field []float64) error {
+	return BatchtestClient.ConfigUpdateRatioDelvalue(field)
+}
+
+// ConfigUpdateLabelsSetkey set key for map field of Config
+func (c *Client) ConfigUpdateLabelsSetkey(field map[interface{}]interface{}) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldLabels, opInsert, oMap))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateLabelsSetkey set key for map field of Config by default client
+func ConfigUpdateLabelsSetkey(field map[interface{}]interface{}) error {
+	return BatchtestClient.ConfigUpdateLabelsSetkey(field)
+}
+
+// ConfigUpdateLabelsDelkey del key for map field of Config
+func (c *Client) ConfigUpdateLabelsDelkey(field map[interface{}]interface{}) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldLabels, opDelete, oMap))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdateLabelsDelkey del key for map field of Config by default client
+func ConfigUpdateLabelsDelkey(field map[interface{}]interface{}) error {
+	return BatchtestClient.ConfigUpdateLabelsDelkey(field)
+}
+
+// ConfigUpdatePortsSetkey set key for map field of Config
+func (c *Client) ConfigUpdatePortsSetkey(field map[interface{}]interface{}) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldPorts, opInsert, oMap))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdatePortsSetkey set key for map field of Config by default client
+func ConfigUpdatePortsSetkey(field map[interface{}]interface{}) error {
+	return BatchtestClient.ConfigUpdatePortsSetkey(field)
+}
+
+// ConfigUpdatePortsDelkey del key for map field of Config
+func (c *Client) ConfigUpdatePortsDelkey(field map[interface{}]interface{}) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	if _, num := c.SelectRows(Config, conditions); num != 1 {
+		return fmt.Errorf("table %v not created yet", Config)
+	}
+
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ConfigFieldPorts, opDelete, oMap))
+
+	if c.MutateRows(Config, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ConfigUpdatePortsDelkey del key for map field of Config by default client
+func ConfigUpdatePortsDelkey(field map[interface{}]interface{}) error {
+	return BatchtestClient.ConfigUpdatePortsDelkey(field)
+}
+
+// ConvertRowToConfig convert map[string]interface{} to table struct
+func ConvertRowToConfig(row libovsdb.ResultRow) TableConfig {
+	var table TableConfig
+	tablePtr := &table
+	typ := reflect.TypeOf(table)
+	val := reflect.ValueOf(table)
+	tableElems := reflect.ValueOf(tablePtr).Elem()
+
+	float64ToInt(row)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Name == "UUID" {
+			if UUID, ok := row["_uuid"].(libovsdb.UUID); ok {
+				tableElems.FieldByName(typ.Field(i).Name).SetString(UUID.GoUUID)
+			}
+			continue
+		}
+		switch val.Field(i).Interface().(type) {
+		case string:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(string)))
+			}
+		case int:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(int)))
+			}
+		case float64:
+			switch value := row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(float64(value)))
+			}
+		case bool:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(bool)))
+			}
+		case libovsdb.UUID:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)))
+			}
+		case []string:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]string{row[ConfigFieldMapToColumn[typ.Field(i).Name]].(string)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToStringArray(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []int:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]int{row[ConfigFieldMapToColumn[typ.Field(i).Name]].(int)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToIntArray(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []float64:
+			switch value := row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{value}))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{float64(value)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(convertOvsSetToRealArray(value)))
+			}
+		case []bool:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]bool{row[ConfigFieldMapToColumn[typ.Field(i).Name]].(bool)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToBoolArray(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []libovsdb.UUID:
+			switch row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]libovsdb.UUID{row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToUUIDArray(row[ConfigFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case map[interface{}]interface{}:
+			switch value := row[ConfigFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.OvsMap:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value.GoMap))
+			}
+		}
+	}
+
+	return table
+}
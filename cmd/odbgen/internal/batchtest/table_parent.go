@@ -0,0 +1,558 @@
+package batchtest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ebay/libovsdb"
+)
+
+// TableParent definition
+type TableParent struct {
+	UUID     string
+	Children []libovsdb.UUID
+	Count    int
+	Name     string
+	Options  map[interface{}]interface{}
+	Tags     []string
+}
+
+// ParentIndex definition
+type ParentIndex struct {
+	Name string
+}
+
+// ParentUUIDIndex definition
+type ParentUUIDIndex struct {
+	UUID string
+}
+
+// ParentFields name
+const (
+	ParentFieldUUID     string = "_uuid"
+	ParentFieldChildren string = "children"
+	ParentFieldCount    string = "count"
+	ParentFieldName     string = "name"
+	ParentFieldOptions  string = "options"
+	ParentFieldTags     string = "tags"
+)
+
+// ParentFieldMapToColumn map field name to columns
+var ParentFieldMapToColumn map[string]string = map[string]string{
+	"UUID":     ParentFieldUUID,
+	"Children": ParentFieldChildren,
+	"Count":    ParentFieldCount,
+	"Name":     ParentFieldName,
+	"Options":  ParentFieldOptions,
+	"Tags":     ParentFieldTags,
+}
+
+// constraintsParent schema constraints of Parent columns
+var constraintsParent = []columnConstraint{}
+
+// Validate check TableParent against schema constraints before insert
+func (table TableParent) Validate() error {
+	return validateTable(Parent, table, constraintsParent, true)
+}
+
+// ParentAdd create Parent
+func (c *Client) ParentAdd(table TableParent) (string, error) {
+	if err := table.Validate(); err != nil {
+		return "", err
+	}
+
+	namedUUID, err := newRowUUID()
+	if err != nil {
+		return "", err
+	}
+
+	row, err := ConvertTableToRow(table, ParentFieldMapToColumn)
+	if err != nil {
+		return "", err
+	}
+	insertOp := libovsdb.Operation{
+		Op:       opInsert,
+		Table:    Parent,
+		Row:      row,
+		UUIDName: namedUUID,
+	}
+	ops := []libovsdb.Operation{insertOp}
+	reply, err := c.Transact(ops...)
+	if err != nil {
+		return "", err
+	}
+	return reply[0].UUID.GoUUID, err
+}
+
+// ParentAdd create Parent by default client
+func ParentAdd(table TableParent) (string, error) {
+	return BatchtestClient.ParentAdd(table)
+}
+
+// ParentSet set fields of Parent
+func (c *Client) ParentSet(tableIndex interface{}, table TableParent) error {
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+	_, num := c.ParentGet(conditions)
+	if num != 1 {
+		return fmt.Errorf("table %v not exist", tableIndex)
+	}
+
+	if err := validateTable(Parent, table, constraintsParent, false); err != nil {
+		return err
+	}
+
+	rowsUpdate, err := ConvertTableToRow(table, ParentFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	if c.UpdateRows(Parent, rowsUpdate, conditions) == 0 {
+		return fmt.Errorf("Set fields %v failed", table)
+	}
+	return nil
+}
+
+// ParentSet set fields of Parent by default client
+func ParentSet(tableIndex interface{}, table TableParent) error {
+	return BatchtestClient.ParentSet(tableIndex, table)
+}
+
+// ParentDel delete Parent rows
+func (c *Client) ParentDel(conditions []interface{}) error {
+	_, tableNum := c.SelectRows(Parent, conditions)
+	if tableNum == 0 {
+		return fmt.Errorf("table %v not exist", conditions)
+	}
+
+	if c.DeleteRows(Parent, conditions) == 0 {
+		return fmt.Errorf("table %v delete failed", conditions)
+	}
+	return nil
+}
+
+// ParentDel delete Parent rows by default client
+func ParentDel(conditions []interface{}) error {
+	return BatchtestClient.ParentDel(conditions)
+}
+
+// ParentDelByIndex delete Parent by index
+func (c *Client) ParentDelByIndex(tableIndex interface{}) error {
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+	return c.ParentDel(conditions)
+}
+
+// ParentDelByIndex delete Parent by index by default client
+func ParentDelByIndex(tableIndex interface{}) error {
+	return BatchtestClient.ParentDelByIndex(tableIndex)
+}
+
+// ParentDelByUUID delete Parent by UUID
+func (c *Client) ParentDelByUUID(uuid string) error {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "==", stringToGoUUID(uuid)))
+	return c.ParentDel(conditions)
+}
+
+// ParentDelByUUID delete Parent by UUID by default client
+func ParentDelByUUID(uuid string) error {
+	return BatchtestClient.ParentDelByUUID(uuid)
+}
+
+// ParentGet get Parent rows
+func (c *Client) ParentGet(conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	return c.SelectRows(Parent, conditions)
+}
+
+// ParentGet get Parent rows by default client
+func ParentGet(conditions []interface{}) ([]libovsdb.ResultRow, int) {
+	return BatchtestClient.ParentGet(conditions)
+}
+
+// ParentGetByIndex get Parent by index
+func (c *Client) ParentGetByIndex(tableIndex interface{}) (TableParent, error) {
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+	rows, num := c.ParentGet(conditions)
+	if num != 1 {
+		return TableParent{}, fmt.Errorf("table %v not exist", tableIndex)
+	}
+	table := ConvertRowToParent(rows[0])
+	return table, nil
+}
+
+// ParentGetByIndex get Parent by index by default client
+func ParentGetByIndex(tableIndex interface{}) (TableParent, error) {
+	return BatchtestClient.ParentGetByIndex(tableIndex)
+}
+
+// ParentGetByUUID get Parent by UUID
+func (c *Client) ParentGetByUUID(uuid string) (TableParent, error) {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "==", stringToGoUUID(uuid)))
+	rows, num := c.ParentGet(conditions)
+	if num != 1 {
+		return TableParent{}, fmt.Errorf("table %v not exist", uuid)
+	}
+	table := ConvertRowToParent(rows[0])
+	return table, nil
+}
+
+// ParentGetByUUID get Parent by UUID by default client
+func ParentGetByUUID(uuid string) (TableParent, error) {
+	return BatchtestClient.ParentGetByUUID(uuid)
+}
+
+// ParentGetCount get Parent count
+func (c *Client) ParentGetCount() int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	_, num := c.ParentGet(conditions)
+	return num
+}
+
+// ParentGetCount get Parent count by default client
+func ParentGetCount() int {
+	return BatchtestClient.ParentGetCount()
+}
+
+// ParentIterator traverse Parent and call fn
+// return traversed number
+func (c *Client) ParentIterator(fn func(TableParent)) int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	rows, num := c.ParentGet(conditions)
+	if num > 0 {
+		for _, row := range rows {
+			table := ConvertRowToParent(row)
+			fn(table)
+		}
+	}
+	return num
+}
+
+// ParentIterator traverse Parent and call fn by default client
+func ParentIterator(fn func(TableParent)) int {
+	return BatchtestClient.ParentIterator(fn)
+}
+
+// ParentClear clear all Parent
+// return deleted rows number
+func (c *Client) ParentClear() int {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: InvalidUUID}))
+	return c.DeleteRows(Parent, conditions)
+}
+
+// ParentClear clear all Parent by default client
+func ParentClear() int {
+	return BatchtestClient.ParentClear()
+}
+
+// ParentSetField set field of Parent
+func (c *Client) ParentSetField(tableIndex interface{}, field string, value interface{}) error {
+	rowUpdate, err := convertFieldToRow(field, value)
+	if err != nil {
+		return err
+	}
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+	if c.UpdateRows(Parent, rowUpdate, conditions) == 0 {
+		return fmt.Errorf("Set field %v failed", value)
+	}
+	return nil
+}
+
+// ParentSetField set field of Parent by default client
+func ParentSetField(tableIndex interface{}, field string, value interface{}) error {
+	return BatchtestClient.ParentSetField(tableIndex, field, value)
+}
+
+// ParentUpdateChildrenAddvalue add value for array field of Parent
+func (c *Client) ParentUpdateChildrenAddvalue(tableIndex interface{},
+	field []libovsdb.UUID) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldChildren, opInsert, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateChildrenAddvalue add value for array field of Parent by default client
+func ParentUpdateChildrenAddvalue(tableIndex interface{},
+	field []libovsdb.UUID) error {
+	return BatchtestClient.ParentUpdateChildrenAddvalue(tableIndex, field)
+}
+
+// ParentUpdateChildrenDelvalue del value for array field of Parent
+func (c *Client) ParentUpdateChildrenDelvalue(tableIndex interface{},
+	field []libovsdb.UUID) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldChildren, opDelete, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateChildrenDelvalue del value for array field of Parent by default client
+func ParentUpdateChildrenDelvalue(tableIndex interface{},
+	field []libovsdb.UUID) error {
+	return BatchtestClient.ParentUpdateChildrenDelvalue(tableIndex, field)
+}
+
+// ParentUpdateAddChildren add value for array field of Parent
+func (c *Client) ParentUpdateAddChildren(tableIndex interface{},
+	tableRef TableChild) error {
+	var ops []libovsdb.Operation
+
+	insertRefOp, err := ChildAddOp(tableRef)
+	if err != nil {
+		return fmt.Errorf("Get refTable %v operation failed", ParentFieldChildren)
+	}
+	ops = append(ops, insertRefOp)
+
+	oSet, err := libovsdb.NewOvsSet([]libovsdb.UUID{stringToGoUUID(insertRefOp.UUIDName)})
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", insertRefOp.UUIDName)
+	}
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldChildren, opInsert, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+	mutateOp := libovsdb.Operation{
+		Op:        opMutate,
+		Table:     Parent,
+		Mutations: mutations,
+		Where:     conditions,
+	}
+	ops = append(ops, mutateOp)
+
+	_, err = c.Transact(ops...)
+	if err != nil {
+		return fmt.Errorf("error: %v", err)
+	}
+	return nil
+}
+
+// ParentUpdateAddChildren add value for array field of Parent by default client
+func ParentUpdateAddChildren(tableIndex interface{},
+	tableRef TableChild) error {
+	return BatchtestClient.ParentUpdateAddChildren(tableIndex, tableRef)
+}
+
+// ParentUpdateTagsAddvalue add value for array field of Parent
+func (c *Client) ParentUpdateTagsAddvalue(tableIndex interface{},
+	field []string) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldTags, opInsert, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateTagsAddvalue add value for array field of Parent by default client
+func ParentUpdateTagsAddvalue(tableIndex interface{},
+	field []string) error {
+	return BatchtestClient.ParentUpdateTagsAddvalue(tableIndex, field)
+}
+
+// ParentUpdateTagsDelvalue del value for array field of Parent
+func (c *Client) ParentUpdateTagsDelvalue(tableIndex interface{},
+	field []string) error {
+	oSet, err := libovsdb.NewOvsSet(field)
+	if err != nil {
+		return fmt.Errorf("OvsSet trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldTags, opDelete, oSet))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateTagsDelvalue del value for array field of Parent by default client
+func ParentUpdateTagsDelvalue(tableIndex interface{},
+	field []string) error {
+	return BatchtestClient.ParentUpdateTagsDelvalue(tableIndex, field)
+}
+
+// ParentUpdateOptionsSetkey set key for map field of Parent
+func (c *Client) ParentUpdateOptionsSetkey(tableIndex interface{},
+	field map[interface{}]interface{}) error {
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldOptions, opInsert, oMap))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateOptionsSetkey set key for map field of Parent by default client
+func ParentUpdateOptionsSetkey(tableIndex interface{},
+	field map[interface{}]interface{}) error {
+	return BatchtestClient.ParentUpdateOptionsSetkey(tableIndex, field)
+}
+
+// ParentUpdateOptionsDelkey del key for map field of Parent
+func (c *Client) ParentUpdateOptionsDelkey(tableIndex interface{},
+	field map[interface{}]interface{}) error {
+	oMap, err := libovsdb.NewOvsMap(field)
+	if err != nil {
+		return fmt.Errorf("OvsMap trans error for %v", field)
+	}
+
+	var mutations []interface{}
+	mutations = append(mutations, libovsdb.NewMutation(ParentFieldOptions, opDelete, oMap))
+	conditions, _ := convertIndexToConditions(tableIndex, ParentFieldMapToColumn)
+
+	if c.MutateRows(Parent, mutations, conditions) == 0 {
+		return fmt.Errorf("Update field %v failed", field)
+	}
+	return nil
+}
+
+// ParentUpdateOptionsDelkey del key for map field of Parent by default client
+func ParentUpdateOptionsDelkey(tableIndex interface{},
+	field map[interface{}]interface{}) error {
+	return BatchtestClient.ParentUpdateOptionsDelkey(tableIndex, field)
+}
+
+// ConvertRowToParent convert map[string]interface{} to table struct
+func ConvertRowToParent(row libovsdb.ResultRow) TableParent {
+	var table TableParent
+	tablePtr := &table
+	typ := reflect.TypeOf(table)
+	val := reflect.ValueOf(table)
+	tableElems := reflect.ValueOf(tablePtr).Elem()
+
+	float64ToInt(row)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Name == "UUID" {
+			if UUID, ok := row["_uuid"].(libovsdb.UUID); ok {
+				tableElems.FieldByName(typ.Field(i).Name).SetString(UUID.GoUUID)
+			}
+			continue
+		}
+		switch val.Field(i).Interface().(type) {
+		case string:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ParentFieldMapToColumn[typ.Field(i).Name]].(string)))
+			}
+		case int:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ParentFieldMapToColumn[typ.Field(i).Name]].(int)))
+			}
+		case float64:
+			switch value := row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(float64(value)))
+			}
+		case bool:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ParentFieldMapToColumn[typ.Field(i).Name]].(bool)))
+			}
+		case libovsdb.UUID:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)))
+			}
+		case []string:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case string:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]string{row[ParentFieldMapToColumn[typ.Field(i).Name]].(string)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToStringArray(row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []int:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]int{row[ParentFieldMapToColumn[typ.Field(i).Name]].(int)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToIntArray(row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []float64:
+			switch value := row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case float64:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{value}))
+			case int:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf([]float64{float64(value)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(convertOvsSetToRealArray(value)))
+			}
+		case []bool:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case bool:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]bool{row[ParentFieldMapToColumn[typ.Field(i).Name]].(bool)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToBoolArray(row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case []libovsdb.UUID:
+			switch row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.UUID:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf([]libovsdb.UUID{row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.UUID)}))
+			case libovsdb.OvsSet:
+				tableElems.FieldByName(typ.Field(i).Name).
+					Set(reflect.ValueOf(convertOvsSetToUUIDArray(row[ParentFieldMapToColumn[typ.Field(i).Name]].(libovsdb.OvsSet))))
+			}
+		case map[interface{}]interface{}:
+			switch value := row[ParentFieldMapToColumn[typ.Field(i).Name]].(type) {
+			case libovsdb.OvsMap:
+				tableElems.FieldByName(typ.Field(i).Name).Set(reflect.ValueOf(value.GoMap))
+			}
+		}
+	}
+
+	return table
+}
@@ -0,0 +1,172 @@
+package batchtest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/ebay/libovsdb"
+)
+
+// bounds of schema ranges and cardinality
+const (
+	intMax       int     = int(^uint(0) >> 1)
+	intMin       int     = -intMax - 1
+	realMax      float64 = math.MaxFloat64
+	realMin      float64 = -math.MaxFloat64
+	maxUnlimited int     = intMax
+)
+
+// baseConstraint schema constraints of column key or value
+type baseConstraint struct {
+	atomic      string
+	enum        []interface{}
+	intRange    bool
+	minInteger  int
+	maxInteger  int
+	realRange   bool
+	minReal     float64
+	maxReal     float64
+	lengthRange bool
+	minLength   int
+	maxLength   int
+}
+
+// columnConstraint schema constraints of column, refTable is set
+// for required reference column
+type columnConstraint struct {
+	field    string
+	column   string
+	min      int
+	max      int
+	key      baseConstraint
+	value    *baseConstraint
+	refTable string
+}
+
+// normalizeAtom libovsdb decode all numbers as float64
+func (b *baseConstraint) normalizeAtom(atom interface{}) interface{} {
+	switch v := atom.(type) {
+	case float64:
+		if b.atomic == "integer" && v == math.Trunc(v) {
+			return int(v)
+		}
+	case int:
+		if b.atomic == "real" {
+			return float64(v)
+		}
+	}
+	return atom
+}
+
+func (b *baseConstraint) validate(table string, column string, atom interface{}) error {
+	atom = b.normalizeAtom(atom)
+
+	if len(b.enum) > 0 {
+		found := false
+		for _, e := range b.enum {
+			if e == atom {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s.%s: %v is not one of %v", table, column, atom, b.enum)
+		}
+	}
+
+	if b.intRange {
+		v, ok := atom.(int)
+		if !ok {
+			return fmt.Errorf("%s.%s: %v is not integer", table, column, atom)
+		}
+		if v < b.minInteger || v > b.maxInteger {
+			return fmt.Errorf("%s.%s: %d out of range [%d, %d]", table, column, v, b.minInteger, b.maxInteger)
+		}
+	}
+
+	if b.realRange {
+		v, ok := atom.(float64)
+		if !ok {
+			return fmt.Errorf("%s.%s: %v is not real", table, column, atom)
+		}
+		if v < b.minReal || v > b.maxReal {
+			return fmt.Errorf("%s.%s: %v out of range [%v, %v]", table, column, v, b.minReal, b.maxReal)
+		}
+	}
+
+	if b.lengthRange {
+		v, ok := atom.(string)
+		if !ok {
+			return fmt.Errorf("%s.%s: %v is not string", table, column, atom)
+		}
+		if n := utf8.RuneCountInString(v); n < b.minLength || n > b.maxLength {
+			return fmt.Errorf("%s.%s: length of %q out of range [%d, %d]", table, column, v, b.minLength, b.maxLength)
+		}
+	}
+	return nil
+}
+
+func (c *columnConstraint) validateCount(table string, n int, insert bool) error {
+	if n > c.max {
+		return fmt.Errorf("%s.%s: %d elements, at most %d allowed", table, c.column, n, c.max)
+	}
+	// empty set and map are not written by Set, only check min on insert
+	if insert && n < c.min {
+		return fmt.Errorf("%s.%s: %d elements, at least %d required", table, c.column, n, c.min)
+	}
+	return nil
+}
+
+// validateTable check table struct with column constraints, insert
+// means the row would be inserted, then required references and min
+// cardinality are checked too
+func validateTable(table string, row interface{}, constraints []columnConstraint, insert bool) error {
+	val := reflect.ValueOf(row)
+
+	for i := range constraints {
+		c := &constraints[i]
+		field := val.FieldByName(c.field)
+
+		switch field.Kind() {
+		case reflect.Slice:
+			if err := c.validateCount(table, field.Len(), insert); err != nil {
+				return err
+			}
+			for j := 0; j < field.Len(); j++ {
+				if err := c.key.validate(table, c.column, field.Index(j).Interface()); err != nil {
+					return err
+				}
+			}
+		case reflect.Map:
+			if err := c.validateCount(table, field.Len(), insert); err != nil {
+				return err
+			}
+			iter := field.MapRange()
+			for iter.Next() {
+				if err := c.key.validate(table, c.column, iter.Key().Interface()); err != nil {
+					return err
+				}
+				if c.value == nil {
+					continue
+				}
+				if err := c.value.validate(table, c.column, iter.Value().Interface()); err != nil {
+					return err
+				}
+			}
+		default:
+			if uuid, ok := field.Interface().(libovsdb.UUID); ok {
+				// unset reference is not written by Set
+				if insert && c.refTable != "" && uuid.GoUUID == "" {
+					return fmt.Errorf("%s.%s: required reference to %s not set", table, c.column, c.refTable)
+				}
+				continue
+			}
+			if err := c.key.validate(table, c.column, field.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
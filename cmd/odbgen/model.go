@@ -0,0 +1,444 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// dbModel is the data passed to templates for one database
+type dbModel struct {
+	Name      string // schema name, eg: OVN_Northbound
+	Package   string // go package name, eg: ovnnorthbound
+	Const     string // db name const, eg: OVNNORTHBOUND
+	Prefix    string // exported name prefix, eg: Ovnnorthbound
+	ClientVar string // default client, eg: OvnnorthboundClient
+	Tables    []*tableModel
+}
+
+// tableModel is the data passed to templates for one table
+type tableModel struct {
+	DB          *dbModel
+	Name        string // schema name, eg: Port_Binding
+	GoName      string // eg: PortBinding
+	NameConst   string // table name const, eg: PortBinding
+	FileName    string // eg: table_port_binding.go
+	IsRoot      bool
+	Global      bool // maxRows is 1
+	Columns     []*columnModel
+	Indexes     []indexModel
+	Defaults    []*columnModel // columns having default value
+	UUIDColumns []string
+}
+
+// columnModel is the data passed to templates for one column
+type columnModel struct {
+	Table     *tableModel
+	Name      string // schema name, eg: tunnel_key
+	GoName    string // eg: TunnelKey
+	GoType    string // struct field type
+	KeyType   string // go type of key
+	ValueType string // go type of value, only for map column
+	Kind      string // scalar, optional, set or map
+	Min       int
+	Max       int
+
+	// KeyObject the type of column is an object with key member,
+	// Addvalue/Delvalue helpers are generated for them
+	KeyObject bool
+	RefTable  *tableModel
+
+	DefaultConst   string // literal of default const, only for key default
+	DefaultLiteral string // literal in fields default value map
+
+	RangeType string
+	RangeMin  string
+	RangeMax  string
+	Enums     []enumModel
+
+	// Constraint literal of columnConstraint used by Validate, empty
+	// when column has nothing to validate
+	Constraint string
+}
+
+type indexModel struct {
+	Name    string
+	Columns []*columnModel
+}
+
+type enumModel struct {
+	Const string
+	Value string
+}
+
+// column kinds
+const (
+	kindScalar   string = "scalar"
+	kindOptional string = "optional"
+	kindSet      string = "set"
+	kindMap      string = "map"
+)
+
+var goAtomicType = map[string]string{
+	atomicInteger: "int",
+	atomicReal:    "float64",
+	atomicBoolean: "bool",
+	atomicString:  "string",
+	atomicUUID:    "libovsdb.UUID",
+}
+
+var upperWords = map[string]bool{
+	"ip": true, "id": true, "acl": true, "tcp": true, "udp": true, "dns": true,
+}
+
+func capitalize(str string) string {
+	if str == "" || str[0] < 'a' || str[0] > 'z' {
+		return str
+	}
+	return string(str[0]-32) + str[1:]
+}
+
+func capitalizeEachWord(str string) string {
+	var processedStr string
+	for _, word := range strings.Split(strings.ToLower(str), "_") {
+		if upperWords[word] {
+			processedStr += strings.ToUpper(word)
+		} else {
+			processedStr += capitalize(word)
+		}
+	}
+	return processedStr
+}
+
+func capitalizeEachWordDelDash(str string) string {
+	processedStr := capitalizeEachWord(str)
+	processedStr = strings.Replace(processedStr, "-", "", -1)
+	processedStr = strings.Replace(processedStr, "|", "", -1)
+	return processedStr
+}
+
+// newDBModel build template data from validated schema, tables and
+// columns are sorted by name so generated code is deterministic
+func newDBModel(s DatabaseSchema) (*dbModel, error) {
+	lower := strings.Replace(strings.ToLower(s.Name), "_", "", -1)
+	db := &dbModel{
+		Name:      s.Name,
+		Package:   lower,
+		Const:     strings.ToUpper(lower),
+		Prefix:    capitalize(lower),
+		ClientVar: capitalize(lower) + "Client",
+	}
+
+	tables := make(map[string]*tableModel)
+	for _, name := range sortedTableNames(s.Tables) {
+		ts := s.Tables[name]
+		table := &tableModel{
+			DB:       db,
+			Name:     name,
+			GoName:   capitalizeEachWord(name),
+			FileName: "table_" + strings.ToLower(name) + ".go",
+			IsRoot:   ts.IsRoot,
+			Global:   ts.MaxRows != nil && *ts.MaxRows == 1,
+		}
+		if table.Global && len(ts.Indexes) > 0 {
+			return nil, fmt.Errorf("global table %v (maxrow=1) don't have indexes", name)
+		}
+		tables[name] = table
+		db.Tables = append(db.Tables, table)
+	}
+	tableNameConsts(db.Tables)
+
+	for _, table := range db.Tables {
+		ts := s.Tables[table.Name]
+		columns := make(map[string]*columnModel)
+		for _, name := range sortedColumnNames(ts.Columns) {
+			column, err := newColumnModel(table, name, ts.Columns[name].Type, tables)
+			if err != nil {
+				return nil, fmt.Errorf("table %s column %s: %v", table.Name, name, err)
+			}
+			columns[name] = column
+			table.Columns = append(table.Columns, column)
+			if column.DefaultLiteral != "" {
+				table.Defaults = append(table.Defaults, column)
+			}
+			if column.KeyType == goAtomicType[atomicUUID] {
+				table.UUIDColumns = append(table.UUIDColumns, name)
+			}
+		}
+
+		for i, index := range ts.Indexes {
+			indexName := table.GoName + "Index"
+			if i > 0 {
+				indexName += strconv.Itoa(i)
+			}
+			im := indexModel{Name: indexName}
+			for _, name := range index {
+				im.Columns = append(im.Columns, columns[name])
+			}
+			table.Indexes = append(table.Indexes, im)
+		}
+	}
+	return db, nil
+}
+
+// tableOpSuffixes suffixes of generated table functions and types
+var tableOpSuffixes = []string{
+	"Add", "AddOp", "Clear", "Del", "DelByIndex", "DelByUUID", "Fields",
+	"Get", "GetByIndex", "GetByUUID", "GetCount", "Handler", "Index",
+	"Iterator", "Set", "SetField", "UUIDIndex",
+}
+
+// tableNameConsts table name const is the go name of table, table whose
+// go name is a generated function of another table, eg Physical_Locator_Set
+// and PhysicalLocatorSet of Physical_Locator, gets suffix Table
+func tableNameConsts(tables []*tableModel) {
+	generated := make(map[string]bool)
+	for _, table := range tables {
+		for _, suffix := range tableOpSuffixes {
+			generated[table.GoName+suffix] = true
+		}
+	}
+	for _, table := range tables {
+		table.NameConst = table.GoName
+		if generated[table.GoName] {
+			table.NameConst += "Table"
+		}
+	}
+}
+
+func newColumnModel(table *tableModel, name string, t ColumnType,
+	tables map[string]*tableModel) (*columnModel, error) {
+	column := &columnModel{
+		Table:     table,
+		Name:      name,
+		GoName:    capitalizeEachWord(name),
+		KeyType:   goAtomicType[t.Key.Type],
+		Min:       t.Min,
+		Max:       t.Max,
+		KeyObject: t.Object && t.Value == nil,
+	}
+
+	switch {
+	case t.IsMap():
+		column.Kind = kindMap
+		column.ValueType = goAtomicType[t.Value.Type]
+		column.GoType = "map[interface{}]interface{}"
+	case t.IsScalar():
+		column.Kind = kindScalar
+		column.GoType = column.KeyType
+	case t.IsOptional():
+		// optional scalar is zero or one element slice
+		column.Kind = kindOptional
+		column.GoType = "[]" + column.KeyType
+	default:
+		column.Kind = kindSet
+		column.GoType = "[]" + column.KeyType
+	}
+
+	if t.Key.RefTable != "" {
+		column.RefTable = tables[t.Key.RefTable]
+	}
+
+	// key default generate const, both key and type default go to
+	// fields default value map
+	if t.Key.Default != nil {
+		literal, err := atomLiteral(t.Key.Default, t.Key.Type)
+		if err != nil {
+			return nil, err
+		}
+		column.DefaultConst = literal
+		column.DefaultLiteral = literal
+	}
+	if t.Default != nil {
+		literal, err := defaultLiteral(t.Default, t)
+		if err != nil {
+			return nil, err
+		}
+		column.DefaultLiteral = literal
+	}
+
+	// value range and enum take precedence over key for map column
+	bases := []BaseType{t.Key}
+	if t.Value != nil {
+		bases = append(bases, *t.Value)
+	}
+	for _, base := range bases {
+		if base.MinInteger != nil || base.MaxInteger != nil {
+			column.RangeType, column.RangeMin, column.RangeMax = "int", "", ""
+			if base.MinInteger != nil {
+				column.RangeMin = strconv.Itoa(*base.MinInteger)
+			}
+			if base.MaxInteger != nil {
+				column.RangeMax = strconv.Itoa(*base.MaxInteger)
+			}
+		}
+		if base.MinReal != nil || base.MaxReal != nil {
+			column.RangeType, column.RangeMin, column.RangeMax = "float64", "", ""
+			if base.MinReal != nil {
+				column.RangeMin = realLiteral(*base.MinReal)
+			}
+			if base.MaxReal != nil {
+				column.RangeMax = realLiteral(*base.MaxReal)
+			}
+		}
+		if len(base.Enum) > 0 && base.Type == atomicString {
+			column.Enums = nil
+			for _, atom := range base.Enum {
+				column.Enums = append(column.Enums, enumModel{
+					Const: table.GoName + column.GoName + capitalizeEachWordDelDash(atom.(string)),
+					Value: atom.(string),
+				})
+			}
+		}
+	}
+	constraint, err := constraintLiteral(column, t)
+	if err != nil {
+		return nil, err
+	}
+	column.Constraint = constraint
+	return column, nil
+}
+
+// constraintLiteral generate columnConstraint literal for column
+func constraintLiteral(column *columnModel, t ColumnType) (string, error) {
+	key, err := baseConstraintLiteral(t.Key)
+	if err != nil {
+		return "", err
+	}
+	value := ""
+	if t.Value != nil {
+		if value, err = baseConstraintLiteral(*t.Value); err != nil {
+			return "", err
+		}
+	}
+	required := t.IsScalar() && t.Key.RefTable != ""
+	cardinality := column.Kind != kindScalar && (t.Min > 0 || t.Max != IntMax)
+	if key == "" && value == "" && !required && !cardinality {
+		return "", nil
+	}
+
+	max := strconv.Itoa(t.Max)
+	if t.Max == IntMax {
+		max = "maxUnlimited"
+	}
+	literal := fmt.Sprintf("{field: %q, column: %sField%s, min: %d, max: %s",
+		column.GoName, column.Table.GoName, column.GoName, t.Min, max)
+	if key != "" {
+		literal += ", key: " + key
+	}
+	if value != "" {
+		literal += ", value: &" + value
+	}
+	if required {
+		literal += fmt.Sprintf(", refTable: %q", t.Key.RefTable)
+	}
+	return literal + "}", nil
+}
+
+// baseConstraintLiteral generate baseConstraint literal, empty when
+// base type has no enum, range or length constraint
+func baseConstraintLiteral(b BaseType) (string, error) {
+	var fields []string
+	if len(b.Enum) > 0 {
+		var atoms []string
+		for _, atom := range b.Enum {
+			literal, err := atomLiteral(atom, b.Type)
+			if err != nil {
+				return "", err
+			}
+			atoms = append(atoms, literal)
+		}
+		fields = append(fields, "enum: []interface{}{"+strings.Join(atoms, ", ")+"}")
+	}
+	if b.MinInteger != nil || b.MaxInteger != nil {
+		min, max := "intMin", "intMax"
+		if b.MinInteger != nil {
+			min = strconv.Itoa(*b.MinInteger)
+		}
+		if b.MaxInteger != nil {
+			max = strconv.Itoa(*b.MaxInteger)
+		}
+		fields = append(fields, "intRange: true, minInteger: "+min+", maxInteger: "+max)
+	}
+	if b.MinReal != nil || b.MaxReal != nil {
+		min, max := "realMin", "realMax"
+		if b.MinReal != nil {
+			min = realLiteral(*b.MinReal)
+		}
+		if b.MaxReal != nil {
+			max = realLiteral(*b.MaxReal)
+		}
+		fields = append(fields, "realRange: true, minReal: "+min+", maxReal: "+max)
+	}
+	if b.MinLength != nil || b.MaxLength != nil {
+		min, max := "0", "intMax"
+		if b.MinLength != nil {
+			min = strconv.Itoa(*b.MinLength)
+		}
+		if b.MaxLength != nil {
+			max = strconv.Itoa(*b.MaxLength)
+		}
+		fields = append(fields, "lengthRange: true, minLength: "+min+", maxLength: "+max)
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("baseConstraint{atomic: %q, %s}", b.Type, strings.Join(fields, ", ")), nil
+}
+
+func realLiteral(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+func atomLiteral(atom interface{}, atomicType string) (string, error) {
+	switch v := atom.(type) {
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		if atomicType == atomicReal {
+			return realLiteral(v), nil
+		}
+		if v != math.Trunc(v) {
+			return "", fmt.Errorf("default %v is not integer", v)
+		}
+		return strconv.FormatInt(int64(v), 10), nil
+	}
+	return "", fmt.Errorf("unsupported default %v", atom)
+}
+
+// defaultLiteral literal of column level default, a map default is
+// generated as map[interface{}]interface{} with sorted keys
+func defaultLiteral(def interface{}, t ColumnType) (string, error) {
+	m, ok := def.(map[string]interface{})
+	if !ok {
+		return atomLiteral(def, t.Key.Type)
+	}
+
+	valueType := atomicString
+	if t.Value != nil {
+		valueType = t.Value.Type
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	literal := "map[interface{}]interface{}{\n"
+	for _, k := range keys {
+		v, err := atomLiteral(m[k], valueType)
+		if err != nil {
+			return "", err
+		}
+		literal += strconv.Quote(k) + ": " + v + ",\n"
+	}
+	return literal + "}", nil
+}
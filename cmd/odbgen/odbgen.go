@@ -1,28 +1,31 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
-	"encoding/json"
+	"crypto/sha256"
 	"flag"
 	"fmt"
-	"io"
+	"go/format"
 	"io/ioutil"
 	"os"
-	"os/exec"
-	"strconv"
+	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
 )
 
-// IntMax ...
-const IntMax = int(^uint(0) >> 1)
-
 var schemaFile string = "ovsdb.ovsschema"
 var libDir string = "../../lib/odbapi/"
+var templateDir string = "template"
+var goldenDir string = "testdata"
+var check bool = false
+var update bool = false
 var help bool = false
 
 func usage() {
 	fmt.Fprintf(os.Stderr, `odbgen
-Usage: odbgen [-h] [-f dbSchemaFile]
+Usage: odbgen [-h] [-f dbSchemaFile] [-d libDir] [-t templateDir] [-check|-update] [-golden goldenDir]
 
 Options:
 `)
@@ -30,749 +33,242 @@ Options:
 }
 
 func init() {
-	flag.StringVar(&schemaFile, "f", schemaFile, "connect to vtep database")
+	flag.StringVar(&schemaFile, "f", schemaFile, "ovsdb schema file")
 	flag.StringVar(&libDir, "d", libDir, "lib ovsdb api dir")
+	flag.StringVar(&templateDir, "t", templateDir, "code template dir")
+	flag.StringVar(&goldenDir, "golden", goldenDir, "golden file dir")
+	flag.BoolVar(&check, "check", false, "compare generated code with golden file, nothing written to lib dir")
+	flag.BoolVar(&update, "update", false, "update golden file with generated code")
 	flag.BoolVar(&help, "h", false, "display this help message")
 	flag.Usage = usage
 }
 
-// DatabaseSchema is a database schema according to RFC7047
-type DatabaseSchema struct {
-	Name    string                 `json:"name"`
-	Version string                 `json:"version"`
-	Tables  map[string]TableSchema `json:"tables"`
-}
-
-// TableSchema is a table schema according to RFC7047
-type TableSchema struct {
-	Columns map[string]ColumnSchema `json:"columns"`
-	Indexes [][]string              `json:"indexes,omitempty"`
-	IsRoot  bool                    `json:"isRoot,omitempty"`
-	MaxRows int                     `json:"maxRows,omitempty"`
-}
-
-// ColumnSchema is a column schema according to RFC7047
-type ColumnSchema struct {
-	Type      interface{} `json:"type"`
-	Ephemeral bool        `json:"ephemeral,omitempty"`
-	Mutable   bool        `json:"mutable,omitempty"`
-}
-
-func schemaCheck() (string, error) {
-	tempDBFileName := "temp.db"
-	os.Remove(tempDBFileName)
-
-	cmd := "sudo ovsdb-tool create " + tempDBFileName + " " + schemaFile
-	out, err := execShell(cmd)
-	os.Remove(tempDBFileName)
-
+// odbgen generate code of all files for one database, return map
+// of file name to gofmt formatted code
+func odbgen(s DatabaseSchema) (map[string][]byte, error) {
+	db, err := newDBModel(s)
 	if err != nil {
-		fmt.Println("remove file ,err ", err)
+		return nil, err
 	}
 
-	return out, err
-}
-
-func fileGetContents(path string) ([]byte, error) {
-	f, err := os.Open(path)
+	tmpl, err := template.ParseGlob(filepath.Join(templateDir, "*.tmpl"))
 	if err != nil {
 		return nil, err
 	}
-	return ioutil.ReadAll(f)
-}
 
-func capitalize(str string) string {
-	var upperStr string
-	vv := []rune(str)
-	for i := 0; i < len(vv); i++ {
-		if i == 0 {
-			if vv[i] >= 97 && vv[i] <= 122 {
-				vv[i] -= 32
-				upperStr += string(vv[i])
-			} else {
-				return str
-			}
-		} else {
-			upperStr += string(vv[i])
+	files := make(map[string][]byte)
+	gen := func(name string, tmplName string, data interface{}) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, tmplName, data); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		code, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: gofmt failed: %v", name, err)
 		}
+		files[name] = code
+		return nil
 	}
-	return upperStr
-}
 
-func capitalizeEachWord(str string) string {
-	var processedStr string
-	str = strings.ToLower(str)
-	words := strings.Split(str, "_")
-	for _, word := range words {
-		if word == "ip" || word == "id" || word == "acl" || word == "tcp" || word == "udp" || word == "dns" {
-			processedStr += strings.ToUpper(word)
-		} else {
-			processedStr += capitalize(word)
+	for _, name := range []string{"odbinit.go", "odbop.go", "common.go", "define.go", "validate.go", "notify.go", "batch.go"} {
+		if err := gen(name, name+".tmpl", db); err != nil {
+			return nil, err
 		}
 	}
-	return processedStr
-}
-
-func capitalizeEachWordDelDash(str string) string {
-	var processedStr string
-	str = strings.ToLower(str)
-	words := strings.Split(str, "_")
-	for _, word := range words {
-		if word == "ip" || word == "id" || word == "acl" || word == "tcp" || word == "udp" || word == "dns" {
-			processedStr += strings.ToUpper(word)
-		} else {
-			processedStr += capitalize(word)
+	for _, table := range db.Tables {
+		if err := gen(table.FileName, "table.go.tmpl", table); err != nil {
+			return nil, err
 		}
 	}
-	processedStr = strings.Replace(processedStr, "-", "", -1)
-	processedStr = strings.Replace(processedStr, "|", "", -1)
-	return processedStr
+	return files, nil
 }
 
-func execShell(s string) (string, error) {
-	cmd := exec.Command("/bin/bash", "-c", s)
-	var out bytes.Buffer
-	cmd.Stderr = &out
-	err := cmd.Run()
-	return out.String(), err
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
-func copyFile(src, des string) (written int64, err error) {
-	srcFile, err := os.Open(src)
+// writeFiles replace files of db dir with generated files, tests of
+// generated package are kept
+func writeFiles(dbDir string, files map[string][]byte) error {
+	old, err := filepath.Glob(filepath.Join(dbDir, "*"))
 	if err != nil {
-		return 0, err
+		return err
 	}
-	defer srcFile.Close()
-
-	fi, _ := srcFile.Stat()
-	perm := fi.Mode()
-
-	desFile, err := os.OpenFile(des, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
-	if err != nil {
-		return 0, err
+	for _, file := range old {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if err := os.RemoveAll(file); err != nil {
+			return err
+		}
 	}
-	defer desFile.Close()
-
-	return io.Copy(desFile, srcFile)
+	if err := os.MkdirAll(dbDir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, name := range sortedFileNames(files) {
+		if err := ioutil.WriteFile(filepath.Join(dbDir, name), files[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func odbgen(c DatabaseSchema) {
-	var err error
-	// Get dbname
-	Dbname := c.Name
-	fmt.Printf("DB: %v\n", Dbname)
-	DbnameLowCase := strings.Replace(strings.ToLower(Dbname), "_", "", -1)
-	DbnameUpCase := strings.Replace(strings.ToUpper(Dbname), "_", "", -1)
-	dbDir := libDir + DbnameLowCase
-
-	// mkdir
-	err = os.RemoveAll(dbDir)
-	err = os.MkdirAll(dbDir, os.ModePerm)
+// goldenManifest sha256sum format manifest of generated files,
+// can also be verified by "sha256sum -c" in lib dir
+func goldenManifest(files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	for _, name := range sortedFileNames(files) {
+		fmt.Fprintf(&buf, "%x  %s\n", sha256.Sum256(files[name]), name)
+	}
+	return buf.Bytes()
+}
 
-	var defineFileStr string
-	var tableFileStrs []string
+func goldenFile(pkg string) string {
+	return filepath.Join(goldenDir, pkg+".golden")
+}
 
-	defineFileStr += "package " + DbnameLowCase + "\n"
-	defineFileStr += "// DB name\n"
-	defineFileStr += "const (\n\t" + DbnameUpCase + " string = \"" + Dbname + "\"\n)\n"
-	defineFileStr += "// Table name\n"
-	defineFileStr += "const (\n"
+// goldenCodeDir dir of golden generated code of pkg, code is kept for
+// a representative subset of schemas, others only have manifest
+func goldenCodeDir(pkg string) string {
+	return filepath.Join(goldenDir, pkg)
+}
 
-	// gen odbinit.go
-	odbinitfileTemp, err := ioutil.ReadFile("dbname/odbinit.go")
-	if err != nil {
-		return
+// checkGolden compare generated files with golden code of pkg if kept,
+// or with its golden manifest
+func checkGolden(pkg string, files map[string][]byte) error {
+	var diffs []string
+	var err error
+	if _, statErr := os.Stat(goldenCodeDir(pkg)); statErr == nil {
+		diffs, err = diffGoldenCode(goldenCodeDir(pkg), files)
+	} else {
+		diffs, err = diffGoldenManifest(goldenFile(pkg), files)
 	}
-	odbinitfileStr := string(odbinitfileTemp)
-	odbinitfileStr = strings.Replace(odbinitfileStr, "dbname", DbnameLowCase, -1)
-	odbinitfileStr = strings.Replace(odbinitfileStr, "DBNAME", DbnameUpCase, -1)
-	odbinitfileStr = strings.Replace(odbinitfileStr, "Dbname", capitalize(DbnameLowCase), -1)
-	odbinitfile, err := os.Create(dbDir + "/odbinit.go")
 	if err != nil {
-		fmt.Printf("odbinit create error: %v\n", err)
-		return
+		return err
 	}
-	defer odbinitfile.Close()
-	odbinitfile.WriteString(odbinitfileStr)
-
-	// gen odbop.go
-	odbopfileTemp, err := ioutil.ReadFile("dbname/odbop.go")
-	if err != nil {
-		return
+	if len(diffs) > 0 {
+		sort.Strings(diffs)
+		return fmt.Errorf("generated code of %s differs from golden:\n\t%s\nrun with -update if the change is expected",
+			pkg, strings.Join(diffs, "\n\t"))
 	}
-	odbopfileStr := string(odbopfileTemp)
-	odbopfileStr = strings.Replace(odbopfileStr, "dbname", DbnameLowCase, -1)
-	odbopfileStr = strings.Replace(odbopfileStr, "DBNAME", DbnameUpCase, -1)
-	odbopfileStr = strings.Replace(odbopfileStr, "Dbname", capitalize(DbnameLowCase), -1)
-	odbopfile, err := os.Create(dbDir + "/odbop.go")
-	if err != nil {
-		fmt.Printf("odbop create error: %v\n", err)
-		return
-	}
-	defer odbopfile.Close()
-	odbopfile.WriteString(odbopfileStr)
+	return nil
+}
 
-	// copy common.go
-	commonfileTemp, err := ioutil.ReadFile("dbname/common.go")
+// diffGoldenManifest files differ from checksums of manifest
+func diffGoldenManifest(manifest string, files map[string][]byte) ([]string, error) {
+	golden, err := ioutil.ReadFile(manifest)
 	if err != nil {
-		return
-	}
-	commonfileStr := string(commonfileTemp)
-	commonfileStr = strings.Replace(commonfileStr, "dbname", DbnameLowCase, -1)
-	commonfile, err := os.Create(dbDir + "/common.go")
-	if err != nil {
-		fmt.Printf("common create error: %v\n", err)
-		return
+		return nil, err
 	}
-	defer commonfile.Close()
-	commonfile.WriteString(commonfileStr)
-
-	tableFiledDefaultMap := make(map[string]map[string]interface{})
-	tableUUIDColumns := make(map[string]map[string]int)
 
-	// Gen ovsdb table code
-	for tablename, table := range c.Tables {
-		fmt.Printf("Gen code for table %v\n", tablename)
-		tablenamenosep := capitalizeEachWord(tablename)
-
-		defineFileStr += "\t" + tablenamenosep + " string = " + "\"" + tablename + "\"\n"
-
-		var tablestruct string
-
-		tablestruct += "// Table" + tablenamenosep + " definition\n"
-		tablestruct += "type Table" + tablenamenosep + " struct {\n"
-		tablestruct += "\tUUID string\n"
-
-		var tableFields string
-		tableFields += "// " + tablenamenosep + "Fields name\n"
-		tableFields += "const (\n"
-		tableFields += "\t" + tablenamenosep + "Field" + "UUID" + " string = " + "\"" + "_uuid" + "\"\n"
-
-		var fieldMapToColumn string
-		fieldMapToColumn += "// " + tablenamenosep + "FieldMapToColumn map field name to columns\n"
-		fieldMapToColumn += "var " + tablenamenosep + "FieldMapToColumn map[string]string = map[string]string{\n"
-		fieldMapToColumn += "\t\"" + "UUID" + "\" : " + tablenamenosep + "Field" + "UUID" + ",\n"
-
-		//var fieldsets []string
-		var fieldadddelvalues []string
-		var fieldsetdelkeys []string
-
-		// decide table code template file
-		tableCodeTmpFile := "dbname/template/tablecode"
-		adddelvalTmpFile := "dbname/template/adddelval"
-		setdelkeyTmpFile := "dbname/template/setdelkey"
-		addreftblTmpFile := "dbname/template/addreftbl"
-		rowtostructTmpFile := "dbname/template/rowtostruct"
-		if table.MaxRows == 1 {
-			tableCodeTmpFile += "_global"
-			if table.IsRoot == false {
-				tableCodeTmpFile += "_nonroot"
-			}
-			adddelvalTmpFile += "_global"
-			setdelkeyTmpFile += "_global"
-			addreftblTmpFile += "_global"
-		} else if table.IsRoot == false {
-			tableCodeTmpFile += "_nonroot"
+	want := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(golden))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
 		}
+		want[fields[1]] = fields[0]
+	}
 
-		columnKeyType := make(map[string]string)
-
-		// for table field enum and range code
-		tableFieldEnum := make(map[string]string)
-		tableFieldRange := make(map[string]string)
-
-		filedDefaultMap := make(map[string]interface{})
-		fieldDefaultExist := false
-		var fieldDefault string
-		fieldDefault += "// " + tablenamenosep + "fields default value\n"
-		fieldDefault += "const (\n"
-
-		UUIDColumns := make(map[string]int)
-		UUIDColumnsExist := false
-
-		for columnname, column := range table.Columns {
-			isSetColumn := false
-			isMapColumn := false
-			min := 1
-			max := 1
-			var keyType string
-			var valType string
-			var refTable string
-
-			haveRangeMin := false
-			haveRangeMax := false
-			rangeMin := 0
-			rangeMax := 0
-			haveEnum := false
-			var enumSet []interface{}
-
-			// TABLENAME fields
-			Columnname := capitalizeEachWord(columnname)
-			tableFields += "\t" + tablenamenosep + "Field" + Columnname + " string = " + "\"" + columnname + "\"\n"
-			// fieldMapToColumn to do reflect
-			fieldMapToColumn += "\t\"" + Columnname + "\" : " + tablenamenosep + "Field" + Columnname + ",\n"
-
-			switch column.Type.(type) {
-			case string:
-				switch column.Type {
-				case "string":
-					tablestruct += "\t" + Columnname + " string\n"
-					keyType = "string"
-				case "integer":
-					tablestruct += "\t" + Columnname + " int\n"
-					keyType = "int"
-				case "boolean":
-					tablestruct += "\t" + Columnname + " bool\n"
-					keyType = "bool"
-				default:
-					fmt.Printf("type %v unknown\n", Columnname)
-					return
-				}
-			case interface{}:
-				for typekey, typevalue := range column.Type.(map[string]interface{}) {
-					switch typekey {
-					case "key":
-						isSetColumn = true
-
-						switch typevalue.(type) {
-						case string:
-							keyType = typevalue.(string)
-							switch keyType {
-							case "string":
-								keyType = "string"
-							case "integer":
-								keyType = "int"
-							case "boolean":
-								keyType = "bool"
-							case "uuid":
-								// uuid type should have refTable (to make sense)
-								keyType = "libovsdb.UUID"
-							default:
-								fmt.Printf("type %v unknown\n", Columnname)
-								return
-							}
-						case interface{}:
-							var defaultValue interface{}
-							defV := false
-							for k, v := range typevalue.(map[string]interface{}) {
-								switch k {
-								case "type":
-									keyType = v.(string)
-									switch keyType {
-									case "string":
-										keyType = "string"
-									case "integer":
-										keyType = "int"
-									case "boolean":
-										keyType = "bool"
-									case "uuid":
-										keyType = "libovsdb.UUID"
-									default:
-										fmt.Printf("type %v unknown\n", Columnname)
-										return
-									}
-								case "refTable":
-									refTable = v.(string)
-								case "minInteger":
-									haveRangeMin = true
-									rangeMin = int(v.(float64))
-								case "maxInteger":
-									haveRangeMax = true
-									rangeMax = int(v.(float64))
-								case "enum":
-									haveEnum = true
-									enumSet = v.([]interface{})[1].([]interface{})
-								case "default":
-									fieldDefaultExist = true
-									defV = true
-									defaultValue = v
-								}
-							}
-							if defV {
-								switch keyType {
-								case "string":
-									fieldDefault += tablenamenosep + "Default" + Columnname + " string = " + "\"" + defaultValue.(string) + "\"" + "\n"
-								case "int":
-									fieldDefault += tablenamenosep + "Default" + Columnname + " int = " + strconv.Itoa(int(defaultValue.(float64))) + "\n"
-								case "bool":
-									if defaultValue.(bool) == true {
-										fieldDefault += tablenamenosep + "Default" + Columnname + " bool = " + "true" + "\n"
-									} else {
-										fieldDefault += tablenamenosep + "Default" + Columnname + " bool = " + "false" + "\n"
-									}
-								}
-								filedDefaultMap[columnname] = defaultValue
-							}
-						}
-					case "value":
-						isMapColumn = true
-
-						switch typevalue.(type) {
-						case string:
-							valType = typevalue.(string)
-							switch valType {
-							case "string":
-								valType = "string"
-							case "integer":
-								valType = "int"
-							case "boolean":
-								valType = "bool"
-							case "uuid":
-								valType = "libovsdb.UUID"
-							default:
-								fmt.Printf("type %v unknown\n", Columnname)
-								return
-							}
-						case interface{}:
-							for k, v := range typevalue.(map[string]interface{}) {
-								switch k {
-								case "type":
-									valType = v.(string)
-									switch valType {
-									case "string":
-										valType = "string"
-									case "integer":
-										valType = "int"
-									case "boolean":
-										valType = "bool"
-									case "uuid":
-										valType = "libovsdb.UUID"
-									default:
-										fmt.Printf("type %v unknown\n", Columnname)
-										return
-									}
-								case "minInteger":
-									haveRangeMin = true
-									rangeMin = int(v.(float64))
-								case "maxInteger":
-									haveRangeMax = true
-									rangeMax = int(v.(float64))
-								case "enum":
-									haveEnum = true
-									enumSet = v.([]interface{})[1].([]interface{})
-								}
-							}
-						}
-					case "min":
-						min = int(typevalue.(float64))
-						if min > 1 {
-							fmt.Printf("min must be exactly 0 or exactly 1")
-							return
-						}
-					case "max":
-						switch typevalue.(type) {
-						case string:
-							if typevalue != "unlimited" {
-								fmt.Printf("unsupported max of %v\n", typevalue)
-								return
-							}
-							max = IntMax
-						case float64:
-							max = int(typevalue.(float64))
-							if max < 1 {
-								fmt.Printf("max must be at least 1")
-								return
-							}
-						default:
-							fmt.Printf("max %v unknown\n", Columnname)
-							return
-						}
-					case "default":
-						fieldDefaultExist = true
-						filedDefaultMap[columnname] = typevalue
-					default:
-						fmt.Printf("type %v define error\n", Columnname)
-						return
-					}
-				}
-				// detailed key value type
-				if isMapColumn == true {
-					//tablestruct += "\t" + Columnname + " map[interface{}]interface{}\n"
-					tablestruct += "\t" + Columnname + " map[interface{}]interface{}\n"
-				} else {
-					//tablestruct += "\t" + Columnname + " []interface{}\n"
-					if max == 1 && min == 1 {
-						tablestruct += "\t" + Columnname + " " + keyType + "\n"
-					} else {
-						tablestruct += "\t" + Columnname + " []" + keyType + "\n"
-					}
-				}
-			default:
-				fmt.Printf("unknown %v\n", Columnname)
-				return
-			}
-
-			columnKeyType[columnname] = keyType
-
-			/*fieldset, err := ioutil.ReadFile("dbname/template/fieldset.txt")
-			if err != nil {
-				return
-			}
-			fieldsetStr := string(fieldset)
-			fieldsetStr = strings.Replace(fieldsetStr, "TABLENAME", tablenamenosep, -1)
-			fieldsetStr = strings.Replace(fieldsetStr, "FIELD", Columnname, -1)
-			fieldsets = append(fieldsets, fieldsetStr)*/
-
-			// set column
-			if isSetColumn == true && isMapColumn == false {
-				fieldadddelvalue, err := ioutil.ReadFile(adddelvalTmpFile)
-				if err != nil {
-					fmt.Printf("Read %s failed\n", adddelvalTmpFile)
-					return
-				}
-				fieldadddelvalueStr := string(fieldadddelvalue)
-				fieldadddelvalueStr = strings.Replace(fieldadddelvalueStr, "TABLENAME", tablenamenosep, -1)
-				fieldadddelvalueStr = strings.Replace(fieldadddelvalueStr, "FIELD", Columnname, -1)
-				fieldadddelvalueStr = strings.Replace(fieldadddelvalueStr, "TYPE", keyType, -1)
-				fieldadddelvalues = append(fieldadddelvalues, fieldadddelvalueStr)
-
-				if len(refTable) > 0 {
-					refTbl := c.Tables[refTable]
-					if refTbl.IsRoot == false {
-						refTablenosep := capitalizeEachWord(refTable)
-						fieldaddreftbl, err := ioutil.ReadFile(addreftblTmpFile)
-						if err != nil {
-							fmt.Printf("Read %s failed\n", adddelvalTmpFile)
-							return
-						}
-						fieldaddreftblStr := string(fieldaddreftbl)
-						fieldaddreftblStr = strings.Replace(fieldaddreftblStr, "TABLENAMEREF", refTablenosep, -1)
-						fieldaddreftblStr = strings.Replace(fieldaddreftblStr, "TABLENAME", tablenamenosep, -1)
-						fieldaddreftblStr = strings.Replace(fieldaddreftblStr, "FIELD", Columnname, -1)
-						fieldadddelvalues = append(fieldadddelvalues, fieldaddreftblStr)
-					}
-				}
-			}
-
-			// map column
-			if isSetColumn == true && isMapColumn == true {
-				fieldsetdelkey, err := ioutil.ReadFile(setdelkeyTmpFile)
-				if err != nil {
-					fmt.Printf("Read %s failed\n", setdelkeyTmpFile)
-					return
-				}
-				fieldsetdelkeyStr := string(fieldsetdelkey)
-				fieldsetdelkeyStr = strings.Replace(fieldsetdelkeyStr, "TABLENAME", tablenamenosep, -1)
-				fieldsetdelkeyStr = strings.Replace(fieldsetdelkeyStr, "FIELD", Columnname, -1)
-				fieldsetdelkeyStr = strings.Replace(fieldsetdelkeyStr, "KEY", keyType, -1)
-				fieldsetdelkeyStr = strings.Replace(fieldsetdelkeyStr, "VALUE", valType, -1)
-				fieldsetdelkeys = append(fieldsetdelkeys, fieldsetdelkeyStr)
-			}
-
-			if haveRangeMin || haveRangeMax {
-				if haveRangeMin {
-					tableFieldRange[Columnname] += tablenamenosep + Columnname + "Min int = " + strconv.Itoa(rangeMin) + "\n"
-				}
-				if haveRangeMax {
-					tableFieldRange[Columnname] += tablenamenosep + Columnname + "Max int = " + strconv.Itoa(rangeMax) + "\n"
-				}
-			}
-			if haveEnum {
-				for _, enum := range enumSet {
-					val := capitalizeEachWordDelDash(enum.(string))
-					tableFieldEnum[Columnname] += tablenamenosep + Columnname + val + " string = " + "\"" + enum.(string) + "\"" + "\n"
-				}
-			}
-
-			// for uuid columns
-			if keyType == "libovsdb.UUID" {
-				UUIDColumns[columnname] = 1
-				UUIDColumnsExist = true
-			}
+	var diffs []string
+	for _, name := range sortedFileNames(files) {
+		sum := fmt.Sprintf("%x", sha256.Sum256(files[name]))
+		if wantSum, ok := want[name]; !ok {
+			diffs = append(diffs, "new file "+name)
+		} else if wantSum != sum {
+			diffs = append(diffs, "changed "+name)
 		}
+		delete(want, name)
+	}
+	for name := range want {
+		diffs = append(diffs, "removed "+name)
+	}
+	return diffs, nil
+}
 
-		tablestruct += "}\n"
-		tableFields += ")\n"
-		fieldMapToColumn += "}\n"
-
-		var tableindexes []string
-		// gen table indexes struct
-		for i, index := range table.Indexes {
-			var indexname string
-			var tableindex string
-			if i == 0 {
-				indexname = tablenamenosep + "Index"
-			} else {
-				indexname = tablenamenosep + "Index" + strconv.Itoa(i)
-			}
+// diffGoldenCode files differ from golden code of dir, first different
+// line of changed file is reported
+func diffGoldenCode(dir string, files map[string][]byte) ([]string, error) {
+	golden, err := filepath.Glob(filepath.Join(dir, "*.golden"))
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[string]string)
+	for _, file := range golden {
+		want[strings.TrimSuffix(filepath.Base(file), ".golden")] = file
+	}
 
-			tableindex += "// " + indexname + " definition\n"
-			tableindex += "type " + indexname + " struct {\n"
-			for _, name := range index {
-				tableindex += "\t" + capitalizeEachWord(name) + " " + columnKeyType[name] + "\n"
-			}
-			tableindex += "}\n"
-			tableindexes = append(tableindexes, tableindex)
-		}
-		// check global table(maxrow=1) don't have indexes
-		if table.MaxRows == 1 && len(tableindexes) > 0 {
-			fmt.Printf("Error: global table%v (maxrow=1) don't have indexes\n", tablename)
-			return
+	var diffs []string
+	for _, name := range sortedFileNames(files) {
+		file, ok := want[name]
+		if !ok {
+			diffs = append(diffs, "new file "+name)
+			continue
 		}
-		// check not global table need indexes
-		if table.MaxRows > 1 || table.MaxRows == 0 {
-			// add UUID index for all non-global table
-			//if 0 == len(tableindexes) {
-			indexname := tablenamenosep + "UUIDIndex"
-			tableindex := "// " + indexname + " definition\n"
-			tableindex += "type " + indexname + " struct {\n"
-			tableindex += "\t" + "UUID" + " " + "string" + "\n"
-			tableindex += "}\n"
-			tableindexes = append(tableindexes, tableindex)
-		}
-
-		// table code gen
-		tablecode, err := ioutil.ReadFile(tableCodeTmpFile)
+		delete(want, name)
+		code, err := ioutil.ReadFile(file)
 		if err != nil {
-			fmt.Printf("Read %s failed\n", tableCodeTmpFile)
-			return
-		}
-		tablecodeStr := string(tablecode)
-		tablecodeStr = strings.Replace(tablecodeStr, "TABLENAME", tablenamenosep, -1)
-
-		var tableFileStr string
-		tableFileStr += "package " + DbnameLowCase + "\n"
-		tableFileStr += "import (\n"
-		tableFileStr += "\t\"fmt\"\n"
-		tableFileStr += "\t\"reflect\"\n\n"
-		tableFileStr += "\t\"github.com/ebay/libovsdb\"\n)\n"
-
-		// write tablestruct
-		tableFileStr += "\n" + tablestruct + "\n"
-		// write tableindex
-		for _, indexcode := range tableindexes {
-			tableFileStr += "\n" + indexcode + "\n"
-		}
-		// write fieldname
-		tableFileStr += "\n" + tableFields + "\n"
-		// write fieldMapToColumn
-		tableFileStr += "\n" + fieldMapToColumn + "\n"
-
-		if fieldDefaultExist {
-			tableFiledDefaultMap[tablename] = filedDefaultMap
-			fieldDefault += ")\n"
-			tableFileStr += "\n" + fieldDefault + "\n"
-		}
-
-		// for uuid columns
-		if UUIDColumnsExist {
-			tableUUIDColumns[tablename] = UUIDColumns
-		}
-
-		// write field range and enum
-		for column, crange := range tableFieldRange {
-			tableFileStr += "// " + column + " range\n"
-			tableFileStr += "const (\n" + crange + ")\n"
+			return nil, err
 		}
-		for column, cnum := range tableFieldEnum {
-			tableFileStr += "// " + column + " enum\n"
-			tableFileStr += "const (\n" + cnum + ")\n"
+		if diff := firstDiffLine(code, files[name]); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("changed %s:%s", name, diff))
 		}
+	}
+	for name := range want {
+		diffs = append(diffs, "removed "+name)
+	}
+	return diffs, nil
+}
 
-		// write tablecode
-		tableFileStr += "\n" + tablecodeStr + "\n"
-		/*// write field set
-		for _, fieldsetcode := range fieldsets {
-			tableFileStr += "\n" + fieldsetcode + "\n"
-		}*/
-		// write add del value code
-		for _, fieldadddelvaluecode := range fieldadddelvalues {
-			tableFileStr += "\n" + fieldadddelvaluecode + "\n"
+// firstDiffLine first different line of golden and generated code as
+// "line\n-golden\n+generated", empty if same
+func firstDiffLine(golden []byte, code []byte) string {
+	if bytes.Equal(golden, code) {
+		return ""
+	}
+	goldenLines := strings.Split(string(golden), "\n")
+	codeLines := strings.Split(string(code), "\n")
+	for i := 0; ; i++ {
+		var want, got string
+		if i < len(goldenLines) {
+			want = goldenLines[i]
 		}
-		// wirte set del key code
-		for _, fieldsetdelkeycode := range fieldsetdelkeys {
-			tableFileStr += "\n" + fieldsetdelkeycode + "\n"
+		if i < len(codeLines) {
+			got = codeLines[i]
 		}
-
-		// convert row to struct
-		rowToStruct, err := ioutil.ReadFile(rowtostructTmpFile)
-		if err != nil {
-			fmt.Printf("Read %s failed\n", rowtostructTmpFile)
-			return
+		if want != got || i >= len(goldenLines) || i >= len(codeLines) {
+			return fmt.Sprintf("%d\n\t\t-%s\n\t\t+%s", i+1, want, got)
 		}
-		rowToStructStr := string(rowToStruct)
-		rowToStructStr = strings.Replace(rowToStructStr, "TABLENAME", tablenamenosep, -1)
-		tableFileStr += "\n" + rowToStructStr + "\n"
-
-		tableFile, err := os.Create(dbDir + "/table_" + strings.ToLower(tablename) + ".go")
-		if err != nil {
-			fmt.Printf("%v", err)
-		}
-		defer tableFile.Close()
-		tableFile.WriteString(tableFileStr)
-
-		tableFileStrs = append(tableFileStrs, tableFileStr)
 	}
+}
 
-	defineFileStr += ")\n"
-	// write define code
-	defineFile, _ := os.Create(dbDir + "/define.go")
-	defer defineFile.Close()
-	defineFile.WriteString(defineFileStr)
-
-	// for FiledsDefaultMap
-	FiledDefaultMap := capitalize(DbnameLowCase) + "FiledsDefaultMap"
-	tableFiledDefaultMapStr := "// " + FiledDefaultMap + " table fileds default value map\n"
-	tableFiledDefaultMapStr += "var " + FiledDefaultMap + " = " + "make(map[string]map[string]interface{})\n"
-	defineFile.WriteString(tableFiledDefaultMapStr)
-
-	var FiledDefaultMapStr string
-	for tb, tbDef := range tableFiledDefaultMap {
-		tbDefaultMap := capitalizeEachWord(tb) + "FiledsDefaultMap"
-		tbDefaultMapStr := "var " + tbDefaultMap + " = " + "make(map[string]interface{})\n"
-		for field, fieldDef := range tbDef {
-			var tmpStr string
-			if fieldStr, ok := fieldDef.(string); ok {
-				tmpStr = fmt.Sprintf(tbDefaultMap + "[\"" + field + "\"] = " + "\"" + fieldStr + "\"\n")
-			} else if _, ok := fieldDef.(float64); ok {
-				tmpStr = fmt.Sprintf(tbDefaultMap+"[\""+field+"\"] = %v\n", fieldDef)
-			} else if _, ok := fieldDef.(bool); ok {
-				tmpStr = fmt.Sprintf(tbDefaultMap+"[\""+field+"\"] = %v\n", fieldDef)
-			} else {
-				kMapStr := "var " + capitalizeEachWord(field) + "Map = " + "make(map[interface{}]interface{})\n"
-				var kMapVal string
-				for k, v := range fieldDef.(map[string]interface{}) {
-					kMapVal += capitalizeEachWord(field) + "Map[\"" + k + "\"] = "
-					if va, ok := v.(string); ok {
-						kMapVal += "\"" + va + "\"\n"
-					} else {
-						kMapVal += fmt.Sprintf("%v\n", v)
-					}
-				}
-
-				tmpStr += kMapStr
-				tmpStr += kMapVal
-				tmpStr += fmt.Sprintf(tbDefaultMap + "[\"" + field + "\"] = " + capitalizeEachWord(field) + "Map" + "\n")
-			}
-			tbDefaultMapStr += tmpStr
+// updateGolden write golden code of pkg if kept, or its golden manifest
+func updateGolden(pkg string, files map[string][]byte) error {
+	dir := goldenCodeDir(pkg)
+	if _, err := os.Stat(dir); err != nil {
+		return ioutil.WriteFile(goldenFile(pkg), goldenManifest(files), 0644)
+	}
+	old, err := filepath.Glob(filepath.Join(dir, "*.golden"))
+	if err != nil {
+		return err
+	}
+	for _, file := range old {
+		if err := os.Remove(file); err != nil {
+			return err
 		}
-		FiledDefaultMapStr += tbDefaultMapStr
-		FiledDefaultMapStr += FiledDefaultMap + "[\"" + tb + "\"] = " + tbDefaultMap + "\n"
 	}
-
-	defaultInitFunc := "// " + capitalizeEachWord(DbnameLowCase) + "FiledsDefaultMapInit init fields default value mapping\n"
-	defaultInitFunc += "func " + capitalizeEachWord(DbnameLowCase) + "FiledsDefaultMapInit() {"
-	defaultInitFunc += FiledDefaultMapStr + "}\n"
-	defineFile.WriteString(defaultInitFunc)
-
-	// for uuid columns map
-	UUIDColumnsStr := "// " + "TableUUIDColumns mapping\n"
-	UUIDColumnsStr += "var " + "TableUUIDColumns " + " = " + "map[string]map[string]int {\n"
-	for table, UUIDColumns := range tableUUIDColumns {
-		UUIDColumnsStr += "\"" + table + "\"" + " : {\n"
-		for field := range UUIDColumns {
-			UUIDColumnsStr += "\"" + field + "\"" + " : 1,\n"
+	for _, name := range sortedFileNames(files) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".golden"), files[name], 0644); err != nil {
+			return err
 		}
-		UUIDColumnsStr += " },\n"
 	}
-	UUIDColumnsStr += " }\n"
-	defineFile.WriteString(UUIDColumnsStr)
-
-	// gofmt
-	gofmtCmd := "go fmt " + dbDir + "/*.go"
-	execShell(gofmtCmd)
-
-	fmt.Println("Done!")
+	return nil
 }
 
 func main() {
@@ -782,27 +278,33 @@ func main() {
 		os.Exit(0)
 	}
 
-	var c DatabaseSchema
-	var content []byte
-	var err error
-
-	out, err := schemaCheck()
+	schema, err := loadSchema(schemaFile)
 	if err != nil {
 		fmt.Printf("Something error in %s\n", schemaFile)
-		fmt.Printf("Detail: %s", out[12:])
-		return
+		fmt.Printf("Detail: %v\n", err)
+		os.Exit(1)
 	}
+	fmt.Printf("DB: %v\n", schema.Name)
 
-	content, err = fileGetContents(schemaFile)
+	files, err := odbgen(schema)
 	if err != nil {
-		fmt.Println("open file error: " + err.Error())
-		return
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	pkg := strings.Replace(strings.ToLower(schema.Name), "_", "", -1)
+	switch {
+	case check:
+		err = checkGolden(pkg, files)
+	case update:
+		err = updateGolden(pkg, files)
+	default:
+		err = writeFiles(filepath.Join(libDir, pkg), files)
 	}
-	err = json.Unmarshal([]byte(content), &c)
 	if err != nil {
-		fmt.Println("ERROR: ", err.Error())
-		return
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
 	}
-	odbgen(c)
-	//ovsdb.InitOvsdb("aaa")
+
+	fmt.Println("Done!")
 }
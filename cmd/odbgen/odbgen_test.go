@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGolden generate code of every schema and compare it with golden
+// code or manifest, run "make odbgen-update" if the change is expected
+func TestGolden(t *testing.T) {
+	schemas, err := filepath.Glob(filepath.Join("schema", "*.ovsschema"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) == 0 {
+		t.Fatal("no schema found")
+	}
+	schemas = append(schemas, filepath.Join("testdata", "batch_test.ovsschema"))
+
+	for _, file := range schemas {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			schema, err := loadSchema(file)
+			if err != nil {
+				t.Fatalf("load %s: %v", file, err)
+			}
+			files, err := odbgen(schema)
+			if err != nil {
+				t.Fatalf("generate %s: %v", file, err)
+			}
+			pkg := strings.Replace(strings.ToLower(schema.Name), "_", "", -1)
+			if err = checkGolden(pkg, files); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestDeterministic generated code must not depend on map order
+func TestDeterministic(t *testing.T) {
+	schema, err := loadSchema(filepath.Join("schema", "controller_vtep.ovsschema"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := odbgen(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		files, err := odbgen(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(goldenManifest(first), goldenManifest(files)) {
+			t.Fatalf("run %d generated different code", i)
+		}
+	}
+}
+
+// TestValidateMaxRows maxRows is optional, set value must be at least 1
+func TestValidateMaxRows(t *testing.T) {
+	tests := []struct {
+		maxRows string
+		valid   bool
+	}{
+		{"", true},
+		{`, "maxRows": 1`, true},
+		{`, "maxRows": 2`, true},
+		{`, "maxRows": 0`, false},
+		{`, "maxRows": -1`, false},
+	}
+	for _, test := range tests {
+		var schema DatabaseSchema
+		content := `{"name": "T", "version": "1.0.0", "tables": {"A": {"columns": {"a": {"type": "string"}}` +
+			test.maxRows + `}}}`
+		if err := json.Unmarshal([]byte(content), &schema); err != nil {
+			t.Fatalf("%s: %v", content, err)
+		}
+		if err := schema.validate(); (err == nil) != test.valid {
+			t.Errorf("%s: validate error %v, want valid %v", content, err, test.valid)
+		}
+	}
+}
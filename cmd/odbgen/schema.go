@@ -0,0 +1,385 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+)
+
+// IntMax ...
+const IntMax = int(^uint(0) >> 1)
+
+// atomic types according to RFC7047
+const (
+	atomicInteger string = "integer"
+	atomicReal    string = "real"
+	atomicBoolean string = "boolean"
+	atomicString  string = "string"
+	atomicUUID    string = "uuid"
+)
+
+var idRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+var versionRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// DatabaseSchema is a database schema according to RFC7047
+type DatabaseSchema struct {
+	Name    string                 `json:"name"`
+	Version string                 `json:"version"`
+	Cksum   string                 `json:"cksum,omitempty"`
+	Tables  map[string]TableSchema `json:"tables"`
+}
+
+// TableSchema is a table schema according to RFC7047
+type TableSchema struct {
+	Columns map[string]ColumnSchema `json:"columns"`
+	Indexes [][]string              `json:"indexes,omitempty"`
+	IsRoot  bool                    `json:"isRoot,omitempty"`
+	MaxRows *int                    `json:"maxRows,omitempty"`
+}
+
+// ColumnSchema is a column schema according to RFC7047
+type ColumnSchema struct {
+	Type      ColumnType `json:"type"`
+	Ephemeral bool       `json:"ephemeral,omitempty"`
+	Mutable   *bool      `json:"mutable,omitempty"`
+}
+
+// ColumnType is a column <type> according to RFC7047, Default is a
+// local extension holding the column default value
+type ColumnType struct {
+	Key     BaseType
+	Value   *BaseType
+	Min     int
+	Max     int
+	Default interface{}
+	// Object type declared as an object rather than <atomic-type>
+	Object bool
+}
+
+// BaseType is a <base-type> according to RFC7047, Default is a
+// local extension holding the column default value
+type BaseType struct {
+	Type       string
+	Enum       []interface{}
+	MinInteger *int
+	MaxInteger *int
+	MinReal    *float64
+	MaxReal    *float64
+	MinLength  *int
+	MaxLength  *int
+	RefTable   string
+	RefType    string
+	Default    interface{}
+}
+
+// IsMap column type is a map
+func (t ColumnType) IsMap() bool {
+	return t.Value != nil
+}
+
+// IsScalar column type is exactly one atom
+func (t ColumnType) IsScalar() bool {
+	return t.Value == nil && t.Min == 1 && t.Max == 1
+}
+
+// IsOptional column type is zero or one atom
+func (t ColumnType) IsOptional() bool {
+	return t.Value == nil && t.Min == 0 && t.Max == 1
+}
+
+// UnmarshalJSON parse <type>, either an <atomic-type> or an object
+func (t *ColumnType) UnmarshalJSON(data []byte) error {
+	var atomic string
+	if err := json.Unmarshal(data, &atomic); err == nil {
+		if !isAtomicType(atomic) {
+			return fmt.Errorf("unknown atomic-type %q", atomic)
+		}
+		*t = ColumnType{Key: BaseType{Type: atomic}, Min: 1, Max: 1}
+		return nil
+	}
+
+	var obj struct {
+		Key     *BaseType       `json:"key"`
+		Value   *BaseType       `json:"value"`
+		Min     *int            `json:"min"`
+		Max     json.RawMessage `json:"max"`
+		Default interface{}     `json:"default"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid type %s: %v", data, err)
+	}
+	if obj.Key == nil {
+		return fmt.Errorf("type %s missing required member \"key\"", data)
+	}
+
+	*t = ColumnType{Key: *obj.Key, Value: obj.Value, Min: 1, Max: 1, Default: obj.Default, Object: true}
+	if obj.Min != nil {
+		t.Min = *obj.Min
+	}
+	if len(obj.Max) > 0 {
+		var unlimited string
+		if err := json.Unmarshal(obj.Max, &unlimited); err == nil {
+			if unlimited != "unlimited" {
+				return fmt.Errorf("unsupported max of %q", unlimited)
+			}
+			t.Max = IntMax
+		} else if err := json.Unmarshal(obj.Max, &t.Max); err != nil {
+			return fmt.Errorf("invalid max %s", obj.Max)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON parse <base-type>, either an <atomic-type> or an object
+func (b *BaseType) UnmarshalJSON(data []byte) error {
+	var atomic string
+	if err := json.Unmarshal(data, &atomic); err == nil {
+		if !isAtomicType(atomic) {
+			return fmt.Errorf("unknown atomic-type %q", atomic)
+		}
+		*b = BaseType{Type: atomic}
+		return nil
+	}
+
+	var obj struct {
+		Type       string      `json:"type"`
+		Enum       interface{} `json:"enum"`
+		MinInteger *int        `json:"minInteger"`
+		MaxInteger *int        `json:"maxInteger"`
+		MinReal    *float64    `json:"minReal"`
+		MaxReal    *float64    `json:"maxReal"`
+		MinLength  *int        `json:"minLength"`
+		MaxLength  *int        `json:"maxLength"`
+		RefTable   string      `json:"refTable"`
+		RefType    string      `json:"refType"`
+		Default    interface{} `json:"default"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid base-type %s: %v", data, err)
+	}
+	if !isAtomicType(obj.Type) {
+		return fmt.Errorf("unknown atomic-type %q", obj.Type)
+	}
+
+	*b = BaseType{
+		Type:       obj.Type,
+		MinInteger: obj.MinInteger,
+		MaxInteger: obj.MaxInteger,
+		MinReal:    obj.MinReal,
+		MaxReal:    obj.MaxReal,
+		MinLength:  obj.MinLength,
+		MaxLength:  obj.MaxLength,
+		RefTable:   obj.RefTable,
+		RefType:    obj.RefType,
+		Default:    obj.Default,
+	}
+
+	// enum is a <value>, either a single atom or ["set", [atoms]]
+	if obj.Enum != nil {
+		if set, ok := obj.Enum.([]interface{}); ok {
+			if len(set) != 2 || set[0] != "set" {
+				return fmt.Errorf("invalid enum %v", obj.Enum)
+			}
+			atoms, ok := set[1].([]interface{})
+			if !ok {
+				return fmt.Errorf("invalid enum %v", obj.Enum)
+			}
+			b.Enum = atoms
+		} else {
+			b.Enum = []interface{}{obj.Enum}
+		}
+	}
+	return nil
+}
+
+func isAtomicType(t string) bool {
+	switch t {
+	case atomicInteger, atomicReal, atomicBoolean, atomicString, atomicUUID:
+		return true
+	}
+	return false
+}
+
+// loadSchema read and validate schema file
+func loadSchema(path string) (DatabaseSchema, error) {
+	var schema DatabaseSchema
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return schema, err
+	}
+	if err = json.Unmarshal(content, &schema); err != nil {
+		return schema, fmt.Errorf("%s: %v", path, err)
+	}
+	if err = schema.validate(); err != nil {
+		return schema, fmt.Errorf("%s: %v", path, err)
+	}
+	return schema, nil
+}
+
+// validate check schema constraints of RFC7047 section 3.2
+func (s DatabaseSchema) validate() error {
+	if !idRegexp.MatchString(s.Name) {
+		return fmt.Errorf("invalid database name %q", s.Name)
+	}
+	if !versionRegexp.MatchString(s.Version) {
+		return fmt.Errorf("invalid version %q, must be <x>.<y>.<z>", s.Version)
+	}
+	if len(s.Tables) == 0 {
+		return fmt.Errorf("database %s has no tables", s.Name)
+	}
+
+	for _, tableName := range sortedTableNames(s.Tables) {
+		if err := s.validateTable(tableName, s.Tables[tableName]); err != nil {
+			return fmt.Errorf("table %s: %v", tableName, err)
+		}
+	}
+	return nil
+}
+
+func (s DatabaseSchema) validateTable(name string, t TableSchema) error {
+	if !idRegexp.MatchString(name) || name[0] == '_' {
+		return fmt.Errorf("invalid table name")
+	}
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("table has no columns")
+	}
+	if t.MaxRows != nil && *t.MaxRows < 1 {
+		return fmt.Errorf("maxRows must be at least 1")
+	}
+
+	for _, columnName := range sortedColumnNames(t.Columns) {
+		if err := s.validateColumn(columnName, t.Columns[columnName]); err != nil {
+			return fmt.Errorf("column %s: %v", columnName, err)
+		}
+	}
+
+	for _, index := range t.Indexes {
+		if len(index) == 0 {
+			return fmt.Errorf("index must have at least one column")
+		}
+		for _, columnName := range index {
+			column, ok := t.Columns[columnName]
+			if !ok {
+				return fmt.Errorf("index column %s not exist", columnName)
+			}
+			if column.Ephemeral {
+				return fmt.Errorf("ephemeral column %s can't be indexed", columnName)
+			}
+		}
+	}
+	return nil
+}
+
+func (s DatabaseSchema) validateColumn(name string, c ColumnSchema) error {
+	if !idRegexp.MatchString(name) || name[0] == '_' {
+		return fmt.Errorf("invalid column name")
+	}
+
+	t := c.Type
+	if t.Min != 0 && t.Min != 1 {
+		return fmt.Errorf("min must be exactly 0 or exactly 1")
+	}
+	if t.Max < 1 {
+		return fmt.Errorf("max must be at least 1")
+	}
+	if t.Max < t.Min {
+		return fmt.Errorf("max must be greater than or equal to min")
+	}
+
+	if err := s.validateBaseType(t.Key); err != nil {
+		return fmt.Errorf("key: %v", err)
+	}
+	if t.Value != nil {
+		if err := s.validateBaseType(*t.Value); err != nil {
+			return fmt.Errorf("value: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s DatabaseSchema) validateBaseType(b BaseType) error {
+	if (b.MinInteger != nil || b.MaxInteger != nil) && b.Type != atomicInteger {
+		return fmt.Errorf("minInteger/maxInteger only allowed for integer")
+	}
+	if b.MinInteger != nil && b.MaxInteger != nil && *b.MinInteger > *b.MaxInteger {
+		return fmt.Errorf("minInteger %d greater than maxInteger %d", *b.MinInteger, *b.MaxInteger)
+	}
+	if (b.MinReal != nil || b.MaxReal != nil) && b.Type != atomicReal {
+		return fmt.Errorf("minReal/maxReal only allowed for real")
+	}
+	if b.MinReal != nil && b.MaxReal != nil && *b.MinReal > *b.MaxReal {
+		return fmt.Errorf("minReal %v greater than maxReal %v", *b.MinReal, *b.MaxReal)
+	}
+	if (b.MinLength != nil || b.MaxLength != nil) && b.Type != atomicString {
+		return fmt.Errorf("minLength/maxLength only allowed for string")
+	}
+	if b.MinLength != nil && b.MaxLength != nil && *b.MinLength > *b.MaxLength {
+		return fmt.Errorf("minLength %d greater than maxLength %d", *b.MinLength, *b.MaxLength)
+	}
+
+	if b.RefTable != "" {
+		if b.Type != atomicUUID {
+			return fmt.Errorf("refTable only allowed for uuid")
+		}
+		if _, ok := s.Tables[b.RefTable]; !ok {
+			return fmt.Errorf("refTable %s not exist", b.RefTable)
+		}
+	}
+	if b.RefType != "" {
+		if b.RefTable == "" {
+			return fmt.Errorf("refType requires refTable")
+		}
+		if b.RefType != "strong" && b.RefType != "weak" {
+			return fmt.Errorf("refType must be \"strong\" or \"weak\"")
+		}
+	}
+
+	for _, atom := range b.Enum {
+		if !atomMatchType(atom, b.Type) {
+			return fmt.Errorf("enum %v is not of type %s", atom, b.Type)
+		}
+	}
+	if b.Default != nil && !atomMatchType(b.Default, b.Type) {
+		return fmt.Errorf("default %v is not of type %s", b.Default, b.Type)
+	}
+	return nil
+}
+
+func atomMatchType(atom interface{}, atomicType string) bool {
+	switch v := atom.(type) {
+	case string:
+		return atomicType == atomicString
+	case bool:
+		return atomicType == atomicBoolean
+	case float64:
+		if atomicType == atomicInteger {
+			return v == math.Trunc(v)
+		}
+		return atomicType == atomicReal
+	case []interface{}:
+		return atomicType == atomicUUID && len(v) == 2 && v[0] == "uuid"
+	}
+	return false
+}
+
+func sortedTableNames(tables map[string]TableSchema) []string {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedColumnNames(columns map[string]ColumnSchema) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package hwvtep
+
+import (
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type bridgeAPI struct {
+	moduleID int
+	*hwvtepDriver
+}
+
+func newBridgeAPI(d *hwvtepDriver) *bridgeAPI {
+	return &bridgeAPI{
+		moduleID:     tai.ObjectIDBridge,
+		hwvtepDriver: d,
+	}
+}
+
+// tunnelKeySet set vni of bridge as Logical_Switch tunnel key
+func (d *hwvtepDriver) tunnelKeySet(bdName string, vni int) error {
+	tableLS, err := d.logicalSwitchGet(bdName)
+	if err != nil {
+		return err
+	}
+	if len(tableLS.TunnelKey) == 1 && tableLS.TunnelKey[0] == vni {
+		return nil
+	}
+	return d.db().LogicalSwitchSetField(hwdb.LogicalSwitchIndex{Name: bdName},
+		hwdb.LogicalSwitchFieldTunnelKey, vni)
+}
+
+func (d *bridgeAPI) CreateObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	if _, err := d.logicalSwitchGet(objBridge.Name); err == nil {
+		log.Info("[Driver] Logical_Switch %s already exist\n", objBridge.Name)
+		return d.tunnelKeySet(objBridge.Name, objBridge.Vni)
+	}
+	_, err := d.db().LogicalSwitchAdd(hwdb.TableLogicalSwitch{
+		Name:      objBridge.Name,
+		TunnelKey: []int{objBridge.Vni},
+	})
+	return err
+}
+
+// RemoveObject macs of Logical_Switch are removed with it, local macs
+// learned by switch refer it too. Vlan bindings are removed with l2 ports
+// before.
+func (d *bridgeAPI) RemoveObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	tableLS, err := d.logicalSwitchGet(objBridge.Name)
+	if err != nil {
+		return nil
+	}
+	d.db().UcastMacsRemoteIterator(func(table hwdb.TableUcastMacsRemote) {
+		if table.LogicalSwitch.GoUUID == tableLS.UUID {
+			if err := d.db().UcastMacsRemoteDelByUUID(table.UUID); err != nil {
+				log.Warning("[Driver] Ucast_Macs_Remote %s del failed %v\n", table.Mac, err)
+			}
+		}
+	})
+	d.db().UcastMacsLocalIterator(func(table hwdb.TableUcastMacsLocal) {
+		if table.LogicalSwitch.GoUUID == tableLS.UUID {
+			if err := d.db().UcastMacsLocalDelByUUID(table.UUID); err != nil {
+				log.Warning("[Driver] Ucast_Macs_Local %s del failed %v\n", table.Mac, err)
+			}
+		}
+	})
+	d.db().McastMacsRemoteIterator(func(table hwdb.TableMcastMacsRemote) {
+		if table.LogicalSwitch.GoUUID == tableLS.UUID {
+			if err := d.db().McastMacsRemoteDelByUUID(table.UUID); err != nil {
+				log.Warning("[Driver] Mcast_Macs_Remote %s del failed %v\n", table.Mac, err)
+			}
+		}
+	})
+	return d.db().LogicalSwitchDelByIndex(hwdb.LogicalSwitchIndex{Name: objBridge.Name})
+}
+
+func (d *bridgeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	if attrs.Has(tai.BridgeAttrL2vni) {
+		return d.tunnelKeySet(objBridge.Name, attrs.GetInt(tai.BridgeAttrL2vni))
+	}
+	return nil
+}
+
+func (d *bridgeAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (d *bridgeAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return d.AddObjectAttr(obj, attrs)
+}
+
+func (d *bridgeAPI) GetObjectAttr(obj interface{}, attrIDs []tai.ObjAttrID) (tai.Attrs, error) {
+	attrs := make(tai.Attrs)
+	objBridge := obj.(tai.BridgeObj)
+
+	tableLS, err := d.logicalSwitchGet(objBridge.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrIDs {
+		if attr == tai.BridgeAttrL2vni && len(tableLS.TunnelKey) == 1 {
+			attrs[attr] = tableLS.TunnelKey[0]
+		}
+	}
+	return attrs, nil
+}
+
+// ListObject Logical_Switch of bridges, vni is the tunnel key if set
+func (d *bridgeAPI) ListObject() ([]interface{}, error) {
+	var objs []interface{}
+	for _, tableLS := range d.bridgeSwitches() {
+		objBridge := tai.BridgeObj{Name: tableLS.Name}
+		if len(tableLS.TunnelKey) == 1 {
+			objBridge.Vni = tableLS.TunnelKey[0]
+		}
+		objs = append(objs, objBridge)
+	}
+	return objs, nil
+}
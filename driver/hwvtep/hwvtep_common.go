@@ -0,0 +1,112 @@
+package hwvtep
+
+import (
+	"fmt"
+	"strings"
+
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/ebay/libovsdb"
+)
+
+// ovsdb operations and uuid column of rows
+const (
+	opInsert  = "insert"
+	opUpdate  = "update"
+	fieldUUID = "_uuid"
+)
+
+// bridgeNamePrefix Logical_Switch of bridges are named like Bd100, other
+// switches are not listed
+const bridgeNamePrefix = "Bd"
+
+// bridgeSwitches Logical_Switch rows rendered for bridges
+func (d *hwvtepDriver) bridgeSwitches() []hwdb.TableLogicalSwitch {
+	var switches []hwdb.TableLogicalSwitch
+	d.db().LogicalSwitchIterator(func(table hwdb.TableLogicalSwitch) {
+		if strings.HasPrefix(table.Name, bridgeNamePrefix) {
+			switches = append(switches, table)
+		}
+	})
+	return switches
+}
+
+// logicalSwitchGet Logical_Switch rendered for bridge of name
+func (d *hwvtepDriver) logicalSwitchGet(bdName string) (hwdb.TableLogicalSwitch, error) {
+	tableLS, err := d.db().LogicalSwitchGetByIndex(hwdb.LogicalSwitchIndex{Name: bdName})
+	if err != nil {
+		return tableLS, fmt.Errorf("[Driver] Logical_Switch %s not exist", bdName)
+	}
+	return tableLS, nil
+}
+
+// physicalSwitches Physical_Switch rows programmed by driver instance,
+// all rows if switch of instance is not in db
+func (d *hwvtepDriver) physicalSwitches() []hwdb.TablePhysicalSwitch {
+	if d.physicalSwitch != "" {
+		tablePS, err := d.db().PhysicalSwitchGetByIndex(hwdb.PhysicalSwitchIndex{Name: d.physicalSwitch})
+		if err == nil {
+			return []hwdb.TablePhysicalSwitch{tablePS}
+		}
+	}
+	var switches []hwdb.TablePhysicalSwitch
+	d.db().PhysicalSwitchIterator(func(table hwdb.TablePhysicalSwitch) {
+		switches = append(switches, table)
+	})
+	return switches
+}
+
+// physicalPortGet Physical_Port of name in switches of driver instance
+func (d *hwvtepDriver) physicalPortGet(name string) (hwdb.TablePhysicalPort, error) {
+	for _, tablePS := range d.physicalSwitches() {
+		for _, uuid := range tablePS.Ports {
+			tablePort, err := d.db().PhysicalPortGetByUUID(uuid.GoUUID)
+			if err == nil && tablePort.Name == name {
+				return tablePort, nil
+			}
+		}
+	}
+	return hwdb.TablePhysicalPort{}, fmt.Errorf("[Driver] Physical_Port %s not exist", name)
+}
+
+// locatorGet vxlan Physical_Locator of remote ip, locator not in db is
+// returned as insert operation and its named uuid, which must be in the
+// same transaction as rows referring it
+func (d *hwvtepDriver) locatorGet(remoteIP string) (libovsdb.UUID, []libovsdb.Operation, error) {
+	var uuid string
+	d.db().PhysicalLocatorIterator(func(table hwdb.TablePhysicalLocator) {
+		if table.DstIP == remoteIP && len(table.TunnelKey) == 0 &&
+			table.EncapsulationType == hwdb.PhysicalLocatorEncapsulationTypeVxlanOverIpv4 {
+			uuid = table.UUID
+		}
+	})
+	if uuid != "" {
+		return libovsdb.UUID{GoUUID: uuid}, nil, nil
+	}
+
+	insertOp, err := hwdb.PhysicalLocatorAddOp(hwdb.TablePhysicalLocator{
+		EncapsulationType: hwdb.PhysicalLocatorEncapsulationTypeVxlanOverIpv4,
+		DstIP:             remoteIP,
+	})
+	if err != nil {
+		return libovsdb.UUID{}, nil, err
+	}
+	return libovsdb.UUID{GoUUID: insertOp.UUIDName}, []libovsdb.Operation{insertOp}, nil
+}
+
+// rowUpsertOp insert row into table, or update row of uuid if set
+func rowUpsertOp(table string, uuid string, row map[string]interface{}) libovsdb.Operation {
+	if uuid == "" {
+		return libovsdb.Operation{
+			Op:    opInsert,
+			Table: table,
+			Row:   row,
+		}
+	}
+	return libovsdb.Operation{
+		Op:    opUpdate,
+		Table: table,
+		Row:   row,
+		Where: []interface{}{libovsdb.NewCondition(fieldUUID, "==", libovsdb.UUID{GoUUID: uuid})},
+	}
+}
@@ -0,0 +1,103 @@
+// Package hwvtep is the TAI driver rendering controller state into a
+// standard OVS hardware_vtep database, it drives third-party VTEP switches
+// shipping their own hardware_vtep OVSDB server without a TAI driver on
+// the box.
+//
+// Bridge is rendered as Logical_Switch of same name with vni as its
+// tunnel key, remote fdb as Ucast_Macs_Remote, bum flood list as
+// unknown-dst Mcast_Macs_Remote with a Physical_Locator_Set, l2 port as
+// vlan binding of its physical parent port and tunnel source ip as
+// Physical_Switch tunnel_ips. Local macs learned by the switch in
+// Ucast_Macs_Local are notified to TAI.
+//
+// Default instance programs Addr, switch with own driver config programs
+// its driver address, only Physical_Switch named as the switch if exists.
+package hwvtep
+
+import (
+	"fmt"
+	"sync"
+
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type hwvtepDriver struct {
+	DriverName string
+	ModuleAPIs map[tai.ObjID]moduleAPI
+	// mutex serialize module calls of the instance
+	mutex  sync.Mutex
+	client *hwdb.Client
+	// batch client of client between TaiBegin and TaiCommit or TaiAbort
+	batch *hwdb.Client
+	// physical switch programmed, empty for all switches in db
+	physicalSwitch string
+}
+
+// db client of driver instance, or its batch client in batch
+func (d *hwvtepDriver) db() *hwdb.Client {
+	if d.batch != nil {
+		return d.batch
+	}
+	return d.client
+}
+
+type moduleAPI interface {
+	CreateObject(interface{}) error
+	RemoveObject(interface{}) error
+	AddObjectAttr(interface{}, tai.Attrs) error
+	DelObjectAttr(interface{}, tai.Attrs) error
+	SetObjectAttr(interface{}, tai.Attrs) error
+	GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error)
+	ListObject() ([]interface{}, error)
+}
+
+// batchModule module keeping state of operations pending in batch, state
+// is recorded when batch committed and dropped when aborted
+type batchModule interface {
+	batchDone(committed bool)
+}
+
+// DriverName of hardware_vtep TAI driver
+const DriverName = "HWVTEP"
+
+// Addr hardware_vtep database address of default instance, eg
+// tcp:192.168.1.10:6640
+var Addr string
+
+func init() {
+	tai.RegisterDriver(DriverName, Init)
+}
+
+// Init hardware_vtep TAI driver, connect database of driver address or
+// Addr and monitor its local macs
+func Init(config tai.DriverConfig) (tai.DriverHandler, error) {
+	addr := Addr
+	if config.Addr != "" {
+		addr = config.Addr
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("[Driver] hardware_vtep address of switch %q not set", config.Switch)
+	}
+
+	client, err := hwdb.NewClient(addr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &hwvtepDriver{
+		DriverName:     DriverName,
+		client:         client,
+		physicalSwitch: config.Switch,
+	}
+	d.ModuleAPIs = map[tai.ObjID]moduleAPI{
+		tai.ObjectIDBridge:   newBridgeAPI(d),
+		tai.ObjectIDL2Port:   newL2portAPI(d),
+		tai.ObjectIDFDB:      newFdbAPI(d),
+		tai.ObjectIDTunnel:   newTunnelAPI(d),
+		tai.ObjectIDMcastFDB: newMcastFdbAPI(d),
+	}
+	localMacMonitor(client, addr)
+	return d, nil
+}
@@ -0,0 +1,378 @@
+package hwvtep
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+// testSchema hardware_vtep schema of odbgen
+const testSchema = "../../cmd/odbgen/schema/hardware_vtep.ovsschema"
+
+// testServer hardware_vtep ovsdb-server listening on unix socket of test
+// with Physical_Switch sw1 of ports eth1 and eth2, test is skipped without
+// ovsdb-server. The returned address is the driver address of switch.
+func testServer(t *testing.T) string {
+	t.Helper()
+	server, err := exec.LookPath("ovsdb-server")
+	if err != nil {
+		t.Skip("ovsdb-server not found")
+	}
+	tool, err := exec.LookPath("ovsdb-tool")
+	if err != nil {
+		t.Skip("ovsdb-tool not found")
+	}
+
+	dir := t.TempDir()
+	db := filepath.Join(dir, "vtep.db")
+	socket := filepath.Join(dir, "db.sock")
+	if out, err := exec.Command(tool, "create", db, testSchema).CombinedOutput(); err != nil {
+		t.Skipf("ovsdb-tool create failed %v: %s", err, out)
+	}
+	cmd := exec.Command(server, "--remote=punix:"+socket,
+		"--unixctl="+filepath.Join(dir, "ovsdb-server.ctl"), "--no-chdir", db)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("ovsdb-server start failed %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr := "unix:" + socket
+	var client *hwdb.Client
+	for i := 0; ; i++ {
+		if client, err = hwdb.NewClient(addr, nil); err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("ovsdb-server %s not ready: %v", socket, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Cleanup(func() { client.Conn().Disconnect() })
+
+	var ops []libovsdb.Operation
+	var ports []libovsdb.UUID
+	for _, name := range []string{"eth1", "eth2"} {
+		op, err := hwdb.PhysicalPortAddOp(hwdb.TablePhysicalPort{Name: name})
+		if err != nil {
+			t.Fatalf("Physical_Port %s op failed %v", name, err)
+		}
+		ops = append(ops, op)
+		ports = append(ports, libovsdb.UUID{GoUUID: op.UUIDName})
+	}
+	op, err := hwdb.PhysicalSwitchAddOp(hwdb.TablePhysicalSwitch{Name: "sw1", Ports: ports})
+	if err != nil {
+		t.Fatalf("Physical_Switch op failed %v", err)
+	}
+	if _, err := client.Transact(append(ops, op)...); err != nil {
+		t.Fatalf("Physical_Switch sw1 add failed %v", err)
+	}
+	return addr
+}
+
+func testDriver(t *testing.T, addr string) *hwvtepDriver {
+	t.Helper()
+	driver, err := Init(tai.DriverConfig{Switch: "sw1", Addr: addr})
+	if err != nil {
+		t.Fatalf("driver init on %s failed %v", addr, err)
+	}
+	d := driver.(*hwvtepDriver)
+	t.Cleanup(func() { d.client.Conn().Disconnect() })
+	return d
+}
+
+// testBindings vlan bindings of port to Logical_Switch names
+func testBindings(t *testing.T, d *hwvtepDriver, port string) map[int]string {
+	t.Helper()
+	tablePort, err := d.physicalPortGet(port)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	bindings := make(map[int]string)
+	for vlan, lsUUID := range vlanBindings(tablePort) {
+		tableLS, err := d.client.LogicalSwitchGetByUUID(lsUUID.GoUUID)
+		if err != nil {
+			t.Fatalf("Physical_Port %s vlan %d bound to absent Logical_Switch %s", port, vlan, lsUUID.GoUUID)
+		}
+		bindings[vlan] = tableLS.Name
+	}
+	return bindings
+}
+
+// testLocatorIP dst ip of vxlan Physical_Locator
+func testLocatorIP(t *testing.T, d *hwvtepDriver, locator libovsdb.UUID) string {
+	t.Helper()
+	tableLocator, err := d.client.PhysicalLocatorGetByUUID(locator.GoUUID)
+	if err != nil {
+		t.Fatalf("Physical_Locator %s not exist", locator.GoUUID)
+	}
+	if tableLocator.EncapsulationType != hwdb.PhysicalLocatorEncapsulationTypeVxlanOverIpv4 {
+		t.Errorf("Physical_Locator %s encapsulation %s", tableLocator.DstIP, tableLocator.EncapsulationType)
+	}
+	return tableLocator.DstIP
+}
+
+func TestBridgeFdbTunnel(t *testing.T) {
+	d := testDriver(t, testServer(t))
+
+	objTunnel := tai.TunnelObj{Name: "tun0", Ipaddr: "192.0.2.1"}
+	if err := d.TaiCreateObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel create failed %v", err)
+	}
+	if err := d.TaiSetObjectAttr(tai.ObjectIDTunnel, objTunnel, tai.Attrs{tai.TunnelAttrIpaddr: "192.0.2.2"}); err != nil {
+		t.Fatalf("tunnel ip set failed %v", err)
+	}
+	tablePS, err := d.client.PhysicalSwitchGetByIndex(hwdb.PhysicalSwitchIndex{Name: "sw1"})
+	if err != nil || len(tablePS.TunnelIps) != 1 || tablePS.TunnelIps[0] != "192.0.2.2" {
+		t.Errorf("Physical_Switch tunnel_ips %v, err %v", tablePS.TunnelIps, err)
+	}
+
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	attrs, err := d.TaiGetObjectAttr(tai.ObjectIDBridge, objBridge, []tai.ObjAttrID{tai.BridgeAttrL2vni})
+	if err != nil || attrs.GetInt(tai.BridgeAttrL2vni) != 100 {
+		t.Errorf("bridge vni %v, err %v", attrs, err)
+	}
+
+	objFdb := tai.FdbObj{Bridge: "Bd100", Mac: "52:54:00:00:00:01"}
+	for _, remoteIP := range []string{"192.0.2.10", "192.0.2.11"} {
+		if err := d.TaiAddObjectAttr(tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: remoteIP}); err != nil {
+			t.Fatalf("fdb remote ip %s add failed %v", remoteIP, err)
+		}
+	}
+	tableLS, err := d.logicalSwitchGet("Bd100")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	tableMac, exist := d.ucastMacRemoteGet(tableLS.UUID, objFdb.Mac)
+	if !exist {
+		t.Fatalf("Ucast_Macs_Remote %s not exist", objFdb.Mac)
+	}
+	if ip := testLocatorIP(t, d, tableMac.Locator); ip != "192.0.2.11" {
+		t.Errorf("Ucast_Macs_Remote %s locator %s, expect 192.0.2.11", objFdb.Mac, ip)
+	}
+	if n := d.client.UcastMacsRemoteIterator(func(hwdb.TableUcastMacsRemote) {}); n != 1 {
+		t.Errorf("Ucast_Macs_Remote has %d rows, expect 1", n)
+	}
+
+	d.mutex.Lock()
+	err = d.floodSet("Bd100", []string{"192.0.2.10", "192.0.2.12"})
+	d.mutex.Unlock()
+	if err != nil {
+		t.Fatalf("flood set failed %v", err)
+	}
+	tableMcast, exist := d.mcastMacRemoteGet(tableLS.UUID, unknownDstMac)
+	if !exist {
+		t.Fatalf("Mcast_Macs_Remote %s not exist", unknownDstMac)
+	}
+	tableSet, err := d.client.PhysicalLocatorSetGetByUUID(tableMcast.LocatorSet.GoUUID)
+	if err != nil {
+		t.Fatalf("Physical_Locator_Set not exist")
+	}
+	floods := make(map[string]bool)
+	for _, locator := range tableSet.Locators {
+		floods[testLocatorIP(t, d, locator)] = true
+	}
+	if len(floods) != 2 || !floods["192.0.2.10"] || !floods["192.0.2.12"] {
+		t.Errorf("flood locators %v, expect 192.0.2.10 and 192.0.2.12", floods)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDMcastFDB, tai.McastFdbObj{BridgeName: "Bd100", Mac: unknownDstMac}); err != nil {
+		t.Fatalf("mcast fdb remove failed %v", err)
+	}
+	if _, exist := d.mcastMacRemoteGet(tableLS.UUID, unknownDstMac); exist {
+		t.Errorf("Mcast_Macs_Remote %s not removed", unknownDstMac)
+	}
+	if err := d.TaiRemoveObject(tai.ObjectIDFDB, objFdb); err != nil {
+		t.Fatalf("fdb remove failed %v", err)
+	}
+	if _, exist := d.ucastMacRemoteGet(tableLS.UUID, objFdb.Mac); exist {
+		t.Errorf("Ucast_Macs_Remote %s not removed", objFdb.Mac)
+	}
+	if err := d.TaiRemoveObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge remove failed %v", err)
+	}
+	if _, err := d.logicalSwitchGet("Bd100"); err == nil {
+		t.Errorf("Logical_Switch Bd100 not removed")
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel remove failed %v", err)
+	}
+	tablePS, err = d.client.PhysicalSwitchGetByIndex(hwdb.PhysicalSwitchIndex{Name: "sw1"})
+	if err != nil || len(tablePS.TunnelIps) != 0 {
+		t.Errorf("Physical_Switch tunnel_ips %v not removed, err %v", tablePS.TunnelIps, err)
+	}
+}
+
+func TestL2portVlanBinding(t *testing.T) {
+	d := testDriver(t, testServer(t))
+
+	for _, objBridge := range []tai.BridgeObj{{Name: "Bd100", Vni: 100}, {Name: "Bd200", Vni: 200}} {
+		if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+			t.Fatalf("bridge %s create failed %v", objBridge.Name, err)
+		}
+	}
+
+	untagged := tai.L2portObj{Name: "eth1", BridgeName: "Bd100", PhysicalParentPort: "eth1"}
+	tagged := tai.L2portObj{Name: "eth2.10", BridgeName: "Bd200", PhysicalParentPort: "eth2"}
+	for _, objL2port := range []tai.L2portObj{untagged, tagged} {
+		if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+			t.Fatalf("l2port %s create failed %v", objL2port.Name, err)
+		}
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDL2Port, tagged, tai.Attrs{tai.L2portAttrVlanTag: []int{10}}); err != nil {
+		t.Fatalf("l2port vlan add failed %v", err)
+	}
+	if bindings := testBindings(t, d, "eth1"); len(bindings) != 1 || bindings[0] != "Bd100" {
+		t.Errorf("eth1 vlan bindings %v, expect untagged to Bd100", bindings)
+	}
+	if bindings := testBindings(t, d, "eth2"); len(bindings) != 1 || bindings[10] != "Bd200" {
+		t.Errorf("eth2 vlan bindings %v, expect vlan 10 to Bd200", bindings)
+	}
+
+	if err := d.TaiSetObjectAttr(tai.ObjectIDL2Port, tagged, tai.Attrs{tai.L2portAttrVlanTag: []int{20}}); err != nil {
+		t.Fatalf("l2port vlan set failed %v", err)
+	}
+	if bindings := testBindings(t, d, "eth2"); len(bindings) != 1 || bindings[20] != "Bd200" {
+		t.Errorf("eth2 vlan bindings %v, expect vlan 20 to Bd200", bindings)
+	}
+	err := d.TaiAddObjectAttr(tai.ObjectIDL2Port, tagged, tai.Attrs{tai.L2portAttrVlanTag: []int{10, 20}})
+	if err == nil {
+		t.Errorf("stacked vlans not refused")
+	}
+
+	for _, objL2port := range []tai.L2portObj{untagged, tagged} {
+		if err := d.TaiRemoveObject(tai.ObjectIDL2Port, objL2port); err != nil {
+			t.Fatalf("l2port %s remove failed %v", objL2port.Name, err)
+		}
+		if bindings := testBindings(t, d, objL2port.PhysicalParentPort); len(bindings) != 0 {
+			t.Errorf("%s vlan bindings %v not removed", objL2port.PhysicalParentPort, bindings)
+		}
+	}
+}
+
+// TestL2portBatch vlan binding of l2 port is recorded after batch
+// committed, binding changed in aborted batch is kept
+func TestL2portBatch(t *testing.T) {
+	d := testDriver(t, testServer(t))
+	l2port := d.ModuleAPIs[tai.ObjectIDL2Port].(*l2portAPI)
+
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, tai.BridgeObj{Name: "Bd100", Vni: 100}); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	objL2port := tai.L2portObj{Name: "eth1", BridgeName: "Bd100", PhysicalParentPort: "eth1"}
+
+	if err := d.TaiBegin(); err != nil {
+		t.Fatalf("begin failed %v", err)
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port create failed %v", err)
+	}
+	d.TaiAbort()
+	if _, ok := l2port.bindings["eth1"]; ok {
+		t.Errorf("eth1 binding of aborted batch recorded")
+	}
+	if bindings := testBindings(t, d, "eth1"); len(bindings) != 0 {
+		t.Errorf("eth1 vlan bindings %v of aborted batch", bindings)
+	}
+
+	if err := d.TaiBegin(); err != nil {
+		t.Fatalf("begin failed %v", err)
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port create failed %v", err)
+	}
+	if _, ok := l2port.bindings["eth1"]; ok {
+		t.Errorf("eth1 binding recorded before commit")
+	}
+	if err := d.TaiCommit(); err != nil {
+		t.Fatalf("commit failed %v", err)
+	}
+	if _, ok := l2port.bindings["eth1"]; !ok {
+		t.Errorf("eth1 binding not recorded after commit")
+	}
+
+	if err := d.TaiBegin(); err != nil {
+		t.Fatalf("begin failed %v", err)
+	}
+	if err := d.TaiRemoveObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port remove failed %v", err)
+	}
+	d.TaiAbort()
+	if err := d.TaiRemoveObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port remove failed %v", err)
+	}
+	if bindings := testBindings(t, d, "eth1"); len(bindings) != 0 {
+		t.Errorf("eth1 vlan bindings %v not removed after aborted remove", bindings)
+	}
+}
+
+// TestListObject objects rendered are listed by instance restarted on
+// same database, listed l2port is removed without its name
+func TestListObject(t *testing.T) {
+	addr := testServer(t)
+	d := testDriver(t, addr)
+
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	objL2port := tai.L2portObj{Name: "eth1.10", BridgeName: "Bd100", PhysicalParentPort: "eth1"}
+	objFdb := tai.FdbObj{Bridge: "Bd100", Mac: "52:54:00:00:00:01"}
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	if _, err := d.client.LogicalSwitchAdd(hwdb.TableLogicalSwitch{Name: "other"}); err != nil {
+		t.Fatalf("Logical_Switch other add failed %v", err)
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port create failed %v", err)
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDL2Port, objL2port, tai.Attrs{tai.L2portAttrVlanTag: []int{10}}); err != nil {
+		t.Fatalf("l2port vlan add failed %v", err)
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: "192.0.2.10"}); err != nil {
+		t.Fatalf("fdb add failed %v", err)
+	}
+	d.mutex.Lock()
+	err := d.floodSet("Bd100", []string{"192.0.2.10"})
+	d.mutex.Unlock()
+	if err != nil {
+		t.Fatalf("flood set failed %v", err)
+	}
+
+	d = testDriver(t, addr)
+	expects := map[tai.ObjID]interface{}{
+		tai.ObjectIDBridge:   objBridge,
+		tai.ObjectIDL2Port:   tai.L2portObj{BridgeName: "Bd100", PhysicalParentPort: "eth1"},
+		tai.ObjectIDFDB:      objFdb,
+		tai.ObjectIDMcastFDB: tai.McastFdbObj{BridgeName: "Bd100", Mac: unknownDstMac},
+	}
+	for objID, expect := range expects {
+		objs, err := d.TaiListObject(objID)
+		if err != nil {
+			t.Fatalf("%s list failed %v", tai.ObjectOrder[objID], err)
+		}
+		if len(objs) != 1 || objs[0] != expect {
+			t.Errorf("%s listed %+v, expect %+v", tai.ObjectOrder[objID], objs, expect)
+		}
+	}
+	if objs, err := d.TaiListObject(tai.ObjectIDTunnel); err != nil || len(objs) != 0 {
+		t.Errorf("tunnel listed %+v, err %v", objs, err)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDL2Port, expects[tai.ObjectIDL2Port]); err != nil {
+		t.Fatalf("listed l2port remove failed %v", err)
+	}
+	if bindings := testBindings(t, d, "eth1"); len(bindings) != 0 {
+		t.Errorf("eth1 vlan bindings %v not removed", bindings)
+	}
+}
@@ -0,0 +1,126 @@
+package hwvtep
+
+import (
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+type fdbAPI struct {
+	moduleID int
+	*hwvtepDriver
+}
+
+func newFdbAPI(d *hwvtepDriver) *fdbAPI {
+	return &fdbAPI{
+		moduleID:     tai.ObjectIDFDB,
+		hwvtepDriver: d,
+	}
+}
+
+// ucastMacRemoteGet Ucast_Macs_Remote of mac in Logical_Switch of uuid,
+// table has no index
+func (d *hwvtepDriver) ucastMacRemoteGet(lsUUID string, mac string) (hwdb.TableUcastMacsRemote, bool) {
+	var found hwdb.TableUcastMacsRemote
+	exist := false
+	d.db().UcastMacsRemoteIterator(func(table hwdb.TableUcastMacsRemote) {
+		if table.LogicalSwitch.GoUUID == lsUUID && table.Mac == mac {
+			found, exist = table, true
+		}
+	})
+	return found, exist
+}
+
+// remoteMacAdd Ucast_Macs_Remote of mac point to vxlan locator of remote
+// ip, locator is created with the mac if not exist
+func (d *hwvtepDriver) remoteMacAdd(bdName string, mac string, remoteIP string) error {
+	tableLS, err := d.logicalSwitchGet(bdName)
+	if err != nil {
+		return err
+	}
+	locator, ops, err := d.locatorGet(remoteIP)
+	if err != nil {
+		return err
+	}
+
+	tableMac, exist := d.ucastMacRemoteGet(tableLS.UUID, mac)
+	if exist && tableMac.Locator.GoUUID == locator.GoUUID {
+		return nil
+	}
+	row, err := hwdb.ConvertTableToRow(hwdb.TableUcastMacsRemote{
+		Mac:           mac,
+		LogicalSwitch: libovsdb.UUID{GoUUID: tableLS.UUID},
+		Locator:       locator,
+	}, hwdb.UcastMacsRemoteFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, rowUpsertOp(hwdb.UcastMacsRemote, tableMac.UUID, row))
+	_, err = d.db().Transact(ops...)
+	return err
+}
+
+func (d *hwvtepDriver) remoteMacDel(bdName string, mac string) error {
+	tableLS, err := d.logicalSwitchGet(bdName)
+	if err != nil {
+		return nil
+	}
+	tableMac, exist := d.ucastMacRemoteGet(tableLS.UUID, mac)
+	if !exist {
+		return nil
+	}
+	return d.db().UcastMacsRemoteDelByUUID(tableMac.UUID)
+}
+
+// CreateObject remote mac is set with remote ip attr
+func (v *fdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *fdbAPI) RemoveObject(obj interface{}) error {
+	objFdb := obj.(tai.FdbObj)
+	return v.remoteMacDel(objFdb.Bridge, objFdb.Mac)
+}
+
+func (v *fdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objFdb := obj.(tai.FdbObj)
+
+	if remoteIP := attrs.GetString(tai.FdbAttrRemoteIP); remoteIP != "" {
+		if err := v.remoteMacAdd(objFdb.Bridge, objFdb.Mac, remoteIP); err != nil {
+			log.Warning("[Driver] Ucast_Macs_Remote %s of %s add failed %v\n", objFdb.Mac, objFdb.Bridge, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *fdbAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *fdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *fdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject Ucast_Macs_Remote of bridge Logical_Switch
+func (v *fdbAPI) ListObject() ([]interface{}, error) {
+	bridges := make(map[string]string)
+	for _, tableLS := range v.bridgeSwitches() {
+		bridges[tableLS.UUID] = tableLS.Name
+	}
+
+	var objs []interface{}
+	v.db().UcastMacsRemoteIterator(func(table hwdb.TableUcastMacsRemote) {
+		if bdName, ok := bridges[table.LogicalSwitch.GoUUID]; ok {
+			objs = append(objs, tai.FdbObj{Bridge: bdName, Mac: table.Mac})
+		}
+	})
+	return objs, nil
+}
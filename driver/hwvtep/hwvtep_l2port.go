@@ -0,0 +1,286 @@
+package hwvtep
+
+import (
+	"fmt"
+
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+// vlanBinding vlan of physical port bound to Logical_Switch of l2 port
+type vlanBinding struct {
+	port string
+	vlan int
+	ls   string
+}
+
+type l2portAPI struct {
+	moduleID int
+	*hwvtepDriver
+	// l2 port name to its vlan binding committed to hardware_vtep
+	bindings map[string]vlanBinding
+	// pending bindings changed in batch by l2 port name, nil if unbound,
+	// recorded in bindings after batch committed
+	pending map[string]*vlanBinding
+}
+
+func newL2portAPI(d *hwvtepDriver) *l2portAPI {
+	return &l2portAPI{
+		moduleID:     tai.ObjectIDL2Port,
+		hwvtepDriver: d,
+		bindings:     make(map[string]vlanBinding),
+		pending:      make(map[string]*vlanBinding),
+	}
+}
+
+// bindingGet vlan binding of l2 port, pending binding of batch first
+func (v *l2portAPI) bindingGet(name string) (vlanBinding, bool) {
+	if binding, ok := v.pending[name]; ok {
+		if binding == nil {
+			return vlanBinding{}, false
+		}
+		return *binding, true
+	}
+	binding, ok := v.bindings[name]
+	return binding, ok
+}
+
+// bindingSet record vlan binding of l2 port, pending until commit in batch
+func (v *l2portAPI) bindingSet(name string, binding vlanBinding) {
+	if v.batch != nil {
+		v.pending[name] = &binding
+		return
+	}
+	v.bindings[name] = binding
+}
+
+// bindingDel forget vlan binding of l2 port, pending until commit in batch
+func (v *l2portAPI) bindingDel(name string) {
+	if v.batch != nil {
+		v.pending[name] = nil
+		return
+	}
+	delete(v.bindings, name)
+}
+
+// batchDone record pending bindings of committed batch, drop them of
+// aborted one
+func (v *l2portAPI) batchDone(committed bool) {
+	if committed {
+		for name, binding := range v.pending {
+			if binding == nil {
+				delete(v.bindings, name)
+			} else {
+				v.bindings[name] = *binding
+			}
+		}
+	}
+	v.pending = make(map[string]*vlanBinding)
+}
+
+// vlanBindings vlans of port to Logical_Switch bound, integer keys may be
+// decoded as float64 and uuid values as ["uuid", id] pairs
+func vlanBindings(tablePort hwdb.TablePhysicalPort) map[int]libovsdb.UUID {
+	bindings := make(map[int]libovsdb.UUID)
+	for key, value := range tablePort.VlanBindings {
+		var vlan int
+		switch k := key.(type) {
+		case int:
+			vlan = k
+		case float64:
+			vlan = int(k)
+		default:
+			continue
+		}
+		switch v := value.(type) {
+		case libovsdb.UUID:
+			bindings[vlan] = v
+		case []interface{}:
+			if len(v) != 2 || v[0] != "uuid" {
+				continue
+			}
+			if id, ok := v[1].(string); ok {
+				bindings[vlan] = libovsdb.UUID{GoUUID: id}
+			}
+		}
+	}
+	return bindings
+}
+
+// vlanBindingGet Logical_Switch vlan of port is bound to
+func vlanBindingGet(tablePort hwdb.TablePhysicalPort, vlan int) (libovsdb.UUID, bool) {
+	lsUUID, ok := vlanBindings(tablePort)[vlan]
+	return lsUUID, ok
+}
+
+// vlanBind bind vlan of physical parent port to Logical_Switch of bridge,
+// vlan 0 is untagged. Binding of vlan to other switch is replaced.
+func (v *l2portAPI) vlanBind(objL2port tai.L2portObj, vlan int) error {
+	tableLS, err := v.logicalSwitchGet(objL2port.BridgeName)
+	if err != nil {
+		return err
+	}
+	tablePort, err := v.physicalPortGet(objL2port.PhysicalParentPort)
+	if err != nil {
+		return err
+	}
+
+	portIndex := hwdb.PhysicalPortUUIDIndex{UUID: tablePort.UUID}
+	if old, ok := vlanBindingGet(tablePort, vlan); ok {
+		if old.GoUUID == tableLS.UUID {
+			v.bindingSet(objL2port.Name, vlanBinding{port: tablePort.Name, vlan: vlan, ls: tableLS.UUID})
+			return nil
+		}
+		log.Warning("[Driver] Physical_Port %s vlan %d bound to %s, rebound to %s\n",
+			tablePort.Name, vlan, old.GoUUID, objL2port.BridgeName)
+		err = v.db().PhysicalPortUpdateVlanBindingsDelkey(portIndex, map[interface{}]interface{}{vlan: old})
+		if err != nil {
+			return err
+		}
+	}
+	err = v.db().PhysicalPortUpdateVlanBindingsSetkey(portIndex,
+		map[interface{}]interface{}{vlan: libovsdb.UUID{GoUUID: tableLS.UUID}})
+	if err != nil {
+		return err
+	}
+	v.bindingSet(objL2port.Name, vlanBinding{port: tablePort.Name, vlan: vlan, ls: tableLS.UUID})
+	return nil
+}
+
+func (v *l2portAPI) vlanUnbind(name string) error {
+	binding, ok := v.bindingGet(name)
+	if !ok {
+		return nil
+	}
+	v.bindingDel(name)
+
+	tablePort, err := v.physicalPortGet(binding.port)
+	if err != nil {
+		return nil
+	}
+	return v.db().PhysicalPortUpdateVlanBindingsDelkey(hwdb.PhysicalPortUUIDIndex{UUID: tablePort.UUID},
+		map[interface{}]interface{}{binding.vlan: libovsdb.UUID{GoUUID: binding.ls}})
+}
+
+// CreateObject untagged port is the physical parent port itself, vlan of
+// tagged port is bound with vlan tag attr
+func (v *l2portAPI) CreateObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if objL2port.Name != objL2port.PhysicalParentPort {
+		return nil
+	}
+	return v.vlanBind(objL2port, 0)
+}
+
+// RemoveObject listed l2port has no name, vlans of its physical parent
+// port bound to bridge are removed except those of l2ports created again
+func (v *l2portAPI) RemoveObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if objL2port.Name != "" {
+		return v.vlanUnbind(objL2port.Name)
+	}
+	tableLS, err := v.logicalSwitchGet(objL2port.BridgeName)
+	if err != nil {
+		return nil
+	}
+	tablePort, err := v.physicalPortGet(objL2port.PhysicalParentPort)
+	if err != nil {
+		return nil
+	}
+	claimed := make(map[int]bool)
+	for name := range v.bindings {
+		if binding, ok := v.bindingGet(name); ok && binding.port == tablePort.Name {
+			claimed[binding.vlan] = true
+		}
+	}
+	for _, binding := range v.pending {
+		if binding != nil && binding.port == tablePort.Name {
+			claimed[binding.vlan] = true
+		}
+	}
+	stale := make(map[interface{}]interface{})
+	for vlan, lsUUID := range vlanBindings(tablePort) {
+		if lsUUID.GoUUID == tableLS.UUID && !claimed[vlan] {
+			stale[vlan] = lsUUID
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return v.db().PhysicalPortUpdateVlanBindingsDelkey(hwdb.PhysicalPortUUIDIndex{UUID: tablePort.UUID}, stale)
+}
+
+func (v *l2portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL2port := obj.(tai.L2portObj)
+
+	tags := attrs.GetInts(tai.L2portAttrVlanTag)
+	switch len(tags) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("[Driver] L2port %s vlan %v: stacked vlans not supported by hardware_vtep",
+			objL2port.Name, tags)
+	}
+	if err := v.vlanBind(objL2port, tags[0]); err != nil {
+		log.Warning("[Driver] L2port %s vlan %v add failed %v\n", objL2port.Name, tags, err)
+		return err
+	}
+	return nil
+}
+
+func (v *l2portAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *l2portAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if !attrs.Has(tai.L2portAttrVlanTag) {
+		return nil
+	}
+	if err := v.vlanUnbind(objL2port.Name); err != nil {
+		return err
+	}
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *l2portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject ports of driver instance with vlans bound to bridge
+// Logical_Switch, l2port name is not kept by hardware_vtep and not part of
+// listed l2port
+func (v *l2portAPI) ListObject() ([]interface{}, error) {
+	bridges := make(map[string]string)
+	for _, tableLS := range v.bridgeSwitches() {
+		bridges[tableLS.UUID] = tableLS.Name
+	}
+
+	var objs []interface{}
+	for _, tablePS := range v.physicalSwitches() {
+		for _, uuid := range tablePS.Ports {
+			tablePort, err := v.db().PhysicalPortGetByUUID(uuid.GoUUID)
+			if err != nil {
+				continue
+			}
+			listed := make(map[string]bool)
+			for _, lsUUID := range vlanBindings(tablePort) {
+				bdName, ok := bridges[lsUUID.GoUUID]
+				if !ok || listed[bdName] {
+					continue
+				}
+				listed[bdName] = true
+				objs = append(objs, tai.L2portObj{BridgeName: bdName, PhysicalParentPort: tablePort.Name})
+			}
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,143 @@
+package hwvtep
+
+import (
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+// unknownDstMac mac of hardware_vtep flood list
+const unknownDstMac = "unknown-dst"
+
+type mcastFdbAPI struct {
+	moduleID int
+	*hwvtepDriver
+}
+
+func newMcastFdbAPI(d *hwvtepDriver) *mcastFdbAPI {
+	return &mcastFdbAPI{
+		moduleID:     tai.ObjectIDMcastFDB,
+		hwvtepDriver: d,
+	}
+}
+
+// mcastMacRemoteGet Mcast_Macs_Remote of mac in Logical_Switch of uuid
+func (d *hwvtepDriver) mcastMacRemoteGet(lsUUID string, mac string) (hwdb.TableMcastMacsRemote, bool) {
+	var found hwdb.TableMcastMacsRemote
+	exist := false
+	d.db().McastMacsRemoteIterator(func(table hwdb.TableMcastMacsRemote) {
+		if table.LogicalSwitch.GoUUID == lsUUID && table.Mac == mac {
+			found, exist = table, true
+		}
+	})
+	return found, exist
+}
+
+// floodSet replace unknown-dst locator set of Logical_Switch, locator set
+// is immutable so a new one is created for every change and the old one is
+// garbage collected. Empty list removes the Mcast_Macs_Remote.
+func (d *hwvtepDriver) floodSet(bdName string, ips []string) error {
+	tableLS, err := d.logicalSwitchGet(bdName)
+	if err != nil {
+		return err
+	}
+	tableMac, exist := d.mcastMacRemoteGet(tableLS.UUID, unknownDstMac)
+	if len(ips) == 0 {
+		if !exist {
+			return nil
+		}
+		return d.db().McastMacsRemoteDelByUUID(tableMac.UUID)
+	}
+
+	var ops []libovsdb.Operation
+	var locators []libovsdb.UUID
+	for _, ip := range ips {
+		locator, locatorOps, err := d.locatorGet(ip)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, locatorOps...)
+		locators = append(locators, locator)
+	}
+	setOp, err := hwdb.PhysicalLocatorSetAddOp(hwdb.TablePhysicalLocatorSet{Locators: locators})
+	if err != nil {
+		return err
+	}
+	ops = append(ops, setOp)
+
+	row, err := hwdb.ConvertTableToRow(hwdb.TableMcastMacsRemote{
+		Mac:           unknownDstMac,
+		LogicalSwitch: libovsdb.UUID{GoUUID: tableLS.UUID},
+		LocatorSet:    libovsdb.UUID{GoUUID: setOp.UUIDName},
+	}, hwdb.McastMacsRemoteFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, rowUpsertOp(hwdb.McastMacsRemote, tableMac.UUID, row))
+	_, err = d.db().Transact(ops...)
+	return err
+}
+
+func (v *mcastFdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *mcastFdbAPI) RemoveObject(obj interface{}) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != unknownDstMac {
+		return nil
+	}
+	if _, err := v.logicalSwitchGet(objMcastFdb.BridgeName); err != nil {
+		return nil
+	}
+	return v.floodSet(objMcastFdb.BridgeName, nil)
+}
+
+func (v *mcastFdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != unknownDstMac {
+		log.Info("[Driver] Mcast fdb %s of BD %s ignored\n", objMcastFdb.Mac, objMcastFdb.BridgeName)
+		return nil
+	}
+	if attrs.Has(tai.McastFdbAttrLocators) {
+		return v.floodSet(objMcastFdb.BridgeName, attrs.GetStrings(tai.McastFdbAttrLocators))
+	}
+	return nil
+}
+
+// DelObjectAttr flood list is removed with its last remote ip
+func (v *mcastFdbAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != unknownDstMac {
+		return nil
+	}
+	if _, ok := attrs[tai.McastFdbAttrLocators]; !ok {
+		return nil
+	}
+	return v.floodSet(objMcastFdb.BridgeName, nil)
+}
+
+func (v *mcastFdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *mcastFdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject unknown-dst flood lists of bridge Logical_Switch
+func (v *mcastFdbAPI) ListObject() ([]interface{}, error) {
+	var objs []interface{}
+	for _, tableLS := range v.bridgeSwitches() {
+		if _, exist := v.mcastMacRemoteGet(tableLS.UUID, unknownDstMac); exist {
+			objs = append(objs, tai.McastFdbObj{BridgeName: tableLS.Name, Mac: unknownDstMac})
+		}
+	}
+	return objs, nil
+}
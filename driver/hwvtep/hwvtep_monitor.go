@@ -0,0 +1,138 @@
+package hwvtep
+
+import (
+	"github.com/cn-pmlabs/govtep/lib/log"
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+// localMac mac learned in Logical_Switch of name
+type localMac struct {
+	bridge string
+	mac    string
+}
+
+// localMacs notify TAI of Ucast_Macs_Local learned by switch, table is
+// compared with macs notified on every change. Port of learned mac is not
+// in hardware_vtep so it's notified without port.
+type localMacs struct {
+	client *hwdb.Client
+	// addr of hardware_vtep, client is reconnected to it when connection
+	// lost
+	addr string
+	// changed wakes reporter, monitor callback can't query db of its own
+	// connection
+	changed  chan struct{}
+	notified map[localMac]bool
+	// dispatcher registered on connection of client
+	dispatcher *hwdb.Dispatcher
+}
+
+// localMacMonitor monitor Ucast_Macs_Local of client connected to addr,
+// monitor is established again after client reconnected
+func localMacMonitor(client *hwdb.Client, addr string) {
+	if client.Conn() == nil {
+		return
+	}
+
+	macs := &localMacs{
+		client:   client,
+		addr:     addr,
+		changed:  make(chan struct{}, 1),
+		notified: make(map[localMac]bool),
+	}
+	macs.dispatcher = hwdb.NewDispatcher()
+	macs.dispatcher.OnDisconnected = macs.disconnected
+	if err := macs.dispatcher.Register(macs); err != nil {
+		log.Warning("[Driver] hardware_vtep local macs handler register failed %v\n", err)
+		return
+	}
+	go macs.reporter()
+	macs.monitor()
+}
+
+// monitor register macs on connection of client and monitor its
+// Ucast_Macs_Local, macs are compared with current table
+func (m *localMacs) monitor() {
+	conn := m.client.Conn()
+	conn.Register(m.dispatcher)
+	requests := map[string]libovsdb.MonitorRequest{
+		hwdb.UcastMacsLocal: {
+			Columns: []string{hwdb.UcastMacsLocalFieldMac, hwdb.UcastMacsLocalFieldLogicalSwitch},
+			Select:  libovsdb.MonitorSelect{Insert: true, Delete: true, Modify: true},
+		},
+	}
+	if _, err := conn.Monitor(hwdb.HARDWAREVTEP, "local-macs", requests); err != nil {
+		log.Warning("[Driver] monitor hardware_vtep local macs failed %v\n", err)
+		return
+	}
+	m.notifyChanged()
+}
+
+func (m *localMacs) notifyChanged() {
+	select {
+	case m.changed <- struct{}{}:
+	default:
+		// report pending
+	}
+}
+
+func (m *localMacs) reporter() {
+	for range m.changed {
+		m.report()
+	}
+}
+
+func (m *localMacs) report() {
+	bridges := make(map[string]string)
+	m.client.LogicalSwitchIterator(func(table hwdb.TableLogicalSwitch) {
+		bridges[table.UUID] = table.Name
+	})
+
+	current := make(map[localMac]bool)
+	m.client.UcastMacsLocalIterator(func(table hwdb.TableUcastMacsLocal) {
+		if bridge, ok := bridges[table.LogicalSwitch.GoUUID]; ok {
+			current[localMac{bridge: bridge, mac: table.Mac}] = true
+		}
+	})
+
+	for mac := range current {
+		if !m.notified[mac] {
+			tai.Notify(tai.FdbNotification{Bridge: mac.bridge, Mac: mac.mac})
+		}
+	}
+	for mac := range m.notified {
+		if !current[mac] {
+			tai.Notify(tai.FdbNotification{Bridge: mac.bridge, Mac: mac.mac, Aged: true})
+		}
+	}
+	m.notified = current
+}
+
+// OnUcastMacsLocalInsert hwdb.UcastMacsLocalHandler
+func (m *localMacs) OnUcastMacsLocalInsert(hwdb.TableUcastMacsLocal) {
+	m.notifyChanged()
+}
+
+// OnUcastMacsLocalUpdate hwdb.UcastMacsLocalHandler
+func (m *localMacs) OnUcastMacsLocalUpdate(_, _ hwdb.TableUcastMacsLocal, _ []string) {
+	m.notifyChanged()
+}
+
+// OnUcastMacsLocalDelete hwdb.UcastMacsLocalHandler
+func (m *localMacs) OnUcastMacsLocalDelete(hwdb.TableUcastMacsLocal) {
+	m.notifyChanged()
+}
+
+// disconnected reconnect client and monitor again, macs learned or aged
+// meanwhile are notified by the report after monitored
+func (m *localMacs) disconnected(*libovsdb.OvsdbClient) {
+	log.Warning("[Driver] hardware_vtep %s disconnected, try reconnect\n", m.addr)
+	go func() {
+		m.client.SetConn(odbc.Reconnect(hwdb.HARDWAREVTEP, m.addr, nil))
+		m.monitor()
+	}()
+}
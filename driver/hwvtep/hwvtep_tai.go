@@ -0,0 +1,164 @@
+package hwvtep
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// module get object api, driver mutex is held until unlock called
+func (d *hwvtepDriver) module(objID tai.ObjID) (moduleAPI, error) {
+	d.mutex.Lock()
+	if d.ModuleAPIs[objID] == nil {
+		d.mutex.Unlock()
+		return nil, fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
+	}
+	return d.ModuleAPIs[objID], nil
+}
+
+// TaiBegin start batch, hardware_vtep operations of modules are pending
+// until TaiCommit and sent in one transaction
+func (d *hwvtepDriver) TaiBegin() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.batch != nil {
+		return fmt.Errorf("[Driver] hardware_vtep batch already begun")
+	}
+	d.batch = d.client.Begin()
+	return nil
+}
+
+// TaiCommit send pending hardware_vtep operations in one transaction
+func (d *hwvtepDriver) TaiCommit() error {
+	d.mutex.Lock()
+	batch := d.batch
+	d.batch = nil
+	d.mutex.Unlock()
+	if batch == nil {
+		return fmt.Errorf("[Driver] hardware_vtep batch not begun")
+	}
+	err := batch.Commit()
+
+	d.mutex.Lock()
+	d.modulesBatchDone(err == nil)
+	d.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("[Driver] commit hardware_vtep transaction failed: %v", err)
+	}
+	return nil
+}
+
+// TaiAbort drop pending hardware_vtep operations
+func (d *hwvtepDriver) TaiAbort() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.batch != nil {
+		d.batch.Abort()
+		d.batch = nil
+		d.modulesBatchDone(false)
+	}
+}
+
+// modulesBatchDone record or drop module state pending in batch
+func (d *hwvtepDriver) modulesBatchDone(committed bool) {
+	for _, api := range d.ModuleAPIs {
+		if module, ok := api.(batchModule); ok {
+			module.batchDone(committed)
+		}
+	}
+}
+
+func (d *hwvtepDriver) TaiCreateObject(objID tai.ObjID, obj interface{}) error {
+	log.Info("[Driver] TaiCreateObject %v => %+v\n", tai.ObjectOrder[objID], obj)
+
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.CreateObject(obj)
+}
+
+func (d *hwvtepDriver) TaiRemoveObject(objID tai.ObjID, obj interface{}) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.RemoveObject(obj)
+}
+
+func (d *hwvtepDriver) TaiAddObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.AddObjectAttr(obj, attr)
+}
+
+func (d *hwvtepDriver) TaiDelObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.DelObjectAttr(obj, attr)
+}
+
+func (d *hwvtepDriver) TaiSetObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.SetObjectAttr(obj, attr)
+}
+
+func (d *hwvtepDriver) TaiGetObjectAttr(objID tai.ObjID, obj interface{},
+	attr []tai.ObjAttrID) (tai.Attrs, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.GetObjectAttr(obj, attr)
+}
+
+func (d *hwvtepDriver) TaiListObject(objID tai.ObjID) ([]interface{}, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.ListObject()
+}
+
+// capabilityAttrs attrs rendered into hardware_vtep, vxlan tunnel of
+// bridge is owned by the switch
+var capabilityAttrs = map[tai.ObjID][]tai.ObjAttrID{
+	tai.ObjectIDBridge:   {tai.BridgeAttrL2vni},
+	tai.ObjectIDL2Port:   {tai.L2portAttrVlanTag},
+	tai.ObjectIDFDB:      {tai.FdbAttrRemoteIP},
+	tai.ObjectIDTunnel:   {tai.TunnelAttrIpaddr},
+	tai.ObjectIDMcastFDB: {tai.McastFdbAttrLocators},
+}
+
+// TaiGetCapability objects of hardware_vtep schema, l3 objects and ACL
+// are not rendered
+func (d *hwvtepDriver) TaiGetCapability() tai.Capability {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	capability := tai.Capability{
+		Objects: make(map[tai.ObjID][]tai.ObjAttrID),
+	}
+	for objID := range d.ModuleAPIs {
+		capability.Objects[objID] = capabilityAttrs[objID]
+	}
+	return capability
+}
@@ -0,0 +1,108 @@
+package hwvtep
+
+import (
+	hwdb "github.com/cn-pmlabs/govtep/lib/odbapi/hardwarevtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type tunnelAPI struct {
+	moduleID int
+	*hwvtepDriver
+	// tunnel name to source ip
+	tunnels map[string]string
+}
+
+func newTunnelAPI(d *hwvtepDriver) *tunnelAPI {
+	return &tunnelAPI{
+		moduleID:     tai.ObjectIDTunnel,
+		hwvtepDriver: d,
+		tunnels:      make(map[string]string),
+	}
+}
+
+// tunnelIPSet replace source ip old by ip in tunnel_ips of switches of
+// driver instance, empty ip removes old only
+func (d *hwvtepDriver) tunnelIPSet(old string, ip string) error {
+	for _, tablePS := range d.physicalSwitches() {
+		psIndex := hwdb.PhysicalSwitchIndex{Name: tablePS.Name}
+		var exist bool
+		for _, tunnelIP := range tablePS.TunnelIps {
+			if tunnelIP == ip {
+				exist = true
+			}
+			if tunnelIP == old && old != ip {
+				if err := d.db().PhysicalSwitchUpdateTunnelIpsDelvalue(psIndex, []string{old}); err != nil {
+					return err
+				}
+			}
+		}
+		if ip == "" || exist {
+			continue
+		}
+		if err := d.db().PhysicalSwitchUpdateTunnelIpsAddvalue(psIndex, []string{ip}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *tunnelAPI) CreateObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	if objTunnel.Anycast {
+		log.Info("[Driver] Tunnel %s anycast source %s\n", objTunnel.Name, objTunnel.Ipaddr)
+	}
+	if err := v.tunnelIPSet(v.tunnels[objTunnel.Name], objTunnel.Ipaddr); err != nil {
+		return err
+	}
+	v.tunnels[objTunnel.Name] = objTunnel.Ipaddr
+	return nil
+}
+
+func (v *tunnelAPI) RemoveObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	ipaddr, ok := v.tunnels[objTunnel.Name]
+	if !ok {
+		return nil
+	}
+	delete(v.tunnels, objTunnel.Name)
+	return v.tunnelIPSet(ipaddr, "")
+}
+
+func (v *tunnelAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.SetObjectAttr(obj, attrs)
+}
+
+func (v *tunnelAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return nil
+}
+
+func (v *tunnelAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	old, ok := v.tunnels[objTunnel.Name]
+	if !ok {
+		log.Warning("[Driver] Tunnel %s not found when set attr\n", objTunnel.Name)
+		return nil
+	}
+	ipaddr := attrs.GetString(tai.TunnelAttrIpaddr)
+	if ipaddr == "" || ipaddr == old {
+		return nil
+	}
+	if err := v.tunnelIPSet(old, ipaddr); err != nil {
+		return err
+	}
+	v.tunnels[objTunnel.Name] = ipaddr
+	return nil
+}
+
+func (v *tunnelAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *tunnelAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
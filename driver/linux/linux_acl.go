@@ -0,0 +1,249 @@
+package linux
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+)
+
+// acl saved for nftables ruleset render
+type acl struct {
+	created bool
+	stage   string
+	aclType string
+	ports   []string
+	// rule sequence to rule attrs
+	rules map[int]tai.Attrs
+}
+
+type aclAPI struct {
+	moduleID int
+	*linuxDriver
+	acls map[string]*acl
+}
+
+func newAclAPI(d *linuxDriver) *aclAPI {
+	return &aclAPI{
+		moduleID:    tai.ObjectIDACL,
+		linuxDriver: d,
+		acls:        make(map[string]*acl),
+	}
+}
+
+var nftNameInvalidChar = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// getACL get saved acl, acl rule may be notified before acl
+func (v *aclAPI) getACL(name string) *acl {
+	a, ok := v.acls[name]
+	if !ok {
+		a = &acl{
+			rules: make(map[int]tai.Attrs),
+		}
+		v.acls[name] = a
+	}
+	return a
+}
+
+// aclFamily nftables family of acl type, L2 acl filter bridged packets
+func aclFamily(aclType string) (nftables.TableFamily, bool) {
+	switch aclType {
+	case vtepdb.ACLTypeL2:
+		return nftables.TableFamilyBridge, true
+	case vtepdb.ACLTypeL3, vtepdb.ACLTypeL3v6, "":
+		return nftables.TableFamilyINet, true
+	}
+	return nftables.TableFamilyUnspecified, false
+}
+
+func aclChainName(name string) string {
+	return "acl_" + nftNameInvalidChar.ReplaceAllString(name, "_")
+}
+
+// aclPortExprs jump to acl chain for packets in or out of port
+func aclPortExprs(stage string, port string, chain string) []expr.Any {
+	key := expr.MetaKeyIIFNAME
+	if stage == vtepdb.ACLStageEgress {
+		key = expr.MetaKeyOIFNAME
+	}
+	return []expr.Any{
+		&expr.Meta{Key: key, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ifnameData(port)},
+		&expr.Verdict{Kind: expr.VerdictJump, Chain: chain},
+	}
+}
+
+// ruleComment nftables rule comment in userdata tlv, shown by nft list
+func ruleComment(comment string) []byte {
+	data := append([]byte(comment), 0)
+	return append([]byte{0, byte(len(data))}, data...)
+}
+
+// render replace the whole govtep nftables tables atomically
+func (v *aclAPI) render() error {
+	families := map[nftables.TableFamily][]string{
+		nftables.TableFamilyBridge: nil,
+		nftables.TableFamilyINet:   nil,
+	}
+
+	for name, a := range v.acls {
+		if !a.created {
+			continue
+		}
+		family, ok := aclFamily(a.aclType)
+		if !ok {
+			log.Warning("[Driver] ACL %s type %s not supported\n", name, a.aclType)
+			continue
+		}
+		families[family] = append(families[family], name)
+	}
+
+	policy := nftables.ChainPolicyAccept
+	for _, family := range []nftables.TableFamily{nftables.TableFamilyBridge, nftables.TableFamilyINet} {
+		table := &nftables.Table{Family: family, Name: nftTableName}
+		// add before delete, so delete never fails
+		v.nft.AddTable(table)
+		v.nft.DelTable(table)
+		if len(families[family]) == 0 {
+			continue
+		}
+		sort.Strings(families[family])
+
+		v.nft.AddTable(table)
+		forward := v.nft.AddChain(&nftables.Chain{
+			Name:     "forward",
+			Table:    table,
+			Type:     nftables.ChainTypeFilter,
+			Hooknum:  nftables.ChainHookForward,
+			Priority: nftables.ChainPriorityFilter,
+			Policy:   &policy,
+		})
+
+		for _, name := range families[family] {
+			a := v.acls[name]
+			chain := v.nft.AddChain(&nftables.Chain{Name: aclChainName(name), Table: table})
+
+			sequences := make([]int, 0, len(a.rules))
+			for sequence := range a.rules {
+				sequences = append(sequences, sequence)
+			}
+			sort.Ints(sequences)
+			for _, sequence := range sequences {
+				exprs, err := aclRuleExprs(family, a.rules[sequence])
+				if err != nil {
+					log.Warning("[Driver] ACL %s rule %d invalid %v\n", name, sequence, err)
+					continue
+				}
+				v.nft.AddRule(&nftables.Rule{
+					Table:    table,
+					Chain:    chain,
+					Exprs:    exprs,
+					UserData: ruleComment("seq " + strconv.Itoa(sequence)),
+				})
+			}
+		}
+
+		for _, name := range families[family] {
+			a := v.acls[name]
+			for _, port := range a.ports {
+				v.nft.AddRule(&nftables.Rule{
+					Table: table,
+					Chain: forward,
+					Exprs: aclPortExprs(a.stage, port, aclChainName(name)),
+				})
+			}
+		}
+	}
+
+	if err := v.nft.Flush(); err != nil {
+		return fmt.Errorf("[Driver] ACL nftables flush failed: %v", err)
+	}
+	return nil
+}
+
+func (v *aclAPI) CreateObject(obj interface{}) error {
+	objACL := obj.(tai.ACLObj)
+
+	a := v.getACL(objACL.ACLName)
+	if a.created {
+		log.Info("[Driver] ACL %s already exist", objACL.ACLName)
+		return nil
+	}
+	a.created = true
+	a.aclType = vtepdb.ACLTypeL2
+	a.stage = vtepdb.ACLStageIngress
+	return nil
+}
+
+func (v *aclAPI) RemoveObject(obj interface{}) error {
+	objACL := obj.(tai.ACLObj)
+
+	delete(v.acls, objACL.ACLName)
+	return v.render()
+}
+
+func (v *aclAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACL := obj.(tai.ACLObj)
+
+	a, ok := v.acls[objACL.ACLName]
+	if !ok || !a.created {
+		return fmt.Errorf("[Driver] ACL %s not exist", objACL.ACLName)
+	}
+
+	for attr, attrValue := range attrs {
+		switch attr {
+		case tai.ACLAttrStage:
+			if stage, ok := attrValue.(string); ok {
+				a.stage = stage
+			}
+		case tai.ACLAttrType:
+			if aclType, ok := attrValue.(string); ok {
+				a.aclType = aclType
+			}
+		case tai.ACLAttrPorts:
+			if ports, ok := attrValue.(string); ok {
+				a.ports = strings.FieldsFunc(ports, func(r rune) bool {
+					return r == ',' || r == ' '
+				})
+			}
+		}
+	}
+	return v.render()
+}
+
+func (v *aclAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACL := obj.(tai.ACLObj)
+
+	a, ok := v.acls[objACL.ACLName]
+	if !ok {
+		return nil
+	}
+	if _, ok := attrs[tai.ACLAttrPorts]; ok {
+		a.ports = nil
+		return v.render()
+	}
+	return nil
+}
+
+func (v *aclAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *aclAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, ACLs are named by OVN ACL and not told from ACLs
+// configured on host
+func (v *aclAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,409 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+type aclRuleAPI struct {
+	moduleID int
+	*linuxDriver
+}
+
+func newAclRuleAPI(d *linuxDriver) *aclRuleAPI {
+	return &aclRuleAPI{
+		moduleID:    tai.ObjectIDACLRule,
+		linuxDriver: d,
+	}
+}
+
+func firstString(attrs tai.Attrs, id tai.ObjAttrID) (string, bool) {
+	values := attrs.GetStrings(id)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func firstInt(attrs tai.Attrs, id tai.ObjAttrID) (int, bool) {
+	values := attrs.GetInts(id)
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[0], true
+}
+
+// nftMatch expressions of nftables rule, every match loads packet field
+// into register 1 and compares it
+type nftMatch struct {
+	family nftables.TableFamily
+	exprs  []expr.Any
+	// dependencies added, eg: ip header for ip address match
+	deps map[string]bool
+}
+
+func (m *nftMatch) cmp(load expr.Any, data []byte) {
+	m.exprs = append(m.exprs, load, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: data})
+}
+
+// masked compare field under mask, eg: ip prefix and tcp flags
+func (m *nftMatch) masked(load expr.Any, mask []byte, data []byte) {
+	m.exprs = append(m.exprs, load, &expr.Bitwise{
+		SourceRegister: 1,
+		DestRegister:   1,
+		Len:            uint32(len(mask)),
+		Mask:           mask,
+		Xor:            make([]byte, len(mask)),
+	}, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: data})
+}
+
+func (m *nftMatch) inRange(load expr.Any, from []byte, to []byte) {
+	m.exprs = append(m.exprs, load, &expr.Range{Op: expr.CmpOpEq, Register: 1, FromData: from, ToData: to})
+}
+
+// depend add dependency match once, nft adds the same implicitly
+func (m *nftMatch) depend(key string, load expr.Any, data []byte) {
+	if m.deps[key] {
+		return
+	}
+	m.deps[key] = true
+	m.cmp(load, data)
+}
+
+func payload(base expr.PayloadBase, offset uint32, len uint32) expr.Any {
+	return &expr.Payload{DestRegister: 1, Base: base, Offset: offset, Len: len}
+}
+
+func meta(key expr.MetaKey) expr.Any {
+	return &expr.Meta{Key: key, Register: 1}
+}
+
+func be16(value int) []byte {
+	return binaryutil.BigEndian.PutUint16(uint16(value))
+}
+
+// ifnameData interface name compared by nftables, padded to IFNAMSIZ
+func ifnameData(name string) []byte {
+	data := make([]byte, unix.IFNAMSIZ)
+	copy(data, name)
+	return data
+}
+
+// etherDepend ethernet header, only bridge family has it for sure
+func (m *nftMatch) etherDepend() {
+	if m.family != nftables.TableFamilyBridge {
+		m.depend("ether", meta(expr.MetaKeyIIFTYPE), binaryutil.NativeEndian.PutUint16(unix.ARPHRD_ETHER))
+	}
+}
+
+// l3Depend network protocol of ip match
+func (m *nftMatch) l3Depend(ipv6 bool) error {
+	key, proto, etherType := "ip", unix.NFPROTO_IPV4, unix.ETH_P_IP
+	if ipv6 {
+		key, proto, etherType = "ip6", unix.NFPROTO_IPV6, unix.ETH_P_IPV6
+	}
+	other := "ip6"
+	if ipv6 {
+		other = "ip"
+	}
+	if m.deps[other] {
+		return fmt.Errorf("ip and ipv6 both matched")
+	}
+	if m.family == nftables.TableFamilyBridge {
+		m.depend(key, payload(expr.PayloadBaseLLHeader, 12, 2), be16(etherType))
+	} else {
+		m.depend(key, meta(expr.MetaKeyNFPROTO), []byte{byte(proto)})
+	}
+	return nil
+}
+
+// l4Depend l4 protocol of tcp flags or icmp match without protocol match
+func (m *nftMatch) l4Depend(protocol int) {
+	m.depend("l4proto", meta(expr.MetaKeyL4PROTO), []byte{byte(protocol)})
+}
+
+// ipPrefix ip prefix of ip and mask, mask is prefix length or dotted
+// decimal
+func ipPrefix(ip string, mask string) (*net.IPNet, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("invalid ip %s", ip)
+	}
+	bits := 128
+	if addr.To4() != nil {
+		addr, bits = addr.To4(), 32
+	}
+	ones := bits
+	if mask != "" {
+		if n, err := strconv.Atoi(mask); err == nil {
+			ones = n
+		} else {
+			maskIP := net.ParseIP(mask)
+			if maskIP == nil || maskIP.To4() == nil {
+				return nil, fmt.Errorf("invalid mask %s", mask)
+			}
+			n, maskBits := net.IPMask(maskIP.To4()).Size()
+			if maskBits == 0 {
+				return nil, fmt.Errorf("invalid mask %s", mask)
+			}
+			ones = n
+		}
+	}
+	if ones < 0 || ones > bits {
+		return nil, fmt.Errorf("invalid mask %s", mask)
+	}
+	ipMask := net.CIDRMask(ones, bits)
+	return &net.IPNet{IP: addr.Mask(ipMask), Mask: ipMask}, nil
+}
+
+// portRange source or destination port range, min or max only is single
+// port
+func portRange(attrs tai.Attrs, minAttr tai.ObjAttrID, maxAttr tai.ObjAttrID) (int, int, bool) {
+	min, okMin := firstInt(attrs, minAttr)
+	max, okMax := firstInt(attrs, maxAttr)
+	switch {
+	case okMin && okMax:
+		return min, max, true
+	case okMin:
+		return min, min, true
+	case okMax:
+		return max, max, true
+	}
+	return 0, 0, false
+}
+
+// etherTypes ethertype names used by acl rules
+var etherTypes = map[string]int{
+	"ip":   unix.ETH_P_IP,
+	"ipv4": unix.ETH_P_IP,
+	"ip6":  unix.ETH_P_IPV6,
+	"ipv6": unix.ETH_P_IPV6,
+	"arp":  unix.ETH_P_ARP,
+	"vlan": unix.ETH_P_8021Q,
+}
+
+func etherType(value string) (int, error) {
+	if ethertype, ok := etherTypes[strings.ToLower(value)]; ok {
+		return ethertype, nil
+	}
+	ethertype, err := strconv.ParseUint(value, 0, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ethertype %s", value)
+	}
+	return int(ethertype), nil
+}
+
+// aclRuleExprs nftables rule expressions of acl rule attrs
+func aclRuleExprs(family nftables.TableFamily, attrs tai.Attrs) ([]expr.Any, error) {
+	m := &nftMatch{family: family, deps: make(map[string]bool)}
+
+	for _, macAttr := range []struct {
+		attr   tai.ObjAttrID
+		offset uint32
+	}{
+		{tai.ACLRuleAttrMatchSRCMAC, 6},
+		{tai.ACLRuleAttrMatchDSTMAC, 0},
+	} {
+		value, ok := firstString(attrs, macAttr.attr)
+		if !ok {
+			continue
+		}
+		mac, err := net.ParseMAC(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mac %s", value)
+		}
+		m.etherDepend()
+		m.cmp(payload(expr.PayloadBaseLLHeader, macAttr.offset, 6), mac)
+	}
+	if value, ok := firstString(attrs, tai.ACLRuleAttrMatchETHERTYPE); ok {
+		ethertype, err := etherType(value)
+		if err != nil {
+			return nil, err
+		}
+		if family == nftables.TableFamilyBridge {
+			m.cmp(payload(expr.PayloadBaseLLHeader, 12, 2), be16(ethertype))
+		} else {
+			m.cmp(meta(expr.MetaKeyPROTOCOL), be16(ethertype))
+		}
+	}
+
+	ipv6 := false
+	for _, ipAttr := range []struct {
+		ip, mask tai.ObjAttrID
+		offset   uint32
+		offset6  uint32
+	}{
+		{tai.ACLRuleAttrMatchSRCIP, tai.ACLRuleAttrMatchSRCMASK, 12, 8},
+		{tai.ACLRuleAttrMatchDSTIP, tai.ACLRuleAttrMatchDSTMASK, 16, 24},
+	} {
+		ip, ok := firstString(attrs, ipAttr.ip)
+		if !ok {
+			continue
+		}
+		mask, _ := firstString(attrs, ipAttr.mask)
+		prefix, err := ipPrefix(ip, mask)
+		if err != nil {
+			return nil, err
+		}
+		ipv6 = len(prefix.IP) == net.IPv6len
+		if err := m.l3Depend(ipv6); err != nil {
+			return nil, err
+		}
+		offset := ipAttr.offset
+		if ipv6 {
+			offset = ipAttr.offset6
+		}
+		load := payload(expr.PayloadBaseNetworkHeader, offset, uint32(len(prefix.IP)))
+		if ones, bits := prefix.Mask.Size(); ones == bits {
+			m.cmp(load, prefix.IP)
+		} else {
+			m.masked(load, prefix.Mask, prefix.IP)
+		}
+	}
+
+	protocol, hasProtocol := firstInt(attrs, tai.ACLRuleAttrMatchPROTOCOL)
+	if hasProtocol {
+		m.l4Depend(protocol)
+	}
+	for _, portAttr := range []struct {
+		min, max tai.ObjAttrID
+		offset   uint32
+	}{
+		{tai.ACLRuleAttrMatchSRCPORTMIN, tai.ACLRuleAttrMatchSRCPORTMAX, 0},
+		{tai.ACLRuleAttrMatchDSTPORTMIN, tai.ACLRuleAttrMatchDSTPORTMAX, 2},
+	} {
+		min, max, ok := portRange(attrs, portAttr.min, portAttr.max)
+		if !ok {
+			continue
+		}
+		load := payload(expr.PayloadBaseTransportHeader, portAttr.offset, 2)
+		if min == max {
+			m.cmp(load, be16(min))
+		} else {
+			m.inRange(load, be16(min), be16(max))
+		}
+	}
+
+	if flags, ok := firstInt(attrs, tai.ACLRuleAttrMatchTCPFLAGS); ok {
+		mask, ok := firstInt(attrs, tai.ACLRuleAttrMatchTCPFLAGSMASK)
+		if !ok {
+			mask = flags
+		}
+		m.l4Depend(unix.IPPROTO_TCP)
+		m.masked(payload(expr.PayloadBaseTransportHeader, 13, 1), []byte{byte(mask)}, []byte{byte(flags & mask)})
+	}
+
+	icmp := unix.IPPROTO_ICMP
+	if ipv6 {
+		icmp = unix.IPPROTO_ICMPV6
+	}
+	for _, icmpAttr := range []struct {
+		attr   tai.ObjAttrID
+		offset uint32
+	}{
+		{tai.ACLRuleAttrMatchICMPTYPE, 0},
+		{tai.ACLRuleAttrMatchICMPCODE, 1},
+	} {
+		value, ok := firstInt(attrs, icmpAttr.attr)
+		if !ok {
+			continue
+		}
+		m.l4Depend(icmp)
+		m.cmp(payload(expr.PayloadBaseTransportHeader, icmpAttr.offset, 1), []byte{byte(value)})
+	}
+
+	action := attrs.GetString(tai.ACLRuleAttrAction)
+	switch action {
+	case vtepdb.ACLRuleActionPermit:
+		m.exprs = append(m.exprs, &expr.Verdict{Kind: expr.VerdictAccept})
+	case vtepdb.ACLRuleActionDeny:
+		m.exprs = append(m.exprs, &expr.Verdict{Kind: expr.VerdictDrop})
+	default:
+		return nil, fmt.Errorf("invalid action %q", action)
+	}
+
+	return m.exprs, nil
+}
+
+func (v *aclRuleAPI) CreateObject(obj interface{}) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	a := v.aclModule.getACL(objACLRule.ACLName)
+	a.rules[objACLRule.Sequence] = make(tai.Attrs)
+	return nil
+}
+
+func (v *aclRuleAPI) RemoveObject(obj interface{}) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	a, ok := v.aclModule.acls[objACLRule.ACLName]
+	if !ok {
+		return nil
+	}
+	delete(a.rules, objACLRule.Sequence)
+	if !a.created {
+		return nil
+	}
+	return v.aclModule.render()
+}
+
+func (v *aclRuleAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	a := v.aclModule.getACL(objACLRule.ACLName)
+	rule, ok := a.rules[objACLRule.Sequence]
+	if !ok {
+		return fmt.Errorf("[Driver] ACL %s rule %d not exist", objACLRule.ACLName, objACLRule.Sequence)
+	}
+	for attr, value := range attrs {
+		rule[attr] = value
+	}
+	if !a.created {
+		return nil
+	}
+	return v.aclModule.render()
+}
+
+func (v *aclRuleAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	a, ok := v.aclModule.acls[objACLRule.ACLName]
+	if !ok {
+		return nil
+	}
+	rule, ok := a.rules[objACLRule.Sequence]
+	if !ok {
+		return nil
+	}
+	for attr := range attrs {
+		delete(rule, attr)
+	}
+	if !a.created {
+		return nil
+	}
+	return v.aclModule.render()
+}
+
+func (v *aclRuleAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *aclRuleAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, rules are listed with ACLs
+func (v *aclRuleAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
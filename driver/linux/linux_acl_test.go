@@ -0,0 +1,88 @@
+package linux
+
+import (
+	"testing"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/google/nftables"
+)
+
+func TestACL(t *testing.T) {
+	d, _ := testDriver(t)
+	if d.nft == nil {
+		t.Skip("nftables not supported")
+	}
+
+	objACL := tai.ACLObj{ACLName: "acl-1"}
+	rules := []tai.Attrs{
+		{
+			tai.ACLRuleAttrMatchSRCIP:      []string{"10.0.0.0"},
+			tai.ACLRuleAttrMatchSRCMASK:    []string{"255.255.255.0"},
+			tai.ACLRuleAttrMatchPROTOCOL:   []int{6},
+			tai.ACLRuleAttrMatchDSTPORTMIN: []int{80},
+			tai.ACLRuleAttrMatchDSTPORTMAX: []int{90},
+			tai.ACLRuleAttrMatchTCPFLAGS:   []int{0x02},
+			tai.ACLRuleAttrAction:          vtepdb.ACLRuleActionDeny,
+		},
+		{
+			tai.ACLRuleAttrMatchSRCMAC:   []string{"52:54:00:00:00:01"},
+			tai.ACLRuleAttrMatchDSTIP:    []string{"2001:db8::1"},
+			tai.ACLRuleAttrMatchICMPTYPE: []int{128},
+			tai.ACLRuleAttrAction:        vtepdb.ACLRuleActionPermit,
+		},
+		{
+			// invalid rule is skipped
+			tai.ACLRuleAttrMatchSRCIP: []string{"10.0.0.300"},
+			tai.ACLRuleAttrAction:     vtepdb.ACLRuleActionDeny,
+		},
+	}
+	for i, attrs := range rules {
+		objRule := tai.ACLRuleObj{ACLName: objACL.ACLName, Sequence: i + 1}
+		if err := d.TaiCreateObject(tai.ObjectIDACLRule, objRule); err != nil {
+			t.Fatalf("acl rule %d create failed %v", i+1, err)
+		}
+		if err := d.TaiAddObjectAttr(tai.ObjectIDACLRule, objRule, attrs); err != nil {
+			t.Fatalf("acl rule %d attrs add failed %v", i+1, err)
+		}
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDACL, objACL); err != nil {
+		t.Fatalf("acl create failed %v", err)
+	}
+	err := d.TaiAddObjectAttr(tai.ObjectIDACL, objACL, tai.Attrs{
+		tai.ACLAttrType:  vtepdb.ACLTypeL3,
+		tai.ACLAttrStage: vtepdb.ACLStageIngress,
+		tai.ACLAttrPorts: "eth1,eth2",
+	})
+	if err != nil {
+		t.Fatalf("acl attrs add failed %v", err)
+	}
+
+	table := &nftables.Table{Family: nftables.TableFamilyINet, Name: nftTableName}
+	forward, err := d.nft.GetRules(table, &nftables.Chain{Name: "forward", Table: table})
+	if err != nil || len(forward) != 2 {
+		t.Errorf("forward chain rules %d err %v", len(forward), err)
+	}
+	aclRules, err := d.nft.GetRules(table, &nftables.Chain{Name: aclChainName(objACL.ACLName), Table: table})
+	if err != nil || len(aclRules) != 2 {
+		t.Errorf("acl chain rules %d err %v", len(aclRules), err)
+	}
+
+	// acl moved to bridge table with its type
+	if err := d.TaiSetObjectAttr(tai.ObjectIDACL, objACL, tai.Attrs{tai.ACLAttrType: vtepdb.ACLTypeL2}); err != nil {
+		t.Fatalf("acl type set failed %v", err)
+	}
+	tables, err := d.nft.ListTables()
+	if err != nil || len(tables) != 1 || tables[0].Family != nftables.TableFamilyBridge {
+		t.Errorf("tables %v err %v", tables, err)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDACL, objACL); err != nil {
+		t.Fatalf("acl remove failed %v", err)
+	}
+	if tables, err := d.nft.ListTables(); err != nil || len(tables) != 0 {
+		t.Errorf("tables not removed %v err %v", tables, err)
+	}
+}
@@ -0,0 +1,249 @@
+package linux
+
+import (
+	"net"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+// autoGatewayState uplinks and default route nexthops programmed of vrf
+type autoGatewayState struct {
+	uplinks  []tai.AutoGatewayUplink
+	nexthops []string
+}
+
+type autoGatewayConfAPI struct {
+	moduleID int
+	*linuxDriver
+	// vrf name to its auto gateway conf, columns not in obj are from attrs
+	confs map[string]vtepdb.TableAutoGatewayConf
+	// vrf name to uplinks and routes programmed
+	states map[string]autoGatewayState
+}
+
+func newAutoGatewayConfAPI(d *linuxDriver) *autoGatewayConfAPI {
+	return &autoGatewayConfAPI{
+		moduleID:    tai.ObjectIDAutoGatewayConf,
+		linuxDriver: d,
+		confs:       make(map[string]vtepdb.TableAutoGatewayConf),
+		states:      make(map[string]autoGatewayState),
+	}
+}
+
+// autoGatewayUplinkAdd vlan sub interface is created on port, routed port
+// or lag is moved into vrf
+func (d *linuxDriver) autoGatewayUplinkAdd(vrf string, uplink tai.AutoGatewayUplink, mtu int) error {
+	if uplink.Vlan != 0 {
+		if err := d.vlanLinkAdd(uplink.Name, uplink.Port, []int{uplink.Vlan}); err != nil {
+			return err
+		}
+	}
+	if err := d.linkSetMaster(uplink.Name, vrf); err != nil {
+		return err
+	}
+	if mtu != 0 {
+		if err := d.linkSetMtu(uplink.Name, mtu); err != nil {
+			return err
+		}
+	}
+	for _, ip := range uplink.IPs {
+		if err := d.addrReplace(uplink.Name, ip); err != nil {
+			return err
+		}
+	}
+	return d.linkUp(uplink.Name)
+}
+
+// autoGatewayUplinkDel vlan sub interface is deleted, routed port is left
+// to its owner without addresses of uplink
+func (d *linuxDriver) autoGatewayUplinkDel(uplink tai.AutoGatewayUplink) error {
+	if uplink.Vlan != 0 {
+		return d.linkDel(uplink.Name)
+	}
+	if !d.linkExist(uplink.Name) {
+		return nil
+	}
+	for _, ip := range uplink.IPs {
+		if err := d.addrDel(uplink.Name, ip); err != nil {
+			log.Warning("[Driver] uplink %s del addr %s failed %v\n", uplink.Name, ip, err)
+		}
+	}
+	return d.linkSetNoMaster(uplink.Name)
+}
+
+// autoGatewayRouteSet ECMP default routes of vrf over nexthops, default
+// route of address family is removed only if it was set by uplink
+func (d *linuxDriver) autoGatewayRouteSet(vrf string, nexthops []string, oldNexthops []string) error {
+	table, err := d.routeTable(vrf)
+	if err != nil {
+		return err
+	}
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		var multipath []*netlink.NexthopInfo
+		for _, nh := range nexthops {
+			if ip := net.ParseIP(nh); ip != nil && ipFamily(ip) == family {
+				multipath = append(multipath, &netlink.NexthopInfo{Gw: ip})
+			}
+		}
+		route := &netlink.Route{Dst: defaultDst(family), Table: table}
+		if len(multipath) != 0 {
+			route.MultiPath = multipath
+			if err := d.handle.RouteReplace(route); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, nh := range oldNexthops {
+			if ip := net.ParseIP(nh); ip != nil && ipFamily(ip) == family {
+				if err := d.handle.RouteDel(route); err != nil {
+					log.Warning("[Driver] vrf %s del default route failed %v\n", vrf, err)
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// sync program uplinks and default routes of vrf by its auto gateway
+// conf, uplinks and addresses not configured any more are removed
+func (v *autoGatewayConfAPI) sync(vrf string) error {
+	conf := v.confs[vrf]
+	uplinks := tai.AutoGatewayUplinks(conf)
+	state := v.states[vrf]
+
+	for _, old := range state.uplinks {
+		var uplink *tai.AutoGatewayUplink
+		for i := range uplinks {
+			if uplinks[i].Name == old.Name {
+				uplink = &uplinks[i]
+				break
+			}
+		}
+		if uplink == nil {
+			if err := v.autoGatewayUplinkDel(old); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, ip := range old.IPs {
+			if hasField(uplink.IPs, ip) {
+				continue
+			}
+			if err := v.addrDel(old.Name, ip); err != nil {
+				log.Warning("[Driver] uplink %s del addr %s failed %v\n", old.Name, ip, err)
+			}
+		}
+	}
+
+	mtu := 0
+	if len(conf.Mtu) > 0 {
+		mtu = conf.Mtu[0]
+	}
+	for _, uplink := range uplinks {
+		if err := v.autoGatewayUplinkAdd(vrf, uplink, mtu); err != nil {
+			log.Warning("[Driver] vrf %s uplink %s add failed %v\n", vrf, uplink.Name, err)
+			return err
+		}
+	}
+
+	var nexthops []string
+	if len(uplinks) > 0 {
+		nexthops = conf.Nexthops
+	}
+	if err := v.autoGatewayRouteSet(vrf, nexthops, state.nexthops); err != nil {
+		return err
+	}
+	v.states[vrf] = autoGatewayState{uplinks: uplinks, nexthops: nexthops}
+	return nil
+}
+
+// confSet auto gateway conf of vrf with obj and attrs added or set
+func (v *autoGatewayConfAPI) confSet(objConf tai.AutoGatewayConfObj, attrs tai.Attrs) {
+	conf := v.confs[objConf.Vrf]
+	conf.Vrf = objConf.Vrf
+	conf.Bdname = objConf.Bdname
+	conf.PhysicalPort = objConf.PhysicalPort
+	conf.Vlan = objConf.Vlan
+	conf.IP = objConf.IP
+	if attrs.Has(tai.AutoGatewayConfAttrIpv6) {
+		conf.Ipv6 = attrs.GetString(tai.AutoGatewayConfAttrIpv6)
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrMtu) {
+		conf.Mtu = []int{attrs.GetInt(tai.AutoGatewayConfAttrMtu)}
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrNexthops) {
+		conf.Nexthops = attrs.GetStrings(tai.AutoGatewayConfAttrNexthops)
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrUplinks) {
+		conf.Uplinks = make(map[interface{}]interface{})
+		for name, ips := range attrs.GetStringMap(tai.AutoGatewayConfAttrUplinks) {
+			conf.Uplinks[name] = ips
+		}
+	}
+	v.confs[objConf.Vrf] = conf
+}
+
+func (v *autoGatewayConfAPI) CreateObject(obj interface{}) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	v.confSet(objConf, nil)
+	return v.sync(objConf.Vrf)
+}
+
+func (v *autoGatewayConfAPI) RemoveObject(obj interface{}) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	delete(v.confs, objConf.Vrf)
+	if err := v.sync(objConf.Vrf); err != nil {
+		return err
+	}
+	delete(v.states, objConf.Vrf)
+	return nil
+}
+
+func (v *autoGatewayConfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	v.confSet(objConf, attrs)
+	return v.sync(objConf.Vrf)
+}
+
+func (v *autoGatewayConfAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	v.confSet(objConf, nil)
+	conf := v.confs[objConf.Vrf]
+	if attrs.Has(tai.AutoGatewayConfAttrIpv6) {
+		conf.Ipv6 = ""
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrMtu) {
+		conf.Mtu = nil
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrNexthops) {
+		conf.Nexthops = nil
+	}
+	if attrs.Has(tai.AutoGatewayConfAttrUplinks) {
+		conf.Uplinks = nil
+	}
+	v.confs[objConf.Vrf] = conf
+	return v.sync(objConf.Vrf)
+}
+
+func (v *autoGatewayConfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *autoGatewayConfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, auto gateway conf is loaded from uplink config
+func (v *autoGatewayConfAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,128 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+type bridgeAPI struct {
+	moduleID int
+	*linuxDriver
+	// bridge name to vxlan tunnel name
+	tunnels map[string]string
+}
+
+func newBridgeAPI(d *linuxDriver) *bridgeAPI {
+	return &bridgeAPI{
+		moduleID:    tai.ObjectIDBridge,
+		linuxDriver: d,
+		tunnels:     make(map[string]string),
+	}
+}
+
+func (d *bridgeAPI) CreateObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	if getVniByName(objBridge.Name, bridgeNamePrefix) == 0 {
+		return fmt.Errorf("[Driver] Invalid bridge name %s", objBridge.Name)
+	}
+
+	if err := d.bridgeLinkAdd(objBridge.Name); err != nil {
+		return err
+	}
+	return d.linkUp(objBridge.Name)
+}
+
+func (d *bridgeAPI) RemoveObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	vni := getVniByName(objBridge.Name, bridgeNamePrefix)
+	if err := d.linkDel(vxlanName(vni)); err != nil {
+		return err
+	}
+	delete(d.tunnels, objBridge.Name)
+	return d.linkDel(objBridge.Name)
+}
+
+func (d *bridgeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	if !d.linkExist(objBridge.Name) {
+		log.Warning("[Driver] BD %s not exist\n", objBridge.Name)
+		return fmt.Errorf("[Driver] BD %s not exist", objBridge.Name)
+	}
+
+	if tunnelName := attrs.GetString(tai.BridgeAttrVxlanTunnel); tunnelName != "" {
+		vni := getVniByName(objBridge.Name, bridgeNamePrefix)
+		if err := d.vxlanLinkAdd(objBridge.Name, vni, tunnelName); err != nil {
+			log.Warning("[Driver] BD %s tunnel %s add failed %v\n", objBridge.Name, tunnelName, err)
+			return err
+		}
+		d.tunnels[objBridge.Name] = tunnelName
+	}
+
+	return nil
+}
+
+func (d *bridgeAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (d *bridgeAPI) SetObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (d *bridgeAPI) GetObjectAttr(obj interface{}, attrIDs []tai.ObjAttrID) (tai.Attrs, error) {
+	attrs := make(tai.Attrs)
+	objBridge := obj.(tai.BridgeObj)
+
+	if !d.linkExist(objBridge.Name) {
+		return nil, fmt.Errorf("[Driver] BD %s not exist", objBridge.Name)
+	}
+
+	for _, attr := range attrIDs {
+		switch attr {
+		case tai.BridgeAttrL2vni:
+			attrs[attr] = getVniByName(objBridge.Name, bridgeNamePrefix)
+		case tai.BridgeAttrVxlanTunnel:
+			attrs[attr] = d.tunnels[objBridge.Name]
+		}
+	}
+
+	return attrs, nil
+}
+
+// bridgeLinks bridges named "Bd"+vni
+func (d *linuxDriver) bridgeLinks() ([]netlink.Link, error) {
+	links, err := d.linkList()
+	if err != nil {
+		return nil, err
+	}
+
+	var bridges []netlink.Link
+	for _, link := range links {
+		if _, ok := link.(*netlink.Bridge); ok && getVniByName(link.Attrs().Name, bridgeNamePrefix) != 0 {
+			bridges = append(bridges, link)
+		}
+	}
+	return bridges, nil
+}
+
+// ListObject bridges named "Bd"+vni, vxlan tunnel of bridge is set by attr
+func (d *bridgeAPI) ListObject() ([]interface{}, error) {
+	bridges, err := d.bridgeLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for _, link := range bridges {
+		name := link.Attrs().Name
+		objs = append(objs, tai.BridgeObj{Name: name, Vni: getVniByName(name, bridgeNamePrefix)})
+	}
+	return objs, nil
+}
@@ -0,0 +1,486 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	vniMin = 1
+	vniMax = 16777215
+)
+
+const (
+	vxlanDefaultDestPort = 4789
+	interfaceDefaultMtu  = 9000
+)
+
+// kernel route table id, vrf table is vrfTableOffset+vni, pbr tables
+// are allocated after pbrTableOffset which is above all vrf tables
+const (
+	vrfTableOffset = 1000
+	pbrTableOffset = vrfTableOffset + vniMax + 1
+)
+
+// pbr ip rules are before l3mdev rule(priority 1000)
+const pbrRulePriority = 900
+
+// nftables table name of ACL
+const nftTableName = "govtep"
+
+// l3 bridge of vrf holds vxlan device of l3vni for symmetric IRB
+const (
+	bridgeNamePrefix   = "Bd"
+	vrfNamePrefix      = "Vrf"
+	vxlanNamePrefix    = "vxlan"
+	l3BridgeNamePrefix = "Br"
+)
+
+func (d *linuxDriver) linkByName(name string) (netlink.Link, error) {
+	link, err := d.handle.LinkByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] link %s: %v", name, err)
+	}
+	return link, nil
+}
+
+func (d *linuxDriver) linkExist(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := d.handle.LinkByName(name)
+	return err == nil
+}
+
+// linkAdd create link if not exist
+func (d *linuxDriver) linkAdd(link netlink.Link) error {
+	name := link.Attrs().Name
+	if d.linkExist(name) {
+		log.Info("[Driver] %s %s already exist\n", link.Type(), name)
+		return nil
+	}
+	if err := d.handle.LinkAdd(link); err != nil {
+		return fmt.Errorf("[Driver] %s %s add: %v", link.Type(), name, err)
+	}
+	return nil
+}
+
+func (d *linuxDriver) bridgeLinkAdd(name string) error {
+	return d.linkAdd(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}})
+}
+
+func (d *linuxDriver) linkUp(name string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkSetUp(link)
+}
+
+func (d *linuxDriver) linkDel(name string) error {
+	if !d.linkExist(name) {
+		return nil
+	}
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkDel(link)
+}
+
+// linkList links of netns of driver instance
+func (d *linuxDriver) linkList() ([]netlink.Link, error) {
+	links, err := d.handle.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] link list: %v", err)
+	}
+	return links, nil
+}
+
+// linkIndexes links by link index
+func linkIndexes(links []netlink.Link) map[int]netlink.Link {
+	indexes := make(map[int]netlink.Link)
+	for _, link := range links {
+		indexes[link.Attrs().Index] = link
+	}
+	return indexes
+}
+
+// linkMaster name of master of link, empty if link has no master
+func linkMaster(link netlink.Link, indexes map[int]netlink.Link) string {
+	master, ok := indexes[link.Attrs().MasterIndex]
+	if !ok {
+		return ""
+	}
+	return master.Attrs().Name
+}
+
+// vlanLinkParent physical parent port of vlan sub interface, lower link
+// of stacked vlans is the outer vlan interface
+func vlanLinkParent(link netlink.Link, indexes map[int]netlink.Link) string {
+	for {
+		vlan, ok := link.(*netlink.Vlan)
+		if !ok {
+			return link.Attrs().Name
+		}
+		if link, ok = indexes[vlan.ParentIndex]; !ok {
+			return ""
+		}
+	}
+}
+
+// remoteFdbList fdb entries of vxlan device pointing to remote vteps
+func (d *linuxDriver) remoteFdbList(dev string) ([]netlink.Neigh, error) {
+	link, err := d.linkByName(dev)
+	if err != nil {
+		return nil, err
+	}
+	neighs, err := d.handle.NeighList(link.Attrs().Index, unix.AF_BRIDGE)
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] fdb list of %s: %v", dev, err)
+	}
+
+	var fdbs []netlink.Neigh
+	for _, neigh := range neighs {
+		if neigh.Flags&netlink.NTF_SELF != 0 && neigh.IP != nil && neigh.HardwareAddr != nil {
+			fdbs = append(fdbs, neigh)
+		}
+	}
+	return fdbs, nil
+}
+
+func (d *linuxDriver) linkSetMaster(name string, master string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	masterLink, err := d.linkByName(master)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkSetMaster(link, masterLink)
+}
+
+func (d *linuxDriver) linkSetNoMaster(name string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkSetNoMaster(link)
+}
+
+func (d *linuxDriver) linkSetMac(name string, mac string) error {
+	hwaddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return fmt.Errorf("[Driver] link %s invalid mac %s", name, mac)
+	}
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkSetHardwareAddr(link, hwaddr)
+}
+
+func (d *linuxDriver) linkSetMtu(name string, mtu int) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	return d.handle.LinkSetMTU(link, mtu)
+}
+
+// vlanLinkAdd create vlan sub interface, two tags create stacked vlans
+// parent.outer.inner
+func (d *linuxDriver) vlanLinkAdd(name string, parent string, tags []int) error {
+	if d.linkExist(name) {
+		log.Info("[Driver] vlan interface %s already exist\n", name)
+		return nil
+	}
+
+	switch len(tags) {
+	case 1:
+		return d.vlanLinkAddOne(name, parent, tags[0], netlink.VLAN_PROTOCOL_8021Q)
+	case 2:
+		outer := parent + "." + strconv.Itoa(tags[0])
+		if !d.linkExist(outer) {
+			if err := d.vlanLinkAddOne(outer, parent, tags[0], netlink.VLAN_PROTOCOL_8021AD); err != nil {
+				return err
+			}
+			if err := d.linkUp(outer); err != nil {
+				return err
+			}
+		}
+		return d.vlanLinkAddOne(name, outer, tags[1], netlink.VLAN_PROTOCOL_8021Q)
+	}
+	return fmt.Errorf("[Driver] invalid vlan tags %v for %s", tags, name)
+}
+
+func (d *linuxDriver) vlanLinkAddOne(name string, parent string, tag int, protocol netlink.VlanProtocol) error {
+	parentLink, err := d.linkByName(parent)
+	if err != nil {
+		return err
+	}
+	return d.linkAdd(&netlink.Vlan{
+		LinkAttrs:    netlink.LinkAttrs{Name: name, ParentIndex: parentLink.Attrs().Index},
+		VlanId:       tag,
+		VlanProtocol: protocol,
+	})
+}
+
+// parsePrefix ip prefix, host prefix if length not set
+func parsePrefix(prefix string) (*net.IPNet, error) {
+	_, ipnet, err := net.ParseCIDR(l3portAddr(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] invalid prefix %s", prefix)
+	}
+	return ipnet, nil
+}
+
+// parseAddr interface address with its prefix length
+func parseAddr(ipaddr string) (*netlink.Addr, error) {
+	ip, ipnet, err := net.ParseCIDR(l3portAddr(ipaddr))
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] invalid address %s", ipaddr)
+	}
+	return &netlink.Addr{IPNet: &net.IPNet{IP: ip, Mask: ipnet.Mask}}, nil
+}
+
+// l3portAddr address with prefix length, host address if not set
+func l3portAddr(ipaddr string) string {
+	if strings.Contains(ipaddr, "/") {
+		return ipaddr
+	}
+	if strings.Contains(ipaddr, ":") {
+		return ipaddr + "/128"
+	}
+	return ipaddr + "/32"
+}
+
+func (d *linuxDriver) addrReplace(name string, ipaddr string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	addr, err := parseAddr(ipaddr)
+	if err != nil {
+		return err
+	}
+	return d.handle.AddrReplace(link, addr)
+}
+
+func (d *linuxDriver) addrDel(name string, ipaddr string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	addr, err := parseAddr(ipaddr)
+	if err != nil {
+		return err
+	}
+	return d.handle.AddrDel(link, addr)
+}
+
+// addrFlush remove all addresses of link
+func (d *linuxDriver) addrFlush(name string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	addrs, err := d.handle.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	for i := range addrs {
+		if err := d.handle.AddrDel(link, &addrs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// neighReplace static neighbour of ip on link, it's never probed
+func (d *linuxDriver) neighReplace(ipaddr string, mac string, name string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(ipaddr)
+	hwaddr, err := net.ParseMAC(mac)
+	if ip == nil || err != nil {
+		return fmt.Errorf("[Driver] invalid neighbour %s mac %s", ipaddr, mac)
+	}
+	return d.handle.NeighSet(&netlink.Neigh{
+		LinkIndex:    link.Attrs().Index,
+		Family:       ipFamily(ip),
+		State:        netlink.NUD_NOARP,
+		IP:           ip,
+		HardwareAddr: hwaddr,
+	})
+}
+
+func (d *linuxDriver) neighDel(ipaddr string, name string) error {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(ipaddr)
+	if ip == nil {
+		return fmt.Errorf("[Driver] invalid neighbour %s", ipaddr)
+	}
+	return d.handle.NeighDel(&netlink.Neigh{
+		LinkIndex: link.Attrs().Index,
+		Family:    ipFamily(ip),
+		IP:        ip,
+	})
+}
+
+// fdbEntry bridge fdb entry of mac on link, flags is NTF_MASTER for entry
+// in master bridge or NTF_SELF for entry in vxlan device with remote ip
+func (d *linuxDriver) fdbEntry(mac string, name string, remoteIP string, flags int) (*netlink.Neigh, error) {
+	link, err := d.linkByName(name)
+	if err != nil {
+		return nil, err
+	}
+	hwaddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] invalid fdb mac %s", mac)
+	}
+	entry := &netlink.Neigh{
+		LinkIndex:    link.Attrs().Index,
+		Family:       unix.AF_BRIDGE,
+		State:        netlink.NUD_NOARP | netlink.NUD_REACHABLE,
+		Flags:        flags,
+		HardwareAddr: hwaddr,
+	}
+	if remoteIP != "" {
+		if entry.IP = net.ParseIP(remoteIP); entry.IP == nil {
+			return nil, fmt.Errorf("[Driver] invalid fdb remote ip %s", remoteIP)
+		}
+	}
+	return entry, nil
+}
+
+// fdbReplace static fdb entry, it's never aged
+func (d *linuxDriver) fdbReplace(mac string, name string, remoteIP string, flags int) error {
+	entry, err := d.fdbEntry(mac, name, remoteIP, flags)
+	if err != nil {
+		return err
+	}
+	return d.handle.NeighSet(entry)
+}
+
+// fdbAppend permanent fdb entry of another remote ip of mac, used by
+// flood list of vxlan device
+func (d *linuxDriver) fdbAppend(mac string, name string, remoteIP string) error {
+	entry, err := d.fdbEntry(mac, name, remoteIP, netlink.NTF_SELF)
+	if err != nil {
+		return err
+	}
+	entry.State = netlink.NUD_NOARP | netlink.NUD_PERMANENT
+	return d.handle.NeighAppend(entry)
+}
+
+// fdbDel delete fdb entry, all remote ips of mac are deleted if remote
+// ip is empty
+func (d *linuxDriver) fdbDel(mac string, name string, remoteIP string, flags int) error {
+	entry, err := d.fdbEntry(mac, name, remoteIP, flags)
+	if err != nil {
+		return err
+	}
+	if remoteIP != "" || flags&netlink.NTF_SELF == 0 {
+		return d.handle.NeighDel(entry)
+	}
+
+	// vxlan device needs remote ip of every entry to delete
+	entries, err := d.handle.NeighList(entry.LinkIndex, unix.AF_BRIDGE)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].Flags&netlink.NTF_SELF == 0 || entries[i].IP == nil ||
+			entries[i].HardwareAddr.String() != entry.HardwareAddr.String() {
+			continue
+		}
+		entry.IP = entries[i].IP
+		if err := d.handle.NeighDel(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routeTable route table of vrf device, main table if vrf is empty
+func (d *linuxDriver) routeTable(vrf string) (int, error) {
+	if vrf == "" {
+		return unix.RT_TABLE_MAIN, nil
+	}
+	link, err := d.linkByName(vrf)
+	if err != nil {
+		return 0, err
+	}
+	vrfLink, ok := link.(*netlink.Vrf)
+	if !ok {
+		return 0, fmt.Errorf("[Driver] link %s is not vrf", vrf)
+	}
+	return int(vrfLink.Table), nil
+}
+
+// routeTableFlush remove routes of family in route table
+func (d *linuxDriver) routeTableFlush(family int, table int) error {
+	routes, err := d.handle.RouteListFiltered(family, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		if err := d.handle.RouteDel(&routes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultDst default route prefix of family
+func defaultDst(family int) *net.IPNet {
+	if family == netlink.FAMILY_V6 {
+		return &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+	}
+	return &net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)}
+}
+
+func ipFamily(ip net.IP) int {
+	if ip.To4() == nil {
+		return netlink.FAMILY_V6
+	}
+	return netlink.FAMILY_V4
+}
+
+// getVniByName get vni from name like Bd100 or Vrf100
+func getVniByName(name string, prefix string) int {
+	if !strings.HasPrefix(name, prefix) {
+		return 0
+	}
+	vni, err := strconv.Atoi(name[len(prefix):])
+	if err != nil || vni < vniMin || vni > vniMax {
+		return 0
+	}
+	return vni
+}
+
+func vxlanName(vni int) string {
+	return vxlanNamePrefix + strconv.Itoa(vni)
+}
+
+func l3BridgeName(vni int) string {
+	return l3BridgeNamePrefix + strconv.Itoa(vni)
+}
+
+func vrfTable(vni int) int {
+	return vrfTableOffset + vni
+}
@@ -0,0 +1,122 @@
+// Package linux is the TAI driver programming the Linux kernel dataplane
+// by netlink and nftables, it makes a plain Linux box a software VTEP.
+//
+// All objects are programmed in network namespace Netns if set, so the
+// driver can be tested without touching the host network, eg: create
+// namespace by "ip netns add vtep0" and ports by "ip -n vtep0 link add
+// eth1 type dummy", then set Netns to "vtep0" before Init. Switch with own
+// driver config programs the namespace set as its driver address.
+//
+// Link oper status, learned fdb and neighbours are notified to TAI by
+// netlink subscriptions in every programmed namespace.
+package linux
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/google/nftables"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+type linuxDriver struct {
+	DriverName string
+	ModuleAPIs map[tai.ObjID]moduleAPI
+	// mutex serialize module calls of the instance
+	mutex  sync.Mutex
+	netns  string
+	handle *netlink.Handle
+	nft    *nftables.Conn
+	// modules referenced by other modules of the instance
+	bdModule     *bridgeAPI
+	vrfModule    *vrfAPI
+	tunnelModule *tunnelAPI
+	aclModule    *aclAPI
+}
+
+type moduleAPI interface {
+	CreateObject(interface{}) error
+	RemoveObject(interface{}) error
+	AddObjectAttr(interface{}, tai.Attrs) error
+	DelObjectAttr(interface{}, tai.Attrs) error
+	SetObjectAttr(interface{}, tai.Attrs) error
+	GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error)
+	ListObject() ([]interface{}, error)
+}
+
+// DriverName of linux TAI driver
+const DriverName = "LINUX"
+
+// Netns network namespace programmed by driver, empty for current one
+var Netns string
+
+func init() {
+	tai.RegisterDriver(DriverName, Init)
+}
+
+// nsHandle network namespace by name, current one if empty
+func nsHandle(name string) (netns.NsHandle, error) {
+	if name == "" {
+		return netns.Get()
+	}
+	return netns.GetFromName(name)
+}
+
+// Init Linux TAI driver, Netns or namespace of driver address must exist
+func Init(config tai.DriverConfig) (tai.DriverHandler, error) {
+	d := &linuxDriver{
+		DriverName: DriverName,
+		netns:      Netns,
+	}
+	if config.Addr != "" {
+		d.netns = config.Addr
+	}
+
+	// namespace is kept open for nftables and monitors resubscribing
+	ns, err := nsHandle(d.netns)
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] netns %q: %v", d.netns, err)
+	}
+	if d.handle, err = netlink.NewHandleAt(ns); err != nil {
+		ns.Close()
+		return nil, fmt.Errorf("[Driver] netns %q netlink: %v", d.netns, err)
+	}
+
+	d.bdModule = newBridgeAPI(d)
+	d.vrfModule = newVrfAPI(d)
+	d.tunnelModule = newTunnelAPI(d)
+	d.aclModule = newAclAPI(d)
+	d.ModuleAPIs = map[tai.ObjID]moduleAPI{
+		tai.ObjectIDBridge:          d.bdModule,
+		tai.ObjectIDVrf:             d.vrfModule,
+		tai.ObjectIDL2Port:          newL2portAPI(d),
+		tai.ObjectIDL3Port:          newL3portAPI(d),
+		tai.ObjectIDFDB:             newFdbAPI(d),
+		tai.ObjectIDNeighbour:       newNeighbourAPI(d),
+		tai.ObjectIDRoute:           newRouteAPI(d),
+		tai.ObjectIDTunnel:          d.tunnelModule,
+		tai.ObjectIDMcastFDB:        newMcastFdbAPI(d),
+		tai.ObjectIDACL:             d.aclModule,
+		tai.ObjectIDACLRule:         newAclRuleAPI(d),
+		tai.ObjectIDPBR:             newPbrAPI(d),
+		tai.ObjectIDAutoGatewayConf: newAutoGatewayConfAPI(d),
+	}
+	// ACL is not in capability without nftables, ACL objects are fault
+	// marked by TAI
+	d.nft, err = nftables.New(nftables.WithNetNSFd(int(ns)))
+	if err == nil {
+		_, err = d.nft.ListTables()
+	}
+	if err != nil {
+		log.Warning("[Driver] nftables not supported %v, ACL is not supported\n", err)
+		d.nft = nil
+		delete(d.ModuleAPIs, tai.ObjectIDACL)
+		delete(d.ModuleAPIs, tai.ObjectIDACLRule)
+	}
+	monitorStart(d.netns, ns)
+	return d, nil
+}
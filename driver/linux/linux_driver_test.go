@@ -0,0 +1,395 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+var testNetnsIndex int32
+
+// testDriver linux driver instance programming a new netns, test is
+// skipped if netns can't be created. The returned handle inspects netns.
+func testDriver(t *testing.T) (*linuxDriver, *netlink.Handle) {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("netns test requires root")
+	}
+
+	name := fmt.Sprintf("govtep-test-%d-%d", os.Getpid(), atomic.AddInt32(&testNetnsIndex, 1))
+	runtime.LockOSThread()
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		t.Skipf("netns get failed %v", err)
+	}
+	ns, err := netns.NewNamed(name)
+	netns.Set(origin)
+	origin.Close()
+	runtime.UnlockOSThread()
+	if err != nil {
+		t.Skipf("netns %s create failed %v", name, err)
+	}
+	t.Cleanup(func() { netns.DeleteNamed(name) })
+
+	handle, err := netlink.NewHandleAt(ns)
+	ns.Close()
+	if err != nil {
+		t.Fatalf("netns %s handle failed %v", name, err)
+	}
+	t.Cleanup(handle.Delete)
+
+	driver, err := Init(tai.DriverConfig{Addr: name})
+	if err != nil {
+		t.Fatalf("driver init in netns %s failed %v", name, err)
+	}
+	return driver.(*linuxDriver), handle
+}
+
+// skipUnsupported skip test if kernel lacks link type or feature
+func skipUnsupported(t *testing.T, err error) {
+	t.Helper()
+	if err != nil && (strings.Contains(err.Error(), "not supported") ||
+		strings.Contains(err.Error(), "protocol not available")) {
+		t.Skipf("kernel not supported: %v", err)
+	}
+}
+
+func testLink(t *testing.T, handle *netlink.Handle, name string) netlink.Link {
+	t.Helper()
+	link, err := handle.LinkByName(name)
+	if err != nil {
+		t.Fatalf("link %s not found: %v", name, err)
+	}
+	return link
+}
+
+// testPort bridge as port of test, dummy links are not in every kernel
+func testPort(t *testing.T, handle *netlink.Handle, name string) netlink.Link {
+	t.Helper()
+	if err := handle.LinkAdd(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}}); err != nil {
+		t.Fatalf("port %s add failed %v", name, err)
+	}
+	link := testLink(t, handle, name)
+	if err := handle.LinkSetUp(link); err != nil {
+		t.Fatalf("port %s up failed %v", name, err)
+	}
+	return link
+}
+
+func TestBridgeVxlan(t *testing.T) {
+	d, handle := testDriver(t)
+
+	objTunnel := tai.TunnelObj{Name: "tun0", Ipaddr: "192.0.2.1"}
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	if err := d.TaiCreateObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel create failed %v", err)
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	bridge := testLink(t, handle, "Bd100")
+
+	// vxlan add error is returned, not only logged
+	err := d.TaiAddObjectAttr(tai.ObjectIDBridge, objBridge, tai.Attrs{tai.BridgeAttrVxlanTunnel: "tun1"})
+	if err == nil {
+		t.Fatalf("bridge with unknown tunnel should fail")
+	}
+
+	err = d.TaiAddObjectAttr(tai.ObjectIDBridge, objBridge, tai.Attrs{tai.BridgeAttrVxlanTunnel: "tun0"})
+	skipUnsupported(t, err)
+	if err != nil {
+		t.Fatalf("bridge vxlan add failed %v", err)
+	}
+	vxlan, ok := testLink(t, handle, "vxlan100").(*netlink.Vxlan)
+	if !ok {
+		t.Fatalf("vxlan100 is not vxlan")
+	}
+	if vxlan.VxlanId != 100 || !vxlan.SrcAddr.Equal(net.ParseIP("192.0.2.1")) ||
+		vxlan.Port != vxlanDefaultDestPort || vxlan.Learning {
+		t.Errorf("vxlan100 unexpected %+v", vxlan)
+	}
+	if vxlan.MasterIndex != bridge.Attrs().Index {
+		t.Errorf("vxlan100 master %d, expect Bd100 %d", vxlan.MasterIndex, bridge.Attrs().Index)
+	}
+
+	attrs, err := d.TaiGetObjectAttr(tai.ObjectIDBridge, objBridge,
+		[]tai.ObjAttrID{tai.BridgeAttrL2vni, tai.BridgeAttrVxlanTunnel})
+	if err != nil || attrs.GetInt(tai.BridgeAttrL2vni) != 100 || attrs.GetString(tai.BridgeAttrVxlanTunnel) != "tun0" {
+		t.Errorf("bridge attrs %v err %v", attrs, err)
+	}
+
+	// remote mac point to remote vtep in vxlan device
+	objFdb := tai.FdbObj{Bridge: "Bd100", Mac: "52:54:00:00:00:01"}
+	if err := d.TaiCreateObject(tai.ObjectIDFDB, objFdb); err != nil {
+		t.Fatalf("fdb create failed %v", err)
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: "192.0.2.2"}); err != nil {
+		t.Fatalf("fdb remote ip add failed %v", err)
+	}
+	if !testFdbExist(t, handle, vxlan, objFdb.Mac, "192.0.2.2") {
+		t.Errorf("fdb %s to 192.0.2.2 not found", objFdb.Mac)
+	}
+	if err := d.TaiRemoveObject(tai.ObjectIDFDB, objFdb); err != nil {
+		t.Fatalf("fdb remove failed %v", err)
+	}
+	if testFdbExist(t, handle, vxlan, objFdb.Mac, "") {
+		t.Errorf("fdb %s not removed", objFdb.Mac)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge remove failed %v", err)
+	}
+	for _, name := range []string{"Bd100", "vxlan100"} {
+		if _, err := handle.LinkByName(name); err == nil {
+			t.Errorf("link %s not removed", name)
+		}
+	}
+}
+
+// testFdbExist fdb entry of mac in vxlan device, any remote if ip empty
+func testFdbExist(t *testing.T, handle *netlink.Handle, link netlink.Link, mac string, remoteIP string) bool {
+	t.Helper()
+	entries, err := handle.NeighList(link.Attrs().Index, unix.AF_BRIDGE)
+	if err != nil {
+		t.Fatalf("fdb list failed %v", err)
+	}
+	for _, entry := range entries {
+		if entry.HardwareAddr.String() == mac && (remoteIP == "" || entry.IP.Equal(net.ParseIP(remoteIP))) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestL3portNeighbourRoute(t *testing.T) {
+	d, handle := testDriver(t)
+	port := testPort(t, handle, "eth1")
+
+	objL3port := tai.L3portObj{Name: "eth1"}
+	err := d.TaiAddObjectAttr(tai.ObjectIDL3Port, objL3port, tai.Attrs{
+		tai.L3portAttrIpaddr:  []string{"10.0.0.1/24"},
+		tai.L3portAttrMacaddr: "52:54:00:00:00:10",
+	})
+	if err != nil {
+		t.Fatalf("l3port attrs add failed %v", err)
+	}
+	if testLink(t, handle, "eth1").Attrs().HardwareAddr.String() != "52:54:00:00:00:10" {
+		t.Errorf("eth1 mac not set")
+	}
+	addrs, err := handle.AddrList(port, netlink.FAMILY_V4)
+	if err != nil || len(addrs) != 1 || addrs[0].IPNet.String() != "10.0.0.1/24" {
+		t.Fatalf("eth1 addrs %v err %v", addrs, err)
+	}
+
+	objNeighbour := tai.NeighbourObj{Ipaddr: "10.0.0.2"}
+	if err := d.TaiCreateObject(tai.ObjectIDNeighbour, objNeighbour); err != nil {
+		t.Fatalf("neighbour create failed %v", err)
+	}
+	err = d.TaiAddObjectAttr(tai.ObjectIDNeighbour, objNeighbour, tai.Attrs{
+		tai.NeighbourAttrMacaddr: "52:54:00:00:00:02",
+		tai.NeighbourAttrOutPort: "eth1",
+	})
+	if err != nil {
+		t.Fatalf("neighbour attrs add failed %v", err)
+	}
+	neighs, err := handle.NeighList(port.Attrs().Index, netlink.FAMILY_V4)
+	if err != nil || len(neighs) != 1 || neighs[0].HardwareAddr.String() != "52:54:00:00:00:02" ||
+		neighs[0].State != netlink.NUD_NOARP {
+		t.Errorf("eth1 neighbours %v err %v", neighs, err)
+	}
+
+	objRoute := tai.RouteObj{IPPrefix: "198.51.100.0/24", Nexthop: "10.0.0.2", OutputPort: "eth1"}
+	if err := d.TaiCreateObject(tai.ObjectIDRoute, objRoute); err != nil {
+		t.Fatalf("route create failed %v", err)
+	}
+	routes, err := handle.RouteGet(net.ParseIP("198.51.100.7"))
+	if err != nil || len(routes) != 1 || !routes[0].Gw.Equal(net.ParseIP("10.0.0.2")) ||
+		routes[0].LinkIndex != port.Attrs().Index {
+		t.Errorf("route to 198.51.100.7 %v err %v", routes, err)
+	}
+	if err := d.TaiRemoveObject(tai.ObjectIDRoute, objRoute); err != nil {
+		t.Fatalf("route remove failed %v", err)
+	}
+	if routes, err := handle.RouteGet(net.ParseIP("198.51.100.7")); err == nil {
+		t.Errorf("route to 198.51.100.7 not removed %v", routes)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDNeighbour, objNeighbour); err != nil {
+		t.Fatalf("neighbour remove failed %v", err)
+	}
+	if neighs, _ := handle.NeighList(port.Attrs().Index, netlink.FAMILY_V4); len(neighs) != 0 {
+		t.Errorf("eth1 neighbours not removed %v", neighs)
+	}
+
+	// physical port is only unbound, addresses flushed
+	if err := d.TaiRemoveObject(tai.ObjectIDL3Port, objL3port); err != nil {
+		t.Fatalf("l3port remove failed %v", err)
+	}
+	if addrs, _ := handle.AddrList(port, netlink.FAMILY_V4); len(addrs) != 0 {
+		t.Errorf("eth1 addrs not flushed %v", addrs)
+	}
+}
+
+func TestL2portVlan(t *testing.T) {
+	d, handle := testDriver(t)
+	testPort(t, handle, "eth1")
+
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	objL2port := tai.L2portObj{Name: "eth1.10.20", BridgeName: "Bd100", PhysicalParentPort: "eth1"}
+	if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port create failed %v", err)
+	}
+	err := d.TaiAddObjectAttr(tai.ObjectIDL2Port, objL2port, tai.Attrs{tai.L2portAttrVlanTag: []int{10, 20}})
+	skipUnsupported(t, err)
+	if err != nil {
+		t.Fatalf("l2port vlan add failed %v", err)
+	}
+
+	outer, ok := testLink(t, handle, "eth1.10").(*netlink.Vlan)
+	if !ok || outer.VlanId != 10 || outer.VlanProtocol != netlink.VLAN_PROTOCOL_8021AD {
+		t.Errorf("outer vlan eth1.10 unexpected %+v", outer)
+	}
+	inner, ok := testLink(t, handle, "eth1.10.20").(*netlink.Vlan)
+	if !ok || inner.VlanId != 20 || inner.ParentIndex != outer.Index ||
+		inner.MasterIndex != testLink(t, handle, "Bd100").Attrs().Index {
+		t.Errorf("inner vlan eth1.10.20 unexpected %+v", inner)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port remove failed %v", err)
+	}
+	if _, err := handle.LinkByName("eth1.10.20"); err == nil {
+		t.Errorf("eth1.10.20 not removed")
+	}
+}
+
+func TestPBR(t *testing.T) {
+	d, handle := testDriver(t)
+	port := testPort(t, handle, "eth1")
+	if err := handle.AddrAdd(port, &netlink.Addr{IPNet: &net.IPNet{
+		IP: net.ParseIP("10.0.0.1"), Mask: net.CIDRMask(24, 32)}}); err != nil {
+		t.Fatalf("eth1 addr add failed %v", err)
+	}
+
+	objPBR := tai.PBRObj{IP: "203.0.113.1", Protocol: "tcp", Port: 80}
+	err := d.TaiCreateObject(tai.ObjectIDPBR, objPBR)
+	skipUnsupported(t, err)
+	if err != nil {
+		t.Fatalf("pbr create failed %v", err)
+	}
+	table := pbrTableOffset
+	rules, err := handle.RuleList(netlink.FAMILY_V4)
+	if err != nil {
+		t.Fatalf("rule list failed %v", err)
+	}
+	found := false
+	for _, rule := range rules {
+		if rule.Table == table && rule.Priority == pbrRulePriority && rule.IPProto == unix.IPPROTO_TCP &&
+			rule.Dport != nil && rule.Dport.Start == 80 && rule.Dst.String() == "203.0.113.1/32" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("pbr rule of table %d not found in %v", table, rules)
+	}
+
+	if err := d.TaiAddObjectAttr(tai.ObjectIDPBR, objPBR, tai.Attrs{
+		tai.PBRAttrNexthopGroup: []string{"10.0.0.2", "10.0.0.3"}}); err != nil {
+		t.Fatalf("pbr next hops add failed %v", err)
+	}
+	routes, err := handle.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil || len(routes) != 1 || len(routes[0].MultiPath) != 2 {
+		t.Errorf("pbr table %d routes %v err %v", table, routes, err)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDPBR, objPBR); err != nil {
+		t.Fatalf("pbr remove failed %v", err)
+	}
+	routes, _ = handle.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	rules, _ = handle.RuleList(netlink.FAMILY_V4)
+	for _, rule := range rules {
+		if rule.Table == table {
+			t.Errorf("pbr rule not removed %v", rule)
+		}
+	}
+	if len(routes) != 0 {
+		t.Errorf("pbr table %d not flushed %v", table, routes)
+	}
+}
+
+// testListObject objects of module listed by driver
+func testListObject(t *testing.T, d *linuxDriver, objID tai.ObjID, expect ...interface{}) {
+	t.Helper()
+	objs, err := d.TaiListObject(objID)
+	if err != nil {
+		t.Fatalf("object %d list failed %v", objID, err)
+	}
+	if len(objs) != len(expect) {
+		t.Fatalf("object %d listed %v, expect %v", objID, objs, expect)
+	}
+	for i := range objs {
+		if objs[i] != expect[i] {
+			t.Errorf("object %d listed %v, expect %v", objID, objs, expect)
+		}
+	}
+}
+
+// TestListObject objects of driver are listed from netns for warm restart
+func TestListObject(t *testing.T) {
+	d, handle := testDriver(t)
+
+	objTunnel := tai.TunnelObj{Name: "tun0", Ipaddr: "192.0.2.1"}
+	objBridge := tai.BridgeObj{Name: "Bd200", Vni: 200}
+	objFdb := tai.FdbObj{Bridge: "Bd200", Mac: "52:54:00:00:00:02"}
+	if err := d.TaiCreateObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel create failed %v", err)
+	}
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	err := d.TaiAddObjectAttr(tai.ObjectIDBridge, objBridge, tai.Attrs{tai.BridgeAttrVxlanTunnel: "tun0"})
+	skipUnsupported(t, err)
+	if err != nil {
+		t.Fatalf("bridge vxlan add failed %v", err)
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: "192.0.2.2"}); err != nil {
+		t.Fatalf("fdb remote ip add failed %v", err)
+	}
+	// bridge not named by vni is not listed
+	testPort(t, handle, "br0")
+
+	testListObject(t, d, tai.ObjectIDBridge, objBridge)
+	testListObject(t, d, tai.ObjectIDFDB, objFdb)
+
+	objVrf := tai.VrfObj{Name: "Vrf300"}
+	objL3port := tai.L3portObj{Name: "Bd200"}
+	if err := d.TaiCreateObject(tai.ObjectIDVrf, objVrf); err == nil {
+		if err := d.TaiAddObjectAttr(tai.ObjectIDL3Port, objL3port, tai.Attrs{tai.L3portAttrVrfBinding: "Vrf300"}); err != nil {
+			t.Fatalf("l3port vrf binding failed %v", err)
+		}
+		testListObject(t, d, tai.ObjectIDVrf, objVrf)
+		testListObject(t, d, tai.ObjectIDL3Port, objL3port)
+	} else {
+		t.Logf("vrf not listed: %v", err)
+	}
+
+	if err := d.TaiRemoveObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge remove failed %v", err)
+	}
+	testListObject(t, d, tai.ObjectIDFDB)
+	testListObject(t, d, tai.ObjectIDL3Port)
+}
@@ -0,0 +1,110 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+type fdbAPI struct {
+	moduleID int
+	*linuxDriver
+}
+
+func newFdbAPI(d *linuxDriver) *fdbAPI {
+	return &fdbAPI{
+		moduleID:    tai.ObjectIDFDB,
+		linuxDriver: d,
+	}
+}
+
+// remoteFdbAdd static fdb of remote mac point to vxlan device of bridge
+// in bridge and to remote vtep ip in vxlan device
+func (d *linuxDriver) remoteFdbAdd(bdName string, mac string, remoteIP string) error {
+	vni := getVniByName(bdName, bridgeNamePrefix)
+	if vni == 0 {
+		return fmt.Errorf("[Driver] Invalid bridge name %s", bdName)
+	}
+	dev := vxlanName(vni)
+	if !d.linkExist(dev) {
+		return fmt.Errorf("[Driver] BD %s vxlan %s not exist", bdName, dev)
+	}
+
+	if err := d.fdbReplace(mac, dev, "", netlink.NTF_MASTER); err != nil {
+		return err
+	}
+	return d.fdbReplace(mac, dev, remoteIP, netlink.NTF_SELF)
+}
+
+func (d *linuxDriver) remoteFdbDel(bdName string, mac string) error {
+	dev := vxlanName(getVniByName(bdName, bridgeNamePrefix))
+	if !d.linkExist(dev) {
+		return nil
+	}
+
+	if err := d.fdbDel(mac, dev, "", netlink.NTF_MASTER); err != nil {
+		log.Warning("[Driver] fdb %s del from bridge %s failed %v\n", mac, bdName, err)
+	}
+	return d.fdbDel(mac, dev, "", netlink.NTF_SELF)
+}
+
+// CreateObject remote fdb is set with remote ip attr
+func (v *fdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *fdbAPI) RemoveObject(obj interface{}) error {
+	objFdb := obj.(tai.FdbObj)
+	return v.remoteFdbDel(objFdb.Bridge, objFdb.Mac)
+}
+
+func (v *fdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objFdb := obj.(tai.FdbObj)
+
+	if remoteIP := attrs.GetString(tai.FdbAttrRemoteIP); remoteIP != "" {
+		return v.remoteFdbAdd(objFdb.Bridge, objFdb.Mac, remoteIP)
+	}
+	return nil
+}
+
+func (v *fdbAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *fdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *fdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject remote fdbs in vxlan devices of bridges named "Bd"+vni
+func (v *fdbAPI) ListObject() ([]interface{}, error) {
+	bridges, err := v.bridgeLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for _, link := range bridges {
+		bdName := link.Attrs().Name
+		dev := vxlanName(getVniByName(bdName, bridgeNamePrefix))
+		if !v.linkExist(dev) {
+			continue
+		}
+		fdbs, err := v.remoteFdbList(dev)
+		if err != nil {
+			return nil, err
+		}
+		for _, fdb := range fdbs {
+			if mac := fdb.HardwareAddr.String(); mac != bumMac {
+				objs = append(objs, tai.FdbObj{Bridge: bdName, Mac: mac})
+			}
+		}
+	}
+	return objs, nil
+}
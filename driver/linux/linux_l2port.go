@@ -0,0 +1,117 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+type l2portAPI struct {
+	moduleID int
+	*linuxDriver
+}
+
+func newL2portAPI(d *linuxDriver) *l2portAPI {
+	return &l2portAPI{
+		moduleID:    tai.ObjectIDL2Port,
+		linuxDriver: d,
+	}
+}
+
+// l2portAttach add port to bridge, vlan sub interface is created if
+// port is not the physical parent port
+func (d *linuxDriver) l2portAttach(objL2port tai.L2portObj, tags []int) error {
+	if objL2port.Name != objL2port.PhysicalParentPort && !d.linkExist(objL2port.Name) {
+		if len(tags) == 0 {
+			return nil
+		}
+		if err := d.vlanLinkAdd(objL2port.Name, objL2port.PhysicalParentPort, tags); err != nil {
+			return err
+		}
+	}
+
+	if !d.linkExist(objL2port.Name) {
+		return fmt.Errorf("[Driver] L2port %s not exist", objL2port.Name)
+	}
+	if err := d.linkSetMaster(objL2port.Name, objL2port.BridgeName); err != nil {
+		return err
+	}
+	return d.linkUp(objL2port.Name)
+}
+
+func (v *l2portAPI) CreateObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if !v.linkExist(objL2port.BridgeName) {
+		return fmt.Errorf("[Driver] L2port %s bridge %s not exist", objL2port.Name, objL2port.BridgeName)
+	}
+	// vlan sub interface is created with vlan tag attr
+	return v.l2portAttach(objL2port, nil)
+}
+
+func (v *l2portAPI) RemoveObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if objL2port.Name != objL2port.PhysicalParentPort {
+		return v.linkDel(objL2port.Name)
+	}
+	if !v.linkExist(objL2port.Name) {
+		return nil
+	}
+	return v.linkSetNoMaster(objL2port.Name)
+}
+
+func (v *l2portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if tags := attrs.GetInts(tai.L2portAttrVlanTag); len(tags) != 0 {
+		if err := v.l2portAttach(objL2port, tags); err != nil {
+			log.Warning("[Driver] L2port %s vlan %v add failed %v\n", objL2port.Name, tags, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *l2portAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *l2portAPI) SetObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *l2portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject vlan sub interfaces in bridges named "Bd"+vni, physical
+// ports are only bound to bridge and not listed
+func (v *l2portAPI) ListObject() ([]interface{}, error) {
+	links, err := v.linkList()
+	if err != nil {
+		return nil, err
+	}
+	indexes := linkIndexes(links)
+
+	var objs []interface{}
+	for _, link := range links {
+		bdName := linkMaster(link, indexes)
+		if _, ok := link.(*netlink.Vlan); !ok || getVniByName(bdName, bridgeNamePrefix) == 0 {
+			continue
+		}
+		parent := vlanLinkParent(link, indexes)
+		if parent == "" {
+			continue
+		}
+		objs = append(objs, tai.L2portObj{
+			Name:               link.Attrs().Name,
+			BridgeName:         bdName,
+			PhysicalParentPort: parent,
+		})
+	}
+	return objs, nil
+}
@@ -0,0 +1,135 @@
+package linux
+
+import (
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+type l3portAPI struct {
+	moduleID int
+	*linuxDriver
+}
+
+func newL3portAPI(d *linuxDriver) *l3portAPI {
+	return &l3portAPI{
+		moduleID:    tai.ObjectIDL3Port,
+		linuxDriver: d,
+	}
+}
+
+func (v *l3portAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *l3portAPI) RemoveObject(obj interface{}) error {
+	objL3port := obj.(tai.L3portObj)
+
+	if !v.linkExist(objL3port.Name) {
+		return nil
+	}
+	// vlan sub interface created by driver, bridge interface and
+	// physical port are only unbound
+	if objL3port.PhysicalParentPort != "" && objL3port.Name != objL3port.PhysicalParentPort {
+		return v.linkDel(objL3port.Name)
+	}
+	if err := v.addrFlush(objL3port.Name); err != nil {
+		return err
+	}
+	return v.linkSetNoMaster(objL3port.Name)
+}
+
+func (v *l3portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL3port := obj.(tai.L3portObj)
+
+	if tags := attrs.GetInts(tai.L3portAttrVlanTag); len(tags) != 0 && objL3port.PhysicalParentPort != "" {
+		if err := v.vlanLinkAdd(objL3port.Name, objL3port.PhysicalParentPort, tags); err != nil {
+			return err
+		}
+	}
+
+	if !v.linkExist(objL3port.Name) {
+		log.Warning("[Driver] interface %s not exist\n", objL3port.Name)
+		return nil
+	}
+
+	// vrf binding flush addresses, so bind vrf before ip address set
+	if vrfName := attrs.GetString(tai.L3portAttrVrfBinding); vrfName != "" {
+		if err := v.linkSetMaster(objL3port.Name, vrfName); err != nil {
+			log.Warning("[Driver] Interface %s binding vrf %s failed %v\n", objL3port.Name, vrfName, err)
+			return err
+		}
+	}
+
+	for _, ipaddr := range attrs.GetStrings(tai.L3portAttrIpaddr) {
+		if err := v.addrReplace(objL3port.Name, ipaddr); err != nil {
+			return err
+		}
+	}
+
+	if mac := attrs.GetString(tai.L3portAttrMacaddr); mac != "" {
+		if err := v.linkSetMac(objL3port.Name, mac); err != nil {
+			return err
+		}
+	}
+
+	return v.linkUp(objL3port.Name)
+}
+
+func (v *l3portAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL3port := obj.(tai.L3portObj)
+
+	if !v.linkExist(objL3port.Name) {
+		log.Warning("[Driver] interface %s not exist\n", objL3port.Name)
+		return nil
+	}
+
+	for _, ipaddr := range attrs.GetStrings(tai.L3portAttrIpaddr) {
+		if err := v.addrDel(objL3port.Name, ipaddr); err != nil {
+			log.Warning("[Driver] Interface %s del address %s failed %v\n", objL3port.Name, ipaddr, err)
+		}
+	}
+
+	if vrfName := attrs.GetString(tai.L3portAttrVrfBinding); vrfName != "" {
+		return v.linkSetNoMaster(objL3port.Name)
+	}
+	return nil
+}
+
+func (v *l3portAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *l3portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject bridge interfaces and vlan sub interfaces in vrfs named
+// "Vrf"+vni, physical ports are only bound to vrf and not listed
+func (v *l3portAPI) ListObject() ([]interface{}, error) {
+	links, err := v.linkList()
+	if err != nil {
+		return nil, err
+	}
+	indexes := linkIndexes(links)
+
+	var objs []interface{}
+	for _, link := range links {
+		name := link.Attrs().Name
+		if getVniByName(linkMaster(link, indexes), vrfNamePrefix) == 0 {
+			continue
+		}
+		switch link.(type) {
+		case *netlink.Bridge:
+			if getVniByName(name, bridgeNamePrefix) != 0 {
+				objs = append(objs, tai.L3portObj{Name: name})
+			}
+		case *netlink.Vlan:
+			if parent := vlanLinkParent(link, indexes); parent != "" {
+				objs = append(objs, tai.L3portObj{Name: name, PhysicalParentPort: parent})
+			}
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,143 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+// bum traffic is head end replicated to all-zero fdb entries
+const bumMac = "00:00:00:00:00:00"
+
+type mcastFdbAPI struct {
+	moduleID int
+	*linuxDriver
+	// bridge name to flood remote ips
+	floods map[string][]string
+}
+
+func newMcastFdbAPI(d *linuxDriver) *mcastFdbAPI {
+	return &mcastFdbAPI{
+		moduleID:    tai.ObjectIDMcastFDB,
+		linuxDriver: d,
+		floods:      make(map[string][]string),
+	}
+}
+
+// floodSet replace flood list of bridge vxlan device
+func (v *mcastFdbAPI) floodSet(bdName string, ips []string) error {
+	dev := vxlanName(getVniByName(bdName, bridgeNamePrefix))
+	if !v.linkExist(dev) {
+		return fmt.Errorf("[Driver] BD %s vxlan %s not exist", bdName, dev)
+	}
+
+	newIps := make(map[string]bool)
+	for _, ip := range ips {
+		newIps[ip] = true
+	}
+	for _, ip := range v.floods[bdName] {
+		if newIps[ip] {
+			delete(newIps, ip)
+			continue
+		}
+		if err := v.fdbDel(bumMac, dev, ip, netlink.NTF_SELF); err != nil {
+			log.Warning("[Driver] BD %s flood %s del failed %v\n", bdName, ip, err)
+		}
+	}
+	for ip := range newIps {
+		if err := v.fdbAppend(bumMac, dev, ip); err != nil {
+			return err
+		}
+	}
+
+	v.floods[bdName] = ips
+	return nil
+}
+
+func (v *mcastFdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *mcastFdbAPI) RemoveObject(obj interface{}) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if _, ok := v.floods[objMcastFdb.BridgeName]; !ok {
+		return nil
+	}
+	err := v.floodSet(objMcastFdb.BridgeName, nil)
+	delete(v.floods, objMcastFdb.BridgeName)
+	return err
+}
+
+func (v *mcastFdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != "unknown-dst" && objMcastFdb.Mac != bumMac {
+		log.Info("[Driver] Mcast fdb %s of BD %s ignored\n", objMcastFdb.Mac, objMcastFdb.BridgeName)
+		return nil
+	}
+	if attrs.Has(tai.McastFdbAttrLocators) {
+		return v.floodSet(objMcastFdb.BridgeName, attrs.GetStrings(tai.McastFdbAttrLocators))
+	}
+	return nil
+}
+
+// DelObjectAttr flood list is removed with its last remote ip
+func (v *mcastFdbAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != "unknown-dst" && objMcastFdb.Mac != bumMac {
+		return nil
+	}
+	if _, ok := attrs[tai.McastFdbAttrLocators]; !ok {
+		return nil
+	}
+	return v.floodSet(objMcastFdb.BridgeName, nil)
+}
+
+func (v *mcastFdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *mcastFdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject flood lists of bridges named "Bd"+vni, flood remote ips are
+// loaded to be removed with listed mcast fdb
+func (v *mcastFdbAPI) ListObject() ([]interface{}, error) {
+	bridges, err := v.bridgeLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for _, link := range bridges {
+		bdName := link.Attrs().Name
+		dev := vxlanName(getVniByName(bdName, bridgeNamePrefix))
+		if !v.linkExist(dev) {
+			continue
+		}
+		fdbs, err := v.remoteFdbList(dev)
+		if err != nil {
+			return nil, err
+		}
+		var ips []string
+		for _, fdb := range fdbs {
+			if fdb.HardwareAddr.String() == bumMac {
+				ips = append(ips, fdb.IP.String())
+			}
+		}
+		if len(ips) == 0 {
+			continue
+		}
+		if _, ok := v.floods[bdName]; !ok {
+			v.floods[bdName] = ips
+		}
+		objs = append(objs, tai.McastFdbObj{BridgeName: bdName})
+	}
+	return objs, nil
+}
@@ -0,0 +1,222 @@
+package linux
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// monitorRestartInterval monitor is subscribed again after netlink error
+const monitorRestartInterval = 5 * time.Second
+
+// monitorStarted namespaces monitored
+var monitorStarted = struct {
+	mutex sync.Mutex
+	netns map[string]bool
+}{
+	netns: make(map[string]bool),
+}
+
+// monitorState last notified state, kernel repeats events on every
+// neighbour state transition
+var monitorState = struct {
+	mutex      sync.Mutex
+	linkUp     map[string]bool
+	fdbs       map[string]string // netns/bridge/mac to port
+	neighbours map[string]string // netns/ip to mac/port
+}{
+	linkUp:     make(map[string]bool),
+	fdbs:       make(map[string]string),
+	neighbours: make(map[string]string),
+}
+
+// monitorStart notify TAI of link oper status, learned fdb and neighbour
+// by netlink subscriptions in netns, monitors are started once per netns
+func monitorStart(name string, ns netns.NsHandle) {
+	monitorStarted.mutex.Lock()
+	defer monitorStarted.mutex.Unlock()
+	if monitorStarted.netns[name] {
+		return
+	}
+	monitorStarted.netns[name] = true
+
+	go monitorLinks(name, ns)
+	go monitorNeighbours(name, ns)
+}
+
+func monitorLinks(name string, ns netns.NsHandle) {
+	for {
+		updates := make(chan netlink.LinkUpdate)
+		err := netlink.LinkSubscribeAt(ns, updates, nil)
+		if err == nil {
+			for update := range updates {
+				monitorLink(name, update)
+			}
+		}
+		log.Warning("[Driver] netns %q link monitor exited %v, restart after %v\n",
+			name, err, monitorRestartInterval)
+		time.Sleep(monitorRestartInterval)
+	}
+}
+
+func monitorNeighbours(name string, ns netns.NsHandle) {
+	for {
+		var updates chan netlink.NeighUpdate
+		handle, err := netlink.NewHandleAt(ns)
+		if err == nil {
+			updates = make(chan netlink.NeighUpdate)
+			err = netlink.NeighSubscribeAt(ns, updates, nil)
+		}
+		if err == nil {
+			links := &monitorLinkNames{handle: handle, names: make(map[int]string)}
+			for update := range updates {
+				if update.Family == unix.AF_BRIDGE {
+					monitorFdb(name, links, update)
+				} else {
+					monitorNeighbour(name, links, update)
+				}
+			}
+		}
+		if handle != nil {
+			handle.Delete()
+		}
+		log.Warning("[Driver] netns %q neighbour monitor exited %v, restart after %v\n",
+			name, err, monitorRestartInterval)
+		time.Sleep(monitorRestartInterval)
+	}
+}
+
+// monitorLinkNames link name of ifindex in events, names are kept for
+// events of links deleted
+type monitorLinkNames struct {
+	handle *netlink.Handle
+	names  map[int]string
+}
+
+func (l *monitorLinkNames) name(index int) string {
+	if index == 0 {
+		return ""
+	}
+	if link, err := l.handle.LinkByIndex(index); err == nil {
+		l.names[index] = link.Attrs().Name
+	}
+	return l.names[index]
+}
+
+// driverLink links created by driver are not reported
+func driverLink(name string) bool {
+	return strings.HasPrefix(name, bridgeNamePrefix) || strings.HasPrefix(name, vrfNamePrefix) ||
+		strings.HasPrefix(name, vxlanNamePrefix) || strings.HasPrefix(name, l3BridgeNamePrefix)
+}
+
+func hasField(fields []string, keys ...string) bool {
+	for _, field := range fields {
+		for _, key := range keys {
+			if field == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// monitorLink oper status of link, unknown status is not reported
+func monitorLink(netns string, update netlink.LinkUpdate) {
+	if update.Header.Type == unix.RTM_DELLINK {
+		return
+	}
+	name := update.Attrs().Name
+	if driverLink(name) {
+		return
+	}
+
+	var up bool
+	switch update.Attrs().OperState {
+	case netlink.OperUp:
+		up = true
+	case netlink.OperDown, netlink.OperLowerLayerDown:
+		up = false
+	default:
+		return
+	}
+
+	key := netns + "/" + name
+	monitorState.mutex.Lock()
+	last, ok := monitorState.linkUp[key]
+	monitorState.linkUp[key] = up
+	monitorState.mutex.Unlock()
+	if !ok || last != up {
+		tai.Notify(tai.PortOperNotification{Port: name, Up: up})
+	}
+}
+
+// monitorFdb fdb learned in bridge, entries programmed by driver are
+// static or on vxlan device
+func monitorFdb(netns string, links *monitorLinkNames, update netlink.NeighUpdate) {
+	if update.State&(netlink.NUD_PERMANENT|netlink.NUD_NOARP) != 0 || update.Flags&netlink.NTF_SELF != 0 {
+		return
+	}
+	aged := update.Type == unix.RTM_DELNEIGH
+	mac, port, bridge := update.HardwareAddr.String(), links.name(update.LinkIndex), links.name(update.MasterIndex)
+	if !strings.HasPrefix(bridge, bridgeNamePrefix) || port == "" || driverLink(port) {
+		return
+	}
+
+	key := netns + "/" + bridge + "/" + mac
+	monitorState.mutex.Lock()
+	last, ok := monitorState.fdbs[key]
+	if aged {
+		delete(monitorState.fdbs, key)
+	} else {
+		monitorState.fdbs[key] = port
+	}
+	monitorState.mutex.Unlock()
+	if aged && !ok || !aged && ok && last == port {
+		return
+	}
+	tai.Notify(tai.FdbNotification{Bridge: bridge, Mac: mac, Port: port, Aged: aged})
+}
+
+// monitorNeighbour neighbour resolved or failed, neighbours programmed by
+// driver are noarp
+func monitorNeighbour(netns string, links *monitorLinkNames, update netlink.NeighUpdate) {
+	if update.IP == nil || update.State&(netlink.NUD_PERMANENT|netlink.NUD_NOARP) != 0 {
+		return
+	}
+	aged := update.Type == unix.RTM_DELNEIGH
+	ip, port := update.IP.String(), links.name(update.LinkIndex)
+	var mac string
+	if len(update.HardwareAddr) != 0 {
+		mac = update.HardwareAddr.String()
+	}
+	if port == "" || strings.HasPrefix(port, vxlanNamePrefix) {
+		return
+	}
+	if update.State&netlink.NUD_FAILED != 0 {
+		aged = true
+	} else if !aged && (mac == "" ||
+		update.State&(netlink.NUD_REACHABLE|netlink.NUD_STALE|netlink.NUD_DELAY|netlink.NUD_PROBE) == 0) {
+		return
+	}
+
+	key, value := netns+"/"+ip, mac+"/"+port
+	monitorState.mutex.Lock()
+	last, ok := monitorState.neighbours[key]
+	if aged {
+		delete(monitorState.neighbours, key)
+	} else {
+		monitorState.neighbours[key] = value
+	}
+	monitorState.mutex.Unlock()
+	if aged && !ok || !aged && ok && last == value {
+		return
+	}
+	tai.Notify(tai.NeighbourNotification{Ipaddr: ip, Mac: mac, Port: port, Aged: aged})
+}
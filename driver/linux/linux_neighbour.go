@@ -0,0 +1,116 @@
+package linux
+
+import (
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type neighbour struct {
+	mac      string
+	outPort  string
+	remoteIP string
+}
+
+type neighbourAPI struct {
+	moduleID int
+	*linuxDriver
+	// neighbour attrs are added separately, save them by ip
+	neighbours map[string]*neighbour
+}
+
+func newNeighbourAPI(d *linuxDriver) *neighbourAPI {
+	return &neighbourAPI{
+		moduleID:    tai.ObjectIDNeighbour,
+		linuxDriver: d,
+		neighbours:  make(map[string]*neighbour),
+	}
+}
+
+func (v *neighbourAPI) CreateObject(obj interface{}) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	if _, ok := v.neighbours[objNeighbour.Ipaddr]; ok {
+		log.Info("[Driver] Neighbour for %s already exist\n", objNeighbour.Ipaddr)
+		return nil
+	}
+	v.neighbours[objNeighbour.Ipaddr] = &neighbour{}
+	return nil
+}
+
+func (v *neighbourAPI) RemoveObject(obj interface{}) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	nh, ok := v.neighbours[objNeighbour.Ipaddr]
+	if !ok {
+		return nil
+	}
+	delete(v.neighbours, objNeighbour.Ipaddr)
+
+	if nh.outPort == "" || !v.linkExist(nh.outPort) {
+		return nil
+	}
+	if nh.remoteIP != "" && getVniByName(nh.outPort, bridgeNamePrefix) != 0 {
+		if err := v.remoteFdbDel(nh.outPort, nh.mac); err != nil {
+			log.Warning("[Driver] Neighbour %s fdb del failed %v\n", objNeighbour.Ipaddr, err)
+		}
+	}
+	return v.neighDel(objNeighbour.Ipaddr, nh.outPort)
+}
+
+// AddObjectAttr neighbour is set when mac and outport are known, remote
+// mac in bridge also point to remote vtep
+func (v *neighbourAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	nh, ok := v.neighbours[objNeighbour.Ipaddr]
+	if !ok {
+		log.Warning("[Driver] Neighbour for %s not exist\n", objNeighbour.Ipaddr)
+		return nil
+	}
+
+	for attr, value := range attrs {
+		switch attr {
+		case tai.NeighbourAttrMacaddr:
+			nh.mac, _ = value.(string)
+		case tai.NeighbourAttrOutPort:
+			nh.outPort, _ = value.(string)
+		case tai.NeighbourAttrRemoteIP:
+			nh.remoteIP, _ = value.(string)
+		}
+	}
+
+	if nh.mac == "" || nh.outPort == "" {
+		return nil
+	}
+	if !v.linkExist(nh.outPort) {
+		log.Warning("[Driver] Neighbour %s outport %s not exist\n", objNeighbour.Ipaddr, nh.outPort)
+		return nil
+	}
+
+	if err := v.neighReplace(objNeighbour.Ipaddr, nh.mac, nh.outPort); err != nil {
+		return err
+	}
+
+	if nh.remoteIP != "" && getVniByName(nh.outPort, bridgeNamePrefix) != 0 {
+		return v.remoteFdbAdd(nh.outPort, nh.mac, nh.remoteIP)
+	}
+	return nil
+}
+
+func (v *neighbourAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *neighbourAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *neighbourAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, static neighbours are not told from neighbours
+// resolved by kernel
+func (v *neighbourAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,189 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+type pbrAPI struct {
+	moduleID int
+	*linuxDriver
+	// pbr object to route table id
+	tables map[tai.PBRObj]int
+}
+
+func newPbrAPI(d *linuxDriver) *pbrAPI {
+	return &pbrAPI{
+		moduleID:    tai.ObjectIDPBR,
+		linuxDriver: d,
+		tables:      make(map[tai.PBRObj]int),
+	}
+}
+
+func (v *pbrAPI) allocTable() int {
+	used := make(map[int]bool)
+	for _, table := range v.tables {
+		used[table] = true
+	}
+	table := pbrTableOffset
+	for used[table] {
+		table++
+	}
+	return table
+}
+
+// pbrProtocols ip protocol number of pbr l4 protocol
+var pbrProtocols = map[string]int{
+	vtepdb.PolicyBasedRouteProtocolTCP:  unix.IPPROTO_TCP,
+	vtepdb.PolicyBasedRouteProtocolUDP:  unix.IPPROTO_UDP,
+	vtepdb.PolicyBasedRouteProtocolSctp: unix.IPPROTO_SCTP,
+}
+
+// pbrRule ip rule of pbr, match destination ip, l4 protocol and port of
+// packets in vrf
+func pbrRule(objPBR tai.PBRObj, table int) (*netlink.Rule, error) {
+	dst, err := parsePrefix(objPBR.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := netlink.NewRule()
+	rule.Priority = pbrRulePriority
+	rule.Family = ipFamily(dst.IP)
+	rule.IifName = objPBR.Vrf
+	rule.Dst = dst
+	rule.Table = table
+	if objPBR.Protocol != "" && objPBR.Protocol != vtepdb.PolicyBasedRouteProtocolIgnore {
+		proto, ok := pbrProtocols[objPBR.Protocol]
+		if !ok {
+			return nil, fmt.Errorf("[Driver] PBR invalid protocol %s", objPBR.Protocol)
+		}
+		rule.IPProto = proto
+		if objPBR.Port != 0 {
+			rule.Dport = netlink.NewRulePortRange(uint16(objPBR.Port), uint16(objPBR.Port))
+		}
+	}
+	return rule, nil
+}
+
+// nexthopDev get output device of next hop in vrf
+func (d *linuxDriver) nexthopDev(nexthop string, vrf string) (int, error) {
+	ip := net.ParseIP(nexthop)
+	if ip == nil {
+		return 0, fmt.Errorf("[Driver] invalid next hop %s", nexthop)
+	}
+	routes, err := d.handle.RouteGetWithOptions(ip, &netlink.RouteGetOptions{VrfName: vrf})
+	if err != nil {
+		return 0, err
+	}
+	if len(routes) == 0 || routes[0].LinkIndex == 0 {
+		return 0, fmt.Errorf("[Driver] next hop %s device not found", nexthop)
+	}
+	return routes[0].LinkIndex, nil
+}
+
+// pbrNexthopSet replace ecmp default route of pbr table
+func (d *linuxDriver) pbrNexthopSet(objPBR tai.PBRObj, table int, nexthops []string) error {
+	family := netlink.FAMILY_V4
+	if strings.Contains(objPBR.IP, ":") {
+		family = netlink.FAMILY_V6
+	}
+
+	var multipath []*netlink.NexthopInfo
+	for _, nexthop := range nexthops {
+		dev, err := d.nexthopDev(nexthop, objPBR.Vrf)
+		if err != nil {
+			log.Warning("[Driver] PBR %+v next hop %s unreachable %v\n", objPBR, nexthop, err)
+			continue
+		}
+		multipath = append(multipath, &netlink.NexthopInfo{LinkIndex: dev, Gw: net.ParseIP(nexthop)})
+	}
+	if len(multipath) == 0 {
+		return d.routeTableFlush(family, table)
+	}
+
+	return d.handle.RouteReplace(&netlink.Route{
+		Dst:       defaultDst(family),
+		Table:     table,
+		MultiPath: multipath,
+	})
+}
+
+func (v *pbrAPI) CreateObject(obj interface{}) error {
+	objPBR := obj.(tai.PBRObj)
+
+	if _, ok := v.tables[objPBR]; ok {
+		log.Info("[Driver] PBR %+v already exist\n", objPBR)
+		return nil
+	}
+
+	table := v.allocTable()
+	rule, err := pbrRule(objPBR, table)
+	if err != nil {
+		return err
+	}
+	if err := v.handle.RuleAdd(rule); err != nil {
+		return err
+	}
+	v.tables[objPBR] = table
+	return nil
+}
+
+func (v *pbrAPI) RemoveObject(obj interface{}) error {
+	objPBR := obj.(tai.PBRObj)
+
+	table, ok := v.tables[objPBR]
+	if !ok {
+		return nil
+	}
+	delete(v.tables, objPBR)
+
+	if err := v.pbrNexthopSet(objPBR, table, nil); err != nil {
+		log.Warning("[Driver] PBR %+v table %d flush failed %v\n", objPBR, table, err)
+	}
+	rule, err := pbrRule(objPBR, table)
+	if err != nil {
+		return err
+	}
+	return v.handle.RuleDel(rule)
+}
+
+func (v *pbrAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objPBR := obj.(tai.PBRObj)
+
+	table, ok := v.tables[objPBR]
+	if !ok {
+		return fmt.Errorf("[Driver] PBR %+v not exist", objPBR)
+	}
+	if nhGroup := attrs.GetStrings(tai.PBRAttrNexthopGroup); attrs.Has(tai.PBRAttrNexthopGroup) {
+		return v.pbrNexthopSet(objPBR, table, nhGroup)
+	}
+	return nil
+}
+
+func (v *pbrAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *pbrAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *pbrAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, destination of kernel rule is normalized and not told
+// from PBR ip of vtepdb
+func (v *pbrAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
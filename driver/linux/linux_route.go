@@ -0,0 +1,235 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+// symmetricNexthop remote vtep next hop of route through l3 bridge of
+// vrf l3vni, router mac of remote vtep is the inner dmac
+type symmetricNexthop struct {
+	vni     int
+	nexthop string
+	rmac    string
+}
+
+type routeAPI struct {
+	moduleID int
+	*linuxDriver
+	// route key to its symmetric next hop
+	symmetric map[string]symmetricNexthop
+}
+
+func newRouteAPI(d *linuxDriver) *routeAPI {
+	return &routeAPI{
+		moduleID:    tai.ObjectIDRoute,
+		linuxDriver: d,
+		symmetric:   make(map[string]symmetricNexthop),
+	}
+}
+
+func routeKey(objRoute tai.RouteObj) string {
+	return objRoute.Vrf + "/" + objRoute.IPPrefix
+}
+
+// symmetricNexthopAdd neighbour of remote vtep on l3 bridge with its
+// router mac, the mac point to remote vtep in vxlan device of l3vni
+func (d *linuxDriver) symmetricNexthopAdd(nh symmetricNexthop) error {
+	dev := vxlanName(nh.vni)
+	if err := d.neighReplace(nh.nexthop, nh.rmac, l3BridgeName(nh.vni)); err != nil {
+		return err
+	}
+	if err := d.fdbReplace(nh.rmac, dev, "", netlink.NTF_MASTER); err != nil {
+		return err
+	}
+	return d.fdbReplace(nh.rmac, dev, nh.nexthop, netlink.NTF_SELF)
+}
+
+// symmetricNexthopDel remove neighbour and fdb of next hop not used by
+// other routes
+func (v *routeAPI) symmetricNexthopDel(nh symmetricNexthop) {
+	for _, used := range v.symmetric {
+		if used == nh {
+			return
+		}
+	}
+	dev := vxlanName(nh.vni)
+	if !v.linkExist(dev) {
+		return
+	}
+	if err := v.neighDel(nh.nexthop, l3BridgeName(nh.vni)); err != nil {
+		log.Warning("[Driver] l3vni %d neighbour %s del failed %v\n", nh.vni, nh.nexthop, err)
+	}
+	if err := v.fdbDel(nh.rmac, dev, "", netlink.NTF_MASTER); err != nil {
+		log.Warning("[Driver] l3vni %d fdb %s del failed %v\n", nh.vni, nh.rmac, err)
+	}
+	if err := v.fdbDel(nh.rmac, dev, "", netlink.NTF_SELF); err != nil {
+		log.Warning("[Driver] l3vni %d fdb %s del failed %v\n", nh.vni, nh.rmac, err)
+	}
+}
+
+// symmetricRouteSet route to remote vtep with router mac is routed by
+// l3vni of vrf, without l3vni it's routed as before
+func (v *routeAPI) symmetricRouteSet(objRoute tai.RouteObj, rmac string) error {
+	key := routeKey(objRoute)
+	old, exist := v.symmetric[key]
+	delete(v.symmetric, key)
+
+	l3vni, ok := v.vrfModule.l3vnis[objRoute.Vrf]
+	if ok && rmac != "" && objRoute.Nexthop != "" {
+		nh := symmetricNexthop{vni: l3vni.vni, nexthop: objRoute.Nexthop, rmac: rmac}
+		if err := v.symmetricNexthopAdd(nh); err != nil {
+			log.Warning("[Driver] Route %s l3vni %d next hop add failed %v\n", objRoute.IPPrefix, l3vni.vni, err)
+		} else {
+			v.symmetric[key] = nh
+		}
+	}
+	if exist {
+		v.symmetricNexthopDel(old)
+	}
+	return v.routeReplace(objRoute)
+}
+
+func (v *routeAPI) routeReplace(objRoute tai.RouteObj) error {
+	route, err := v.route(objRoute)
+	if err != nil {
+		return err
+	}
+	return v.handle.RouteReplace(route)
+}
+
+// route kernel route of route object in vrf table, route to other vrf is
+// leaked by the next hop vrf device, symmetric route is onlink via remote
+// vtep on l3 bridge
+func (v *routeAPI) route(objRoute tai.RouteObj) (*netlink.Route, error) {
+	dst, err := parsePrefix(objRoute.IPPrefix)
+	if err != nil {
+		return nil, err
+	}
+	table, err := v.routeTable(objRoute.Vrf)
+	if err != nil {
+		return nil, err
+	}
+	route := &netlink.Route{Dst: dst, Table: table}
+
+	dev := objRoute.OutputPort
+	gateway := objRoute.Nexthop
+	if nh, ok := v.symmetric[routeKey(objRoute)]; ok {
+		dev, gateway = l3BridgeName(nh.vni), nh.nexthop
+		route.Flags = int(netlink.FLAG_ONLINK)
+	} else if objRoute.Nhvrf != "" && objRoute.Nhvrf != objRoute.Vrf {
+		dev, gateway = objRoute.Nhvrf, ""
+	}
+
+	if gateway != "" {
+		if route.Gw = net.ParseIP(gateway); route.Gw == nil {
+			return nil, fmt.Errorf("[Driver] invalid next hop %s", gateway)
+		}
+	}
+	if dev != "" {
+		link, err := v.linkByName(dev)
+		if err != nil {
+			return nil, err
+		}
+		route.LinkIndex = link.Attrs().Index
+	}
+	return route, nil
+}
+
+func (v *routeAPI) CreateObject(obj interface{}) error {
+	objRoute := obj.(tai.RouteObj)
+
+	if objRoute.Vrf != "" && !v.linkExist(objRoute.Vrf) {
+		log.Warning("[Driver] Static route %+v create failed because of invalid vrf", objRoute)
+	}
+	if objRoute.Policy != "" {
+		log.Info("[Driver] Static route %+v policy ignored\n", objRoute)
+	}
+
+	return v.routeReplace(objRoute)
+}
+
+func (v *routeAPI) RemoveObject(obj interface{}) error {
+	objRoute := obj.(tai.RouteObj)
+
+	key := routeKey(objRoute)
+	if nh, ok := v.symmetric[key]; ok {
+		delete(v.symmetric, key)
+		v.symmetricNexthopDel(nh)
+	}
+
+	if objRoute.Vrf != "" && !v.linkExist(objRoute.Vrf) {
+		return nil
+	}
+	dst, err := parsePrefix(objRoute.IPPrefix)
+	if err != nil {
+		return err
+	}
+	table, err := v.routeTable(objRoute.Vrf)
+	if err != nil {
+		return err
+	}
+	return v.handle.RouteDel(&netlink.Route{Dst: dst, Table: table})
+}
+
+// AddObjectAttr route attrs are carried in route object and set by
+// create, except router mac of remote vtep for symmetric routing
+func (v *routeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objRoute := obj.(tai.RouteObj)
+
+	if rmac := attrs.GetString(tai.RouteAttrRouteMac); rmac != "" {
+		return v.symmetricRouteSet(objRoute, rmac)
+	}
+	return nil
+}
+
+// DelObjectAttr route without router mac is routed as before
+func (v *routeAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objRoute := obj.(tai.RouteObj)
+
+	if _, ok := v.symmetric[routeKey(objRoute)]; ok && attrs.Has(tai.RouteAttrRouteMac) {
+		return v.symmetricRouteSet(objRoute, "")
+	}
+	return nil
+}
+
+// SetObjectAttr route is replaced with new attrs
+func (v *routeAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objRoute := obj.(tai.RouteObj)
+
+	for attr, attrValue := range attrs {
+		value, ok := attrValue.(string)
+		if !ok {
+			continue
+		}
+		switch attr {
+		case tai.RouteAttrNexthop:
+			objRoute.Nexthop = value
+		case tai.RouteAttrNhvrf:
+			objRoute.Nhvrf = value
+		case tai.RouteAttrOutputPort:
+			objRoute.OutputPort = value
+		}
+	}
+
+	rmac := v.symmetric[routeKey(objRoute)].rmac
+	if attrs.Has(tai.RouteAttrRouteMac) {
+		rmac = attrs.GetString(tai.RouteAttrRouteMac)
+	}
+	return v.symmetricRouteSet(objRoute, rmac)
+}
+
+func (v *routeAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, prefix of kernel route is normalized and not told
+// from route prefix of vtepdb
+func (v *routeAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
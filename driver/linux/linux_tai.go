@@ -0,0 +1,132 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// module get object api, driver mutex is held until unlock called
+func (d *linuxDriver) module(objID tai.ObjID) (moduleAPI, error) {
+	d.mutex.Lock()
+	if d.ModuleAPIs[objID] == nil {
+		d.mutex.Unlock()
+		return nil, fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
+	}
+	return d.ModuleAPIs[objID], nil
+}
+
+// TaiBegin calls are programmed into kernel immediately, batch can't be
+// rolled back
+func (d *linuxDriver) TaiBegin() error {
+	return nil
+}
+
+func (d *linuxDriver) TaiCommit() error {
+	return nil
+}
+
+func (d *linuxDriver) TaiAbort() {}
+
+func (d *linuxDriver) TaiCreateObject(objID tai.ObjID, obj interface{}) error {
+	log.Info("[Driver] TaiCreateObject %v => %+v\n", tai.ObjectOrder[objID], obj)
+
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.CreateObject(obj)
+}
+
+func (d *linuxDriver) TaiRemoveObject(objID tai.ObjID, obj interface{}) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.RemoveObject(obj)
+}
+
+func (d *linuxDriver) TaiAddObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.AddObjectAttr(obj, attr)
+}
+
+func (d *linuxDriver) TaiDelObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.DelObjectAttr(obj, attr)
+}
+
+func (d *linuxDriver) TaiSetObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.SetObjectAttr(obj, attr)
+}
+
+func (d *linuxDriver) TaiGetObjectAttr(objID tai.ObjID, obj interface{},
+	attr []tai.ObjAttrID) (tai.Attrs, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.GetObjectAttr(obj, attr)
+}
+
+func (d *linuxDriver) TaiListObject(objID tai.ObjID) ([]interface{}, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.ListObject()
+}
+
+// capabilityAttrs attrs programmed by modules, ACL rule attrs are all
+// rendered
+var capabilityAttrs = map[tai.ObjID][]tai.ObjAttrID{
+	tai.ObjectIDBridge:          {tai.BridgeAttrVxlanTunnel, tai.BridgeAttrL2vni},
+	tai.ObjectIDVrf:             {tai.VrfAttrL3vni, tai.VrfAttrTunnel},
+	tai.ObjectIDL2Port:          {tai.L2portAttrVlanTag, tai.L2portAttrVlanTransparent},
+	tai.ObjectIDL3Port:          {tai.L3portAttrVrfBinding, tai.L3portAttrIpaddr, tai.L3portAttrMacaddr, tai.L3portAttrVlanTag},
+	tai.ObjectIDFDB:             {tai.FdbAttrRemoteIP},
+	tai.ObjectIDNeighbour:       {tai.NeighbourAttrMacaddr, tai.NeighbourAttrOutPort, tai.NeighbourAttrRemoteIP},
+	tai.ObjectIDRoute:           {tai.RouteAttrNexthop, tai.RouteAttrNhvrf, tai.RouteAttrOutputPort, tai.RouteAttrRouteMac},
+	tai.ObjectIDTunnel:          {tai.TunnelAttrIpaddr, tai.TunnelAttrRouteMac},
+	tai.ObjectIDMcastFDB:        {tai.McastFdbAttrLocators},
+	tai.ObjectIDACL:             {tai.ACLAttrPorts, tai.ACLAttrStage, tai.ACLAttrType},
+	tai.ObjectIDACLRule:         nil,
+	tai.ObjectIDPBR:             {tai.PBRAttrNexthopGroup},
+	tai.ObjectIDAutoGatewayConf: {tai.AutoGatewayConfAttrIP, tai.AutoGatewayConfAttrIpv6, tai.AutoGatewayConfAttrMtu, tai.AutoGatewayConfAttrUplinks, tai.AutoGatewayConfAttrNexthops},
+}
+
+// TaiGetCapability objects of registered modules, kernel tables have no
+// fixed size so no limit is reported
+func (d *linuxDriver) TaiGetCapability() tai.Capability {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	capability := tai.Capability{
+		Objects: make(map[tai.ObjID][]tai.ObjAttrID),
+	}
+	for objID := range d.ModuleAPIs {
+		capability.Objects[objID] = capabilityAttrs[objID]
+	}
+	return capability
+}
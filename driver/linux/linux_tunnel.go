@@ -0,0 +1,177 @@
+package linux
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+type tunnelAPI struct {
+	moduleID int
+	*linuxDriver
+	// tunnel name to source ip
+	tunnels map[string]string
+	// tunnel name to local router mac
+	routeMacs map[string]string
+}
+
+func newTunnelAPI(d *linuxDriver) *tunnelAPI {
+	return &tunnelAPI{
+		moduleID:    tai.ObjectIDTunnel,
+		linuxDriver: d,
+		tunnels:     make(map[string]string),
+		routeMacs:   make(map[string]string),
+	}
+}
+
+// vxlanLinkAdd create vxlan device of vni in bridge, source ip get from
+// tunnel, mac learning is disabled since remote fdb is set by controller
+func (d *linuxDriver) vxlanLinkAdd(bdName string, vni int, tunnelName string) error {
+	srcIP, ok := d.tunnelModule.tunnels[tunnelName]
+	if !ok {
+		return fmt.Errorf("[Driver] tunnel %s not exist", tunnelName)
+	}
+
+	name := vxlanName(vni)
+	err := d.linkAdd(&netlink.Vxlan{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		VxlanId:   vni,
+		SrcAddr:   net.ParseIP(srcIP),
+		Port:      vxlanDefaultDestPort,
+		Learning:  false,
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.linkSetMtu(name, interfaceDefaultMtu-50); err != nil {
+		log.Warning("[Driver] vxlan %s set mtu failed %v\n", name, err)
+	}
+	if err := d.linkSetMaster(name, bdName); err != nil {
+		return err
+	}
+	return d.linkUp(name)
+}
+
+func (v *tunnelAPI) CreateObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	if objTunnel.Anycast {
+		log.Info("[Driver] Tunnel %s anycast source %s\n", objTunnel.Name, objTunnel.Ipaddr)
+	}
+	if _, ok := v.tunnels[objTunnel.Name]; ok {
+		log.Info("[Driver] Tunnel %s already exist\n", objTunnel.Name)
+	}
+	v.tunnels[objTunnel.Name] = objTunnel.Ipaddr
+	return nil
+}
+
+func (v *tunnelAPI) RemoveObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	for bdName, tunnelName := range v.bdModule.tunnels {
+		if tunnelName != objTunnel.Name {
+			continue
+		}
+		if err := v.linkDel(vxlanName(getVniByName(bdName, bridgeNamePrefix))); err != nil {
+			return err
+		}
+		delete(v.bdModule.tunnels, bdName)
+	}
+	for vrfName, l3vni := range v.vrfModule.l3vnis {
+		if l3vni.tunnel != objTunnel.Name {
+			continue
+		}
+		if err := v.l3vniLinkDel(l3vni.vni); err != nil {
+			return err
+		}
+		delete(v.vrfModule.l3vnis, vrfName)
+	}
+	delete(v.tunnels, objTunnel.Name)
+	delete(v.routeMacs, objTunnel.Name)
+	return nil
+}
+
+// routeMacSet set router mac of tunnel to l3 bridges of vrfs
+func (v *tunnelAPI) routeMacSet(tunnelName string, rmac string) {
+	v.routeMacs[tunnelName] = rmac
+	if rmac == "" {
+		return
+	}
+	for _, l3vni := range v.vrfModule.l3vnis {
+		if l3vni.tunnel != tunnelName {
+			continue
+		}
+		name := l3BridgeName(l3vni.vni)
+		if err := v.linkSetMac(name, rmac); err != nil {
+			log.Warning("[Driver] l3 bridge %s set mac %s failed %v\n", name, rmac, err)
+		}
+	}
+}
+
+func (v *tunnelAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.SetObjectAttr(obj, attrs)
+}
+
+func (v *tunnelAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return nil
+}
+
+// SetObjectAttr source ip can't be changed on vxlan device, recreate
+// vxlan devices of tunnel, remote fdb would be resynced by controller
+func (v *tunnelAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	if _, ok := v.tunnels[objTunnel.Name]; !ok {
+		log.Warning("[Driver] Tunnel %s not found when set attr\n", objTunnel.Name)
+		return nil
+	}
+
+	if attrs.Has(tai.TunnelAttrRouteMac) {
+		v.routeMacSet(objTunnel.Name, attrs.GetString(tai.TunnelAttrRouteMac))
+	}
+
+	ipaddr := attrs.GetString(tai.TunnelAttrIpaddr)
+	if ipaddr == "" || ipaddr == v.tunnels[objTunnel.Name] {
+		return nil
+	}
+	v.tunnels[objTunnel.Name] = ipaddr
+
+	for bdName, tunnelName := range v.bdModule.tunnels {
+		if tunnelName != objTunnel.Name {
+			continue
+		}
+		vni := getVniByName(bdName, bridgeNamePrefix)
+		if err := v.linkDel(vxlanName(vni)); err != nil {
+			return err
+		}
+		if err := v.vxlanLinkAdd(bdName, vni, tunnelName); err != nil {
+			return err
+		}
+	}
+	for vrfName, l3vni := range v.vrfModule.l3vnis {
+		if l3vni.tunnel != objTunnel.Name {
+			continue
+		}
+		if err := v.linkDel(vxlanName(l3vni.vni)); err != nil {
+			return err
+		}
+		if err := v.vxlanLinkAdd(l3BridgeName(l3vni.vni), l3vni.vni, l3vni.tunnel); err != nil {
+			log.Warning("[Driver] vrf %s l3vni %d add failed %v\n", vrfName, l3vni.vni, err)
+		}
+	}
+	return nil
+}
+
+func (v *tunnelAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject none, tunnel is not a kernel device, vxlan devices of
+// tunnel are removed with bridges and vrfs
+func (v *tunnelAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,173 @@
+package linux
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/vishvananda/netlink"
+)
+
+// l3vni vxlan of vrf routing symmetric IRB to remote vteps
+type l3vni struct {
+	vni    int
+	tunnel string
+}
+
+type vrfAPI struct {
+	moduleID int
+	*linuxDriver
+	// vrf name to its l3vni
+	l3vnis map[string]l3vni
+}
+
+func newVrfAPI(d *linuxDriver) *vrfAPI {
+	return &vrfAPI{
+		moduleID:    tai.ObjectIDVrf,
+		linuxDriver: d,
+		l3vnis:      make(map[string]l3vni),
+	}
+}
+
+// l3vniLinkAdd create l3 bridge of vni in vrf with vxlan device of vni,
+// mac of l3 bridge is router mac of tunnel which is inner dmac from
+// remote vteps
+func (d *linuxDriver) l3vniLinkAdd(vrfName string, vni int, tunnelName string) error {
+	name := l3BridgeName(vni)
+	if err := d.bridgeLinkAdd(name); err != nil {
+		return err
+	}
+	if err := d.linkSetMaster(name, vrfName); err != nil {
+		return err
+	}
+	if rmac := d.tunnelModule.routeMacs[tunnelName]; rmac != "" {
+		if err := d.linkSetMac(name, rmac); err != nil {
+			log.Warning("[Driver] l3 bridge %s set mac %s failed %v\n", name, rmac, err)
+		}
+	}
+	if err := d.linkUp(name); err != nil {
+		return err
+	}
+	return d.vxlanLinkAdd(name, vni, tunnelName)
+}
+
+func (d *linuxDriver) l3vniLinkDel(vni int) error {
+	if err := d.linkDel(vxlanName(vni)); err != nil {
+		return err
+	}
+	return d.linkDel(l3BridgeName(vni))
+}
+
+func (v *vrfAPI) CreateObject(obj interface{}) error {
+	objVrf := obj.(tai.VrfObj)
+
+	vni := getVniByName(objVrf.Name, vrfNamePrefix)
+	if vni == 0 {
+		return fmt.Errorf("[Driver] Invalid vrf name %s", objVrf.Name)
+	}
+
+	err := v.linkAdd(&netlink.Vrf{
+		LinkAttrs: netlink.LinkAttrs{Name: objVrf.Name},
+		Table:     uint32(vrfTable(vni)),
+	})
+	if err != nil {
+		return err
+	}
+	return v.linkUp(objVrf.Name)
+}
+
+func (v *vrfAPI) RemoveObject(obj interface{}) error {
+	objVrf := obj.(tai.VrfObj)
+
+	if l3vni, ok := v.l3vnis[objVrf.Name]; ok {
+		if err := v.l3vniLinkDel(l3vni.vni); err != nil {
+			return err
+		}
+		delete(v.l3vnis, objVrf.Name)
+	}
+	return v.linkDel(objVrf.Name)
+}
+
+// AddObjectAttr vrf route table is bound to vrf name, l3vni is added
+// with tunnel of vrf for symmetric IRB
+func (v *vrfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objVrf := obj.(tai.VrfObj)
+
+	if !v.linkExist(objVrf.Name) {
+		return fmt.Errorf("[Driver] vrf %s not exist", objVrf.Name)
+	}
+
+	l3vni := v.l3vnis[objVrf.Name]
+	if attrs.Has(tai.VrfAttrL3vni) {
+		l3vni.vni = attrs.GetInt(tai.VrfAttrL3vni)
+	}
+	if attrs.Has(tai.VrfAttrTunnel) {
+		l3vni.tunnel = attrs.GetString(tai.VrfAttrTunnel)
+	}
+	if l3vni.vni == 0 || l3vni.tunnel == "" {
+		return nil
+	}
+	if err := v.l3vniLinkAdd(objVrf.Name, l3vni.vni, l3vni.tunnel); err != nil {
+		log.Warning("[Driver] vrf %s l3vni %d add failed %v\n", objVrf.Name, l3vni.vni, err)
+		return nil
+	}
+	v.l3vnis[objVrf.Name] = l3vni
+	return nil
+}
+
+func (v *vrfAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objVrf := obj.(tai.VrfObj)
+
+	l3vni, ok := v.l3vnis[objVrf.Name]
+	if !ok || !attrs.Has(tai.VrfAttrL3vni) && !attrs.Has(tai.VrfAttrTunnel) {
+		return nil
+	}
+	delete(v.l3vnis, objVrf.Name)
+	return v.l3vniLinkDel(l3vni.vni)
+}
+
+// SetObjectAttr l3vni devices are recreated for new vni or tunnel
+func (v *vrfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objVrf := obj.(tai.VrfObj)
+
+	if l3vni, ok := v.l3vnis[objVrf.Name]; ok {
+		if err := v.l3vniLinkDel(l3vni.vni); err != nil {
+			return err
+		}
+	}
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *vrfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject vrfs named "Vrf"+vni, l3vni of vrf is loaded from l3 bridge
+// in vrf to be removed with listed vrf
+func (v *vrfAPI) ListObject() ([]interface{}, error) {
+	links, err := v.linkList()
+	if err != nil {
+		return nil, err
+	}
+	indexes := linkIndexes(links)
+
+	var objs []interface{}
+	for _, link := range links {
+		name := link.Attrs().Name
+		if _, ok := link.(*netlink.Vrf); ok && getVniByName(name, vrfNamePrefix) != 0 {
+			objs = append(objs, tai.VrfObj{Name: name})
+			continue
+		}
+
+		vni := getVniByName(name, l3BridgeNamePrefix)
+		vrfName := linkMaster(link, indexes)
+		if vni == 0 || getVniByName(vrfName, vrfNamePrefix) == 0 {
+			continue
+		}
+		if _, ok := v.l3vnis[vrfName]; !ok {
+			v.l3vnis[vrfName] = l3vni{vni: vni}
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,133 @@
+// Package record is the TAI driver recording every TAI call into a file
+// without programming any switch. It's used as a shadow driver beside the
+// real driver for auditing, or replayed against a canary driver.
+//
+// One call is one line: time, operation, object, then object attrs or
+// attr ids, eg:
+//
+//	2021-03-01T10:00:00.000Z create Bridge {Name:Bd100 Vni:100}
+//
+// Batch calls have no object, eg:
+//
+//	2021-03-01T10:00:00.000Z commit
+//
+// Calls of driver instance of a switch with own driver config are tagged
+// with the switch name, eg:
+//
+//	2021-03-01T10:00:00.000Z [ps1] commit
+package record
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// DriverName of record TAI driver
+const DriverName = "RECORD"
+
+// File TAI calls are appended to
+var File = "/var/log/govtep/tai_record.log"
+
+type recordDriver struct {
+	DriverName string
+	file       *os.File
+	mutex      sync.Mutex
+	tag        string
+}
+
+func init() {
+	tai.RegisterDriver(DriverName, Init)
+}
+
+// Init record TAI driver, open record file. Driver address is the record
+// file of instance, File by default.
+func Init(config tai.DriverConfig) (tai.DriverHandler, error) {
+	name := File
+	if config.Addr != "" {
+		name = config.Addr
+	}
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("[Driver] open record file failed: %v", err)
+	}
+
+	d := &recordDriver{
+		DriverName: DriverName,
+		file:       file,
+	}
+	if config.Switch != "" {
+		d.tag = "[" + config.Switch + "] "
+	}
+	return d, nil
+}
+
+// record write one call line, map is printed with sorted keys
+func (d *recordDriver) record(op string, objID tai.ObjID, obj interface{}, args ...interface{}) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	line := fmt.Sprintf("%s %s%s", time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), d.tag, op)
+	if objID != 0 {
+		line += fmt.Sprintf(" %v %+v", tai.ObjectOrder[objID], obj)
+	}
+	for _, arg := range args {
+		line += fmt.Sprintf(" %+v", arg)
+	}
+	_, err := fmt.Fprintln(d.file, line)
+	return err
+}
+
+func (d *recordDriver) TaiBegin() error {
+	return d.record("begin", 0, nil)
+}
+
+func (d *recordDriver) TaiCommit() error {
+	return d.record("commit", 0, nil)
+}
+
+func (d *recordDriver) TaiAbort() {
+	_ = d.record("abort", 0, nil)
+}
+
+func (d *recordDriver) TaiCreateObject(objID tai.ObjID, obj interface{}) error {
+	return d.record("create", objID, obj)
+}
+
+func (d *recordDriver) TaiRemoveObject(objID tai.ObjID, obj interface{}) error {
+	return d.record("remove", objID, obj)
+}
+
+func (d *recordDriver) TaiAddObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	return d.record("add", objID, obj, attr)
+}
+
+func (d *recordDriver) TaiDelObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	return d.record("del", objID, obj, attr)
+}
+
+func (d *recordDriver) TaiSetObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	return d.record("set", objID, obj, attr)
+}
+
+// TaiGetObjectAttr record driver keeps no state, nil result is not
+// compared with primary driver
+func (d *recordDriver) TaiGetObjectAttr(objID tai.ObjID, obj interface{},
+	attr []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, d.record("get", objID, obj, attr)
+}
+
+func (d *recordDriver) TaiListObject(objID tai.ObjID) ([]interface{}, error) {
+	return nil, d.record("list", objID, nil)
+}
+
+// TaiGetCapability record driver accepts every object
+func (d *recordDriver) TaiGetCapability() tai.Capability {
+	return tai.FullCapability()
+}
@@ -0,0 +1,120 @@
+package sonic
+
+import (
+	"fmt"
+	"strings"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type aclAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newAclAPI(d *sonicDriver) *aclAPI {
+	return &aclAPI{
+		moduleID:    tai.ObjectIDACL,
+		sonicDriver: d,
+	}
+}
+
+// aclTypes vtep acl type to SONiC ACL_TABLE type
+var aclTypes = map[string]string{
+	vtepdb.ACLTypeL2:       "L2",
+	vtepdb.ACLTypeL3:       "L3",
+	vtepdb.ACLTypeL3v6:     "L3V6",
+	vtepdb.ACLTypeMirror:   "MIRROR",
+	vtepdb.ACLTypeMirrorv6: "MIRRORV6",
+}
+
+func aclTableKey(aclName string) string {
+	return configKey(tableACLTable, aclName)
+}
+
+func (v *aclAPI) CreateObject(obj interface{}) error {
+	objACL := obj.(tai.ACLObj)
+
+	key := aclTableKey(objACL.ACLName)
+	if exist, err := v.configDB.exists(key); err != nil {
+		return err
+	} else if exist {
+		log.Info("[Driver] ACL %s already exist\n", objACL.ACLName)
+		return nil
+	}
+	return v.configDB.hset(key, map[string]string{
+		"policy_desc": objACL.ACLName,
+		"type":        aclTypes[vtepdb.ACLTypeL2],
+		"stage":       strings.ToLower(vtepdb.ACLStageIngress),
+	})
+}
+
+// RemoveObject rules of acl are removed with acl
+func (v *aclAPI) RemoveObject(obj interface{}) error {
+	objACL := obj.(tai.ACLObj)
+
+	rules, err := v.configDB.scan(configKey(tableACLRule, objACL.ACLName, "*"))
+	if err != nil {
+		return err
+	}
+	return v.configDB.del(append(rules, aclTableKey(objACL.ACLName))...)
+}
+
+func (v *aclAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACL := obj.(tai.ACLObj)
+
+	key := aclTableKey(objACL.ACLName)
+	if exist, err := v.configDB.exists(key); err != nil {
+		return err
+	} else if !exist {
+		return fmt.Errorf("[Driver] ACL %s not exist", objACL.ACLName)
+	}
+
+	fields := make(map[string]string)
+	for attr, attrValue := range attrs {
+		value, ok := attrValue.(string)
+		if !ok {
+			continue
+		}
+		switch attr {
+		case tai.ACLAttrStage:
+			fields["stage"] = strings.ToLower(value)
+		case tai.ACLAttrType:
+			aclType, ok := aclTypes[value]
+			if !ok {
+				return fmt.Errorf("[Driver] ACL %s type %s not supported", objACL.ACLName, value)
+			}
+			fields["type"] = aclType
+		case tai.ACLAttrPorts:
+			ports := strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+			fields["ports@"] = strings.Join(ports, ",")
+		}
+	}
+	return v.configDB.hset(key, fields)
+}
+
+func (v *aclAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACL := obj.(tai.ACLObj)
+
+	if _, ok := attrs[tai.ACLAttrPorts]; ok {
+		return v.configDB.hdel(aclTableKey(objACL.ACLName), "ports@")
+	}
+	return nil
+}
+
+func (v *aclAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *aclAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *aclAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
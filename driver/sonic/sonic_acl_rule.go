@@ -0,0 +1,257 @@
+package sonic
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// aclRuleMaxPriority SONiC match higher priority first, vtep acl rule
+// match lower sequence first
+const aclRuleMaxPriority = 9999
+
+type aclRuleAPI struct {
+	moduleID int
+	*sonicDriver
+	// acl rule attrs are updated separately, rule entry is rewritten
+	rules map[tai.ACLRuleObj]tai.Attrs
+}
+
+func newAclRuleAPI(d *sonicDriver) *aclRuleAPI {
+	return &aclRuleAPI{
+		moduleID:    tai.ObjectIDACLRule,
+		sonicDriver: d,
+		rules:       make(map[tai.ACLRuleObj]tai.Attrs),
+	}
+}
+
+func aclRuleKey(objACLRule tai.ACLRuleObj) string {
+	return configKey(tableACLRule, objACLRule.ACLName, "RULE_"+strconv.Itoa(objACLRule.Sequence))
+}
+
+func aclRulePriority(sequence int) string {
+	priority := aclRuleMaxPriority - sequence
+	if priority < 1 {
+		priority = 1
+	}
+	return strconv.Itoa(priority)
+}
+
+func firstString(attrs tai.Attrs, id tai.ObjAttrID) (string, bool) {
+	values := attrs.GetStrings(id)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func firstInt(attrs tai.Attrs, id tai.ObjAttrID) (int, bool) {
+	values := attrs.GetInts(id)
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[0], true
+}
+
+// ipMask ip prefix of ip and mask, mask is prefix length or dotted decimal
+func ipMask(ip string, mask string) (string, bool, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return "", false, fmt.Errorf("[Driver] invalid ip %s", ip)
+	}
+	v6 := addr.To4() == nil
+	if mask == "" {
+		return ipPrefix(ip), v6, nil
+	}
+	if _, err := strconv.Atoi(mask); err == nil {
+		return ip + "/" + mask, v6, nil
+	}
+	maskIP := net.ParseIP(mask)
+	if maskIP == nil || maskIP.To4() == nil {
+		return "", false, fmt.Errorf("[Driver] invalid mask %s", mask)
+	}
+	ones, bits := net.IPMask(maskIP.To4()).Size()
+	if bits == 0 {
+		return "", false, fmt.Errorf("[Driver] invalid mask %s", mask)
+	}
+	return ip + "/" + strconv.Itoa(ones), v6, nil
+}
+
+func portRange(attrs tai.Attrs, minAttr tai.ObjAttrID, maxAttr tai.ObjAttrID) (string, bool) {
+	min, okMin := firstInt(attrs, minAttr)
+	max, okMax := firstInt(attrs, maxAttr)
+	switch {
+	case okMin && okMax && min != max:
+		return strconv.Itoa(min) + "-" + strconv.Itoa(max), true
+	case okMin:
+		return strconv.Itoa(min), false
+	case okMax:
+		return strconv.Itoa(max), false
+	}
+	return "", false
+}
+
+// aclRuleFields ACL_RULE fields of acl rule attrs
+func aclRuleFields(objACLRule tai.ACLRuleObj, attrs tai.Attrs) (map[string]string, error) {
+	fields := map[string]string{
+		"PRIORITY": aclRulePriority(objACLRule.Sequence),
+	}
+
+	if mac, ok := firstString(attrs, tai.ACLRuleAttrMatchSRCMAC); ok {
+		fields["SRC_MAC"] = mac
+	}
+	if mac, ok := firstString(attrs, tai.ACLRuleAttrMatchDSTMAC); ok {
+		fields["DST_MAC"] = mac
+	}
+	if ethertype, ok := firstString(attrs, tai.ACLRuleAttrMatchETHERTYPE); ok {
+		fields["ETHER_TYPE"] = ethertype
+	}
+
+	v6 := false
+	for _, ipAttr := range []struct {
+		ip, mask tai.ObjAttrID
+		field    string
+	}{
+		{tai.ACLRuleAttrMatchSRCIP, tai.ACLRuleAttrMatchSRCMASK, "SRC_IP"},
+		{tai.ACLRuleAttrMatchDSTIP, tai.ACLRuleAttrMatchDSTMASK, "DST_IP"},
+	} {
+		ip, ok := firstString(attrs, ipAttr.ip)
+		if !ok {
+			continue
+		}
+		mask, _ := firstString(attrs, ipAttr.mask)
+		prefix, isV6, err := ipMask(ip, mask)
+		if err != nil {
+			return nil, err
+		}
+		field := ipAttr.field
+		if isV6 {
+			v6 = true
+			field += "V6"
+		}
+		fields[field] = prefix
+	}
+
+	if protocol, ok := firstInt(attrs, tai.ACLRuleAttrMatchPROTOCOL); ok {
+		if v6 {
+			fields["NEXT_HEADER"] = strconv.Itoa(protocol)
+		} else {
+			fields["IP_PROTOCOL"] = strconv.Itoa(protocol)
+		}
+	}
+	if port, isRange := portRange(attrs, tai.ACLRuleAttrMatchSRCPORTMIN, tai.ACLRuleAttrMatchSRCPORTMAX); port != "" {
+		if isRange {
+			fields["L4_SRC_PORT_RANGE"] = port
+		} else {
+			fields["L4_SRC_PORT"] = port
+		}
+	}
+	if port, isRange := portRange(attrs, tai.ACLRuleAttrMatchDSTPORTMIN, tai.ACLRuleAttrMatchDSTPORTMAX); port != "" {
+		if isRange {
+			fields["L4_DST_PORT_RANGE"] = port
+		} else {
+			fields["L4_DST_PORT"] = port
+		}
+	}
+
+	if flags, ok := firstInt(attrs, tai.ACLRuleAttrMatchTCPFLAGS); ok {
+		mask, ok := firstInt(attrs, tai.ACLRuleAttrMatchTCPFLAGSMASK)
+		if !ok {
+			mask = flags
+		}
+		fields["TCP_FLAGS"] = fmt.Sprintf("0x%x/0x%x", flags, mask)
+	}
+
+	icmp := "ICMP"
+	if v6 {
+		icmp = "ICMPV6"
+	}
+	if icmpType, ok := firstInt(attrs, tai.ACLRuleAttrMatchICMPTYPE); ok {
+		fields[icmp+"_TYPE"] = strconv.Itoa(icmpType)
+	}
+	if icmpCode, ok := firstInt(attrs, tai.ACLRuleAttrMatchICMPCODE); ok {
+		fields[icmp+"_CODE"] = strconv.Itoa(icmpCode)
+	}
+
+	action := attrs.GetString(tai.ACLRuleAttrAction)
+	switch action {
+	case vtepdb.ACLRuleActionPermit:
+		fields["PACKET_ACTION"] = "FORWARD"
+	case vtepdb.ACLRuleActionDeny:
+		fields["PACKET_ACTION"] = "DROP"
+	default:
+		return nil, fmt.Errorf("[Driver] ACL %s rule %d invalid action %q",
+			objACLRule.ACLName, objACLRule.Sequence, action)
+	}
+
+	return fields, nil
+}
+
+// ruleWrite rewrite whole acl rule entry, so deleted match is removed
+func (v *aclRuleAPI) ruleWrite(objACLRule tai.ACLRuleObj) error {
+	fields, err := aclRuleFields(objACLRule, v.rules[objACLRule])
+	if err != nil {
+		return err
+	}
+	key := aclRuleKey(objACLRule)
+	if err = v.configDB.del(key); err != nil {
+		return err
+	}
+	return v.configDB.hset(key, fields)
+}
+
+func (v *aclRuleAPI) CreateObject(obj interface{}) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	v.rules[objACLRule] = make(tai.Attrs)
+	return nil
+}
+
+func (v *aclRuleAPI) RemoveObject(obj interface{}) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	delete(v.rules, objACLRule)
+	return v.configDB.del(aclRuleKey(objACLRule))
+}
+
+func (v *aclRuleAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	rule, ok := v.rules[objACLRule]
+	if !ok {
+		return fmt.Errorf("[Driver] ACL %s rule %d not exist", objACLRule.ACLName, objACLRule.Sequence)
+	}
+	for attr, value := range attrs {
+		rule[attr] = value
+	}
+	return v.ruleWrite(objACLRule)
+}
+
+func (v *aclRuleAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objACLRule := obj.(tai.ACLRuleObj)
+
+	rule, ok := v.rules[objACLRule]
+	if !ok {
+		return nil
+	}
+	for attr := range attrs {
+		delete(rule, attr)
+	}
+	return v.ruleWrite(objACLRule)
+}
+
+func (v *aclRuleAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *aclRuleAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *aclRuleAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
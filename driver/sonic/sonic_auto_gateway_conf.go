@@ -0,0 +1,78 @@
+package sonic
+
+import (
+	"strconv"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type autoGatewayConfAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newAutoGatewayConfAPI(d *sonicDriver) *autoGatewayConfAPI {
+	return &autoGatewayConfAPI{
+		moduleID:    tai.ObjectIDAutoGatewayConf,
+		sonicDriver: d,
+	}
+}
+
+// autoGatewayPort vlan sub interface of auto gateway physical port
+func autoGatewayPort(objConf tai.AutoGatewayConfObj) string {
+	return objConf.PhysicalPort + "." + strconv.Itoa(objConf.Vlan)
+}
+
+func (v *autoGatewayConfAPI) CreateObject(obj interface{}) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	fields := map[string]string{
+		"admin_status": interfaceDefaultAdminStatus,
+		"vlan":         strconv.Itoa(objConf.Vlan),
+	}
+	if objConf.Vrf != "" {
+		fields["vrf_name"] = objConf.Vrf
+	}
+	return v.configDB.hset(configKey(tableVlanSubInterface, autoGatewayPort(objConf)), fields)
+}
+
+func (v *autoGatewayConfAPI) RemoveObject(obj interface{}) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	port := autoGatewayPort(objConf)
+	addrs, err := v.configDB.scan(configKey(tableVlanSubInterface, port, "*"))
+	if err != nil {
+		return err
+	}
+	return v.configDB.del(append(addrs, configKey(tableVlanSubInterface, port))...)
+}
+
+func (v *autoGatewayConfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	if ip := attrs.GetString(tai.AutoGatewayConfAttrIP); ip != "" {
+		return v.configDB.hset(configKey(tableVlanSubInterface, autoGatewayPort(objConf), ipPrefix(ip)), nullFields)
+	}
+	return nil
+}
+
+func (v *autoGatewayConfAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objConf := obj.(tai.AutoGatewayConfObj)
+
+	if ip := attrs.GetString(tai.AutoGatewayConfAttrIP); ip != "" {
+		return v.configDB.del(configKey(tableVlanSubInterface, autoGatewayPort(objConf), ipPrefix(ip)))
+	}
+	return nil
+}
+
+func (v *autoGatewayConfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *autoGatewayConfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *autoGatewayConfAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,231 @@
+package sonic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type bridgeAPI struct {
+	moduleID int
+	*sonicDriver
+	// bridge name to local vlan id
+	vlans map[string]int
+	// bridge name to vxlan tunnel name
+	tunnels map[string]string
+}
+
+func newBridgeAPI(d *sonicDriver) *bridgeAPI {
+	return &bridgeAPI{
+		moduleID:    tai.ObjectIDBridge,
+		sonicDriver: d,
+		vlans:       make(map[string]int),
+		tunnels:     make(map[string]string),
+	}
+}
+
+// allocVlan SONiC bridges vni by vlan, vlan created by controller is
+// described by bridge name so it's reused after restart, vlan equal to
+// vni is preferred
+func (d *bridgeAPI) allocVlan(bdName string, vni int) (int, error) {
+	if vlan, ok := d.vlans[bdName]; ok {
+		return vlan, nil
+	}
+
+	keys, err := d.configDB.scan(configKey(tableVlan, vlanNamePrefix+"*"))
+	if err != nil {
+		return 0, err
+	}
+	used := make(map[int]bool)
+	for _, key := range keys {
+		vlan, err := strconv.Atoi(strings.TrimPrefix(key, configKey(tableVlan, vlanNamePrefix)))
+		if err != nil {
+			continue
+		}
+		used[vlan] = true
+		if description, _ := d.configDB.hget(key, "description"); description == bdName {
+			return vlan, nil
+		}
+	}
+
+	if vni >= vlanMin && vni <= vlanMax && !used[vni] {
+		return vni, nil
+	}
+	for vlan := vlanMin; vlan <= vlanMax; vlan++ {
+		if !used[vlan] {
+			return vlan, nil
+		}
+	}
+	return 0, fmt.Errorf("[Driver] no free vlan for bridge %s", bdName)
+}
+
+// bdVlan get vlan name of bridge
+func (d *sonicDriver) bdVlan(bdName string) (string, error) {
+	vlan, ok := d.bdModule.vlans[bdName]
+	if !ok {
+		return "", fmt.Errorf("[Driver] BD %s not exist", bdName)
+	}
+	return vlanName(vlan), nil
+}
+
+// vniMapKey VXLAN_TUNNEL_MAP key of bridge
+func vniMapKey(tunnelName string, vni int, vlan string) string {
+	return configKey(tableVxlanTunnelMap, tunnelName, "map_"+strconv.Itoa(vni)+"_"+vlan)
+}
+
+func (d *bridgeAPI) CreateObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	vni := getVniByName(objBridge.Name, bridgeNamePrefix)
+	if vni == 0 {
+		return fmt.Errorf("[Driver] Invalid bridge name %s", objBridge.Name)
+	}
+
+	vlan, err := d.allocVlan(objBridge.Name, vni)
+	if err != nil {
+		return err
+	}
+	err = d.configDB.hset(configKey(tableVlan, vlanName(vlan)), map[string]string{
+		"vlanid":       strconv.Itoa(vlan),
+		"description":  objBridge.Name,
+		"admin_status": interfaceDefaultAdminStatus,
+	})
+	if err != nil {
+		return err
+	}
+	d.vlans[objBridge.Name] = vlan
+	return nil
+}
+
+func (d *bridgeAPI) RemoveObject(obj interface{}) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	vlan, ok := d.vlans[objBridge.Name]
+	if !ok {
+		return nil
+	}
+	vni := getVniByName(objBridge.Name, bridgeNamePrefix)
+	if tunnelName, ok := d.tunnels[objBridge.Name]; ok {
+		if err := d.configDB.del(vniMapKey(tunnelName, vni, vlanName(vlan))); err != nil {
+			return err
+		}
+		delete(d.tunnels, objBridge.Name)
+	}
+
+	members, err := d.configDB.scan(configKey(tableVlanMember, vlanName(vlan), "*"))
+	if err != nil {
+		return err
+	}
+	if err = d.configDB.del(append(members, configKey(tableVlan, vlanName(vlan)))...); err != nil {
+		return err
+	}
+	delete(d.vlans, objBridge.Name)
+	return nil
+}
+
+func (d *bridgeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objBridge := obj.(tai.BridgeObj)
+
+	vlan, err := d.bdVlan(objBridge.Name)
+	if err != nil {
+		log.Warning("[Driver] BD %s not exist\n", objBridge.Name)
+		return err
+	}
+
+	if tunnelName := attrs.GetString(tai.BridgeAttrVxlanTunnel); tunnelName != "" {
+		vni := getVniByName(objBridge.Name, bridgeNamePrefix)
+		err = d.configDB.hset(vniMapKey(tunnelName, vni, vlan), map[string]string{
+			"vni":  strconv.Itoa(vni),
+			"vlan": vlan,
+		})
+		if err != nil {
+			return err
+		}
+		d.tunnels[objBridge.Name] = tunnelName
+	}
+
+	return nil
+}
+
+func (d *bridgeAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (d *bridgeAPI) SetObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (d *bridgeAPI) GetObjectAttr(obj interface{}, attrIDs []tai.ObjAttrID) (tai.Attrs, error) {
+	attrs := make(tai.Attrs)
+	objBridge := obj.(tai.BridgeObj)
+
+	if _, err := d.bdVlan(objBridge.Name); err != nil {
+		return nil, err
+	}
+
+	for _, attr := range attrIDs {
+		switch attr {
+		case tai.BridgeAttrL2vni:
+			attrs[attr] = getVniByName(objBridge.Name, bridgeNamePrefix)
+		case tai.BridgeAttrVxlanTunnel:
+			attrs[attr] = d.tunnels[objBridge.Name]
+		}
+	}
+
+	return attrs, nil
+}
+
+// load bridges and their tunnels of last run from vlans described by
+// bridge name and vni maps of bridge vlans
+func (d *bridgeAPI) load() error {
+	keys, err := d.configDB.scan(configKey(tableVlan, vlanNamePrefix+"*"))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		vlan, err := strconv.Atoi(strings.TrimPrefix(key, configKey(tableVlan, vlanNamePrefix)))
+		if err != nil {
+			continue
+		}
+		bdName, _ := d.configDB.hget(key, "description")
+		if getVniByName(bdName, bridgeNamePrefix) == 0 {
+			continue
+		}
+		if _, ok := d.vlans[bdName]; !ok {
+			d.vlans[bdName] = vlan
+		}
+	}
+
+	maps, err := d.configDB.scan(configKey(tableVxlanTunnelMap, "*"))
+	if err != nil {
+		return err
+	}
+	for bdName, vlan := range d.vlans {
+		vni := getVniByName(bdName, bridgeNamePrefix)
+		for _, key := range maps {
+			keys := strings.Split(key, configDBSeparator)
+			if len(keys) == 3 && key == vniMapKey(keys[1], vni, vlanName(vlan)) {
+				if _, ok := d.tunnels[bdName]; !ok {
+					d.tunnels[bdName] = keys[1]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ListObject bridges of vlans described by bridge name
+func (d *bridgeAPI) ListObject() ([]interface{}, error) {
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for bdName := range d.vlans {
+		objs = append(objs, tai.BridgeObj{Name: bdName, Vni: getVniByName(bdName, bridgeNamePrefix)})
+	}
+	return objs, nil
+}
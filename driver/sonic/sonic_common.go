@@ -0,0 +1,95 @@
+package sonic
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	vniMin = 1
+	vniMax = 16777215
+)
+
+const (
+	vlanMin = 2
+	vlanMax = 4094
+)
+
+// redis database index of SONiC
+const (
+	applDBIndex   = 0
+	configDBIndex = 4
+)
+
+const (
+	configDBSeparator = "|"
+	applDBSeparator   = ":"
+)
+
+// CONFIG_DB tables
+const (
+	tableVlan             = "VLAN"
+	tableVlanMember       = "VLAN_MEMBER"
+	tableInterface        = "INTERFACE"
+	tableVlanInterface    = "VLAN_INTERFACE"
+	tableVlanSubInterface = "VLAN_SUB_INTERFACE"
+	tableVrf              = "VRF"
+	tableVxlanTunnel      = "VXLAN_TUNNEL"
+	tableVxlanTunnelMap   = "VXLAN_TUNNEL_MAP"
+	tableStaticRoute      = "STATIC_ROUTE"
+	tableNeigh            = "NEIGH"
+	tableACLTable         = "ACL_TABLE"
+	tableACLRule          = "ACL_RULE"
+)
+
+// APPL_DB tables
+const (
+	tableVxlanFdb       = "VXLAN_FDB_TABLE"
+	tableVxlanRemoteVni = "VXLAN_REMOTE_VNI_TABLE"
+)
+
+const (
+	bridgeNamePrefix = "Bd"
+	vrfNamePrefix    = "Vrf"
+	vlanNamePrefix   = "Vlan"
+)
+
+const interfaceDefaultAdminStatus = "up"
+
+// nullFields fields of CONFIG_DB entry without attribute
+var nullFields = map[string]string{"NULL": "NULL"}
+
+func configKey(table string, keys ...string) string {
+	return strings.Join(append([]string{table}, keys...), configDBSeparator)
+}
+
+func applKey(table string, keys ...string) string {
+	return strings.Join(append([]string{table}, keys...), applDBSeparator)
+}
+
+// getVniByName get vni from name like Bd100 or Vrf100
+func getVniByName(name string, prefix string) int {
+	if !strings.HasPrefix(name, prefix) {
+		return 0
+	}
+	vni, err := strconv.Atoi(name[len(prefix):])
+	if err != nil || vni < vniMin || vni > vniMax {
+		return 0
+	}
+	return vni
+}
+
+func vlanName(vlan int) string {
+	return vlanNamePrefix + strconv.Itoa(vlan)
+}
+
+// ipPrefix append host prefix length to ip without prefix
+func ipPrefix(ipaddr string) string {
+	if strings.Contains(ipaddr, "/") {
+		return ipaddr
+	}
+	if strings.Contains(ipaddr, ":") {
+		return ipaddr + "/128"
+	}
+	return ipaddr + "/32"
+}
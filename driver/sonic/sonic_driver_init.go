@@ -0,0 +1,85 @@
+// Package sonic is the TAI driver of community SONiC, it maps TAI objects
+// onto CONFIG_DB tables (VXLAN_TUNNEL, VXLAN_TUNNEL_MAP, VLAN, VRF,
+// VLAN_SUB_INTERFACE, STATIC_ROUTE, ACL_TABLE/ACL_RULE ...) through the
+// redis protocol. Remote VTEP fdb and flood entries have no CONFIG_DB
+// table, they are written to APPL_DB VXLAN_FDB_TABLE and
+// VXLAN_REMOTE_VNI_TABLE consumed by vxlanorch.
+//
+// The driver can be tested against a local redis-server, eg: run
+// "redis-server --port 6380" and set RedisAddr to "127.0.0.1:6380" before
+// Init, then check result by "redis-cli -p 6380 -n 4 --scan". Switch
+// with own driver config programs the redis set as its driver address.
+package sonic
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type sonicDriver struct {
+	DriverName string
+	ModuleAPIs map[tai.ObjID]moduleAPI
+	// mutex serialize module calls of the instance
+	mutex    sync.Mutex
+	configDB *redisClient
+	applDB   *redisClient
+	// bridge vlans referenced by other modules of the instance
+	bdModule *bridgeAPI
+}
+
+type moduleAPI interface {
+	CreateObject(interface{}) error
+	RemoveObject(interface{}) error
+	AddObjectAttr(interface{}, tai.Attrs) error
+	DelObjectAttr(interface{}, tai.Attrs) error
+	SetObjectAttr(interface{}, tai.Attrs) error
+	GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error)
+	ListObject() ([]interface{}, error)
+}
+
+// DriverName of SONiC TAI driver
+const DriverName = "SONIC"
+
+// RedisAddr address of SONiC redis, host:port or unix:path
+var RedisAddr = "127.0.0.1:6379"
+
+func init() {
+	tai.RegisterDriver(DriverName, Init)
+}
+
+// Init SONiC TAI driver, redis of RedisAddr or driver address is checked
+// at init and reconnected on demand later
+func Init(config tai.DriverConfig) (tai.DriverHandler, error) {
+	addr := RedisAddr
+	if config.Addr != "" {
+		addr = config.Addr
+	}
+	d := &sonicDriver{
+		DriverName: DriverName,
+		configDB:   newRedisClient(addr, configDBIndex),
+		applDB:     newRedisClient(addr, applDBIndex),
+	}
+	if _, err := d.configDB.do("PING"); err != nil {
+		return nil, fmt.Errorf("[Driver] SONiC redis %s unreachable: %v", addr, err)
+	}
+
+	d.bdModule = newBridgeAPI(d)
+	d.ModuleAPIs = map[tai.ObjID]moduleAPI{
+		tai.ObjectIDBridge:          d.bdModule,
+		tai.ObjectIDVrf:             newVrfAPI(d),
+		tai.ObjectIDL2Port:          newL2portAPI(d),
+		tai.ObjectIDL3Port:          newL3portAPI(d),
+		tai.ObjectIDFDB:             newFdbAPI(d),
+		tai.ObjectIDNeighbour:       newNeighbourAPI(d),
+		tai.ObjectIDRoute:           newRouteAPI(d),
+		tai.ObjectIDTunnel:          newTunnelAPI(d),
+		tai.ObjectIDMcastFDB:        newMcastFdbAPI(d),
+		tai.ObjectIDACL:             newAclAPI(d),
+		tai.ObjectIDACLRule:         newAclRuleAPI(d),
+		tai.ObjectIDPBR:             newPbrAPI(d),
+		tai.ObjectIDAutoGatewayConf: newAutoGatewayConfAPI(d),
+	}
+	return d, nil
+}
@@ -0,0 +1,303 @@
+package sonic
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// testDriver SONiC driver instance on a local redis-server listening on
+// unix socket of test, test is skipped without redis-server. The returned
+// clients inspect CONFIG_DB and APPL_DB.
+func testDriver(t *testing.T) (*sonicDriver, *redisClient, *redisClient) {
+	t.Helper()
+	server, err := exec.LookPath("redis-server")
+	if err != nil {
+		t.Skip("redis-server not found")
+	}
+
+	socket := filepath.Join(t.TempDir(), "redis.sock")
+	cmd := exec.Command(server, "--port", "0", "--unixsocket", socket,
+		"--save", "", "--appendonly", "no")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("redis-server start failed %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr := "unix:" + socket
+	configClient := newRedisClient(addr, configDBIndex)
+	applClient := newRedisClient(addr, applDBIndex)
+	t.Cleanup(configClient.close)
+	t.Cleanup(applClient.close)
+	for i := 0; ; i++ {
+		if _, err = configClient.do("PING"); err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("redis-server %s not ready: %v", socket, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	driver, err := Init(tai.DriverConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("driver init on %s failed %v", addr, err)
+	}
+	return driver.(*sonicDriver), configClient, applClient
+}
+
+// testFields check fields of redis hash key
+func testFields(t *testing.T, client *redisClient, key string, expect map[string]string) {
+	t.Helper()
+	fields, err := client.hgetall(key)
+	if err != nil {
+		t.Fatalf("%s get failed %v", key, err)
+	}
+	if len(fields) != len(expect) {
+		t.Errorf("%s fields %v, expect %v", key, fields, expect)
+		return
+	}
+	for field, value := range expect {
+		if fields[field] != value {
+			t.Errorf("%s fields %v, expect %v", key, fields, expect)
+			return
+		}
+	}
+}
+
+func testNotExist(t *testing.T, client *redisClient, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if exist, err := client.exists(key); err != nil || exist {
+			t.Errorf("%s not removed, err %v", key, err)
+		}
+	}
+}
+
+func TestRedisScan(t *testing.T) {
+	_, configClient, _ := testDriver(t)
+
+	// more keys than a single SCAN call returns
+	var expect []string
+	for i := 0; i < 2500; i++ {
+		key := configKey(tableVlanMember, "Vlan100", fmt.Sprintf("Ethernet%d", i))
+		if err := configClient.hset(key, map[string]string{"tagging_mode": "tagged"}); err != nil {
+			t.Fatalf("%s set failed %v", key, err)
+		}
+		expect = append(expect, key)
+	}
+	if err := configClient.hset(configKey(tableVlan, "Vlan100"), map[string]string{"vlanid": "100"}); err != nil {
+		t.Fatalf("vlan set failed %v", err)
+	}
+
+	keys, err := configClient.scan(configKey(tableVlanMember, "Vlan100", "*"))
+	if err != nil {
+		t.Fatalf("scan failed %v", err)
+	}
+	sort.Strings(keys)
+	sort.Strings(expect)
+	if len(keys) != len(expect) {
+		t.Fatalf("scan got %d keys, expect %d", len(keys), len(expect))
+	}
+	for i := range keys {
+		if keys[i] != expect[i] {
+			t.Fatalf("scan key %s, expect %s", keys[i], expect[i])
+		}
+	}
+
+	if keys, err = configClient.scan(configKey(tableVlan, "Vlan200")); err != nil || len(keys) != 0 {
+		t.Errorf("scan of absent key got %v err %v", keys, err)
+	}
+}
+
+func TestBridgeTunnel(t *testing.T) {
+	d, configClient, applClient := testDriver(t)
+
+	objTunnel := tai.TunnelObj{Name: "tun0", Ipaddr: "192.0.2.1"}
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	if err := d.TaiCreateObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel create failed %v", err)
+	}
+	testFields(t, configClient, "VXLAN_TUNNEL|tun0", map[string]string{"src_ip": "192.0.2.1"})
+
+	if err := d.TaiCreateObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge create failed %v", err)
+	}
+	testFields(t, configClient, "VLAN|Vlan100", map[string]string{
+		"vlanid": "100", "description": "Bd100", "admin_status": "up",
+	})
+	err := d.TaiAddObjectAttr(tai.ObjectIDBridge, objBridge, tai.Attrs{tai.BridgeAttrVxlanTunnel: "tun0"})
+	if err != nil {
+		t.Fatalf("bridge tunnel add failed %v", err)
+	}
+	testFields(t, configClient, "VXLAN_TUNNEL_MAP|tun0|map_100_Vlan100",
+		map[string]string{"vni": "100", "vlan": "Vlan100"})
+
+	// tagged member must carry the bridge vlan
+	objL2port := tai.L2portObj{Name: "Ethernet0.100", BridgeName: "Bd100", PhysicalParentPort: "Ethernet0"}
+	if err := d.TaiCreateObject(tai.ObjectIDL2Port, objL2port); err != nil {
+		t.Fatalf("l2port create failed %v", err)
+	}
+	err = d.TaiAddObjectAttr(tai.ObjectIDL2Port, objL2port, tai.Attrs{tai.L2portAttrVlanTag: []int{200}})
+	if err == nil {
+		t.Errorf("l2port of vlan translation should fail")
+	}
+	err = d.TaiAddObjectAttr(tai.ObjectIDL2Port, objL2port, tai.Attrs{tai.L2portAttrVlanTag: []int{100}})
+	if err != nil {
+		t.Fatalf("l2port vlan add failed %v", err)
+	}
+	testFields(t, configClient, "VLAN_MEMBER|Vlan100|Ethernet0", map[string]string{"tagging_mode": "tagged"})
+
+	objFdb := tai.FdbObj{Bridge: "Bd100", Mac: "52:54:00:00:00:01"}
+	if err := d.TaiCreateObject(tai.ObjectIDFDB, objFdb); err != nil {
+		t.Fatalf("fdb create failed %v", err)
+	}
+	if err := d.TaiAddObjectAttr(tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: "192.0.2.2"}); err != nil {
+		t.Fatalf("fdb remote ip add failed %v", err)
+	}
+	testFields(t, applClient, "VXLAN_FDB_TABLE:Vlan100:52:54:00:00:00:01", map[string]string{
+		"remote_vtep": "192.0.2.2", "type": "static", "vni": "100",
+	})
+	if err := d.TaiRemoveObject(tai.ObjectIDFDB, objFdb); err != nil {
+		t.Fatalf("fdb remove failed %v", err)
+	}
+	testNotExist(t, applClient, "VXLAN_FDB_TABLE:Vlan100:52:54:00:00:00:01")
+
+	// members and vni map are removed with bridge, vni maps with tunnel
+	if err := d.TaiRemoveObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge remove failed %v", err)
+	}
+	testNotExist(t, configClient, "VLAN|Vlan100", "VLAN_MEMBER|Vlan100|Ethernet0",
+		"VXLAN_TUNNEL_MAP|tun0|map_100_Vlan100")
+	if err := d.TaiRemoveObject(tai.ObjectIDTunnel, objTunnel); err != nil {
+		t.Fatalf("tunnel remove failed %v", err)
+	}
+	testNotExist(t, configClient, "VXLAN_TUNNEL|tun0")
+}
+
+func TestL3portRoute(t *testing.T) {
+	d, configClient, _ := testDriver(t)
+
+	objL3port := tai.L3portObj{Name: "Ethernet4.10", PhysicalParentPort: "Ethernet4"}
+	if err := d.TaiCreateObject(tai.ObjectIDL3Port, objL3port); err != nil {
+		t.Fatalf("l3port create failed %v", err)
+	}
+	err := d.TaiAddObjectAttr(tai.ObjectIDL3Port, objL3port, tai.Attrs{
+		tai.L3portAttrVlanTag:    []int{10},
+		tai.L3portAttrVrfBinding: "Vrf200",
+		tai.L3portAttrIpaddr:     []string{"10.0.0.1/24", "2001:db8::1/64"},
+	})
+	if err != nil {
+		t.Fatalf("l3port attrs add failed %v", err)
+	}
+	testFields(t, configClient, "VLAN_SUB_INTERFACE|Ethernet4.10", map[string]string{
+		"admin_status": "up", "vlan": "10", "vrf_name": "Vrf200",
+	})
+	testFields(t, configClient, "VLAN_SUB_INTERFACE|Ethernet4.10|10.0.0.1/24", nullFields)
+	testFields(t, configClient, "VLAN_SUB_INTERFACE|Ethernet4.10|2001:db8::1/64", nullFields)
+
+	objRoute := tai.RouteObj{Vrf: "Vrf200", IPPrefix: "192.168.0.0/16", Nexthop: "10.0.0.2", OutputPort: "Ethernet4.10"}
+	if err := d.TaiCreateObject(tai.ObjectIDRoute, objRoute); err != nil {
+		t.Fatalf("route create failed %v", err)
+	}
+	testFields(t, configClient, "STATIC_ROUTE|Vrf200|192.168.0.0/16",
+		map[string]string{"nexthop": "10.0.0.2", "ifname": "Ethernet4.10"})
+	err = d.TaiSetObjectAttr(tai.ObjectIDRoute, objRoute, tai.Attrs{tai.RouteAttrNexthop: "10.0.0.3"})
+	if err != nil {
+		t.Fatalf("route set failed %v", err)
+	}
+	testFields(t, configClient, "STATIC_ROUTE|Vrf200|192.168.0.0/16",
+		map[string]string{"nexthop": "10.0.0.3", "ifname": "Ethernet4.10"})
+	if err := d.TaiRemoveObject(tai.ObjectIDRoute, objRoute); err != nil {
+		t.Fatalf("route remove failed %v", err)
+	}
+	testNotExist(t, configClient, "STATIC_ROUTE|Vrf200|192.168.0.0/16")
+
+	err = d.TaiDelObjectAttr(tai.ObjectIDL3Port, objL3port, tai.Attrs{tai.L3portAttrIpaddr: []string{"2001:db8::1/64"}})
+	if err != nil {
+		t.Fatalf("l3port address del failed %v", err)
+	}
+	testNotExist(t, configClient, "VLAN_SUB_INTERFACE|Ethernet4.10|2001:db8::1/64")
+
+	// addresses are removed with interface
+	if err := d.TaiRemoveObject(tai.ObjectIDL3Port, objL3port); err != nil {
+		t.Fatalf("l3port remove failed %v", err)
+	}
+	testNotExist(t, configClient, "VLAN_SUB_INTERFACE|Ethernet4.10",
+		"VLAN_SUB_INTERFACE|Ethernet4.10|10.0.0.1/24")
+}
+
+func TestListObject(t *testing.T) {
+	d, _, _ := testDriver(t)
+
+	objTunnel := tai.TunnelObj{Name: "tun0", Ipaddr: "192.0.2.1"}
+	objBridge := tai.BridgeObj{Name: "Bd100", Vni: 100}
+	objVrf := tai.VrfObj{Name: "Vrf200"}
+	objL2port := tai.L2portObj{Name: "Ethernet0", BridgeName: "Bd100", PhysicalParentPort: "Ethernet0"}
+	objFdb := tai.FdbObj{Bridge: "Bd100", Mac: "52:54:00:00:00:01"}
+	objRoute := tai.RouteObj{Vrf: "Vrf200", IPPrefix: "192.168.0.0/16", Nexthop: "10.0.0.2"}
+	for _, create := range []struct {
+		objID tai.ObjID
+		obj   interface{}
+		attrs tai.Attrs
+	}{
+		{tai.ObjectIDTunnel, objTunnel, nil},
+		{tai.ObjectIDBridge, objBridge, tai.Attrs{tai.BridgeAttrVxlanTunnel: "tun0"}},
+		{tai.ObjectIDVrf, objVrf, nil},
+		{tai.ObjectIDL2Port, objL2port, nil},
+		{tai.ObjectIDFDB, objFdb, tai.Attrs{tai.FdbAttrRemoteIP: "192.0.2.2"}},
+		{tai.ObjectIDRoute, objRoute, nil},
+	} {
+		if err := d.TaiCreateObject(create.objID, create.obj); err != nil {
+			t.Fatalf("%+v create failed %v", create.obj, err)
+		}
+		if create.attrs == nil {
+			continue
+		}
+		if err := d.TaiAddObjectAttr(create.objID, create.obj, create.attrs); err != nil {
+			t.Fatalf("%+v attrs add failed %v", create.obj, err)
+		}
+	}
+
+	// bridges of last run are loaded from CONFIG_DB by restarted driver
+	restarted, err := Init(tai.DriverConfig{Addr: d.configDB.addr})
+	if err != nil {
+		t.Fatalf("driver restart failed %v", err)
+	}
+	d = restarted.(*sonicDriver)
+	for _, expect := range []struct {
+		objID tai.ObjID
+		obj   interface{}
+	}{
+		{tai.ObjectIDBridge, objBridge},
+		{tai.ObjectIDTunnel, tai.TunnelObj{Name: "tun0"}},
+		{tai.ObjectIDVrf, objVrf},
+		{tai.ObjectIDL2Port, tai.L2portObj{BridgeName: "Bd100", PhysicalParentPort: "Ethernet0"}},
+		{tai.ObjectIDFDB, objFdb},
+		{tai.ObjectIDRoute, tai.RouteObj{Vrf: "Vrf200", IPPrefix: "192.168.0.0/16"}},
+	} {
+		objs, err := d.TaiListObject(expect.objID)
+		if err != nil {
+			t.Fatalf("%T list failed %v", expect.obj, err)
+		}
+		if len(objs) != 1 || objs[0] != expect.obj {
+			t.Errorf("%T list %+v, expect %+v", expect.obj, objs, expect.obj)
+		}
+	}
+
+	// listed bridge is removed with its members and vni map
+	if err := d.TaiRemoveObject(tai.ObjectIDBridge, objBridge); err != nil {
+		t.Fatalf("bridge remove failed %v", err)
+	}
+	if objs, err := d.TaiListObject(tai.ObjectIDL2Port); err != nil || len(objs) != 0 {
+		t.Errorf("l2port list %+v after bridge removed, err %v", objs, err)
+	}
+}
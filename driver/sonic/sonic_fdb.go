@@ -0,0 +1,94 @@
+package sonic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type fdbAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newFdbAPI(d *sonicDriver) *fdbAPI {
+	return &fdbAPI{
+		moduleID:    tai.ObjectIDFDB,
+		sonicDriver: d,
+	}
+}
+
+// remoteFdbAdd static remote mac of bridge vlan point to remote vtep ip,
+// written to APPL_DB since CONFIG_DB has no remote fdb table
+func (d *sonicDriver) remoteFdbAdd(bdName string, mac string, remoteIP string) error {
+	vlan, err := d.bdVlan(bdName)
+	if err != nil {
+		return err
+	}
+
+	return d.applDB.hset(applKey(tableVxlanFdb, vlan, strings.ToLower(mac)), map[string]string{
+		"remote_vtep": remoteIP,
+		"type":        "static",
+		"vni":         strconv.Itoa(getVniByName(bdName, bridgeNamePrefix)),
+	})
+}
+
+func (d *sonicDriver) remoteFdbDel(bdName string, mac string) error {
+	vlan, err := d.bdVlan(bdName)
+	if err != nil {
+		return nil
+	}
+	return d.applDB.del(applKey(tableVxlanFdb, vlan, strings.ToLower(mac)))
+}
+
+// CreateObject remote fdb is set with remote ip attr
+func (v *fdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *fdbAPI) RemoveObject(obj interface{}) error {
+	objFdb := obj.(tai.FdbObj)
+	return v.remoteFdbDel(objFdb.Bridge, objFdb.Mac)
+}
+
+func (v *fdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objFdb := obj.(tai.FdbObj)
+
+	if remoteIP := attrs.GetString(tai.FdbAttrRemoteIP); remoteIP != "" {
+		return v.remoteFdbAdd(objFdb.Bridge, objFdb.Mac, remoteIP)
+	}
+	return nil
+}
+
+func (v *fdbAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *fdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *fdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject remote fdbs of bridge vlans
+func (v *fdbAPI) ListObject() ([]interface{}, error) {
+	if err := v.bdModule.load(); err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for bdName, vlan := range v.bdModule.vlans {
+		prefix := applKey(tableVxlanFdb, vlanName(vlan), "")
+		keys, err := v.applDB.scan(prefix + "*")
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			objs = append(objs, tai.FdbObj{Bridge: bdName, Mac: strings.TrimPrefix(key, prefix)})
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,116 @@
+package sonic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type l2portAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newL2portAPI(d *sonicDriver) *l2portAPI {
+	return &l2portAPI{
+		moduleID:    tai.ObjectIDL2Port,
+		sonicDriver: d,
+	}
+}
+
+// l2portMember add physical parent port to bridge vlan, SONiC can't
+// translate vlan, so port vlan tag must be the bridge vlan
+func (d *sonicDriver) l2portMember(objL2port tai.L2portObj, tags []int) error {
+	vlan, err := d.bdVlan(objL2port.BridgeName)
+	if err != nil {
+		return err
+	}
+
+	mode := "untagged"
+	switch len(tags) {
+	case 0:
+	case 1:
+		if vlanName(tags[0]) != vlan {
+			return fmt.Errorf("[Driver] L2port %s vlan %d differs from BD %s %s, vlan translation not supported",
+				objL2port.Name, tags[0], objL2port.BridgeName, vlan)
+		}
+		mode = "tagged"
+	default:
+		return fmt.Errorf("[Driver] L2port %s stacked vlan %v not supported", objL2port.Name, tags)
+	}
+
+	return d.configDB.hset(configKey(tableVlanMember, vlan, objL2port.PhysicalParentPort),
+		map[string]string{"tagging_mode": mode})
+}
+
+func (v *l2portAPI) CreateObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if _, err := v.bdVlan(objL2port.BridgeName); err != nil {
+		return fmt.Errorf("[Driver] L2port %s bridge %s not exist", objL2port.Name, objL2port.BridgeName)
+	}
+	// tagged member is added with vlan tag attr
+	if objL2port.Name != objL2port.PhysicalParentPort {
+		return nil
+	}
+	return v.l2portMember(objL2port, nil)
+}
+
+func (v *l2portAPI) RemoveObject(obj interface{}) error {
+	objL2port := obj.(tai.L2portObj)
+
+	vlan, err := v.bdVlan(objL2port.BridgeName)
+	if err != nil {
+		return nil
+	}
+	return v.configDB.del(configKey(tableVlanMember, vlan, objL2port.PhysicalParentPort))
+}
+
+func (v *l2portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL2port := obj.(tai.L2portObj)
+
+	if tags := attrs.GetInts(tai.L2portAttrVlanTag); len(tags) != 0 {
+		if err := v.l2portMember(objL2port, tags); err != nil {
+			log.Warning("[Driver] L2port %s vlan %v add failed %v\n", objL2port.Name, tags, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *l2portAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *l2portAPI) SetObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *l2portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject members of bridge vlans, name of l2port is not kept by
+// SONiC and not part of listed l2port
+func (v *l2portAPI) ListObject() ([]interface{}, error) {
+	if err := v.bdModule.load(); err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for bdName, vlan := range v.bdModule.vlans {
+		members, err := v.configDB.scan(configKey(tableVlanMember, vlanName(vlan), "*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range members {
+			objs = append(objs, tai.L2portObj{
+				BridgeName:         bdName,
+				PhysicalParentPort: strings.TrimPrefix(key, configKey(tableVlanMember, vlanName(vlan), "")),
+			})
+		}
+	}
+	return objs, nil
+}
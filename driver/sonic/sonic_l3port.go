@@ -0,0 +1,143 @@
+package sonic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type l3portAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newL3portAPI(d *sonicDriver) *l3portAPI {
+	return &l3portAPI{
+		moduleID:    tai.ObjectIDL3Port,
+		sonicDriver: d,
+	}
+}
+
+// l3portTable CONFIG_DB table and interface name of l3 port, bridge port
+// is the bridge vlan interface
+func (d *sonicDriver) l3portTable(objL3port tai.L3portObj) (string, string) {
+	if vlan, err := d.bdVlan(objL3port.Name); err == nil {
+		return tableVlanInterface, vlan
+	}
+	if objL3port.PhysicalParentPort != "" && objL3port.Name != objL3port.PhysicalParentPort {
+		return tableVlanSubInterface, objL3port.Name
+	}
+	return tableInterface, objL3port.Name
+}
+
+func (v *l3portAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *l3portAPI) RemoveObject(obj interface{}) error {
+	objL3port := obj.(tai.L3portObj)
+
+	table, name := v.l3portTable(objL3port)
+	addrs, err := v.configDB.scan(configKey(table, name, "*"))
+	if err != nil {
+		return err
+	}
+	return v.configDB.del(append(addrs, configKey(table, name))...)
+}
+
+func (v *l3portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL3port := obj.(tai.L3portObj)
+
+	table, name := v.l3portTable(objL3port)
+	fields := make(map[string]string)
+	if table == tableVlanSubInterface {
+		fields["admin_status"] = interfaceDefaultAdminStatus
+		if tags := attrs.GetInts(tai.L3portAttrVlanTag); len(tags) == 1 {
+			fields["vlan"] = strconv.Itoa(tags[0])
+		} else if len(tags) > 1 {
+			log.Warning("[Driver] Interface %s stacked vlan %v not supported\n", name, tags)
+		}
+	}
+	// vrf binding must be set before ip address
+	if vrfName := attrs.GetString(tai.L3portAttrVrfBinding); vrfName != "" {
+		fields["vrf_name"] = vrfName
+	}
+	if len(fields) == 0 {
+		fields = nullFields
+	}
+	if err := v.configDB.hset(configKey(table, name), fields); err != nil {
+		return err
+	}
+
+	for _, ipaddr := range attrs.GetStrings(tai.L3portAttrIpaddr) {
+		if err := v.configDB.hset(configKey(table, name, ipPrefix(ipaddr)), nullFields); err != nil {
+			return err
+		}
+	}
+
+	if mac := attrs.GetString(tai.L3portAttrMacaddr); mac != "" {
+		log.Info("[Driver] Interface %s mac %s ignored, SONiC use system mac\n", name, mac)
+	}
+	return nil
+}
+
+func (v *l3portAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objL3port := obj.(tai.L3portObj)
+
+	table, name := v.l3portTable(objL3port)
+	for _, ipaddr := range attrs.GetStrings(tai.L3portAttrIpaddr) {
+		if err := v.configDB.del(configKey(table, name, ipPrefix(ipaddr))); err != nil {
+			log.Warning("[Driver] Interface %s del address %s failed %v\n", name, ipaddr, err)
+		}
+	}
+
+	if vrfName := attrs.GetString(tai.L3portAttrVrfBinding); vrfName != "" {
+		return v.configDB.hdel(configKey(table, name), "vrf_name")
+	}
+	return nil
+}
+
+func (v *l3portAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *l3portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject vlan interfaces of bridges and routed ports bound to vrfs
+// named "Vrf"+vni, sub interfaces are shared with auto gateway conf and
+// not listed
+func (v *l3portAPI) ListObject() ([]interface{}, error) {
+	if err := v.bdModule.load(); err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for bdName, vlan := range v.bdModule.vlans {
+		exist, err := v.configDB.exists(configKey(tableVlanInterface, vlanName(vlan)))
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			objs = append(objs, tai.L3portObj{Name: bdName})
+		}
+	}
+
+	keys, err := v.configDB.scan(configKey(tableInterface, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		keys := strings.Split(key, configDBSeparator)
+		if len(keys) != 2 {
+			continue
+		}
+		if vrfName, _ := v.configDB.hget(key, "vrf_name"); getVniByName(vrfName, vrfNamePrefix) != 0 {
+			objs = append(objs, tai.L3portObj{Name: keys[1]})
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,133 @@
+package sonic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+const bumMac = "00:00:00:00:00:00"
+
+type mcastFdbAPI struct {
+	moduleID int
+	*sonicDriver
+	// bridge name to flood remote ips
+	floods map[string][]string
+}
+
+func newMcastFdbAPI(d *sonicDriver) *mcastFdbAPI {
+	return &mcastFdbAPI{
+		moduleID:    tai.ObjectIDMcastFDB,
+		sonicDriver: d,
+		floods:      make(map[string][]string),
+	}
+}
+
+// floodSet replace remote vtep flood list of bridge vlan in APPL_DB
+func (v *mcastFdbAPI) floodSet(bdName string, ips []string) error {
+	vlan, err := v.bdVlan(bdName)
+	if err != nil {
+		return err
+	}
+	vni := strconv.Itoa(getVniByName(bdName, bridgeNamePrefix))
+
+	newIps := make(map[string]bool)
+	for _, ip := range ips {
+		newIps[ip] = true
+	}
+	for _, ip := range v.floods[bdName] {
+		if newIps[ip] {
+			delete(newIps, ip)
+			continue
+		}
+		if err := v.applDB.del(applKey(tableVxlanRemoteVni, vlan, ip)); err != nil {
+			log.Warning("[Driver] BD %s flood %s del failed %v\n", bdName, ip, err)
+		}
+	}
+	for ip := range newIps {
+		if err := v.applDB.hset(applKey(tableVxlanRemoteVni, vlan, ip), map[string]string{"vni": vni}); err != nil {
+			return err
+		}
+	}
+
+	v.floods[bdName] = ips
+	return nil
+}
+
+func (v *mcastFdbAPI) CreateObject(obj interface{}) error {
+	return nil
+}
+
+func (v *mcastFdbAPI) RemoveObject(obj interface{}) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if _, ok := v.floods[objMcastFdb.BridgeName]; !ok {
+		return nil
+	}
+	err := v.floodSet(objMcastFdb.BridgeName, nil)
+	delete(v.floods, objMcastFdb.BridgeName)
+	return err
+}
+
+func (v *mcastFdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != "unknown-dst" && objMcastFdb.Mac != bumMac {
+		log.Info("[Driver] Mcast fdb %s of BD %s ignored\n", objMcastFdb.Mac, objMcastFdb.BridgeName)
+		return nil
+	}
+	if attrs.Has(tai.McastFdbAttrLocators) {
+		return v.floodSet(objMcastFdb.BridgeName, attrs.GetStrings(tai.McastFdbAttrLocators))
+	}
+	return nil
+}
+
+// DelObjectAttr flood list is removed with its last remote ip
+func (v *mcastFdbAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objMcastFdb := obj.(tai.McastFdbObj)
+
+	if objMcastFdb.Mac != "unknown-dst" && objMcastFdb.Mac != bumMac {
+		return nil
+	}
+	if _, ok := attrs[tai.McastFdbAttrLocators]; !ok {
+		return nil
+	}
+	return v.floodSet(objMcastFdb.BridgeName, nil)
+}
+
+func (v *mcastFdbAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *mcastFdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject flood lists of bridge vlans, flood remote ips are loaded to
+// be removed with listed mcast fdb
+func (v *mcastFdbAPI) ListObject() ([]interface{}, error) {
+	if err := v.bdModule.load(); err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for bdName, vlan := range v.bdModule.vlans {
+		prefix := applKey(tableVxlanRemoteVni, vlanName(vlan), "")
+		keys, err := v.applDB.scan(prefix + "*")
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if _, ok := v.floods[bdName]; !ok {
+			for _, key := range keys {
+				v.floods[bdName] = append(v.floods[bdName], strings.TrimPrefix(key, prefix))
+			}
+		}
+		objs = append(objs, tai.McastFdbObj{BridgeName: bdName})
+	}
+	return objs, nil
+}
@@ -0,0 +1,130 @@
+package sonic
+
+import (
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type neighbour struct {
+	mac      string
+	outPort  string
+	remoteIP string
+}
+
+type neighbourAPI struct {
+	moduleID int
+	*sonicDriver
+	// neighbour attrs are added separately, save them by ip
+	neighbours map[string]*neighbour
+}
+
+func newNeighbourAPI(d *sonicDriver) *neighbourAPI {
+	return &neighbourAPI{
+		moduleID:    tai.ObjectIDNeighbour,
+		sonicDriver: d,
+		neighbours:  make(map[string]*neighbour),
+	}
+}
+
+// neighInterface SONiC interface of neighbour outport, bridge is the
+// bridge vlan interface
+func (d *sonicDriver) neighInterface(outPort string) string {
+	if vlan, err := d.bdVlan(outPort); err == nil {
+		return vlan
+	}
+	return outPort
+}
+
+func (v *neighbourAPI) CreateObject(obj interface{}) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	if _, ok := v.neighbours[objNeighbour.Ipaddr]; ok {
+		log.Info("[Driver] Neighbour for %s already exist\n", objNeighbour.Ipaddr)
+		return nil
+	}
+	v.neighbours[objNeighbour.Ipaddr] = &neighbour{}
+	return nil
+}
+
+func (v *neighbourAPI) RemoveObject(obj interface{}) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	nh, ok := v.neighbours[objNeighbour.Ipaddr]
+	if !ok {
+		return nil
+	}
+	delete(v.neighbours, objNeighbour.Ipaddr)
+
+	if nh.outPort == "" {
+		return nil
+	}
+	if nh.remoteIP != "" && getVniByName(nh.outPort, bridgeNamePrefix) != 0 {
+		if err := v.remoteFdbDel(nh.outPort, nh.mac); err != nil {
+			log.Warning("[Driver] Neighbour %s fdb del failed %v\n", objNeighbour.Ipaddr, err)
+		}
+	}
+	return v.configDB.del(configKey(tableNeigh, v.neighInterface(nh.outPort), objNeighbour.Ipaddr))
+}
+
+// AddObjectAttr neighbour is set when mac and outport are known, remote
+// mac in bridge also point to remote vtep
+func (v *neighbourAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objNeighbour := obj.(tai.NeighbourObj)
+
+	nh, ok := v.neighbours[objNeighbour.Ipaddr]
+	if !ok {
+		log.Warning("[Driver] Neighbour for %s not exist\n", objNeighbour.Ipaddr)
+		return nil
+	}
+
+	for attr, value := range attrs {
+		switch attr {
+		case tai.NeighbourAttrMacaddr:
+			nh.mac, _ = value.(string)
+		case tai.NeighbourAttrOutPort:
+			nh.outPort, _ = value.(string)
+		case tai.NeighbourAttrRemoteIP:
+			nh.remoteIP, _ = value.(string)
+		}
+	}
+
+	if nh.mac == "" || nh.outPort == "" {
+		return nil
+	}
+
+	family := "IPv4"
+	if strings.Contains(objNeighbour.Ipaddr, ":") {
+		family = "IPv6"
+	}
+	err := v.configDB.hset(configKey(tableNeigh, v.neighInterface(nh.outPort), objNeighbour.Ipaddr),
+		map[string]string{
+			"neigh":  nh.mac,
+			"family": family,
+		})
+	if err != nil {
+		return err
+	}
+
+	if nh.remoteIP != "" && getVniByName(nh.outPort, bridgeNamePrefix) != 0 {
+		return v.remoteFdbAdd(nh.outPort, nh.mac, nh.remoteIP)
+	}
+	return nil
+}
+
+func (v *neighbourAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *neighbourAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *neighbourAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *neighbourAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,176 @@
+package sonic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// pbrRulePriority pbr acl only contain pbr rules
+const pbrRulePriority = aclRuleMaxPriority
+
+var pbrProtocols = map[string]string{
+	vtepdb.PolicyBasedRouteProtocolTCP:  "6",
+	vtepdb.PolicyBasedRouteProtocolUDP:  "17",
+	vtepdb.PolicyBasedRouteProtocolSctp: "132",
+}
+
+type pbrAPI struct {
+	moduleID int
+	*sonicDriver
+	// pbr object to acl rule index
+	rules map[tai.PBRObj]int
+}
+
+func newPbrAPI(d *sonicDriver) *pbrAPI {
+	return &pbrAPI{
+		moduleID:    tai.ObjectIDPBR,
+		sonicDriver: d,
+		rules:       make(map[tai.PBRObj]int),
+	}
+}
+
+func (v *pbrAPI) allocRule() int {
+	used := make(map[int]bool)
+	for _, rule := range v.rules {
+		used[rule] = true
+	}
+	rule := 1
+	for used[rule] {
+		rule++
+	}
+	return rule
+}
+
+// pbrACLName pbr is redirect rule of ingress acl per vrf and ip family,
+// like UNOS PBR_<vrf>
+func pbrACLName(objPBR tai.PBRObj) (string, string) {
+	if strings.Contains(objPBR.IP, ":") {
+		return "PBR_V6_" + objPBR.Vrf, "L3V6"
+	}
+	return "PBR_" + objPBR.Vrf, "L3"
+}
+
+// pbrACLCreate create pbr acl bound to gateway port of vrf
+func (d *sonicDriver) pbrACLCreate(objPBR tai.PBRObj) (string, error) {
+	aclName, aclType := pbrACLName(objPBR)
+	key := aclTableKey(aclName)
+	if exist, err := d.configDB.exists(key); err != nil || exist {
+		return aclName, err
+	}
+
+	fields := map[string]string{
+		"policy_desc": aclName,
+		"type":        aclType,
+		"stage":       strings.ToLower(vtepdb.ACLStageIngress),
+	}
+	tableAGC, err := vtepdb.AutoGatewayConfGetByIndex(vtepdb.AutoGatewayConfIndex{Vrf: objPBR.Vrf})
+	if err == nil {
+		fields["ports@"] = tableAGC.PhysicalPort
+	} else {
+		log.Warning("[Driver] PBR acl %s gateway port of vrf %s not exist\n", aclName, objPBR.Vrf)
+	}
+	return aclName, d.configDB.hset(key, fields)
+}
+
+func pbrRuleKey(objPBR tai.PBRObj, rule int) string {
+	aclName, _ := pbrACLName(objPBR)
+	return configKey(tableACLRule, aclName, "RULE_"+strconv.Itoa(rule))
+}
+
+// pbrRuleSet redirect packets match destination ip, l4 protocol and port
+// to next hop group
+func (d *sonicDriver) pbrRuleSet(objPBR tai.PBRObj, rule int, nexthops []string) error {
+	key := pbrRuleKey(objPBR, rule)
+	if len(nexthops) == 0 {
+		return d.configDB.del(key)
+	}
+
+	ip, v6, err := ipMask(strings.Split(objPBR.IP, "/")[0], "")
+	if err != nil {
+		return err
+	}
+	fields := map[string]string{
+		"PRIORITY":      strconv.Itoa(pbrRulePriority),
+		"PACKET_ACTION": "REDIRECT:" + strings.Join(nexthops, ","),
+	}
+	if v6 {
+		fields["DST_IPV6"] = ip
+	} else {
+		fields["DST_IP"] = ip
+	}
+	if protocol, ok := pbrProtocols[objPBR.Protocol]; ok {
+		if v6 {
+			fields["NEXT_HEADER"] = protocol
+		} else {
+			fields["IP_PROTOCOL"] = protocol
+		}
+		if objPBR.Port != 0 {
+			fields["L4_DST_PORT"] = strconv.Itoa(objPBR.Port)
+		}
+	}
+
+	if err = d.configDB.del(key); err != nil {
+		return err
+	}
+	return d.configDB.hset(key, fields)
+}
+
+func (v *pbrAPI) CreateObject(obj interface{}) error {
+	objPBR := obj.(tai.PBRObj)
+
+	if _, ok := v.rules[objPBR]; ok {
+		log.Info("[Driver] PBR %+v already exist\n", objPBR)
+		return nil
+	}
+	if _, err := v.pbrACLCreate(objPBR); err != nil {
+		return err
+	}
+	v.rules[objPBR] = v.allocRule()
+	return nil
+}
+
+func (v *pbrAPI) RemoveObject(obj interface{}) error {
+	objPBR := obj.(tai.PBRObj)
+
+	rule, ok := v.rules[objPBR]
+	if !ok {
+		return nil
+	}
+	delete(v.rules, objPBR)
+	return v.pbrRuleSet(objPBR, rule, nil)
+}
+
+func (v *pbrAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objPBR := obj.(tai.PBRObj)
+
+	rule, ok := v.rules[objPBR]
+	if !ok {
+		return fmt.Errorf("[Driver] PBR %+v not exist", objPBR)
+	}
+	if nhGroup := attrs.GetStrings(tai.PBRAttrNexthopGroup); attrs.Has(tai.PBRAttrNexthopGroup) {
+		return v.pbrRuleSet(objPBR, rule, nhGroup)
+	}
+	return nil
+}
+
+func (v *pbrAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *pbrAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *pbrAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+func (v *pbrAPI) ListObject() ([]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,261 @@
+package sonic
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redisDialTimeout = 5 * time.Second
+
+// redisError error reply of redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisClient minimal RESP client of one redis database, connection is
+// (re)built on demand
+type redisClient struct {
+	addr   string
+	db     int
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRedisClient(addr string, db int) *redisClient {
+	return &redisClient{
+		addr: addr,
+		db:   db,
+	}
+}
+
+// dial connect redis by tcp host:port, or unix socket unix:path
+func (c *redisClient) dial() error {
+	network, addr := "tcp", c.addr
+	if strings.HasPrefix(addr, "unix:") {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix:")
+	} else if strings.HasPrefix(addr, "/") {
+		network = "unix"
+	}
+
+	conn, err := net.DialTimeout(network, addr, redisDialTimeout)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if _, err = c.roundTrip("SELECT", strconv.Itoa(c.db)); err != nil {
+		c.close()
+		return err
+	}
+	return nil
+}
+
+func (c *redisClient) close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
+func (c *redisClient) roundTrip(args ...string) (interface{}, error) {
+	var cmd bytes.Buffer
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write(cmd.Bytes()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *redisClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", fmt.Errorf("redis: invalid reply line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// readReply read one reply, bulk string is returned as string, nil bulk
+// string and array as nil
+func (c *redisClient) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		array := make([]interface{}, size)
+		for i := range array {
+			if array[i], err = c.readReply(); err != nil {
+				if _, ok := err.(redisError); !ok {
+					return nil, err
+				}
+			}
+		}
+		return array, nil
+	}
+	return nil, fmt.Errorf("redis: invalid reply %q", line)
+}
+
+// do run redis command, connection is rebuilt and command retried once if
+// connection broken
+func (c *redisClient) do(args ...string) (interface{}, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for retry := 0; ; retry++ {
+		if c.conn == nil {
+			if err := c.dial(); err != nil {
+				return nil, err
+			}
+		}
+		reply, err := c.roundTrip(args...)
+		if err == nil {
+			return reply, nil
+		}
+		if _, ok := err.(redisError); ok {
+			return nil, err
+		}
+		c.close()
+		if retry > 0 {
+			return nil, err
+		}
+	}
+}
+
+// hset set fields of hash key
+func (c *redisClient) hset(key string, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	args := []string{"HSET", key}
+	for field, value := range fields {
+		args = append(args, field, value)
+	}
+	_, err := c.do(args...)
+	return err
+}
+
+func (c *redisClient) hdel(key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	_, err := c.do(append([]string{"HDEL", key}, fields...)...)
+	return err
+}
+
+func (c *redisClient) hget(key string, field string) (string, error) {
+	reply, err := c.do("HGET", key, field)
+	if err != nil {
+		return "", err
+	}
+	value, _ := reply.(string)
+	return value, nil
+}
+
+func (c *redisClient) hgetall(key string) (map[string]string, error) {
+	reply, err := c.do("HGETALL", key)
+	if err != nil {
+		return nil, err
+	}
+	array, _ := reply.([]interface{})
+	fields := make(map[string]string, len(array)/2)
+	for i := 0; i+1 < len(array); i += 2 {
+		field, _ := array[i].(string)
+		value, _ := array[i+1].(string)
+		fields[field] = value
+	}
+	return fields, nil
+}
+
+func (c *redisClient) del(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := c.do(append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+func (c *redisClient) exists(key string) (bool, error) {
+	reply, err := c.do("EXISTS", key)
+	if err != nil {
+		return false, err
+	}
+	count, _ := reply.(int64)
+	return count > 0, nil
+}
+
+// scanCount keys hinted to return by every SCAN call
+const scanCount = "1000"
+
+// scan keys matching pattern by SCAN iterations, KEYS blocks redis
+// serving the whole SONiC database. Keys may be returned more than once
+// by SCAN, they're returned once here.
+func (c *redisClient) scan(pattern string) ([]string, error) {
+	var keys []string
+	seen := make(map[string]bool)
+
+	cursor := "0"
+	for {
+		reply, err := c.do("SCAN", cursor, "MATCH", pattern, "COUNT", scanCount)
+		if err != nil {
+			return nil, err
+		}
+		array, _ := reply.([]interface{})
+		if len(array) != 2 {
+			return nil, fmt.Errorf("redis: invalid SCAN reply %v", reply)
+		}
+		cursor, _ = array[0].(string)
+		batch, _ := array[1].([]interface{})
+		for _, key := range batch {
+			if k, ok := key.(string); ok && !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			return keys, nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sonic
+
+import (
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type routeAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newRouteAPI(d *sonicDriver) *routeAPI {
+	return &routeAPI{
+		moduleID:    tai.ObjectIDRoute,
+		sonicDriver: d,
+	}
+}
+
+func routeKey(objRoute tai.RouteObj) string {
+	if objRoute.Vrf != "" {
+		return configKey(tableStaticRoute, objRoute.Vrf, objRoute.IPPrefix)
+	}
+	return configKey(tableStaticRoute, objRoute.IPPrefix)
+}
+
+// routeFields STATIC_ROUTE fields of route object, route to other vrf is
+// leaked by nexthop-vrf
+func (d *sonicDriver) routeFields(objRoute tai.RouteObj) map[string]string {
+	fields := make(map[string]string)
+	if objRoute.Nexthop != "" {
+		fields["nexthop"] = objRoute.Nexthop
+	}
+	if objRoute.OutputPort != "" {
+		fields["ifname"] = d.neighInterface(objRoute.OutputPort)
+	}
+	if objRoute.Nhvrf != "" && objRoute.Nhvrf != objRoute.Vrf {
+		fields["nexthop-vrf"] = objRoute.Nhvrf
+	}
+	if len(fields) == 0 {
+		fields["blackhole"] = "true"
+	}
+	return fields
+}
+
+func (v *routeAPI) CreateObject(obj interface{}) error {
+	objRoute := obj.(tai.RouteObj)
+
+	if objRoute.Policy != "" {
+		log.Info("[Driver] Static route %+v policy ignored\n", objRoute)
+	}
+	return v.configDB.hset(routeKey(objRoute), v.routeFields(objRoute))
+}
+
+func (v *routeAPI) RemoveObject(obj interface{}) error {
+	objRoute := obj.(tai.RouteObj)
+	return v.configDB.del(routeKey(objRoute))
+}
+
+// AddObjectAttr route attrs are carried in route object and set by create
+func (v *routeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return nil
+}
+
+func (v *routeAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+// SetObjectAttr route entry is replaced with new attrs
+func (v *routeAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objRoute := obj.(tai.RouteObj)
+
+	for attr, attrValue := range attrs {
+		value, ok := attrValue.(string)
+		if !ok {
+			continue
+		}
+		switch attr {
+		case tai.RouteAttrNexthop:
+			objRoute.Nexthop = value
+		case tai.RouteAttrNhvrf:
+			objRoute.Nhvrf = value
+		case tai.RouteAttrOutputPort:
+			objRoute.OutputPort = value
+		}
+	}
+
+	key := routeKey(objRoute)
+	if err := v.configDB.del(key); err != nil {
+		return err
+	}
+	return v.configDB.hset(key, v.routeFields(objRoute))
+}
+
+func (v *routeAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject static routes of vrfs named "Vrf"+vni, nexthop of route is
+// updated by attr and not part of listed route
+func (v *routeAPI) ListObject() ([]interface{}, error) {
+	keys, err := v.configDB.scan(configKey(tableStaticRoute, vrfNamePrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for _, key := range keys {
+		keys := strings.SplitN(key, configDBSeparator, 3)
+		if len(keys) != 3 || getVniByName(keys[1], vrfNamePrefix) == 0 {
+			continue
+		}
+		objs = append(objs, tai.RouteObj{Vrf: keys[1], IPPrefix: keys[2]})
+	}
+	return objs, nil
+}
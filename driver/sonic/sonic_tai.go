@@ -0,0 +1,135 @@
+package sonic
+
+import (
+	"fmt"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+// module get object api, driver mutex is held until unlock called
+func (d *sonicDriver) module(objID tai.ObjID) (moduleAPI, error) {
+	d.mutex.Lock()
+	if d.ModuleAPIs[objID] == nil {
+		d.mutex.Unlock()
+		return nil, fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
+	}
+	return d.ModuleAPIs[objID], nil
+}
+
+// TaiBegin calls are programmed into CONFIG_DB immediately, batch can't be
+// rolled back
+func (d *sonicDriver) TaiBegin() error {
+	return nil
+}
+
+func (d *sonicDriver) TaiCommit() error {
+	return nil
+}
+
+func (d *sonicDriver) TaiAbort() {}
+
+func (d *sonicDriver) TaiCreateObject(objID tai.ObjID, obj interface{}) error {
+	log.Info("[Driver] TaiCreateObject %v => %+v\n", tai.ObjectOrder[objID], obj)
+
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.CreateObject(obj)
+}
+
+func (d *sonicDriver) TaiRemoveObject(objID tai.ObjID, obj interface{}) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.RemoveObject(obj)
+}
+
+func (d *sonicDriver) TaiAddObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.AddObjectAttr(obj, attr)
+}
+
+func (d *sonicDriver) TaiDelObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.DelObjectAttr(obj, attr)
+}
+
+func (d *sonicDriver) TaiSetObjectAttr(objID tai.ObjID, obj interface{},
+	attr tai.Attrs) error {
+	api, err := d.module(objID)
+	if err != nil {
+		return err
+	}
+	defer d.mutex.Unlock()
+	return api.SetObjectAttr(obj, attr)
+}
+
+func (d *sonicDriver) TaiGetObjectAttr(objID tai.ObjID, obj interface{},
+	attr []tai.ObjAttrID) (tai.Attrs, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.GetObjectAttr(obj, attr)
+}
+
+func (d *sonicDriver) TaiListObject(objID tai.ObjID) ([]interface{}, error) {
+	api, err := d.module(objID)
+	if err != nil {
+		return nil, err
+	}
+	defer d.mutex.Unlock()
+	return api.ListObject()
+}
+
+// capabilityAttrs attrs programmed by modules, ACL rule attrs are all
+// rendered
+var capabilityAttrs = map[tai.ObjID][]tai.ObjAttrID{
+	tai.ObjectIDBridge:          {tai.BridgeAttrVxlanTunnel, tai.BridgeAttrL2vni},
+	tai.ObjectIDVrf:             {tai.VrfAttrL3vni},
+	tai.ObjectIDL2Port:          {tai.L2portAttrVlanTag},
+	tai.ObjectIDL3Port:          {tai.L3portAttrVrfBinding, tai.L3portAttrIpaddr, tai.L3portAttrMacaddr, tai.L3portAttrVlanTag},
+	tai.ObjectIDFDB:             {tai.FdbAttrRemoteIP},
+	tai.ObjectIDNeighbour:       {tai.NeighbourAttrMacaddr, tai.NeighbourAttrOutPort, tai.NeighbourAttrRemoteIP},
+	tai.ObjectIDRoute:           {tai.RouteAttrNexthop, tai.RouteAttrNhvrf, tai.RouteAttrOutputPort},
+	tai.ObjectIDTunnel:          {tai.TunnelAttrIpaddr},
+	tai.ObjectIDMcastFDB:        {tai.McastFdbAttrLocators},
+	tai.ObjectIDACL:             {tai.ACLAttrPorts, tai.ACLAttrStage, tai.ACLAttrType},
+	tai.ObjectIDACLRule:         nil,
+	tai.ObjectIDPBR:             {tai.PBRAttrNexthopGroup},
+	tai.ObjectIDAutoGatewayConf: {tai.AutoGatewayConfAttrIP},
+}
+
+// TaiGetCapability objects of registered modules, bridges are limited by
+// vlans
+func (d *sonicDriver) TaiGetCapability() tai.Capability {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	capability := tai.Capability{
+		Objects: make(map[tai.ObjID][]tai.ObjAttrID),
+		Limits: map[string]int{
+			tai.LimitBridge: vlanMax - vlanMin + 1,
+		},
+	}
+	for objID := range d.ModuleAPIs {
+		capability.Objects[objID] = capabilityAttrs[objID]
+	}
+	return capability
+}
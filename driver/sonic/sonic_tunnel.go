@@ -0,0 +1,89 @@
+package sonic
+
+import (
+	"github.com/cn-pmlabs/govtep/lib/log"
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type tunnelAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newTunnelAPI(d *sonicDriver) *tunnelAPI {
+	return &tunnelAPI{
+		moduleID:    tai.ObjectIDTunnel,
+		sonicDriver: d,
+	}
+}
+
+func (v *tunnelAPI) CreateObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	key := configKey(tableVxlanTunnel, objTunnel.Name)
+	if exist, err := v.configDB.exists(key); err != nil {
+		return err
+	} else if exist {
+		log.Info("[Driver] Tunnel %s already exist\n", objTunnel.Name)
+	}
+	return v.configDB.hset(key, map[string]string{"src_ip": objTunnel.Ipaddr})
+}
+
+// RemoveObject vni maps of tunnel are removed with tunnel
+func (v *tunnelAPI) RemoveObject(obj interface{}) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	maps, err := v.configDB.scan(configKey(tableVxlanTunnelMap, objTunnel.Name, "*"))
+	if err != nil {
+		return err
+	}
+	if err = v.configDB.del(maps...); err != nil {
+		return err
+	}
+	for bdName, tunnelName := range v.bdModule.tunnels {
+		if tunnelName == objTunnel.Name {
+			delete(v.bdModule.tunnels, bdName)
+		}
+	}
+	return v.configDB.del(configKey(tableVxlanTunnel, objTunnel.Name))
+}
+
+func (v *tunnelAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.SetObjectAttr(obj, attrs)
+}
+
+func (v *tunnelAPI) DelObjectAttr(interface{}, tai.Attrs) error {
+	return nil
+}
+
+func (v *tunnelAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	if ipaddr := attrs.GetString(tai.TunnelAttrIpaddr); ipaddr != "" {
+		return v.configDB.hset(configKey(tableVxlanTunnel, objTunnel.Name),
+			map[string]string{"src_ip": ipaddr})
+	}
+	return nil
+}
+
+func (v *tunnelAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject tunnels mapping vni of bridges, source ip of tunnel is
+// updated by attr and not part of listed tunnel
+func (v *tunnelAPI) ListObject() ([]interface{}, error) {
+	if err := v.bdModule.load(); err != nil {
+		return nil, err
+	}
+
+	tunnels := make(map[string]bool)
+	var objs []interface{}
+	for _, tunnelName := range v.bdModule.tunnels {
+		if !tunnels[tunnelName] {
+			tunnels[tunnelName] = true
+			objs = append(objs, tai.TunnelObj{Name: tunnelName})
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,86 @@
+package sonic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cn-pmlabs/govtep/tai"
+)
+
+type vrfAPI struct {
+	moduleID int
+	*sonicDriver
+}
+
+func newVrfAPI(d *sonicDriver) *vrfAPI {
+	return &vrfAPI{
+		moduleID:    tai.ObjectIDVrf,
+		sonicDriver: d,
+	}
+}
+
+func (v *vrfAPI) CreateObject(obj interface{}) error {
+	objVrf := obj.(tai.VrfObj)
+
+	if getVniByName(objVrf.Name, vrfNamePrefix) == 0 {
+		return fmt.Errorf("[Driver] Invalid vrf name %s", objVrf.Name)
+	}
+	return v.configDB.hset(configKey(tableVrf, objVrf.Name), nullFields)
+}
+
+func (v *vrfAPI) RemoveObject(obj interface{}) error {
+	objVrf := obj.(tai.VrfObj)
+	return v.configDB.del(configKey(tableVrf, objVrf.Name))
+}
+
+func (v *vrfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objVrf := obj.(tai.VrfObj)
+
+	key := configKey(tableVrf, objVrf.Name)
+	if exist, err := v.configDB.exists(key); err != nil {
+		return err
+	} else if !exist {
+		return fmt.Errorf("[Driver] vrf %s not exist", objVrf.Name)
+	}
+
+	if l3vni := attrs.GetInt(tai.VrfAttrL3vni); l3vni != 0 {
+		return v.configDB.hset(key, map[string]string{"vni": strconv.Itoa(l3vni)})
+	}
+	return nil
+}
+
+func (v *vrfAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objVrf := obj.(tai.VrfObj)
+
+	if _, ok := attrs[tai.VrfAttrL3vni]; ok {
+		return v.configDB.hdel(configKey(tableVrf, objVrf.Name), "vni")
+	}
+	return nil
+}
+
+func (v *vrfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.AddObjectAttr(obj, attrs)
+}
+
+func (v *vrfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
+	return nil, nil
+}
+
+// ListObject vrfs named "Vrf"+vni
+func (v *vrfAPI) ListObject() ([]interface{}, error) {
+	keys, err := v.configDB.scan(configKey(tableVrf, vrfNamePrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []interface{}
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, configKey(tableVrf, ""))
+		if getVniByName(name, vrfNamePrefix) == 0 {
+			continue
+		}
+		objs = append(objs, tai.VrfObj{Name: name})
+	}
+	return objs, nil
+}
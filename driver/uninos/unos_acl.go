@@ -13,10 +13,7 @@ import (
 
 type aclAPI struct {
 	moduleID int
-}
-
-var aclAPIs = aclAPI{
-	moduleID: tai.ObjectIDACL,
+	*unosDriver
 }
 
 func (v aclAPI) CreateObject(obj interface{}) error {
@@ -25,7 +22,7 @@ func (v aclAPI) CreateObject(obj interface{}) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: objACL.ACLName,
 	}
-	_, err := cdb.ACLGetByIndex(aclIndex)
+	_, err := v.db().ACLGetByIndex(aclIndex)
 	if err == nil {
 		log.Info("[Driver] ACL %s already exist", objACL.ACLName)
 		return nil
@@ -37,7 +34,7 @@ func (v aclAPI) CreateObject(obj interface{}) error {
 		Stage:   cdb.ACLStageIngress,
 	}
 
-	_, err = cdb.ACLAdd(aclCfg)
+	_, err = v.db().ACLAdd(aclCfg)
 	if err != nil {
 		return err
 	}
@@ -50,7 +47,7 @@ func (v aclAPI) RemoveObject(obj interface{}) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: objACL.ACLName,
 	}
-	err := cdb.ACLDelByIndex(aclIndex)
+	err := v.db().ACLDelByIndex(aclIndex)
 	if err != nil {
 		return err
 	}
@@ -58,13 +55,13 @@ func (v aclAPI) RemoveObject(obj interface{}) error {
 	return nil
 }
 
-func (v aclAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v aclAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objACL := obj.(tai.ACLObj)
 
 	aclIndex := cdb.ACLIndex{
 		ACLName: objACL.ACLName,
 	}
-	_, err := cdb.ACLGetByIndex(aclIndex)
+	_, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		return fmt.Errorf("[Driver] ACL %s not exist", objACL.ACLName)
 	}
@@ -72,9 +69,9 @@ func (v aclAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	for attr, attrValue := range attrs {
 		switch attr {
 		case tai.ACLAttrStage:
-			cdb.ACLSetField(aclIndex, cdb.ACLFieldStage, attrValue)
+			v.db().ACLSetField(aclIndex, cdb.ACLFieldStage, attrValue)
 		case tai.ACLAttrType:
-			cdb.ACLSetField(aclIndex, cdb.ACLFieldType, attrValue)
+			v.db().ACLSetField(aclIndex, cdb.ACLFieldType, attrValue)
 		case tai.ACLAttrRules:
 			aclRules, ok := attrValue.([]int)
 			if !ok {
@@ -86,14 +83,14 @@ func (v aclAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 					ACLName:  objACL.ACLName,
 					Sequence: aclRuleSeqnum,
 				}
-				tableACLRule, err := cdb.ACLRuleGetByIndex(aclRuleIndex)
+				tableACLRule, err := v.db().ACLRuleGetByIndex(aclRuleIndex)
 				if err != nil {
 					log.Warning("[Driver] ACLRule %d not exist", aclRuleSeqnum)
 					continue
 				}
 
 				ruleUpdate := []libovsdb.UUID{{GoUUID: tableACLRule.UUID}}
-				err = cdb.ACLUpdateRuleNameAddvalue(aclIndex, ruleUpdate)
+				err = v.db().ACLUpdateRuleNameAddvalue(aclIndex, ruleUpdate)
 				if err != nil {
 					continue
 				}
@@ -104,18 +101,20 @@ func (v aclAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v aclAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v aclAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v aclAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v aclAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v aclAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v aclAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject none, ACLs are named by OVN ACL and not told from ACLs
+// configured on switch
 func (v aclAPI) ListObject() ([]interface{}, error) {
 	return nil, nil
 }
@@ -13,10 +13,7 @@ import (
 
 type aclRuleAPI struct {
 	moduleID int
-}
-
-var aclRuleAPIs = aclRuleAPI{
-	moduleID: tai.ObjectIDACLRule,
+	*unosDriver
 }
 
 func (v aclRuleAPI) CreateObject(obj interface{}) error {
@@ -25,7 +22,7 @@ func (v aclRuleAPI) CreateObject(obj interface{}) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: objACLRule.ACLName,
 	}
-	_, err := cdb.ACLGetByIndex(aclIndex)
+	_, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		log.Warning("[Driver] ACL %s not exist", aclIndex.ACLName)
 		return nil
@@ -35,7 +32,7 @@ func (v aclRuleAPI) CreateObject(obj interface{}) error {
 		ACLName:  objACLRule.ACLName,
 		Sequence: objACLRule.Sequence,
 	}
-	_, err = cdb.ACLRuleGetByIndex(aclRuleIndex)
+	_, err = v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err == nil {
 		log.Info("[Driver] ACLRule %d already exist", objACLRule.Sequence)
 		return nil
@@ -46,7 +43,7 @@ func (v aclRuleAPI) CreateObject(obj interface{}) error {
 		Sequence: objACLRule.Sequence,
 	}
 
-	err = cdb.ACLUpdateAddRuleName(aclIndex, aclRuleCfg)
+	err = v.db().ACLUpdateAddRuleName(aclIndex, aclRuleCfg)
 	if err != nil {
 		return err
 	}
@@ -59,7 +56,7 @@ func (v aclRuleAPI) RemoveObject(obj interface{}) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: objACLRule.ACLName,
 	}
-	_, err := cdb.ACLGetByIndex(aclIndex)
+	_, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		return fmt.Errorf("[Driver] ACL %s not exist", aclIndex.ACLName)
 	}
@@ -68,13 +65,13 @@ func (v aclRuleAPI) RemoveObject(obj interface{}) error {
 		ACLName:  objACLRule.ACLName,
 		Sequence: objACLRule.Sequence,
 	}
-	tableACLRule, err := cdb.ACLRuleGetByIndex(aclRuleIndex)
+	tableACLRule, err := v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err != nil {
 		return fmt.Errorf("[Driver] ACLRule %d not exist", objACLRule.Sequence)
 	}
 	ruleUpdate := []libovsdb.UUID{{GoUUID: tableACLRule.UUID}}
 
-	err = cdb.ACLUpdateRuleNameDelvalue(aclIndex, ruleUpdate)
+	err = v.db().ACLUpdateRuleNameDelvalue(aclIndex, ruleUpdate)
 	if err != nil {
 		return err
 	}
@@ -82,22 +79,24 @@ func (v aclRuleAPI) RemoveObject(obj interface{}) error {
 	return nil
 }
 
-func (v aclRuleAPI) AddObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v aclRuleAPI) AddObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v aclRuleAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v aclRuleAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v aclRuleAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v aclRuleAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v aclRuleAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v aclRuleAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject none, rules of ACLs are not told from rules configured on
+// switch
 func (v aclRuleAPI) ListObject() ([]interface{}, error) {
 	return nil, nil
 }
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
@@ -14,176 +15,246 @@ import (
 
 type autoGatewayConfAPI struct {
 	moduleID int
+	*unosDriver
 }
 
-var autoGatewayConfAPIs = autoGatewayConfAPI{
-	moduleID: tai.ObjectIDAutoGatewayConf,
-}
-
-// AutoGatewayConfRelevantTable definition
-type AutoGatewayConfRelevantTable struct {
-	Vlan         int
-	IP           string
-	Bdname       string
-	PhysicalPort string
-	Vrf          string
-}
+// gatewayDefaultPrefixes default route prefixes of vrf, ECMP over nexthops
+// of the same address family
+var gatewayDefaultPrefixes = []string{"0.0.0.0/0", "::/0"}
 
-func addAutoGatewayconfRelevantTable(table AutoGatewayConfRelevantTable) error {
-	log.Info("Add auto gateway relevant table %+v.\n", table)
-	subportIndex := cdb.SubPortIndex{
-		Name: table.PhysicalPort + "." + strconv.Itoa(table.Vlan),
+// gatewayInterfaceIndex l3 interface of gateway uplink, sub interface with
+// vlan, otherwise routed lag or physical port
+func (d *unosDriver) gatewayInterfaceIndex(port string, vlan int) cdb.InterfaceIndex {
+	if vlan != 0 {
+		return cdb.InterfaceIndex{
+			Name: port + "." + strconv.Itoa(vlan),
+			Type: cdb.InterfaceTypeSubPort,
+		}
 	}
-	_, errExist := cdb.SubPortGetByIndex(subportIndex)
-	if errExist != nil {
-		tableSubport := cdb.TableSubPort{
-			Name: table.PhysicalPort + "." + strconv.Itoa(table.Vlan),
-			Vlan: []string{strconv.Itoa(table.Vlan)},
+	if _, err := d.db().LagGetByIndex(cdb.LagIndex{Name: port}); err == nil {
+		return cdb.InterfaceIndex{
+			Name: port,
+			Type: cdb.InterfaceTypeLag,
 		}
+	}
+	return cdb.InterfaceIndex{
+		Name: port,
+		Type: cdb.InterfaceTypePhysicalPort,
+	}
+}
 
-		portIndex := cdb.PortIndex{
-			Name: table.PhysicalPort,
+func (d *unosDriver) gatewayUplinkAdd(tableVrf cdb.TableVrf, uplink tai.AutoGatewayUplink, mtu int) error {
+	log.Info("[Driver] Add gateway uplink %+v of vrf %s\n", uplink, tableVrf.Name)
+	ifIndex := d.gatewayInterfaceIndex(uplink.Port, uplink.Vlan)
+	if uplink.Vlan != 0 {
+		if err := d.subPortAdd(uplink.Port, ifIndex.Name, []int{uplink.Vlan}); err != nil {
+			log.Error("[Driver] Create subport %v failed.\n", ifIndex.Name)
+			return err
 		}
+	}
 
-		err := cdb.PortUpdateAddSubport(portIndex, tableSubport)
+	vrf := []libovsdb.UUID{{GoUUID: tableVrf.UUID}}
+	tableIF, err := d.db().InterfaceGetByIndex(ifIndex)
+	if err != nil {
+		_, err = d.db().InterfaceAdd(cdb.TableInterface{
+			Name:        ifIndex.Name,
+			Type:        ifIndex.Type,
+			AdminStatus: []string{cdb.InterfaceDefaultAdminStatus},
+			ProxyArp:    []string{cdb.InterfaceProxyArpDisable},
+			IP:          uplink.IPs,
+			Mtu:         []int{mtu},
+			Vrf:         vrf,
+			SwitchPort:  []string{cdb.InterfaceSwitchPortDisable},
+		})
 		if err != nil {
-			log.Error("Create subport v% failed.\n", tableSubport.Name)
+			log.Error("[Driver] Create interface %v failed.\n", ifIndex.Name)
 			return err
 		}
+		d.gatewayUplinkIPs[ifIndex.Name] = uplink.IPs
+		return nil
 	}
 
-	vrfIndex := cdb.VrfIndex{
-		Name: table.Vrf,
+	if len(tableIF.Vrf) == 0 || tableIF.Vrf[0].GoUUID != tableVrf.UUID {
+		if err = d.db().InterfaceSetField(ifIndex, cdb.InterfaceFieldVrf, vrf); err != nil {
+			return err
+		}
+	}
+	if len(tableIF.SwitchPort) == 0 || tableIF.SwitchPort[0] != cdb.InterfaceSwitchPortDisable {
+		err = d.db().InterfaceSetField(ifIndex, cdb.InterfaceFieldSwitchPort, []string{cdb.InterfaceSwitchPortDisable})
+		if err != nil {
+			return err
+		}
+	}
+	if len(tableIF.Mtu) == 0 || tableIF.Mtu[0] != mtu {
+		if err = d.db().InterfaceSetField(ifIndex, cdb.InterfaceFieldMtu, []int{mtu}); err != nil {
+			return err
+		}
 	}
 
-	tableVrf, errExist := cdb.VrfGetByIndex(vrfIndex)
-	if errExist != nil {
-		log.Error("Get vrf table failed.\n")
-		return errExist
+	var staleIPs []string
+	for _, ip := range d.gatewayUplinkIPs[ifIndex.Name] {
+		if !strings.Contains(","+strings.Join(uplink.IPs, ",")+",", ","+ip+",") {
+			staleIPs = append(staleIPs, ip)
+		}
 	}
+	if len(staleIPs) > 0 {
+		if err = d.db().InterfaceUpdateIPDelvalue(ifIndex, staleIPs); err != nil {
+			return err
+		}
+	}
+	if len(uplink.IPs) > 0 {
+		if err = d.db().InterfaceUpdateIPAddvalue(ifIndex, uplink.IPs); err != nil {
+			log.Error("[Driver] Update interface: %v ip: %v failed.\n", ifIndex.Name, uplink.IPs)
+			return err
+		}
+	}
+	d.gatewayUplinkIPs[ifIndex.Name] = uplink.IPs
+	return nil
+}
 
+// gatewayUplinkDel sub interface is deleted with its sub port, routed port
+// is left without addresses programmed and vrf
+func (d *unosDriver) gatewayUplinkDel(tableIF cdb.TableInterface) error {
+	log.Info("[Driver] Delete gateway uplink %s\n", tableIF.Name)
 	ifIndex := cdb.InterfaceIndex{
-		Name: table.PhysicalPort + "." + strconv.Itoa(table.Vlan),
-		Type: cdb.InterfaceTypeSubPort,
+		Name: tableIF.Name,
+		Type: tableIF.Type,
 	}
+	ips := d.gatewayUplinkIPs[tableIF.Name]
+	delete(d.gatewayUplinkIPs, tableIF.Name)
 
-	tableIF, errExist := cdb.InterfaceGetByIndex(ifIndex)
-	if errExist == nil {
-		log.Info("Interface for %s already exist\n", ifIndex.Name)
+	if tableIF.Type == cdb.InterfaceTypeSubPort {
+		if err := d.db().InterfaceDelByIndex(ifIndex); err != nil {
+			log.Error("[Driver] Delete interface %v failed.\n", ifIndex.Name)
+			return err
+		}
+		return d.subPortDel(tableIF.Name[:strings.LastIndex(tableIF.Name, ".")], tableIF.Name)
+	}
 
-		err := cdb.InterfaceUpdateIPAddvalue(ifIndex, []string{table.IP})
-		if err != nil {
-			log.Error("Update interface: %v ip: %v failed.\n", ifIndex.Name, table.IP)
+	if len(ips) > 0 {
+		if err := d.db().InterfaceUpdateIPDelvalue(ifIndex, ips); err != nil {
 			return err
 		}
+	}
+	if len(tableIF.Vrf) > 0 {
+		if err := d.db().InterfaceUpdateVrfDelvalue(ifIndex, tableIF.Vrf); err != nil {
+			return err
+		}
+	}
+	return d.db().InterfaceSetField(ifIndex, cdb.InterfaceFieldMtu, []int{cdb.InterfaceDefaultMtu})
+}
 
-		if len(tableIF.Vrf) == 0 {
-			err = cdb.InterfaceUpdateVrfAddvalue(ifIndex, []libovsdb.UUID{{GoUUID: tableVrf.UUID}})
-			if err != nil {
-				log.Error("Update interface: %v vrf: %+v failed.\n", ifIndex.Name, libovsdb.UUID{GoUUID: tableVrf.UUID})
-				return err
+// gatewayDefaultRouteSync default routes of vrf with ECMP nexthops, route
+// without nexthop of its address family is removed
+func (d *unosDriver) gatewayDefaultRouteSync(vrf string, nexthops []string) error {
+	vrfIndex := cdb.VrfIndex{
+		Name: vrf,
+	}
+	for _, prefix := range gatewayDefaultPrefixes {
+		ipv6 := strings.Contains(prefix, ":")
+		nhMap := make(map[interface{}]interface{})
+		for _, nh := range nexthops {
+			if strings.Contains(nh, ":") == ipv6 {
+				nhKey := "vrfname:" + vrf + ",ip:" + nh + ",port:"
+				nhMap[nhKey] = "label:,onlink:,color:"
 			}
 		}
 
-	} else {
-		interfaceCfg := cdb.TableInterface{
-			Name:        table.PhysicalPort + "." + strconv.Itoa(table.Vlan),
-			Type:        cdb.InterfaceTypeSubPort,
-			AdminStatus: []string{cdb.InterfaceDefaultAdminStatus},
-			ProxyArp:    []string{cdb.InterfaceProxyArpDisable},
-			IP:          []string{table.IP},
-			Mtu:         []int{cdb.InterfaceDefaultMtu},
-			Vrf:         []libovsdb.UUID{{GoUUID: tableVrf.UUID}},
-			SwitchPort:  []string{cdb.InterfaceSwitchPortDisable},
+		var conditions []interface{}
+		conditions = append(conditions, libovsdb.
+			NewCondition("vrf", "==", vrf))
+		conditions = append(conditions, libovsdb.
+			NewCondition("ip", "==", prefix))
+		rows, num := d.db().StaticRouteGet(conditions)
+
+		var err error
+		switch {
+		case num == 0 && len(nhMap) > 0:
+			err = d.db().VrfUpdateAddRoute(vrfIndex, cdb.TableStaticRoute{
+				Vrf:     vrf,
+				IP:      prefix,
+				Nexthop: nhMap,
+			})
+		case num > 0 && len(nhMap) == 0:
+			tableRoute := cdb.ConvertRowToStaticRoute(rows[0])
+			err = d.db().VrfUpdateRouteDelvalue(vrfIndex, []libovsdb.UUID{{GoUUID: tableRoute.UUID}})
+		case num > 0:
+			tableRoute := cdb.ConvertRowToStaticRoute(rows[0])
+			routeIndex := cdb.StaticRouteUUIDIndex{
+				UUID: tableRoute.UUID,
+			}
+			err = d.db().StaticRouteSetField(routeIndex, cdb.StaticRouteFieldNexthop, nhMap)
 		}
-
-		_, err := cdb.InterfaceAdd(interfaceCfg)
 		if err != nil {
-			log.Error("Create interface %v failed.\n", interfaceCfg.Name)
+			log.Error("[Driver] Default route %s of vrf %s update failed %v\n", prefix, vrf, err)
 			return err
 		}
 	}
 	return nil
 }
 
-func delAutoGatewayconfRelevantTableByVrf(vrf string) error {
-	vrfIndex := cdb.VrfIndex{
-		Name: vrf,
+// autoGatewayConfSync program uplinks and default routes of vrf from its
+// auto gateway conf in vtepdb, uplinks of vrf programmed by driver and not
+// configured any more are removed. Other interfaces of vrf are kept.
+func (d *unosDriver) autoGatewayConfSync(vrf string) error {
+	tableVrf, err := d.db().VrfGetByIndex(cdb.VrfIndex{Name: vrf})
+	if err != nil {
+		log.Warning("[Driver] Get vrf %v failed.\n", vrf)
+		return nil
 	}
 
-	tableVrf, errExist := cdb.VrfGetByIndex(vrfIndex)
-	if errExist != nil {
-		log.Warning("Get vrf %v failed.\n", vrf)
-		return nil
+	var uplinks []tai.AutoGatewayUplink
+	var nexthops []string
+	mtu := cdb.InterfaceDefaultMtu
+	tableAGC, err := vtepdb.AutoGatewayConfGetByIndex(vtepdb.AutoGatewayConfIndex{Vrf: vrf})
+	if err == nil && strings.Contains(tableAGC.Bdname, "Bd") {
+		uplinks = tai.AutoGatewayUplinks(tableAGC)
+		nexthops = tableAGC.Nexthops
+		if len(tableAGC.Mtu) > 0 {
+			mtu = tableAGC.Mtu[0]
+		}
 	}
 
+	names := make(map[string]bool, len(uplinks))
+	for _, uplink := range uplinks {
+		names[d.gatewayInterfaceIndex(uplink.Port, uplink.Vlan).Name] = true
+	}
 	var conditions []interface{}
-	conditions = append(conditions, libovsdb.NewCondition(cdb.InterfaceFieldType, "==", cdb.InterfaceTypeSubPort))
 	conditions = append(conditions, libovsdb.NewCondition(cdb.InterfaceFieldVrf, "==", libovsdb.UUID{GoUUID: tableVrf.UUID}))
-
-	row, num := cdb.InterfaceGet(conditions)
-	if num == 0 {
-		log.Info("Interface with Type : %v, Vrf : %v don't existed.\n", cdb.InterfaceTypeSubPort, vrf)
-		return nil
-	} else {
-		ifIndex := cdb.InterfaceIndex{
-			Name: cdb.ConvertRowToInterface(row[0]).Name,
-			Type: cdb.InterfaceTypeSubPort,
+	rows, _ := d.db().InterfaceGet(conditions)
+	for _, row := range rows {
+		tableIF := cdb.ConvertRowToInterface(row)
+		switch tableIF.Type {
+		case cdb.InterfaceTypeSubPort, cdb.InterfaceTypePhysicalPort, cdb.InterfaceTypeLag:
+		default:
+			continue
 		}
-
-		_, errExist := cdb.InterfaceGetByIndex(ifIndex)
-		if errExist == nil {
-			err := cdb.InterfaceDelByIndex(ifIndex)
-			if err != nil {
-				log.Error("Delete interface %v failed.\n", ifIndex.Name)
-				return err
-			}
+		if _, ok := d.gatewayUplinkIPs[tableIF.Name]; !ok || names[tableIF.Name] {
+			continue
 		}
-
-		subportIndex := cdb.SubPortIndex{
-			Name: ifIndex.Name,
+		if err = d.gatewayUplinkDel(tableIF); err != nil {
+			log.Error("[Driver] Delete gateway uplink %s failed %v\n", tableIF.Name, err)
+			return err
 		}
-		tableSubPort, errExist := cdb.SubPortGetByIndex(subportIndex)
-		if errExist == nil {
-			portIndex := cdb.PortIndex{
-				Name: ifIndex.Name[:strings.Index(ifIndex.Name, ".")],
-			}
-			err := cdb.PortUpdateSubportDelvalue(portIndex, []libovsdb.UUID{{GoUUID: tableSubPort.UUID}})
-			if err != nil {
-				log.Error("Delete subport %v failed.\n", portIndex.Name)
-				return err
-			}
+	}
+
+	for _, uplink := range uplinks {
+		if err = d.gatewayUplinkAdd(tableVrf, uplink, mtu); err != nil {
+			log.Error("[Driver] Add gateway uplink %s failed %v\n", uplink.Name, err)
+			return err
 		}
 	}
-	return nil
+	return d.gatewayDefaultRouteSync(vrf, nexthops)
 }
 
 func (v autoGatewayConfAPI) CreateObject(obj interface{}) error {
 	log.Info("Create object %+v.\n", obj)
 	objAutoGatewayConf := obj.(tai.AutoGatewayConfObj)
 
-	if strings.Contains(objAutoGatewayConf.Bdname, "Bd") && strings.Contains(objAutoGatewayConf.PhysicalPort, "ep") {
-		err := delAutoGatewayconfRelevantTableByVrf(objAutoGatewayConf.Vrf)
-		if err != nil {
-			log.Error("Del autoGatewayconf relevant table by Vrf failed.\n")
-			return err
-		}
-		autoGatewayConfRelevantTable := AutoGatewayConfRelevantTable{
-			Vlan:         objAutoGatewayConf.Vlan,
-			IP:           objAutoGatewayConf.IP,
-			Bdname:       objAutoGatewayConf.Bdname,
-			PhysicalPort: objAutoGatewayConf.PhysicalPort,
-			Vrf:          objAutoGatewayConf.Vrf,
-		}
-
-		err = addAutoGatewayconfRelevantTable(autoGatewayConfRelevantTable)
-		if err != nil {
-			log.Error("Add auto gateway conf relevant table failed.\n")
-			return err
-		}
+	if err := v.autoGatewayConfSync(objAutoGatewayConf.Vrf); err != nil {
+		return err
 	}
-
-	pbrUpdateGateway(objAutoGatewayConf.Vrf, objAutoGatewayConf.PhysicalPort, objAutoGatewayConf.Vlan)
+	v.pbrUpdateGateway(objAutoGatewayConf.Vrf, objAutoGatewayConf.PhysicalPort, objAutoGatewayConf.Vlan)
 
 	return nil
 }
@@ -191,90 +262,48 @@ func (v autoGatewayConfAPI) CreateObject(obj interface{}) error {
 func (v autoGatewayConfAPI) RemoveObject(obj interface{}) error {
 	log.Info("Remove object %+v.\n", obj)
 	objAutoGatewayConf := obj.(tai.AutoGatewayConfObj)
-	err := delAutoGatewayconfRelevantTableByVrf(objAutoGatewayConf.Vrf)
+	err := v.autoGatewayConfSync(objAutoGatewayConf.Vrf)
 	if err != nil {
 		log.Error("Del autoGatewayconf relevant table by Vrf failed.\n")
 	}
 	return err
 }
-func (v autoGatewayConfAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
-	log.Info("Set object : %v attr %+v.\n", obj, attrs)
-	objAutoGatewayConf := obj.(tai.AutoGatewayConfObj)
-	if strings.Contains(objAutoGatewayConf.Bdname, "Bd") && strings.Contains(objAutoGatewayConf.PhysicalPort, "ep") {
-		err := delAutoGatewayconfRelevantTableByVrf(objAutoGatewayConf.Vrf)
-		if err != nil {
-			log.Error("Del autoGatewayconf relevant table by Vrf failed.\n")
-			return err
-		}
-
-		autoGatewayConfRelevantTable := AutoGatewayConfRelevantTable{
-			Vlan:         objAutoGatewayConf.Vlan,
-			IP:           objAutoGatewayConf.IP,
-			Bdname:       objAutoGatewayConf.Bdname,
-			PhysicalPort: objAutoGatewayConf.PhysicalPort,
-			Vrf:          objAutoGatewayConf.Vrf,
-		}
 
-		err = addAutoGatewayconfRelevantTable(autoGatewayConfRelevantTable)
-		if err != nil {
-			log.Error("Add auto gateway conf relevant table failed.\n")
-			return err
-		}
-	}
-
-	pbrUpdateGateway(objAutoGatewayConf.Vrf, objAutoGatewayConf.PhysicalPort, objAutoGatewayConf.Vlan)
-
-	return nil
+func (v autoGatewayConfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	log.Info("Set object : %v attr %+v.\n", obj, attrs)
+	return v.CreateObject(obj)
 }
 
-func (v autoGatewayConfAPI) DelObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v autoGatewayConfAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	log.Info("Set object : %v attr %+v.\n", obj, attrs)
-	objAutoGatewayConf := obj.(tai.AutoGatewayConfObj)
-
-	if !(strings.Contains(objAutoGatewayConf.Bdname, "Bd") && strings.Contains(objAutoGatewayConf.PhysicalPort, "ep")) {
-		err := delAutoGatewayconfRelevantTableByVrf(objAutoGatewayConf.Vrf)
-		if err != nil {
-			log.Error("Del autoGatewayconf relevant table by Vrf failed.\n")
-			return err
-		}
-	}
-	return nil
+	return v.CreateObject(obj)
 }
 
-func (v autoGatewayConfAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v autoGatewayConfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	log.Info("Set object : %v attr %+v.\n", obj, attrs)
-	objAutoGatewayConf := obj.(tai.AutoGatewayConfObj)
-	err := delAutoGatewayconfRelevantTableByVrf(objAutoGatewayConf.Vrf)
-	if err != nil {
-		log.Error("Del autoGatewayconf relevant table by Vrf failed.\n")
-		return err
-	}
-
-	if strings.Contains(objAutoGatewayConf.Bdname, "Bd") && strings.Contains(objAutoGatewayConf.PhysicalPort, "ep") {
-		autoGatewayConfRelevantTable := AutoGatewayConfRelevantTable{
-			Vlan:         objAutoGatewayConf.Vlan,
-			IP:           objAutoGatewayConf.IP,
-			Bdname:       objAutoGatewayConf.Bdname,
-			PhysicalPort: objAutoGatewayConf.PhysicalPort,
-			Vrf:          objAutoGatewayConf.Vrf,
-		}
-
-		err = addAutoGatewayconfRelevantTable(autoGatewayConfRelevantTable)
-		if err != nil {
-			log.Error("Add auto gateway conf relevant table failed.\n")
-			return err
-		}
-	}
-
-	pbrUpdateGateway(objAutoGatewayConf.Vrf, objAutoGatewayConf.PhysicalPort, objAutoGatewayConf.Vlan)
-
-	return nil
+	return v.CreateObject(obj)
 }
 
-func (v autoGatewayConfAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v autoGatewayConfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject conf of vrfs created by driver with default routes, the conf
+// listed by vrf only is synced again from vtepdb on removal
 func (v autoGatewayConfAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	var objs []interface{}
+	for _, tableVrf := range v.driverVrfs() {
+		for _, prefix := range gatewayDefaultPrefixes {
+			var conditions []interface{}
+			conditions = append(conditions, libovsdb.
+				NewCondition("vrf", "==", tableVrf.Name))
+			conditions = append(conditions, libovsdb.
+				NewCondition("ip", "==", prefix))
+			if _, num := v.db().StaticRouteGet(conditions); num > 0 {
+				objs = append(objs, tai.AutoGatewayConfObj{Vrf: tableVrf.Name})
+				break
+			}
+		}
+	}
+	return objs, nil
 }
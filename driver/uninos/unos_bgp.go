@@ -0,0 +1,163 @@
+package driver
+
+import (
+	"fmt"
+
+	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// EvpnAs bgp as of vrf instances exporting tenant prefixes as EVPN type-5
+// routes, 0 disables
+var EvpnAs int
+
+// bgpAfis unicast address families redistributed into EVPN
+var bgpAfis = []string{cdb.BgpAfUnicastAfiIpv4, cdb.BgpAfUnicastAfiIpv6}
+
+// bgpVrfRdRt RD and RT of vrf derived from its l3vni as AS:vni, 4 bytes
+// AS leaves 2 bytes for vni
+func bgpVrfRdRt(vni int) (string, error) {
+	if EvpnAs > 65535 && vni > 65535 {
+		return "", fmt.Errorf("[Driver] l3vni %d exceeds 2 bytes for 4 bytes as %d", vni, EvpnAs)
+	}
+	return fmt.Sprintf("%d:%d", EvpnAs, vni), nil
+}
+
+// bgpVrfCreate bgp instance of vrf exporting connected routes as type-5,
+// connected routes are tenant subnets of vrf and NAT external IPs on
+// gateway sub interface. Router mac of type-5 is mac of BdVrf interface.
+// Instance is created with its address families and redistributes in one
+// transaction, RD and RT of existing instance follow vni.
+func (d *unosDriver) bgpVrfCreate(vrfName string, vni int) error {
+	if EvpnAs == 0 {
+		return nil
+	}
+
+	bgpIndex := cdb.BgpInstanceIndex{
+		BgpAs:   EvpnAs,
+		VrfName: vrfName,
+	}
+	if _, err := d.db().BgpInstanceGetByIndex(bgpIndex); err == nil {
+		log.Info("[Driver] BGP instance of vrf %s already exist\n", vrfName)
+		return d.bgpVrfRdRtSet(vrfName, vni)
+	}
+	rdrt, err := bgpVrfRdRt(vni)
+	if err != nil {
+		return err
+	}
+
+	var ops []libovsdb.Operation
+	var unicasts []libovsdb.UUID
+	for _, afi := range bgpAfis {
+		redistributeOp, err := cdb.RedistributeAddOp(cdb.TableRedistribute{
+			Name:         vrfName + "_" + afi + "_" + cdb.RedistributeTypeConnected,
+			InstanceID:   EvpnAs,
+			InstanceType: cdb.RedistributeInstanceTypeBgp,
+			VrfName:      vrfName,
+			BgpAfi:       []string{afi},
+			Type:         cdb.RedistributeTypeConnected,
+		})
+		if err != nil {
+			return err
+		}
+		unicastOp, err := cdb.BgpAfUnicastAddOp(cdb.TableBgpAfUnicast{
+			Afi:          afi,
+			BgpAs:        EvpnAs,
+			VrfName:      vrfName,
+			Redistribute: []libovsdb.UUID{{GoUUID: redistributeOp.UUIDName}},
+		})
+		if err != nil {
+			return err
+		}
+		ops = append(ops, redistributeOp, unicastOp)
+		unicasts = append(unicasts, libovsdb.UUID{GoUUID: unicastOp.UUIDName})
+	}
+
+	evpnOp, err := cdb.BgpAfEvpnAddOp(cdb.TableBgpAfEvpn{
+		Afi:     cdb.BgpAfEvpnAfiL2vpn,
+		BgpAs:   EvpnAs,
+		VrfName: vrfName,
+		AdvertiseType5: map[interface{}]interface{}{
+			cdb.BgpAfUnicastAfiIpv4: cdb.BgpAfEvpnAdvertiseType5Enable,
+			cdb.BgpAfUnicastAfiIpv6: cdb.BgpAfEvpnAdvertiseType5Enable,
+		},
+		Rd: []string{rdrt},
+		Rt: map[interface{}]interface{}{cdb.BgpAfEvpnRtBoth: rdrt},
+	})
+	if err != nil {
+		return err
+	}
+	ops = append(ops, evpnOp)
+
+	tableBgp := cdb.TableBgpInstance{
+		BgpAs:   EvpnAs,
+		VrfName: vrfName,
+		Unicast: unicasts,
+		Evpn:    []libovsdb.UUID{{GoUUID: evpnOp.UUIDName}},
+	}
+	if err = tableBgp.Validate(); err != nil {
+		return err
+	}
+	row, err := cdb.ConvertTableToRow(tableBgp, cdb.BgpInstanceFieldMapToColumn)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, libovsdb.Operation{
+		Op:    opInsert,
+		Table: cdb.BgpInstance,
+		Row:   row,
+	})
+	_, err = d.db().Transact(ops...)
+	return err
+}
+
+// bgpVrfRdRtSet set RD and RT of bgp instance of vrf by its l3vni
+func (d *unosDriver) bgpVrfRdRtSet(vrfName string, vni int) error {
+	if EvpnAs == 0 {
+		return nil
+	}
+
+	evpnIndex := cdb.BgpAfEvpnIndex{
+		Afi:     cdb.BgpAfEvpnAfiL2vpn,
+		BgpAs:   EvpnAs,
+		VrfName: vrfName,
+	}
+	tableEvpn, err := d.db().BgpAfEvpnGetByIndex(evpnIndex)
+	if err != nil {
+		return fmt.Errorf("[Driver] BGP evpn of vrf %s not exist", vrfName)
+	}
+	rdrt, err := bgpVrfRdRt(vni)
+	if err != nil {
+		return err
+	}
+	if len(tableEvpn.Rd) == 1 && tableEvpn.Rd[0] == rdrt && tableEvpn.Rt[cdb.BgpAfEvpnRtBoth] == rdrt {
+		return nil
+	}
+
+	log.Info("[Driver] BGP instance of vrf %s RD and RT set %s\n", vrfName, rdrt)
+	if err = d.db().BgpAfEvpnSetField(evpnIndex, cdb.BgpAfEvpnFieldRd, []string{rdrt}); err != nil {
+		return err
+	}
+	return d.db().BgpAfEvpnSetField(evpnIndex, cdb.BgpAfEvpnFieldRt,
+		map[interface{}]interface{}{cdb.BgpAfEvpnRtBoth: rdrt})
+}
+
+// bgpVrfRemove bgp instance of vrf, address families and redistributes
+// are removed with it
+func (d *unosDriver) bgpVrfRemove(vrfName string) error {
+	if EvpnAs == 0 {
+		return nil
+	}
+
+	bgpIndex := cdb.BgpInstanceIndex{
+		BgpAs:   EvpnAs,
+		VrfName: vrfName,
+	}
+	if _, err := d.db().BgpInstanceGetByIndex(bgpIndex); err != nil {
+		return nil
+	}
+	return d.db().BgpInstanceDelByIndex(bgpIndex)
+}
@@ -3,7 +3,9 @@ package driver
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
@@ -23,10 +25,7 @@ func getVniByBdName(bdName string) int {
 
 type bridgeAPI struct {
 	moduleID int
-}
-
-var bdAPIs = bridgeAPI{
-	moduleID: tai.ObjectIDBridge,
+	*unosDriver
 }
 
 func (d bridgeAPI) CreateObject(obj interface{}) error {
@@ -51,13 +50,13 @@ func (d bridgeAPI) CreateObject(obj interface{}) error {
 	bridgeIndex := cdb.BridgeIndex{
 		Name: objBridge.Name,
 	}
-	_, err := cdb.BridgeGetByIndex(bridgeIndex)
+	_, err := d.db().BridgeGetByIndex(bridgeIndex)
 	if err == nil {
 		log.Info("[Driver] bridge %s already exist\n", objBridge.Name)
 		goto interfaceADD
 	}
 
-	_, err = cdb.BridgeAdd(bridgeCfg)
+	_, err = d.db().BridgeAdd(bridgeCfg)
 	if err != nil {
 		return err
 	}
@@ -76,13 +75,13 @@ interfaceADD:
 		Name: bridgeCfg.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	_, err = cdb.InterfaceGetByIndex(ifIndex)
+	_, err = d.db().InterfaceGetByIndex(ifIndex)
 	if err == nil {
 		log.Info("[Driver] interface %s type %s already exist\n", ifIndex.Name, ifIndex.Type)
 		return nil
 	}
 
-	_, err = cdb.InterfaceAdd(interfaceCfg)
+	_, err = d.db().InterfaceAdd(interfaceCfg)
 	if err != nil {
 		return err
 	}
@@ -96,7 +95,7 @@ func (d bridgeAPI) RemoveObject(obj interface{}) error {
 	bridgeIndex := cdb.BridgeIndex{
 		Name: objBridge.Name,
 	}
-	err := cdb.BridgeDelByIndex(bridgeIndex)
+	err := d.db().BridgeDelByIndex(bridgeIndex)
 	if err != nil {
 		return err
 	}
@@ -105,7 +104,7 @@ func (d bridgeAPI) RemoveObject(obj interface{}) error {
 		Name: bridgeIndex.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	err = cdb.InterfaceDelByIndex(interfaceIndex)
+	err = d.db().InterfaceDelByIndex(interfaceIndex)
 	if err != nil {
 		return err
 	}
@@ -113,65 +112,91 @@ func (d bridgeAPI) RemoveObject(obj interface{}) error {
 	return nil
 }
 
-func (d bridgeAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (d bridgeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objBridge := obj.(tai.BridgeObj)
 
 	bridgeIndex := cdb.BridgeIndex{
 		Name: objBridge.Name,
 	}
-	_, err := cdb.BridgeGetByIndex(bridgeIndex)
+	_, err := d.db().BridgeGetByIndex(bridgeIndex)
 	if err != nil {
 		log.Warning("[Driver] BD %s not exist\n", bridgeIndex.Name)
 		return err
 	}
 
-	if attrs[tai.BridgeAttrVxlanTunnel] != nil {
-		tunnelName := attrs[tai.BridgeAttrVxlanTunnel].(string)
+	if attrs.Has(tai.BridgeAttrVxlanTunnel) {
+		tunnelName := attrs.GetString(tai.BridgeAttrVxlanTunnel)
 		tunnelIndex := cdb.TunnelIndex{
 			Name: tunnelName,
 		}
-		tableTunnel, err := cdb.TunnelGetByIndex(tunnelIndex)
+		tableTunnel, err := d.db().TunnelGetByIndex(tunnelIndex)
 		if err != nil {
 			log.Warning("[Driver] BD %s tunnel %s not exist\n", bridgeIndex.Name, tunnelName)
 			return nil
 		}
 
-		cdb.BridgeUpdateVxlanTunnelAddvalue(bridgeIndex, []libovsdb.UUID{{GoUUID: tableTunnel.UUID}})
+		d.db().BridgeUpdateVxlanTunnelAddvalue(bridgeIndex, []libovsdb.UUID{{GoUUID: tableTunnel.UUID}})
 	}
 
 	return nil
 }
 
-func (d bridgeAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (d bridgeAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (d bridgeAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (d bridgeAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (d bridgeAPI) GetObjectAttr(obj interface{}, attrIDs []interface{}) (map[interface{}]interface{}, error) {
-	attrs := make(map[interface{}]interface{})
+func (d bridgeAPI) GetObjectAttr(obj interface{}, attrIDs []tai.ObjAttrID) (tai.Attrs, error) {
+	attrs := make(tai.Attrs)
 	objBridge := obj.(tai.BridgeObj)
 
 	bridgeIndex := cdb.BridgeIndex{
 		Name: objBridge.Name,
 	}
-	tableBridge, err := cdb.BridgeGetByIndex(bridgeIndex)
+	tableBridge, err := d.db().BridgeGetByIndex(bridgeIndex)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, attr := range attrIDs {
-		switch attr.(string) {
+		switch attr {
 		case tai.BridgeAttrL2vni:
-			attrs[attr.(string)] = tableBridge.Vni
+			attrs[attr] = tableBridge.Vni
 		}
 	}
 
 	return attrs, nil
 }
 
+// driverBridges bridges created by driver, named "Bd"+vni
+func (d *unosDriver) driverBridges() []cdb.TableBridge {
+	var bridges []cdb.TableBridge
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: vtepdb.InvalidUUID}))
+	rows, _ := d.db().BridgeGet(conditions)
+	for _, row := range rows {
+		tableBridge := cdb.ConvertRowToBridge(row)
+		if !strings.HasPrefix(tableBridge.Name, "Bd") {
+			continue
+		}
+		vni := getVniByBdName(tableBridge.Name)
+		if vni < cdb.BridgeVniMin || vni > cdb.BridgeVniMax {
+			continue
+		}
+		bridges = append(bridges, tableBridge)
+	}
+	return bridges
+}
+
+// ListObject bridges created by driver
 func (d bridgeAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	var objs []interface{}
+	for _, tableBridge := range d.driverBridges() {
+		objs = append(objs, tai.BridgeObj{Name: tableBridge.Name, Vni: getVniByBdName(tableBridge.Name)})
+	}
+	return objs, nil
 }
@@ -1,10 +1,21 @@
 package driver
 
+import (
+	"strconv"
+
+	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
+
+	"github.com/ebay/libovsdb"
+)
+
 const (
 	vniMin = 1
 	vniMax = 16777215
 )
 
+// opInsert insert operation of rows built in one transaction
+const opInsert = "insert"
+
 const (
 	bridgeDefaultMacLimit       = 16384
 	bridgeDefaultMacLearn       = "enable"
@@ -27,3 +38,54 @@ const (
 	interfaceDefaultMtu         = 9600
 	interfaceDefaultAdminStatus = "up"
 )
+
+// subPortAdd sub port of physical port or lag, QinQ sub port has outer tag
+// and inner tag
+func (d *unosDriver) subPortAdd(port string, name string, tags []int) error {
+	if _, err := d.db().SubPortGetByIndex(cdb.SubPortIndex{Name: name}); err == nil {
+		return nil
+	}
+	tableSubport := cdb.TableSubPort{
+		Name: name,
+	}
+	for _, tag := range tags {
+		tableSubport.Vlan = append(tableSubport.Vlan, strconv.Itoa(tag))
+	}
+	if _, err := d.db().LagGetByIndex(cdb.LagIndex{Name: port}); err == nil {
+		return d.db().LagUpdateAddSubport(cdb.LagIndex{Name: port}, tableSubport)
+	}
+	return d.db().PortUpdateAddSubport(cdb.PortIndex{Name: port}, tableSubport)
+}
+
+func (d *unosDriver) subPortDel(port string, name string) error {
+	tableSubPort, err := d.db().SubPortGetByIndex(cdb.SubPortIndex{Name: name})
+	if err != nil {
+		return nil
+	}
+	subport := []libovsdb.UUID{{GoUUID: tableSubPort.UUID}}
+	if _, err := d.db().LagGetByIndex(cdb.LagIndex{Name: port}); err == nil {
+		return d.db().LagUpdateSubportDelvalue(cdb.LagIndex{Name: port}, subport)
+	}
+	return d.db().PortUpdateSubportDelvalue(cdb.PortIndex{Name: port}, subport)
+}
+
+// subPortParents physical port or lag of sub ports by sub port name
+func (d *unosDriver) subPortParents() map[string]string {
+	parents := make(map[string]string)
+	addSubPorts := func(port string, subports []libovsdb.UUID) {
+		for _, subport := range subports {
+			tableSubPort, err := d.db().SubPortGetByUUID(subport.GoUUID)
+			if err != nil {
+				continue
+			}
+			parents[tableSubPort.Name] = port
+		}
+	}
+	d.db().PortIterator(func(table cdb.TablePort) {
+		addSubPorts(table.Name, table.Subport)
+	})
+	d.db().LagIterator(func(table cdb.TableLag) {
+		addSubPorts(table.Name, table.Subport)
+	})
+	return parents
+}
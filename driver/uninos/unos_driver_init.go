@@ -1,6 +1,8 @@
 package driver
 
 import (
+	"sync"
+
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
 
 	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
@@ -10,42 +12,99 @@ import (
 type unosDriver struct {
 	DriverName string
 	ModuleAPIs map[tai.ObjID]moduleAPI
+	// mutex serialize TAI calls of driver instance
+	mutex sync.Mutex
+	// client config db of driver instance programmed by its modules
+	client *cdb.Client
+	// batch client of client between TaiBegin and TaiCommit or TaiAbort
+	batch *cdb.Client
+
+	// gatewayUplinkIPs addresses programmed on uplink interfaces by
+	// interface name, external IPs added by PBR on the same interface are
+	// kept
+	gatewayUplinkIPs map[string][]string
+	// ecmpGroupIDPool ecmp group ids of PBR in use
+	ecmpGroupIDPool map[int]int
+	// ecmpGroupFull table full is notified when no ecmp group id left, and
+	// cleared when an id is released
+	ecmpGroupFull bool
 }
 
 type moduleAPI interface {
 	CreateObject(interface{}) error
 	RemoveObject(interface{}) error
-	AddObjectAttr(interface{}, map[interface{}]interface{}) error
-	DelObjectAttr(interface{}, map[interface{}]interface{}) error
-	SetObjectAttr(interface{}, map[interface{}]interface{}) error
-	GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error)
+	AddObjectAttr(interface{}, tai.Attrs) error
+	DelObjectAttr(interface{}, tai.Attrs) error
+	SetObjectAttr(interface{}, tai.Attrs) error
+	GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error)
 	ListObject() ([]interface{}, error)
 }
 
-var unosDriverHandler = unosDriver{
-	DriverName: "UNOS",
-	ModuleAPIs: make(map[tai.ObjID]moduleAPI),
+// DriverName of UNOS TAI driver
+const DriverName = "UNOS"
+
+func init() {
+	tai.RegisterDriver(DriverName, Init)
+}
+
+// Init UNOS TAI driver instance, switch with own config db address
+// connects its own client, others use the default unosconfig client
+func Init(config tai.DriverConfig) (tai.DriverHandler, error) {
+	var client *cdb.Client
+	addr := config.Addr
+	if addr != "" {
+		var err error
+		if client, err = cdb.NewClient(addr, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		addr = odbc.ConfigdbAddr
+		if cdb.UnosconfigClient.Conn() == nil {
+			cdb.InitUnosconfig(addr)
+		}
+		client = cdb.UnosconfigClient
+	}
+	portInventoryMonitor(client, addr, config.Switch)
+
+	d := &unosDriver{
+		DriverName:       DriverName,
+		client:           client,
+		gatewayUplinkIPs: make(map[string][]string),
+		ecmpGroupIDPool:  make(map[int]int),
+	}
+	d.ModuleAPIs = moduleAPIs(d)
+	return d, nil
+}
+
+// moduleAPIs UNOS modules of driver instance by object
+func moduleAPIs(d *unosDriver) map[tai.ObjID]moduleAPI {
+	return map[tai.ObjID]moduleAPI{
+		tai.ObjectIDBridge:          bridgeAPI{tai.ObjectIDBridge, d},
+		tai.ObjectIDVrf:             vrfAPI{tai.ObjectIDVrf, d},
+		tai.ObjectIDL2Port:          l2portAPI{tai.ObjectIDL2Port, d},
+		tai.ObjectIDL3Port:          l3portAPI{tai.ObjectIDL3Port, d},
+		tai.ObjectIDFDB:             fdbAPI{tai.ObjectIDFDB, d},
+		tai.ObjectIDNeighbour:       neighbourAPI{tai.ObjectIDNeighbour, d},
+		tai.ObjectIDRoute:           routeAPI{tai.ObjectIDRoute, d},
+		tai.ObjectIDTunnel:          tunnelAPI{tai.ObjectIDTunnel, d},
+		tai.ObjectIDACL:             aclAPI{tai.ObjectIDACL, d},
+		tai.ObjectIDACLRule:         aclRuleAPI{tai.ObjectIDACLRule, d},
+		tai.ObjectIDPBR:             pbrAPI{tai.ObjectIDPBR, d},
+		tai.ObjectIDAutoGatewayConf: autoGatewayConfAPI{tai.ObjectIDAutoGatewayConf, d},
+	}
 }
 
-// Init UNOS TAI driver and unosconfig db
-func Init() {
-	cdb.InitUnosconfig(odbc.ConfigdbAddr)
-	tai.RegisterTaiDriverHandler(unosDriverHandler.DriverName, &unosDriverHandler)
-
-	unosDriverHandler.ModuleAPIs = map[tai.ObjID]moduleAPI{
-		tai.ObjectIDBridge:          bdAPIs,
-		tai.ObjectIDVrf:             vrfAPIs,
-		tai.ObjectIDL2Port:          l2portAPIs,
-		tai.ObjectIDL3Port:          l3portAPIs,
-		tai.ObjectIDFDB:             fdbAPIs,
-		tai.ObjectIDNeighbour:       neighbourAPIs,
-		tai.ObjectIDRoute:           routeAPIs,
-		tai.ObjectIDTunnel:          tunnelAPIs,
-		tai.ObjectIDACL:             aclAPIs,
-		tai.ObjectIDACLRule:         aclRuleAPIs,
-		tai.ObjectIDPBR:             pbrAPIs,
-		tai.ObjectIDAutoGatewayConf: autoGatewayConfAPIs,
+// db config db client of driver instance, or its batch client in batch
+func (d *unosDriver) db() *cdb.Client {
+	if d.batch != nil {
+		return d.batch
 	}
+	return d.client
+}
 
-	return
+// use run fn of modules holding mutex of driver instance
+func (d *unosDriver) use(fn func() error) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return fn()
 }
@@ -9,10 +9,7 @@ import (
 
 type fdbAPI struct {
 	moduleID int
-}
-
-var fdbAPIs = fdbAPI{
-	moduleID: tai.ObjectIDFDB,
+	*unosDriver
 }
 
 func (v fdbAPI) CreateObject(obj interface{}) error {
@@ -22,7 +19,7 @@ func (v fdbAPI) CreateObject(obj interface{}) error {
 		Address:       objFdb.Mac,
 		ForwardDomain: objFdb.Bridge,
 	}
-	_, err := cdb.FdbGetByIndex(fdbIndex)
+	_, err := v.db().FdbGetByIndex(fdbIndex)
 	if err == nil {
 		log.Info("[Driver] fdb %+v already exist\n", fdbIndex)
 		return nil
@@ -33,7 +30,7 @@ func (v fdbAPI) CreateObject(obj interface{}) error {
 		ForwardDomain: objFdb.Bridge,
 	}
 
-	_, err = cdb.FdbAdd(fdbCfg)
+	_, err = v.db().FdbAdd(fdbCfg)
 	if err != nil {
 		return err
 	}
@@ -48,21 +45,21 @@ func (v fdbAPI) RemoveObject(obj interface{}) error {
 		ForwardDomain: objFdb.Bridge,
 	}
 
-	err := cdb.FdbDelByIndex(fdbIndex)
+	err := v.db().FdbDelByIndex(fdbIndex)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (v fdbAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v fdbAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objFdb := obj.(tai.FdbObj)
 
 	fdbIndex := cdb.FdbIndex{
 		Address:       objFdb.Mac,
 		ForwardDomain: objFdb.Bridge,
 	}
-	_, err := cdb.FdbGetByIndex(fdbIndex)
+	_, err := v.db().FdbGetByIndex(fdbIndex)
 	if err != nil {
 		log.Warning("[Driver] fdb %+v not exist\n", fdbIndex)
 		return nil
@@ -72,11 +69,11 @@ func (v fdbAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		switch attr {
 		case tai.FdbAttrRemoteIP:
 			if remoteIP, ok := attrValue.(string); ok {
-				cdb.FdbSetField(fdbIndex, cdb.FdbFieldRemoteIP, []string{remoteIP})
+				v.db().FdbSetField(fdbIndex, cdb.FdbFieldRemoteIP, []string{remoteIP})
 			}
 		case tai.FdbAttrTunnelName:
 			if TunnelName, ok := attrValue.(string); ok {
-				cdb.FdbSetField(fdbIndex, cdb.FdbFieldTunnelName, []string{TunnelName})
+				v.db().FdbSetField(fdbIndex, cdb.FdbFieldTunnelName, []string{TunnelName})
 			}
 		}
 	}
@@ -84,18 +81,31 @@ func (v fdbAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v fdbAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v fdbAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v fdbAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v fdbAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v fdbAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v fdbAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject fdbs of bridges created by driver
 func (v fdbAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	bridges := make(map[string]bool)
+	for _, tableBridge := range v.driverBridges() {
+		bridges[tableBridge.Name] = true
+	}
+
+	var objs []interface{}
+	v.db().FdbIterator(func(table cdb.TableFdb) {
+		if !bridges[table.ForwardDomain] {
+			return
+		}
+		objs = append(objs, tai.FdbObj{Bridge: table.ForwardDomain, Mac: table.Address})
+	})
+	return objs, nil
 }
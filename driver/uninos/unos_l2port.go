@@ -11,10 +11,7 @@ import (
 
 type l2portAPI struct {
 	moduleID int
-}
-
-var l2portAPIs = l2portAPI{
-	moduleID: tai.ObjectIDL2Port,
+	*unosDriver
 }
 
 func (v l2portAPI) CreateObject(obj interface{}) error {
@@ -34,12 +31,12 @@ func (v l2portAPI) CreateObject(obj interface{}) error {
 		Name:   objL2port.Name,
 		Bdname: objL2port.BridgeName,
 	}
-	if _, err := cdb.BridgePortGetByIndex(bridgePortIndex); err == nil {
+	if _, err := v.db().BridgePortGetByIndex(bridgePortIndex); err == nil {
 		log.Info("[Driver] Bridge Port %s already exist", objL2port.Name)
 		return nil
 	}
 
-	_, err := cdb.BridgePortAdd(bridgePortCfg)
+	_, err := v.db().BridgePortAdd(bridgePortCfg)
 	if err != nil {
 		return err
 	}
@@ -51,7 +48,7 @@ func (v l2portAPI) CreateObject(obj interface{}) error {
 		Mtu:         []int{cdb.InterfaceDefaultMtu},
 	}
 
-	ifUUID, err := cdb.InterfaceAdd(interfaceCfg)
+	ifUUID, err := v.db().InterfaceAdd(interfaceCfg)
 	if err != nil {
 		return err
 	}
@@ -59,7 +56,7 @@ func (v l2portAPI) CreateObject(obj interface{}) error {
 	bridgeIndex := cdb.BridgeIndex{
 		Name: objL2port.BridgeName,
 	}
-	err = cdb.BridgeSetField(bridgeIndex, cdb.BridgeFieldBridgePorts, libovsdb.UUID{GoUUID: ifUUID})
+	err = v.db().BridgeSetField(bridgeIndex, cdb.BridgeFieldBridgePorts, libovsdb.UUID{GoUUID: ifUUID})
 	if err != nil {
 		return err
 	}
@@ -74,7 +71,7 @@ func (v l2portAPI) RemoveObject(obj interface{}) error {
 		Name:   objL2port.Name,
 		Bdname: objL2port.BridgeName,
 	}
-	err := cdb.BridgePortDelByIndex(bridgePortIndex)
+	err := v.db().BridgePortDelByIndex(bridgePortIndex)
 	if err != nil {
 		return err
 	}
@@ -83,40 +80,78 @@ func (v l2portAPI) RemoveObject(obj interface{}) error {
 		Name: objL2port.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	err = cdb.InterfaceDelByIndex(interfaceIndex)
+	err = v.db().InterfaceDelByIndex(interfaceIndex)
 	if err != nil {
 		return err
 	}
 
+	// QinQ sub port is owned by l2port
+	tableSubPort, err := v.db().SubPortGetByIndex(cdb.SubPortIndex{Name: objL2port.Name})
+	if err == nil && len(tableSubPort.Vlan) == 2 {
+		return v.subPortDel(objL2port.PhysicalParentPort, objL2port.Name)
+	}
+
 	return nil
 }
 
-func (v l2portAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v l2portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objL2port := obj.(tai.L2portObj)
 	bridgePortIndex := cdb.BridgePortIndex{
 		Name:   objL2port.Name,
 		Bdname: objL2port.BridgeName,
 	}
 
-	if attrs[tai.L2portAttrVlanTag] != nil {
-		cdb.BridgePortSetField(bridgePortIndex, cdb.BridgePortFieldTagMode, "tag")
+	if attrs.Has(tai.L2portAttrVlanTag) {
+		// stacked vlans are matched by QinQ sub port of physical parent port
+		tags := attrs.GetInts(tai.L2portAttrVlanTag)
+		if len(tags) == 2 && objL2port.PhysicalParentPort != "" {
+			if err := v.subPortAdd(objL2port.PhysicalParentPort, objL2port.Name, tags); err != nil {
+				log.Warning("[Driver] L2port %s QinQ sub port %v add failed %v\n", objL2port.Name, tags, err)
+				return err
+			}
+		}
+		v.db().BridgePortSetField(bridgePortIndex, cdb.BridgePortFieldTagMode, "tag")
+	}
+	// vlan transparent physical port keeps tags of frames in bridge, native
+	// vlan port is untagged by default tag mode
+	if attrs.GetBool(tai.L2portAttrVlanTransparent) {
+		v.db().BridgePortSetField(bridgePortIndex, cdb.BridgePortFieldTagMode, "tag")
 	}
 
 	return nil
 }
 
-func (v l2portAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v l2portAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v l2portAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v l2portAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v l2portAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v l2portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject bridge ports of bridges created by driver, physical parent
+// port is known for QinQ sub port only
 func (v l2portAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	bridges := make(map[string]bool)
+	for _, tableBridge := range v.driverBridges() {
+		bridges[tableBridge.Name] = true
+	}
+	parents := v.subPortParents()
+
+	var objs []interface{}
+	v.db().BridgePortIterator(func(table cdb.TableBridgePort) {
+		if !bridges[table.Bdname] {
+			return
+		}
+		objs = append(objs, tai.L2portObj{
+			Name:               table.Name,
+			BridgeName:         table.Bdname,
+			PhysicalParentPort: parents[table.Name],
+		})
+	})
+	return objs, nil
 }
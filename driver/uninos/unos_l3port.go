@@ -11,10 +11,72 @@ import (
 
 type l3portAPI struct {
 	moduleID int
+	*unosDriver
 }
 
-var l3portAPIs = l3portAPI{
-	moduleID: tai.ObjectIDL3Port,
+// l3portSubPortAdd AC l3port on sub port of physical parent port, tagged
+// by single vlan or QinQ outer and inner tag
+func (d *unosDriver) l3portSubPortAdd(objL3port tai.L3portObj, attrs tai.Attrs) error {
+	ifIndex := cdb.InterfaceIndex{
+		Name: objL3port.Name,
+		Type: cdb.InterfaceTypeSubPort,
+	}
+
+	var vrf []libovsdb.UUID
+	if vrfName := attrs.GetString(tai.L3portAttrVrfBinding); vrfName != "" {
+		tableVrf, err := d.db().VrfGetByIndex(cdb.VrfIndex{Name: vrfName})
+		if err != nil {
+			log.Warning("[Driver] Interface %s binding vrf %s not exist\n", ifIndex.Name, vrfName)
+			return nil
+		}
+		vrf = []libovsdb.UUID{{GoUUID: tableVrf.UUID}}
+	}
+	ips := attrs.GetStrings(tai.L3portAttrIpaddr)
+
+	if _, err := d.db().InterfaceGetByIndex(ifIndex); err == nil {
+		if len(vrf) > 0 {
+			if err = d.db().InterfaceUpdateVrfAddvalue(ifIndex, vrf); err != nil {
+				return err
+			}
+		}
+		if len(ips) > 0 {
+			return d.db().InterfaceUpdateIPAddvalue(ifIndex, ips)
+		}
+		return nil
+	}
+
+	tags := attrs.GetInts(tai.L3portAttrVlanTag)
+	if len(tags) == 0 {
+		log.Warning("[Driver] Interface %s untagged on %s not supported\n", ifIndex.Name, objL3port.PhysicalParentPort)
+		return nil
+	}
+	if err := d.subPortAdd(objL3port.PhysicalParentPort, objL3port.Name, tags); err != nil {
+		log.Error("[Driver] Create subport %v vlan %v failed.\n", ifIndex.Name, tags)
+		return err
+	}
+	_, err := d.db().InterfaceAdd(cdb.TableInterface{
+		Name:        ifIndex.Name,
+		Type:        ifIndex.Type,
+		AdminStatus: []string{cdb.InterfaceDefaultAdminStatus},
+		IP:          ips,
+		Vrf:         vrf,
+		SwitchPort:  []string{cdb.InterfaceSwitchPortDisable},
+	})
+	return err
+}
+
+// l3portSubPortDel sub interface of AC l3port is deleted with its sub port
+func (d *unosDriver) l3portSubPortDel(objL3port tai.L3portObj) error {
+	ifIndex := cdb.InterfaceIndex{
+		Name: objL3port.Name,
+		Type: cdb.InterfaceTypeSubPort,
+	}
+	if _, err := d.db().InterfaceGetByIndex(ifIndex); err == nil {
+		if err = d.db().InterfaceDelByIndex(ifIndex); err != nil {
+			return err
+		}
+	}
+	return d.subPortDel(objL3port.PhysicalParentPort, objL3port.Name)
 }
 
 func (v l3portAPI) CreateObject(obj interface{}) error {
@@ -22,78 +84,115 @@ func (v l3portAPI) CreateObject(obj interface{}) error {
 	return nil
 }
 
-func (v l3portAPI) RemoveObject(interface{}) error {
+func (v l3portAPI) RemoveObject(obj interface{}) error {
+	objL3port := obj.(tai.L3portObj)
+
+	if objL3port.PhysicalParentPort != "" {
+		return v.l3portSubPortDel(objL3port)
+	}
 	return nil
 }
 
-func (v l3portAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v l3portAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objL3port := obj.(tai.L3portObj)
 
+	if objL3port.PhysicalParentPort != "" {
+		return v.l3portSubPortAdd(objL3port, attrs)
+	}
+
 	ifIndex := cdb.InterfaceIndex{
 		Name: objL3port.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	_, err := cdb.InterfaceGetByIndex(ifIndex)
+	_, err := v.db().InterfaceGetByIndex(ifIndex)
 	if err != nil {
 		log.Warning("[Driver] interface %s type %s not exist\n", ifIndex.Name, ifIndex.Type)
 		return nil
 	}
 
-	if attrs[tai.L3portAttrVrfBinding] != nil {
-		vrfName := attrs[tai.L3portAttrVrfBinding].(string)
+	if attrs.Has(tai.L3portAttrVrfBinding) {
+		vrfName := attrs.GetString(tai.L3portAttrVrfBinding)
 		vrfIndex := cdb.VrfIndex{
 			Name: vrfName,
 		}
-		tableVrf, err := cdb.VrfGetByIndex(vrfIndex)
+		tableVrf, err := v.db().VrfGetByIndex(vrfIndex)
 		if err != nil {
 			log.Warning("[Driver] Interface %s binding vrf %s not exist\n", ifIndex.Name, vrfName)
 			return nil
 		}
 
-		cdb.InterfaceUpdateVrfAddvalue(ifIndex, []libovsdb.UUID{{GoUUID: tableVrf.UUID}})
+		v.db().InterfaceUpdateVrfAddvalue(ifIndex, []libovsdb.UUID{{GoUUID: tableVrf.UUID}})
 	}
 
 	return nil
 }
 
-func (v l3portAPI) DelObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v l3portAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objL3port := obj.(tai.L3portObj)
 
 	ifIndex := cdb.InterfaceIndex{
 		Name: objL3port.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	_, err := cdb.InterfaceGetByIndex(ifIndex)
+	if objL3port.PhysicalParentPort != "" {
+		ifIndex.Type = cdb.InterfaceTypeSubPort
+	}
+	_, err := v.db().InterfaceGetByIndex(ifIndex)
 	if err != nil {
 		log.Warning("[Driver] interface %s type %s not exist\n", ifIndex.Name, ifIndex.Type)
 		return nil
 	}
 
-	if attrs[tai.L3portAttrVrfBinding] != nil {
-		vrfName := attrs[tai.L3portAttrVrfBinding].(string)
+	if ips := attrs.GetStrings(tai.L3portAttrIpaddr); len(ips) > 0 && ifIndex.Type == cdb.InterfaceTypeSubPort {
+		v.db().InterfaceUpdateIPDelvalue(ifIndex, ips)
+	}
+
+	if attrs.Has(tai.L3portAttrVrfBinding) {
+		vrfName := attrs.GetString(tai.L3portAttrVrfBinding)
 		vrfIndex := cdb.VrfIndex{
 			Name: vrfName,
 		}
-		tableVrf, err := cdb.VrfGetByIndex(vrfIndex)
+		tableVrf, err := v.db().VrfGetByIndex(vrfIndex)
 		if err != nil {
 			log.Warning("[Driver] Interface %s binding vrf %s not exist\n", ifIndex.Name, vrfName)
 			return nil
 		}
 
-		cdb.InterfaceUpdateVrfDelvalue(ifIndex, []libovsdb.UUID{{GoUUID: tableVrf.UUID}})
+		v.db().InterfaceUpdateVrfDelvalue(ifIndex, []libovsdb.UUID{{GoUUID: tableVrf.UUID}})
 	}
 
 	return nil
 }
 
-func (v l3portAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v l3portAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v l3portAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v l3portAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject sub interfaces of AC l3ports bound to vrfs created by driver,
+// l3ports of bridge domain are owned by bridge of vrf
 func (v l3portAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	vrfs := make(map[string]bool)
+	for _, tableVrf := range v.driverVrfs() {
+		vrfs[tableVrf.UUID] = true
+	}
+	parents := v.subPortParents()
+
+	var objs []interface{}
+	v.db().InterfaceIterator(func(table cdb.TableInterface) {
+		if table.Type != cdb.InterfaceTypeSubPort || len(table.Vrf) != 1 || !vrfs[table.Vrf[0].GoUUID] {
+			return
+		}
+		if parents[table.Name] == "" {
+			return
+		}
+		objs = append(objs, tai.L3portObj{
+			Name:               table.Name,
+			PhysicalParentPort: parents[table.Name],
+		})
+	})
+	return objs, nil
 }
@@ -9,10 +9,7 @@ import (
 
 type neighbourAPI struct {
 	moduleID int
-}
-
-var neighbourAPIs = neighbourAPI{
-	moduleID: tai.ObjectIDNeighbour,
+	*unosDriver
 }
 
 func (v neighbourAPI) CreateObject(obj interface{}) error {
@@ -25,13 +22,13 @@ func (v neighbourAPI) CreateObject(obj interface{}) error {
 		IP: objNeighbour.Ipaddr,
 	}
 
-	_, err := cdb.NeighborGetByIndex(neighbourIndex)
+	_, err := v.db().NeighborGetByIndex(neighbourIndex)
 	if err == nil {
 		log.Info("[Driver] Neighbour for %s already exist\n", neighbourIndex.IP)
 		return nil
 	}
 
-	_, err = cdb.NeighborAdd(neighbourCfg)
+	_, err = v.db().NeighborAdd(neighbourCfg)
 	if err != nil {
 		return err
 	}
@@ -45,20 +42,20 @@ func (v neighbourAPI) RemoveObject(obj interface{}) error {
 		IP: objNeighbour.Ipaddr,
 	}
 
-	err := cdb.NeighborDelByIndex(neighbourIndex)
+	err := v.db().NeighborDelByIndex(neighbourIndex)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (v neighbourAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v neighbourAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objNeighbour := obj.(tai.NeighbourObj)
 
 	neighbourIndex := cdb.NeighborIndex{
 		IP: objNeighbour.Ipaddr,
 	}
-	_, err := cdb.NeighborGetByIndex(neighbourIndex)
+	_, err := v.db().NeighborGetByIndex(neighbourIndex)
 	if err != nil {
 		log.Warning("[Driver] Neighbour for %s not exist\n", neighbourIndex.IP)
 		return nil
@@ -67,28 +64,28 @@ func (v neighbourAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]inter
 	for attr, value := range attrs {
 		switch attr {
 		case tai.NeighbourAttrMacaddr:
-			if cdb.NeighborSetField(neighbourIndex, cdb.NeighborFieldMac, value.(string)) != nil {
+			if v.db().NeighborSetField(neighbourIndex, cdb.NeighborFieldMac, value.(string)) != nil {
 				log.Warning("[Driver] Neighbour update mac %s for %s failed\n", value.(string), neighbourIndex.IP)
 			}
 		case tai.NeighbourAttrOutPort:
-			if cdb.NeighborSetField(neighbourIndex, cdb.NeighborFieldOutport, value.(string)) != nil {
+			if v.db().NeighborSetField(neighbourIndex, cdb.NeighborFieldOutport, value.(string)) != nil {
 				log.Warning("[Driver] Neighbour update outport %s for %s failed\n", value.(string), neighbourIndex.IP)
 			}
 
 			bdIndex := cdb.BridgeIndex{
 				Name: value.(string),
 			}
-			_, err := cdb.BridgeGetByIndex(bdIndex)
+			_, err := v.db().BridgeGetByIndex(bdIndex)
 			if err != nil {
 				log.Warning("[Driver] BD %s not exist for NeighbourAttrOutPort add\n", bdIndex.Name)
 				continue
 			}
 
-			cdb.NeighborSetField(neighbourIndex, cdb.NeighborFieldBridge, bdIndex.Name)
-			cdb.NeighborSetField(neighbourIndex, cdb.NeighborFieldVxlanid, bdIndex.Name[2:])
+			v.db().NeighborSetField(neighbourIndex, cdb.NeighborFieldBridge, bdIndex.Name)
+			v.db().NeighborSetField(neighbourIndex, cdb.NeighborFieldVxlanid, bdIndex.Name[2:])
 
 		case tai.NeighbourAttrRemoteIP:
-			if cdb.NeighborSetField(neighbourIndex, cdb.NeighborFieldRemoteIP, value.(string)) != nil {
+			if v.db().NeighborSetField(neighbourIndex, cdb.NeighborFieldRemoteIP, value.(string)) != nil {
 				log.Warning("[Driver] Neighbour update remote ip %s for %s failed\n", value.(string), neighbourIndex.IP)
 			}
 		}
@@ -97,18 +94,31 @@ func (v neighbourAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]inter
 	return nil
 }
 
-func (v neighbourAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v neighbourAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v neighbourAPI) SetObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v neighbourAPI) SetObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v neighbourAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v neighbourAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject neighbours of bridges created by driver
 func (v neighbourAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	bridges := make(map[string]bool)
+	for _, tableBridge := range v.driverBridges() {
+		bridges[tableBridge.Name] = true
+	}
+
+	var objs []interface{}
+	v.db().NeighborIterator(func(table cdb.TableNeighbor) {
+		if len(table.Bridge) != 1 || !bridges[table.Bridge[0]] {
+			return
+		}
+		objs = append(objs, tai.NeighbourObj{Ipaddr: table.IP})
+	})
+	return objs, nil
 }
@@ -16,16 +16,11 @@ import (
 
 type pbrAPI struct {
 	moduleID int
-}
-
-var pbrAPIs = pbrAPI{
-	moduleID: tai.ObjectIDPBR,
+	*unosDriver
 }
 
 const pbrACLRuleDefSeq = 1
 
-var ecmpGroupIDPool = make(map[int]int)
-
 func getVniFromVrf(vrf string) int {
 	vni, _ := strconv.Atoi(vrf[3:])
 	return vni
@@ -44,14 +39,14 @@ const (
 	eipOpDel
 )
 
-func pbrAclCreate(vrf string) error {
+func (d *unosDriver) pbrAclCreate(vrf string) error {
 	var err error
 
 	aclIndex := cdb.ACLIndex{
 		ACLName: "PBR_" + vrf,
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := d.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		// 1. create pbr ACL
 		tableACL = cdb.TableACL{
@@ -70,14 +65,14 @@ func pbrAclCreate(vrf string) error {
 			portIndex := cdb.PortIndex{
 				Name: tableAGC.PhysicalPort,
 			}
-			tablePort, err := cdb.PortGetByIndex(portIndex)
+			tablePort, err := d.db().PortGetByIndex(portIndex)
 			if err != nil {
 				log.Warning("ACL add for PBR %s binding ingress port %s not exist\n", vrf, portIndex.Name)
 			}
 			tableACL.Ports = []libovsdb.UUID{{GoUUID: tablePort.UUID}}
 		}
 
-		_, err = cdb.ACLAdd(tableACL)
+		_, err = d.db().ACLAdd(tableACL)
 		if err != nil {
 			log.Error("ACL add for PBR %s failed %v\n", vrf, err)
 			return nil
@@ -87,15 +82,15 @@ func pbrAclCreate(vrf string) error {
 	return err
 }
 
-func pbrAclRemove(vrf string) error {
+func (d *unosDriver) pbrAclRemove(vrf string) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: "PBR_" + vrf,
 	}
 
-	return cdb.ACLDelByIndex(aclIndex)
+	return d.db().ACLDelByIndex(aclIndex)
 }
 
-func pbrUpdateGateway(vrf string, port string, vlan int) error {
+func (d *unosDriver) pbrUpdateGateway(vrf string, port string, vlan int) error {
 	aclIndex := cdb.ACLIndex{
 		ACLName: "PBR_" + vrf,
 	}
@@ -103,27 +98,24 @@ func pbrUpdateGateway(vrf string, port string, vlan int) error {
 	portIndex := cdb.PortIndex{
 		Name: port,
 	}
-	tablePort, err := cdb.PortGetByIndex(portIndex)
+	tablePort, err := d.db().PortGetByIndex(portIndex)
 	if err != nil {
 		log.Warning("ACL update for PBR %s binding ingress port %s not exist\n", vrf, portIndex.Name)
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := d.db().ACLGetByIndex(aclIndex)
 	if err == nil {
 		if len(tableACL.Ports) == 1 {
 			if tableACL.Ports[0].GoUUID == tablePort.UUID {
 				log.Info("ACL for PBR vrf %s ingress port %s not changed\n", vrf, port)
 			} else {
-				err = cdb.ACLSetField(aclIndex, cdb.ACLFieldPorts, []libovsdb.UUID{{GoUUID: tablePort.UUID}})
+				err = d.db().ACLSetField(aclIndex, cdb.ACLFieldPorts, []libovsdb.UUID{{GoUUID: tablePort.UUID}})
 			}
 		}
 	}
 
-	subIfIndex := cdb.InterfaceIndex{
-		Name: port + "." + strconv.Itoa(vlan),
-		Type: cdb.InterfaceTypeSubPort,
-	}
-	tableSubIF, err := cdb.InterfaceGetByIndex(subIfIndex)
+	subIfIndex := d.gatewayInterfaceIndex(port, vlan)
+	tableSubIF, err := d.db().InterfaceGetByIndex(subIfIndex)
 	if err == nil {
 		var conditions []interface{}
 		conditions = append(conditions, libovsdb.
@@ -150,7 +142,7 @@ func pbrUpdateGateway(vrf string, port string, vlan int) error {
 				}
 
 				if false == eipConfigured {
-					err = cdb.InterfaceUpdateIPAddvalue(subIfIndex, []string{extIP + "/32"})
+					err = d.db().InterfaceUpdateIPAddvalue(subIfIndex, []string{extIP + "/32"})
 				}
 			}
 		}
@@ -159,7 +151,7 @@ func pbrUpdateGateway(vrf string, port string, vlan int) error {
 	return nil
 }
 
-func externalIPProcess(eip string, vrf string, op int) error {
+func (d *unosDriver) externalIPProcess(eip string, vrf string, op int) error {
 	var err error
 
 	acgIndex := vtepdb.AutoGatewayConfIndex{
@@ -171,26 +163,23 @@ func externalIPProcess(eip string, vrf string, op int) error {
 	}
 
 	if false == strings.Contains(tableACG.IP, eip) {
-		subPortIndex := cdb.InterfaceIndex{
-			Name: tableACG.PhysicalPort + "." + strconv.Itoa(tableACG.Vlan),
-			Type: cdb.InterfaceTypeSubPort,
-		}
-		_, err := cdb.InterfaceGetByIndex(subPortIndex)
+		subPortIndex := d.gatewayInterfaceIndex(tableACG.PhysicalPort, tableACG.Vlan)
+		_, err := d.db().InterfaceGetByIndex(subPortIndex)
 		if err != nil {
 			return fmt.Errorf("Sub interface %s not found", subPortIndex.Name)
 		}
 
 		if eipOpAdd == op {
-			err = cdb.InterfaceUpdateIPAddvalue(subPortIndex, []string{eip + "/32"})
+			err = d.db().InterfaceUpdateIPAddvalue(subPortIndex, []string{eip + "/32"})
 		} else if eipOpDel == op {
-			err = cdb.InterfaceUpdateIPDelvalue(subPortIndex, []string{eip + "/32"})
+			err = d.db().InterfaceUpdateIPDelvalue(subPortIndex, []string{eip + "/32"})
 		}
 	}
 
 	return err
 }
 
-func getSequenceFromPBR(pbr tai.PBRObj, op int) (int, error) {
+func (d *unosDriver) getSequenceFromPBR(pbr tai.PBRObj, op int) (int, error) {
 	sequence := 0
 
 	var tableExtIP vtepdb.TableExternalIP
@@ -218,7 +207,7 @@ func getSequenceFromPBR(pbr tai.PBRObj, op int) (int, error) {
 
 			vtepdb.ExternalIPSetField(extIPIndex, vtepdb.ExternalIPFieldRefCount, 1)
 
-			err = externalIPProcess(pbr.IP, tableExtIP.Vrf, eipOpAdd)
+			err = d.externalIPProcess(pbr.IP, tableExtIP.Vrf, eipOpAdd)
 			if err != nil {
 				log.Warning("Add eip %s for vrf %s failed", tableExtIP.IP, tableExtIP.Vrf)
 			}
@@ -258,7 +247,7 @@ func getSequenceFromPBR(pbr tai.PBRObj, op int) (int, error) {
 				}
 
 				if false == eipExist {
-					err = externalIPProcess(pbr.IP, tableExtIP.Vrf, eipOpDel)
+					err = d.externalIPProcess(pbr.IP, tableExtIP.Vrf, eipOpDel)
 					if err != nil {
 						log.Warning("Del eip %s for vrf %s failed", tableExtIP.IP, tableExtIP.Vrf)
 					}
@@ -333,19 +322,34 @@ func getIPProtocol(proto string) int {
 	return ipProtocol
 }
 
-func getEcmpGroupID() (int, error) {
+func getIPProtocolName(ipProtocol int) string {
+	var proto string
+
+	switch ipProtocol {
+	case 6:
+		proto = vtepdb.PolicyBasedRouteProtocolTCP
+	case 17:
+		proto = vtepdb.PolicyBasedRouteProtocolUDP
+	case 132:
+		proto = vtepdb.PolicyBasedRouteProtocolSctp
+	}
+
+	return proto
+}
+
+func (d *unosDriver) getEcmpGroupID() (int, error) {
 	ecmpGroupID := 0
 	var ecmpGroupIndex cdb.EcmpGroupIndex
 
 	for i := 1; i <= cdb.EcmpGroupIDMax; i++ {
-		if idInUse, ok := ecmpGroupIDPool[i]; ok {
+		if idInUse, ok := d.ecmpGroupIDPool[i]; ok {
 			if 0 == idInUse {
 				ecmpGroupID = i
 				ecmpGroupIndex.ID = ecmpGroupID
-				_, err := cdb.EcmpGroupGetByIndex(ecmpGroupIndex)
+				_, err := d.db().EcmpGroupGetByIndex(ecmpGroupIndex)
 				if err != nil {
 					// get a valid unused ecmpGroupID
-					ecmpGroupIDPool[i] = 1
+					d.ecmpGroupIDPool[i] = 1
 					break
 				}
 				// the ecmpGroup already in use, get next usable ID
@@ -355,9 +359,9 @@ func getEcmpGroupID() (int, error) {
 		} else {
 			ecmpGroupID = i
 			ecmpGroupIndex.ID = ecmpGroupID
-			_, err := cdb.EcmpGroupGetByIndex(ecmpGroupIndex)
+			_, err := d.db().EcmpGroupGetByIndex(ecmpGroupIndex)
 			if err != nil {
-				ecmpGroupIDPool[i] = 1
+				d.ecmpGroupIDPool[i] = 1
 				break
 			}
 			continue
@@ -371,9 +375,13 @@ func getEcmpGroupID() (int, error) {
 	return ecmpGroupID, nil
 }
 
-func releaseEcmpGroupID(ID int) {
-	if _, ok := ecmpGroupIDPool[ID]; ok {
-		ecmpGroupIDPool[ID] = 0
+func (d *unosDriver) releaseEcmpGroupID(ID int) {
+	if _, ok := d.ecmpGroupIDPool[ID]; ok {
+		d.ecmpGroupIDPool[ID] = 0
+	}
+	if d.ecmpGroupFull {
+		d.ecmpGroupFull = false
+		tai.Notify(tai.TableFullNotification{ObjID: tai.ObjectIDPBR, Cleared: true})
 	}
 }
 
@@ -386,7 +394,7 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 		ACLName: pbrACLName,
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		// 1. create pbr ACL
 		tableACL = cdb.TableACL{
@@ -404,7 +412,7 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 			portIndex := cdb.PortIndex{
 				Name: tableAGC.PhysicalPort,
 			}
-			tablePort, err := cdb.PortGetByIndex(portIndex)
+			tablePort, err := v.db().PortGetByIndex(portIndex)
 			if err != nil {
 				log.Warning("ACL add for PBR %+v get ingress port %s failed\n", objPBR, portIndex.Name)
 			} else {
@@ -412,14 +420,14 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 			}
 		}
 
-		_, err = cdb.ACLAdd(tableACL)
+		_, err = v.db().ACLAdd(tableACL)
 		if err != nil {
 			log.Error("ACL add for PBR %+v failed %v\n", objPBR, err)
 			return nil
 		}
 	}
 
-	sequenceID, err := getSequenceFromPBR(objPBR, eipOpAdd)
+	sequenceID, err := v.getSequenceFromPBR(objPBR, eipOpAdd)
 	if err != nil {
 		log.Warning("ACL rule get sequence for PBR %+v failed\n", objPBR)
 		return nil
@@ -429,26 +437,29 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 		ACLName:  pbrACLName,
 		Sequence: sequenceID,
 	}
-	_, err = cdb.ACLRuleGetByIndex(aclRuleIndex)
+	_, err = v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err == nil {
 		log.Warning("ACL rule for PBR %+v sequenceID %d already exist\n", objPBR, sequenceID)
 		return nil
 	}
 
 	// 2. create a ecmp group for acl redirect
-	ecmpGroupID, err := getEcmpGroupID()
+	ecmpGroupID, err := v.getEcmpGroupID()
 	if err != nil {
 		log.Error("%v\n", err)
-
+		if !v.ecmpGroupFull {
+			v.ecmpGroupFull = true
+			tai.Notify(tai.TableFullNotification{ObjID: tai.ObjectIDPBR})
+		}
 		return nil
 	}
 	tableEcmpGroup := cdb.TableEcmpGroup{
 		ID: ecmpGroupID,
 	}
-	ecmpGroupUUID, err := cdb.EcmpGroupAdd(tableEcmpGroup)
+	ecmpGroupUUID, err := v.db().EcmpGroupAdd(tableEcmpGroup)
 	if err != nil {
 		log.Error("ECMP Group %d add failed\n", ecmpGroupID)
-		releaseEcmpGroupID(ecmpGroupID)
+		v.releaseEcmpGroupID(ecmpGroupID)
 		return nil
 	}
 
@@ -466,14 +477,14 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 	}
 	tableACLRule.RedirectEcmpgroup = []libovsdb.UUID{{GoUUID: ecmpGroupUUID}}
 
-	err = cdb.ACLUpdateAddRuleName(aclIndex, tableACLRule)
+	err = v.db().ACLUpdateAddRuleName(aclIndex, tableACLRule)
 	if err != nil {
 		log.Error("ACL rule add for PBR %+v failed %v\n", objPBR, err)
-		//cdb.ACLDelByIndex(aclIndex)
+		//v.db().ACLDelByIndex(aclIndex)
 
-		err = cdb.EcmpGroupDelByUUID(ecmpGroupUUID)
+		err = v.db().EcmpGroupDelByUUID(ecmpGroupUUID)
 		if err == nil {
-			releaseEcmpGroupID(ecmpGroupID)
+			v.releaseEcmpGroupID(ecmpGroupID)
 		}
 
 		return nil
@@ -484,6 +495,10 @@ func (v pbrAPI) CreateObject(obj interface{}) error {
 
 func (v pbrAPI) RemoveObject(obj interface{}) error {
 	objPBR := obj.(tai.PBRObj)
+	// PBR listed in dnat range without type
+	if objPBR.Type == "" {
+		objPBR.Type = vtepdb.PolicyBasedRouteTypeDnat
+	}
 
 	var err error
 	var tableEcmpGroup cdb.TableEcmpGroup
@@ -493,13 +508,13 @@ func (v pbrAPI) RemoveObject(obj interface{}) error {
 		ACLName: pbrACLName,
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		log.Warning("ACL for PBR %+v not found\n", objPBR)
 		return nil
 	}
 
-	sequenceID, err := getSequenceFromPBR(objPBR, eipOpDel)
+	sequenceID, err := v.getSequenceFromPBR(objPBR, eipOpDel)
 	if err != nil {
 		log.Warning("ACL rule get sequence for PBR %+v failed\n", objPBR)
 		return nil
@@ -509,7 +524,7 @@ func (v pbrAPI) RemoveObject(obj interface{}) error {
 		ACLName:  tableACL.ACLName,
 		Sequence: sequenceID,
 	}
-	tableACLRule, err := cdb.ACLRuleGetByIndex(aclRuleIndex)
+	tableACLRule, err := v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err != nil {
 		log.Warning("ACL rule for PBR %+v not found\n", objPBR)
 		goto out
@@ -519,19 +534,19 @@ func (v pbrAPI) RemoveObject(obj interface{}) error {
 		log.Warning("ACL Rule %s for PBR should have one redirect ecmp group action\n", pbrACLName)
 		return nil
 	}
-	tableEcmpGroup, err = cdb.EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
+	tableEcmpGroup, err = v.db().EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
 	if err != nil {
 		log.Warning("ECMP Group %s not found\n", tableACLRule.RedirectEcmpgroup[0].GoUUID)
 		return nil
 	}
 
 	// remove configDB tables
-	err = cdb.EcmpGroupDelByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
+	err = v.db().EcmpGroupDelByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
 	if err == nil {
-		releaseEcmpGroupID(tableEcmpGroup.ID)
+		v.releaseEcmpGroupID(tableEcmpGroup.ID)
 	}
 
-	err = cdb.ACLUpdateRuleNameDelvalue(aclIndex, []libovsdb.UUID{{GoUUID: tableACLRule.UUID}})
+	err = v.db().ACLUpdateRuleNameDelvalue(aclIndex, []libovsdb.UUID{{GoUUID: tableACLRule.UUID}})
 	if err != nil {
 		log.Warning("ACL Rule for PBR %+v remove failed %v\n", objPBR, err)
 	}
@@ -540,7 +555,7 @@ out:
 	// acl rule no exist any more, remove it
 	// remove acl when vrf delete, 20210421
 	/*if len(tableACL.RuleName) <= 1 {
-		err = cdb.ACLDelByIndex(aclIndex)
+		err = v.db().ACLDelByIndex(aclIndex)
 		if err != nil {
 			log.Warning("ACL %v remove failed %v\n", tableACL.RuleName, err)
 		}
@@ -549,7 +564,7 @@ out:
 	return nil
 }
 
-func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v pbrAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	var err error
 	objPBR := obj.(tai.PBRObj)
 
@@ -558,13 +573,13 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ACLName: pbrACLName,
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		log.Warning("ACL for PBR %+v not found\n", objPBR)
 		return nil
 	}
 
-	sequenceID, err := getSequenceFromPBR(objPBR, 0)
+	sequenceID, err := v.getSequenceFromPBR(objPBR, 0)
 	if err != nil {
 		log.Warning("ACL rule get sequence for PBR %+v failed\n", objPBR)
 		return nil
@@ -574,7 +589,7 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ACLName:  tableACL.ACLName,
 		Sequence: sequenceID,
 	}
-	tableACLRule, err := cdb.ACLRuleGetByIndex(aclRuleIndex)
+	tableACLRule, err := v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err != nil {
 		log.Warning("ACL rule for PBR %+v not found\n", objPBR)
 		return nil
@@ -584,7 +599,7 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		log.Warning("ACL Rule %s for PBR should have one redirect ecmp group action\n", pbrACLName)
 		return nil
 	}
-	tableEcmpGroup, err := cdb.EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
+	tableEcmpGroup, err := v.db().EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
 	if err != nil {
 		log.Warning("ECMP Group %s not found\n", tableACLRule.RedirectEcmpgroup[0].GoUUID)
 		return nil
@@ -593,12 +608,8 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ID: tableEcmpGroup.ID,
 	}
 
-	if attrs[tai.PBRAttrNexthopGroup] != nil {
-		nhGroup, ok := attrs[tai.PBRAttrNexthopGroup].([]string)
-		if !ok {
-			log.Warning("Get nh group for PBR %+v failed\n", objPBR)
-			return nil
-		}
+	if attrs.Has(tai.PBRAttrNexthopGroup) {
+		nhGroup := attrs.GetStrings(tai.PBRAttrNexthopGroup)
 
 		for _, nh := range nhGroup {
 			nhIndex := cdb.NexthopIndex{
@@ -613,13 +624,13 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 				NexthopVrf: objPBR.Vrf,
 				Label:      getVniFromVrf(objPBR.Vrf),
 			}
-			cdbNh, err := cdb.NexthopGetByIndex(nhIndex)
+			cdbNh, err := v.db().NexthopGetByIndex(nhIndex)
 			if err != nil {
 				// add new nexthop
-				err = cdb.EcmpGroupUpdateAddNexthopGroup(ecmpGroupIndex, tableNh)
+				err = v.db().EcmpGroupUpdateAddNexthopGroup(ecmpGroupIndex, tableNh)
 			} else {
 				// add nexthop to ecmp group
-				err = cdb.EcmpGroupUpdateNexthopGroupAddvalue(ecmpGroupIndex,
+				err = v.db().EcmpGroupUpdateNexthopGroupAddvalue(ecmpGroupIndex,
 					[]libovsdb.UUID{{GoUUID: cdbNh.UUID}})
 			}
 
@@ -633,11 +644,11 @@ func (v pbrAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v pbrAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+func (v pbrAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v pbrAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	var err error
 	objPBR := obj.(tai.PBRObj)
 
@@ -646,13 +657,13 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ACLName: pbrACLName,
 	}
 
-	tableACL, err := cdb.ACLGetByIndex(aclIndex)
+	tableACL, err := v.db().ACLGetByIndex(aclIndex)
 	if err != nil {
 		log.Warning("ACL for PBR %+v not found\n", objPBR)
 		return nil
 	}
 
-	sequenceID, err := getSequenceFromPBR(objPBR, 0)
+	sequenceID, err := v.getSequenceFromPBR(objPBR, 0)
 	if err != nil {
 		log.Warning("ACL rule get sequence for PBR %+v failed\n", objPBR)
 		return nil
@@ -662,7 +673,7 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ACLName:  tableACL.ACLName,
 		Sequence: sequenceID,
 	}
-	tableACLRule, err := cdb.ACLRuleGetByIndex(aclRuleIndex)
+	tableACLRule, err := v.db().ACLRuleGetByIndex(aclRuleIndex)
 	if err != nil {
 		log.Warning("ACL rule for PBR %+v not found\n", objPBR)
 		return nil
@@ -672,7 +683,7 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		log.Warning("ACL Rule %s for PBR should have one redirect ecmp group action\n", pbrACLName)
 		return nil
 	}
-	tableEcmpGroup, err := cdb.EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
+	tableEcmpGroup, err := v.db().EcmpGroupGetByUUID(tableACLRule.RedirectEcmpgroup[0].GoUUID)
 	if err != nil {
 		log.Warning("ECMP Group %s not found\n", tableACLRule.RedirectEcmpgroup[0].GoUUID)
 		return nil
@@ -681,16 +692,12 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 		ID: tableEcmpGroup.ID,
 	}
 
-	if attrs[tai.PBRAttrNexthopGroup] != nil {
-		nhGroup, ok := attrs[tai.PBRAttrNexthopGroup].([]string)
-		if !ok {
-			log.Warning("Get nh group for PBR %+v failed\n", objPBR)
-			return nil
-		}
+	if attrs.Has(tai.PBRAttrNexthopGroup) {
+		nhGroup := attrs.GetStrings(tai.PBRAttrNexthopGroup)
 
 		// remove old nhs
 		if len(tableEcmpGroup.NexthopGroup) > 0 {
-			cdb.EcmpGroupUpdateNexthopGroupDelvalue(ecmpGroupIndex, tableEcmpGroup.NexthopGroup)
+			v.db().EcmpGroupUpdateNexthopGroupDelvalue(ecmpGroupIndex, tableEcmpGroup.NexthopGroup)
 		}
 		// set new nh group
 		for _, nh := range nhGroup {
@@ -706,13 +713,13 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 				NexthopVrf: objPBR.Vrf,
 				Label:      getVniFromVrf(objPBR.Vrf),
 			}
-			cdbNh, err := cdb.NexthopGetByIndex(nhIndex)
+			cdbNh, err := v.db().NexthopGetByIndex(nhIndex)
 			if err != nil {
 				// add new nexthop
-				err = cdb.EcmpGroupUpdateAddNexthopGroup(ecmpGroupIndex, tableNh)
+				err = v.db().EcmpGroupUpdateAddNexthopGroup(ecmpGroupIndex, tableNh)
 			} else {
 				// add nexthop to ecmp group
-				err = cdb.EcmpGroupUpdateNexthopGroupAddvalue(ecmpGroupIndex,
+				err = v.db().EcmpGroupUpdateNexthopGroupAddvalue(ecmpGroupIndex,
 					[]libovsdb.UUID{{GoUUID: cdbNh.UUID}})
 			}
 
@@ -726,10 +733,44 @@ func (v pbrAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v pbrAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v pbrAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject PBRs of rules in PBR ACL of vrfs created by driver, type of
+// PBR is told by sequence range, dnat and dnat_and_snat share their range
 func (v pbrAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	var objs []interface{}
+	for _, tableVrf := range v.driverVrfs() {
+		tableACL, err := v.db().ACLGetByIndex(cdb.ACLIndex{ACLName: "PBR_" + tableVrf.Name})
+		if err != nil {
+			continue
+		}
+		for _, rule := range tableACL.RuleName {
+			tableACLRule, err := v.db().ACLRuleGetByUUID(rule.GoUUID)
+			if err != nil || len(tableACLRule.DstIP) != 1 {
+				continue
+			}
+			objPBR := tai.PBRObj{
+				Vrf:      tableVrf.Name,
+				IP:       strings.TrimSuffix(tableACLRule.DstIP[0], "/32"),
+				Protocol: vtepdb.PolicyBasedRouteProtocolIgnore,
+			}
+			switch {
+			case tableACLRule.Sequence > pbrSequenceSNATOffset:
+				objPBR.Type = vtepdb.PolicyBasedRouteTypeSnat
+			case tableACLRule.Sequence > pbrSequenceDNATOffset:
+			default:
+				objPBR.Type = vtepdb.PolicyBasedRouteTypeLb
+			}
+			if len(tableACLRule.IPProtocol) == 1 {
+				objPBR.Protocol = getIPProtocolName(tableACLRule.IPProtocol[0])
+			}
+			if len(tableACLRule.L4DstPort) == 1 {
+				objPBR.Port = tableACLRule.L4DstPort[0]
+			}
+			objs = append(objs, objPBR)
+		}
+	}
+	return objs, nil
 }
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+	"github.com/cn-pmlabs/govtep/tai"
+
+	"github.com/ebay/libovsdb"
+)
+
+// portInventory report front panel ports and lags of config db to TAI,
+// whole inventory is reported again on every Port/Lag change
+type portInventory struct {
+	client *cdb.Client
+	// addr of config db, client is reconnected to it when connection lost
+	addr string
+	sw   string
+	// changed wakes reporter, monitor callback can't query db of its own
+	// connection
+	changed chan struct{}
+}
+
+// inventoryReconnect serialize reconnect of clients shared by switches
+// without own config db
+var inventoryReconnect sync.Mutex
+
+// portInventoryMonitor monitor Port and Lag names of config db client of
+// addr, monitor is established again after client reconnected
+func portInventoryMonitor(client *cdb.Client, addr string, sw string) {
+	inventory := &portInventory{client: client, addr: addr, sw: sw, changed: make(chan struct{}, 1)}
+	go inventory.reporter()
+	if client.Conn() == nil {
+		log.Warning("[Driver] config db not connected, port inventory of switch %q reported after connected\n", sw)
+		go inventory.reconnect(nil)
+		return
+	}
+	inventory.monitor()
+}
+
+// monitor register inventory on connection of client and monitor its
+// Port and Lag names, inventory is reported with current tables
+func (p *portInventory) monitor() {
+	conn := p.client.Conn()
+	conn.Register(p)
+	requests := map[string]libovsdb.MonitorRequest{
+		cdb.Port: {
+			Columns: []string{cdb.PortFieldName, cdb.PortFieldAlias},
+			Select:  libovsdb.MonitorSelect{Insert: true, Delete: true, Modify: true},
+		},
+		cdb.Lag: {
+			Columns: []string{cdb.LagFieldName},
+			Select:  libovsdb.MonitorSelect{Insert: true, Delete: true, Modify: true},
+		},
+	}
+	if _, err := conn.Monitor(cdb.UNOSCONFIG, "port-inventory", requests); err != nil {
+		log.Warning("[Driver] monitor port inventory of switch %q failed %v\n", p.sw, err)
+		return
+	}
+	p.notifyChanged()
+}
+
+// reconnect client lost connection conn and monitor again. Client shared
+// with other switches may be reconnected by their inventory already.
+func (p *portInventory) reconnect(conn *libovsdb.OvsdbClient) {
+	inventoryReconnect.Lock()
+	if current := p.client.Conn(); current == nil || current == conn {
+		p.client.SetConn(odbc.Reconnect(cdb.UNOSCONFIG, p.addr, nil))
+	}
+	inventoryReconnect.Unlock()
+	p.monitor()
+}
+
+func (p *portInventory) notifyChanged() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+		// report pending
+	}
+}
+
+func (p *portInventory) reporter() {
+	for range p.changed {
+		p.report()
+	}
+}
+
+func (p *portInventory) report() {
+	var ports []tai.InventoryPort
+	p.client.PortIterator(func(table cdb.TablePort) {
+		ports = append(ports, tai.InventoryPort{
+			Name:        table.Name,
+			Description: strings.Join(table.Alias, ","),
+		})
+	})
+	p.client.LagIterator(func(table cdb.TableLag) {
+		ports = append(ports, tai.InventoryPort{
+			Name: table.Name,
+			Lag:  true,
+		})
+	})
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+	tai.Notify(tai.PortInventoryNotification{Switch: p.sw, Ports: ports})
+}
+
+func (p *portInventory) Update(context interface{}, updates libovsdb.TableUpdates) {
+	_, port := updates.Updates[cdb.Port]
+	_, lag := updates.Updates[cdb.Lag]
+	if !port && !lag {
+		return
+	}
+	p.notifyChanged()
+}
+
+func (p *portInventory) Locked([]interface{}) {}
+
+func (p *portInventory) Stolen([]interface{}) {}
+
+func (p *portInventory) Echo([]interface{}) {}
+
+func (p *portInventory) Disconnected(conn *libovsdb.OvsdbClient) {
+	log.Warning("[Driver] config db of switch %q disconnected, try reconnect\n", p.sw)
+	go p.reconnect(conn)
+}
@@ -2,6 +2,8 @@ package driver
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
 
@@ -13,10 +15,7 @@ import (
 
 type routeAPI struct {
 	moduleID int
-}
-
-var routeAPIs = routeAPI{
-	moduleID: tai.ObjectIDRoute,
+	*unosDriver
 }
 
 func (v routeAPI) CreateObject(obj interface{}) error {
@@ -28,15 +27,12 @@ func (v routeAPI) CreateObject(obj interface{}) error {
 		Flag: []string{cdb.StaticRouteFlagVxlan},
 	}
 
-	nhMap := make(map[interface{}]interface{})
-	nhKey := "vrfname:" + objRoute.Vrf + ",ip:" + objRoute.Nexthop + ",port:" + "Bd" + objRoute.Vrf
-	nhMap[nhKey] = "label:,onlink:,color:"
-	routeCfg.Nexthop = nhMap
+	routeCfg.Nexthop = routeNexthop(objRoute.Vrf, objRoute.Nexthop, "")
 
 	vrfIndex := cdb.VrfIndex{
 		Name: objRoute.Vrf,
 	}
-	_, err := cdb.VrfGetByIndex(vrfIndex)
+	_, err := v.db().VrfGetByIndex(vrfIndex)
 	if err != nil {
 		log.Warning("[Driver] Static route %+v create failed because of invalid vrf", routeCfg)
 	}
@@ -45,12 +41,12 @@ func (v routeAPI) CreateObject(obj interface{}) error {
 		Vrf: objRoute.Vrf,
 		IP:  objRoute.IPPrefix,
 	}
-	if _, err := cdb.StaticRouteGetByIndex(routeIndex); err == nil {
+	if _, err := v.db().StaticRouteGetByIndex(routeIndex); err == nil {
 		log.Info("[Driver] Route %+v already exist", routeIndex)
 		return nil
 	}
 
-	err = cdb.VrfUpdateAddRoute(vrfIndex, routeCfg)
+	err = v.db().VrfUpdateAddRoute(vrfIndex, routeCfg)
 	if err != nil {
 		return err
 	}
@@ -61,24 +57,16 @@ func (v routeAPI) CreateObject(obj interface{}) error {
 func (v routeAPI) RemoveObject(obj interface{}) error {
 	objRoute := obj.(tai.RouteObj)
 
-	var conditions []interface{}
-	conditions = append(conditions, libovsdb.
-		NewCondition("vrf", "==", objRoute.Vrf))
-	conditions = append(conditions, libovsdb.
-		NewCondition("ip", "==", objRoute.IPPrefix))
-	rows, num := cdb.StaticRouteGet(conditions)
-
-	if num != 1 {
-		log.Warning("[Driver] Static route %+v not exist", objRoute)
-		return fmt.Errorf("[Driver] Static route %+v not exist", objRoute)
+	tableRoute, err := v.staticRouteGet(objRoute)
+	if err != nil {
+		return err
 	}
-	tableRoute := cdb.ConvertRowToStaticRoute(rows[0])
 
 	/*routeIndex := cdb.StaticRouteIndex{
 		Vrf: objRoute.Vrf,
 		IP:  objRoute.IPPrefix,
 	}
-	tableRoute, err := cdb.StaticRouteGetByIndex(routeIndex)
+	tableRoute, err := v.db().StaticRouteGetByIndex(routeIndex)
 	if err != nil {
 		log.Warning("[Driver] Static route %+v not exist", routeIndex)
 	}*/
@@ -86,13 +74,13 @@ func (v routeAPI) RemoveObject(obj interface{}) error {
 	vrfIndex := cdb.VrfIndex{
 		Name: objRoute.Vrf,
 	}
-	_, err := cdb.VrfGetByIndex(vrfIndex)
+	_, err = v.db().VrfGetByIndex(vrfIndex)
 	if err != nil {
 		log.Warning("[Driver] Static route %+v remove failed because of invalid vrf", objRoute)
 	}
 
 	routeUpdate := []libovsdb.UUID{{GoUUID: tableRoute.UUID}}
-	err = cdb.VrfUpdateRouteDelvalue(vrfIndex, routeUpdate)
+	err = v.db().VrfUpdateRouteDelvalue(vrfIndex, routeUpdate)
 	if err != nil {
 		return err
 	}
@@ -100,86 +88,132 @@ func (v routeAPI) RemoveObject(obj interface{}) error {
 	return nil
 }
 
-func (v routeAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
-	objRoute := obj.(tai.RouteObj)
-
+// staticRouteGet vxlan static route of route object
+func (v routeAPI) staticRouteGet(objRoute tai.RouteObj) (cdb.TableStaticRoute, error) {
 	var conditions []interface{}
 	conditions = append(conditions, libovsdb.
 		NewCondition("vrf", "==", objRoute.Vrf))
 	conditions = append(conditions, libovsdb.
 		NewCondition("ip", "==", objRoute.IPPrefix))
-	rows, num := cdb.StaticRouteGet(conditions)
+	rows, num := v.db().StaticRouteGet(conditions)
 
 	if num != 1 {
 		log.Warning("[Driver] Static route %+v not exist", objRoute)
-		return fmt.Errorf("[Driver] Static route %+v not exist", objRoute)
+		return cdb.TableStaticRoute{}, fmt.Errorf("[Driver] Static route %+v not exist", objRoute)
 	}
-	tableRoute := cdb.ConvertRowToStaticRoute(rows[0])
+	return cdb.ConvertRowToStaticRoute(rows[0]), nil
+}
 
-	routeIndex := cdb.StaticRouteUUIDIndex{
-		UUID: tableRoute.UUID,
-	}
+// routeNexthop nexthop of static route through bridge domain interface
+// of vrf, label is l3vni of symmetric route or empty
+func routeNexthop(vrf string, nexthop string, label string) map[interface{}]interface{} {
+	nhMap := make(map[interface{}]interface{})
+	nhKey := "vrfname:" + vrf + ",ip:" + nexthop + ",port:" + "Bd" + vrf
+	nhMap[nhKey] = "label:" + label + ",onlink:,color:"
+	return nhMap
+}
 
-	for attr, attrValue := range attrs {
-		switch attr {
-		case tai.RouteAttrNexthop:
-			if nh, ok := attrValue.(string); ok {
-				//cdb.StaticRouteSetField(routeIndex, cdb.StaticRouteFieldNexthop, nh)
-				nhMap := make(map[interface{}]interface{})
-				nhKey := "vrfname:" + objRoute.Vrf + ",ip:" + nh + ",port:" + "Bd" + objRoute.Vrf
-				nhMap[nhKey] = "label:,onlink:,color:"
-				cdb.StaticRouteSetField(routeIndex, cdb.StaticRouteFieldNexthop, nhMap)
+// routeLabel label of static route nexthop
+func routeLabel(tableRoute cdb.TableStaticRoute) string {
+	for _, value := range tableRoute.Nexthop {
+		for _, field := range strings.Split(fmt.Sprint(value), ",") {
+			if strings.HasPrefix(field, "label:") {
+				return strings.TrimPrefix(field, "label:")
 			}
 		}
 	}
-
-	return nil
+	return ""
 }
 
-func (v routeAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
-	return nil
-}
+// routeMacLabel label route to remote vtep with router mac by l3vni of
+// vrf, router mac is mapped to nexthop in rmac map of vrf tunnel as the
+// inner dmac. Route of vrf without l3vni or tunnel is not symmetric.
+func (v routeAPI) routeMacLabel(objRoute tai.RouteObj, nexthop string, rmac string) string {
+	if rmac == "" || nexthop == "" {
+		return ""
+	}
+	tableVrf, err := v.db().VrfGetByIndex(cdb.VrfIndex{Name: objRoute.Vrf})
+	if err != nil || len(tableVrf.L3vni) != 1 || len(tableVrf.Tunnel) != 1 {
+		log.Warning("[Driver] Static route %s of vrf %s without l3vni tunnel, route mac %s ignored\n",
+			objRoute.IPPrefix, objRoute.Vrf, rmac)
+		return ""
+	}
+	tableTunnel, err := v.db().TunnelGetByUUID(tableVrf.Tunnel[0].GoUUID)
+	if err != nil {
+		log.Warning("[Driver] Tunnel of vrf %s not exist, route mac %s ignored\n", objRoute.Vrf, rmac)
+		return ""
+	}
 
-func (v routeAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
-	objRoute := obj.(tai.RouteObj)
+	// rmac map of tunnel is only written by tunnel module from tunnel rmac
+	// map attr, which maps ip of each remote chassis to its router mac
+	if tableTunnel.RmacMap[nexthop] != rmac {
+		log.Info("[Driver] Tunnel %s rmac of %s is %q not route mac %s yet\n",
+			tableTunnel.Name, nexthop, tableTunnel.RmacMap[nexthop], rmac)
+	}
+	return strconv.Itoa(tableVrf.L3vni[0])
+}
 
-	var conditions []interface{}
-	conditions = append(conditions, libovsdb.
-		NewCondition("vrf", "==", objRoute.Vrf))
-	conditions = append(conditions, libovsdb.
-		NewCondition("ip", "==", objRoute.IPPrefix))
-	rows, num := cdb.StaticRouteGet(conditions)
+// routeAttrsSet rewrite nexthop of static route by nexthop and route mac
+// attrs, label of route is kept if route mac not changed
+func (v routeAPI) routeAttrsSet(objRoute tai.RouteObj, attrs tai.Attrs) error {
+	if !attrs.Has(tai.RouteAttrNexthop) && !attrs.Has(tai.RouteAttrRouteMac) {
+		return nil
+	}
+	tableRoute, err := v.staticRouteGet(objRoute)
+	if err != nil {
+		return err
+	}
 
-	if num != 1 {
-		log.Warning("[Driver] Static route %+v not exist", objRoute)
-		return fmt.Errorf("[Driver] Static route %+v not exist", objRoute)
+	nexthop := objRoute.Nexthop
+	if attrs.Has(tai.RouteAttrNexthop) {
+		nexthop = attrs.GetString(tai.RouteAttrNexthop)
+	}
+	label := routeLabel(tableRoute)
+	if attrs.Has(tai.RouteAttrRouteMac) {
+		label = v.routeMacLabel(objRoute, nexthop, attrs.GetString(tai.RouteAttrRouteMac))
 	}
-	tableRoute := cdb.ConvertRowToStaticRoute(rows[0])
 
 	routeIndex := cdb.StaticRouteUUIDIndex{
 		UUID: tableRoute.UUID,
 	}
+	return v.db().StaticRouteSetField(routeIndex, cdb.StaticRouteFieldNexthop,
+		routeNexthop(objRoute.Vrf, nexthop, label))
+}
 
-	for attr, attrValue := range attrs {
-		switch attr {
-		case tai.RouteAttrNexthop:
-			if nh, ok := attrValue.(string); ok {
-				nhMap := make(map[interface{}]interface{})
-				nhKey := "vrfname:" + objRoute.Vrf + ",ip:" + nh + ",port:" + "Bd" + objRoute.Vrf
-				nhMap[nhKey] = "label:,onlink:,color:"
+func (v routeAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.routeAttrsSet(obj.(tai.RouteObj), attrs)
+}
 
-				cdb.StaticRouteSetField(routeIndex, cdb.StaticRouteFieldNexthop, nhMap)
-			}
-		}
+// DelObjectAttr route without route mac is not labeled by l3vni
+func (v routeAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	if !attrs.Has(tai.RouteAttrRouteMac) {
+		return nil
 	}
+	return v.routeAttrsSet(obj.(tai.RouteObj), tai.Attrs{tai.RouteAttrRouteMac: ""})
+}
 
-	return nil
+func (v routeAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	return v.routeAttrsSet(obj.(tai.RouteObj), attrs)
 }
 
-func (v routeAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v routeAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject vxlan static routes of vrfs created by driver, nexthop of
+// route is updated by attr and not part of listed route
 func (v routeAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	vrfs := make(map[string]bool)
+	for _, tableVrf := range v.driverVrfs() {
+		vrfs[tableVrf.Name] = true
+	}
+
+	var objs []interface{}
+	v.db().StaticRouteIterator(func(table cdb.TableStaticRoute) {
+		if !vrfs[table.Vrf] || len(table.Flag) != 1 || table.Flag[0] != cdb.StaticRouteFlagVxlan {
+			return
+		}
+		objs = append(objs, tai.RouteObj{Vrf: table.Vrf, IPPrefix: table.IP})
+	})
+	return objs, nil
 }
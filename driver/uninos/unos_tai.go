@@ -7,58 +7,149 @@ import (
 	"github.com/cn-pmlabs/govtep/tai"
 )
 
+// TaiBegin start batch, config db operations of modules are pending until
+// TaiCommit and sent in one transaction
+func (d *unosDriver) TaiBegin() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.batch != nil {
+		return fmt.Errorf("[Driver] config db batch already begun")
+	}
+	d.batch = d.client.Begin()
+	return nil
+}
+
+// TaiCommit send pending config db operations in one transaction, config
+// db rolls all of them back if any fails
+func (d *unosDriver) TaiCommit() error {
+	d.mutex.Lock()
+	batch := d.batch
+	d.batch = nil
+	d.mutex.Unlock()
+	if batch == nil {
+		return fmt.Errorf("[Driver] config db batch not begun")
+	}
+	if err := batch.Commit(); err != nil {
+		return fmt.Errorf("[Driver] commit config db transaction failed: %v", err)
+	}
+	return nil
+}
+
+// TaiAbort drop pending config db operations
+func (d *unosDriver) TaiAbort() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.batch != nil {
+		d.batch.Abort()
+		d.batch = nil
+	}
+}
+
 func (d *unosDriver) TaiCreateObject(objID tai.ObjID, obj interface{}) error {
 	log.Info("[Driver] TaiCreateObject %v => %+v\n", tai.ObjectOrder[objID], obj)
 
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	if d.ModuleAPIs[objID] == nil {
 		return fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
 
-	return unosDriverHandler.ModuleAPIs[objID].CreateObject(obj)
+	return d.use(func() error {
+		return d.ModuleAPIs[objID].CreateObject(obj)
+	})
 }
 
 func (d *unosDriver) TaiRemoveObject(objID tai.ObjID, obj interface{}) error {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	if d.ModuleAPIs[objID] == nil {
 		return fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].RemoveObject(obj)
+	return d.use(func() error {
+		return d.ModuleAPIs[objID].RemoveObject(obj)
+	})
 }
 
 func (d *unosDriver) TaiAddObjectAttr(objID tai.ObjID, obj interface{},
-	attr map[interface{}]interface{}) error {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	attr tai.Attrs) error {
+	if d.ModuleAPIs[objID] == nil {
 		return fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].AddObjectAttr(obj, attr)
+	return d.use(func() error {
+		return d.ModuleAPIs[objID].AddObjectAttr(obj, attr)
+	})
 }
 
 func (d *unosDriver) TaiDelObjectAttr(objID tai.ObjID, obj interface{},
-	attr map[interface{}]interface{}) error {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	attr tai.Attrs) error {
+	if d.ModuleAPIs[objID] == nil {
 		return fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].DelObjectAttr(obj, attr)
+	return d.use(func() error {
+		return d.ModuleAPIs[objID].DelObjectAttr(obj, attr)
+	})
 }
 
 func (d *unosDriver) TaiSetObjectAttr(objID tai.ObjID, obj interface{},
-	attr map[interface{}]interface{}) error {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	attr tai.Attrs) error {
+	if d.ModuleAPIs[objID] == nil {
 		return fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].SetObjectAttr(obj, attr)
+	return d.use(func() error {
+		return d.ModuleAPIs[objID].SetObjectAttr(obj, attr)
+	})
 }
 
 func (d *unosDriver) TaiGetObjectAttr(objID tai.ObjID, obj interface{},
-	attr []interface{}) (map[interface{}]interface{}, error) {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	attr []tai.ObjAttrID) (tai.Attrs, error) {
+	if d.ModuleAPIs[objID] == nil {
 		return nil, fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].GetObjectAttr(obj, attr)
+	var attrs tai.Attrs
+	err := d.use(func() (err error) {
+		attrs, err = d.ModuleAPIs[objID].GetObjectAttr(obj, attr)
+		return err
+	})
+	return attrs, err
 }
 
 func (d *unosDriver) TaiListObject(objID tai.ObjID) ([]interface{}, error) {
-	if unosDriverHandler.ModuleAPIs[objID] == nil {
+	if d.ModuleAPIs[objID] == nil {
 		return nil, fmt.Errorf("[Driver] unspported object %v", tai.ObjectOrder[objID])
 	}
-	return unosDriverHandler.ModuleAPIs[objID].ListObject()
+	var objs []interface{}
+	err := d.use(func() (err error) {
+		objs, err = d.ModuleAPIs[objID].ListObject()
+		return err
+	})
+	return objs, err
+}
+
+// capabilityAttrs attrs programmed by UNOS modules, ACL rules are read
+// from vtepdb by ACL and auto gateway conf is synced from its vtepdb row
+var capabilityAttrs = map[tai.ObjID][]tai.ObjAttrID{
+	tai.ObjectIDBridge:          {tai.BridgeAttrVxlanTunnel, tai.BridgeAttrL2vni},
+	tai.ObjectIDVrf:             {tai.VrfAttrL3vni, tai.VrfAttrTunnel},
+	tai.ObjectIDL2Port:          {tai.L2portAttrVlanTag, tai.L2portAttrVlanTransparent},
+	tai.ObjectIDL3Port:          {tai.L3portAttrVrfBinding},
+	tai.ObjectIDFDB:             {tai.FdbAttrRemoteIP, tai.FdbAttrTunnelName},
+	tai.ObjectIDNeighbour:       {tai.NeighbourAttrMacaddr, tai.NeighbourAttrOutPort, tai.NeighbourAttrRemoteIP},
+	tai.ObjectIDRoute:           {tai.RouteAttrNexthop, tai.RouteAttrRouteMac},
+	tai.ObjectIDTunnel:          {tai.TunnelAttrIpaddr, tai.TunnelAttrRmacMap, tai.TunnelAttrRouteMac},
+	tai.ObjectIDACL:             {tai.ACLAttrStage, tai.ACLAttrType, tai.ACLAttrRules},
+	tai.ObjectIDACLRule:         nil,
+	tai.ObjectIDPBR:             {tai.PBRAttrNexthopGroup},
+	tai.ObjectIDAutoGatewayConf: {tai.AutoGatewayConfAttrIP, tai.AutoGatewayConfAttrIpv6, tai.AutoGatewayConfAttrMtu, tai.AutoGatewayConfAttrUplinks, tai.AutoGatewayConfAttrNexthops},
+}
+
+// TaiGetCapability objects of registered modules, limits are from UNOS
+// config db schema: Acl_Rule max rows and Ecmp_Group nexthop group size
+func (d *unosDriver) TaiGetCapability() tai.Capability {
+	capability := tai.Capability{
+		Objects: make(map[tai.ObjID][]tai.ObjAttrID),
+		Limits: map[string]int{
+			tai.LimitACLRule:    1024,
+			tai.LimitECMPMember: 16,
+		},
+	}
+	for objID := range d.ModuleAPIs {
+		capability.Objects[objID] = capabilityAttrs[objID]
+	}
+	return capability
 }
@@ -1,6 +1,8 @@
 package driver
 
 import (
+	"strings"
+
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
@@ -9,10 +11,7 @@ import (
 
 type tunnelAPI struct {
 	moduleID int
-}
-
-var tunnelAPIs = tunnelAPI{
-	moduleID: tai.ObjectIDTunnel,
+	*unosDriver
 }
 
 func (v tunnelAPI) CreateObject(obj interface{}) error {
@@ -23,24 +22,24 @@ func (v tunnelAPI) CreateObject(obj interface{}) error {
 		Type:     cdb.TunnelTypeVxlanTunnel,
 		MacLearn: []string{cdb.TunnelDefaultMacLearn},
 		DestPort: []int{cdb.TunnelDefaultDestPort},
-		SrcIP:    []string{objTunnel.Ipaddr},
 	}
 
-	/* if objTunnel.Anycast == true {
+	// members of gateway group share the encap ip as anycast vtep address
+	if objTunnel.Anycast == true {
 		tunnelCfg.AnycastIP = []string{objTunnel.Ipaddr}
 	} else {
 		tunnelCfg.SrcIP = []string{objTunnel.Ipaddr}
-	} */
+	}
 
 	tunnelIndex := cdb.TunnelIndex{
 		Name: objTunnel.Name,
 	}
-	if _, err := cdb.TunnelGetByIndex(tunnelIndex); err == nil {
+	if _, err := v.db().TunnelGetByIndex(tunnelIndex); err == nil {
 		log.Info("[Driver] Tunnel %s already exist\n", objTunnel.Name)
 		return nil
 	}
 
-	_, err := cdb.TunnelAdd(tunnelCfg)
+	_, err := v.db().TunnelAdd(tunnelCfg)
 	if err != nil {
 		return err
 	}
@@ -50,6 +49,37 @@ func (v tunnelAPI) CreateObject(obj interface{}) error {
 	return nil
 }
 
+// tunnelIPField source ip of tunnel is anycast ip in gateway group
+func tunnelIPField(objTunnel tai.TunnelObj) string {
+	if objTunnel.Anycast == true {
+		return cdb.TunnelFieldAnycastIP
+	}
+	return cdb.TunnelFieldSrcIP
+}
+
+// vrfRouteMacSet set router mac of tunnel as mac of vrf bridge domain
+// interfaces, it's the inner dmac of symmetric IRB from remote vteps
+func (d *unosDriver) vrfRouteMacSet(rmac string) {
+	if rmac == "" {
+		return
+	}
+	d.db().InterfaceIterator(func(table cdb.TableInterface) {
+		if table.Type != cdb.InterfaceTypeBridgeDomain || !strings.HasPrefix(table.Name, "BdVrf") {
+			return
+		}
+		if len(table.Mac) == 1 && table.Mac[0] == rmac {
+			return
+		}
+		ifIndex := cdb.InterfaceIndex{
+			Name: table.Name,
+			Type: table.Type,
+		}
+		if err := d.db().InterfaceSetField(ifIndex, cdb.InterfaceFieldMac, rmac); err != nil {
+			log.Warning("[Driver] interface %s set route mac %s failed %v\n", table.Name, rmac, err)
+		}
+	})
+}
+
 func (v tunnelAPI) RemoveObject(obj interface{}) error {
 	objTunnel := obj.(tai.TunnelObj)
 
@@ -57,58 +87,101 @@ func (v tunnelAPI) RemoveObject(obj interface{}) error {
 		Name: objTunnel.Name,
 	}
 
-	err := cdb.TunnelDelByIndex(tunnelIndex)
+	err := v.db().TunnelDelByIndex(tunnelIndex)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (v tunnelAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v tunnelAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objTunnel := obj.(tai.TunnelObj)
 
 	tunnelIndex := cdb.TunnelIndex{
 		Name: objTunnel.Name,
 	}
-	if _, err := cdb.TunnelGetByIndex(tunnelIndex); err != nil {
+	if _, err := v.db().TunnelGetByIndex(tunnelIndex); err != nil {
 		log.Warning("[Driver] Tunnel %s not found when add attr\n", objTunnel.Name)
 		return nil
 	}
 
-	if attrs[tai.TunnelAttrIpaddr] != nil {
-		cdb.TunnelSetField(tunnelIndex, cdb.TunnelFieldSrcIP, attrs[tai.TunnelAttrIpaddr])
+	if attrs.Has(tai.TunnelAttrIpaddr) {
+		v.db().TunnelSetField(tunnelIndex, tunnelIPField(objTunnel), attrs.GetString(tai.TunnelAttrIpaddr))
 	}
 
-	if attrs[tai.TunnelAttrRmacMap] != nil {
-		cdb.TunnelSetField(tunnelIndex, cdb.TunnelFieldRmacMap, attrs[tai.TunnelAttrRmacMap])
+	if attrs.Has(tai.TunnelAttrRmacMap) {
+		v.db().TunnelSetField(tunnelIndex, cdb.TunnelFieldRmacMap, attrs.GetStringMap(tai.TunnelAttrRmacMap))
+	}
+
+	if attrs.Has(tai.TunnelAttrRouteMac) {
+		v.vrfRouteMacSet(attrs.GetString(tai.TunnelAttrRouteMac))
 	}
 
 	return nil
 }
 
-func (v tunnelAPI) DelObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v tunnelAPI) DelObjectAttr(obj interface{}, attrs tai.Attrs) error {
+	objTunnel := obj.(tai.TunnelObj)
+
+	tunnelIndex := cdb.TunnelIndex{
+		Name: objTunnel.Name,
+	}
+
+	if attrs.Has(tai.TunnelAttrRmacMap) {
+		v.db().TunnelSetField(tunnelIndex, cdb.TunnelFieldRmacMap, map[string]string{})
+	}
 
 	return nil
 }
 
-func (v tunnelAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v tunnelAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objTunnel := obj.(tai.TunnelObj)
 
 	tunnelIndex := cdb.TunnelIndex{
 		Name: objTunnel.Name,
 	}
 
-	if attrs[tai.TunnelAttrIpaddr] != nil {
-		cdb.TunnelSetField(tunnelIndex, cdb.TunnelFieldSrcIP, attrs[tai.TunnelAttrIpaddr])
+	if attrs.Has(tai.TunnelAttrIpaddr) {
+		v.db().TunnelSetField(tunnelIndex, tunnelIPField(objTunnel), attrs.GetString(tai.TunnelAttrIpaddr))
+	}
+
+	if attrs.Has(tai.TunnelAttrRmacMap) {
+		v.db().TunnelSetField(tunnelIndex, cdb.TunnelFieldRmacMap, attrs.GetStringMap(tai.TunnelAttrRmacMap))
+	}
+
+	if attrs.Has(tai.TunnelAttrRouteMac) {
+		v.vrfRouteMacSet(attrs.GetString(tai.TunnelAttrRouteMac))
 	}
 
 	return nil
 }
 
-func (v tunnelAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v tunnelAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// ListObject tunnels referred by bridges and vrfs created by driver, source
+// ip of tunnel is updated by attr and not part of listed tunnel
 func (v tunnelAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	tunnels := make(map[string]bool)
+	for _, tableBridge := range v.driverBridges() {
+		for _, tunnel := range tableBridge.VxlanTunnel {
+			tunnels[tunnel.GoUUID] = true
+		}
+	}
+	for _, tableVrf := range v.driverVrfs() {
+		for _, tunnel := range tableVrf.Tunnel {
+			tunnels[tunnel.GoUUID] = true
+		}
+	}
+
+	var objs []interface{}
+	for uuid := range tunnels {
+		tableTunnel, err := v.db().TunnelGetByUUID(uuid)
+		if err != nil {
+			continue
+		}
+		objs = append(objs, tai.TunnelObj{Name: tableTunnel.Name})
+	}
+	return objs, nil
 }
@@ -3,6 +3,7 @@ package driver
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 	cdb "github.com/cn-pmlabs/govtep/lib/odbapi/unosconfig"
@@ -24,10 +25,7 @@ func getVniByVrfName(vrfName string) int {
 
 type vrfAPI struct {
 	moduleID int
-}
-
-var vrfAPIs = vrfAPI{
-	moduleID: tai.ObjectIDVrf,
+	*unosDriver
 }
 
 func (v vrfAPI) CreateObject(obj interface{}) error {
@@ -55,9 +53,14 @@ func (v vrfAPI) CreateObject(obj interface{}) error {
 	vrfIndex = cdb.VrfIndex{
 		Name: objVrf.Name,
 	}
-	_, err = cdb.VrfGetByIndex(vrfIndex)
+	vrfCfg, err = v.db().VrfGetByIndex(vrfIndex)
 	if err == nil {
 		log.Info("[Driver] Vrf %s already exist", objVrf.Name)
+		// l3vni may be set by attr since created
+		if len(vrfCfg.L3vni) == 1 {
+			vni = vrfCfg.L3vni[0]
+		}
+		vrfUUID = vrfCfg.UUID
 		goto bdvrf
 	}
 
@@ -66,7 +69,7 @@ func (v vrfAPI) CreateObject(obj interface{}) error {
 		L3vni: []int{vni},
 	}
 
-	vrfUUID, err = cdb.VrfAdd(vrfCfg)
+	vrfUUID, err = v.db().VrfAdd(vrfCfg)
 	if err != nil {
 		return err
 	}
@@ -101,19 +104,23 @@ bdvrf:
 		Name: "Bd" + vrfCfg.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	_, err = cdb.InterfaceGetByIndex(ifIndex)
+	_, err = v.db().InterfaceGetByIndex(ifIndex)
 	if err == nil {
 		log.Info("[Driver] interface %s type %s already exist\n", ifIndex.Name, ifIndex.Type)
 		goto pbr
 	}
 
-	_, err = cdb.InterfaceAdd(interfaceCfg)
+	_, err = v.db().InterfaceAdd(interfaceCfg)
 	if err != nil {
 		return err
 	}
 
 pbr:
-	pbrAclCreate(objVrf.Name)
+	v.pbrAclCreate(objVrf.Name)
+
+	if err = v.bgpVrfCreate(objVrf.Name, vni); err != nil {
+		log.Warning("[Driver] BGP instance of vrf %s create failed %v\n", objVrf.Name, err)
+	}
 
 	return nil
 }
@@ -130,7 +137,7 @@ func (v vrfAPI) RemoveObject(obj interface{}) error {
 	vrfIndex = cdb.VrfIndex{
 		Name: objVrf.Name,
 	}
-	err = cdb.VrfDelByIndex(vrfIndex)
+	err = v.db().VrfDelByIndex(vrfIndex)
 	if err != nil {
 		log.Warning("[Driver] Vrf %s Del failed", objVrf.Name)
 	}
@@ -139,23 +146,27 @@ func (v vrfAPI) RemoveObject(obj interface{}) error {
 		Name: "Bd" + vrfIndex.Name,
 		Type: cdb.InterfaceTypeBridgeDomain,
 	}
-	err = cdb.InterfaceDelByIndex(interfaceIndex)
+	err = v.db().InterfaceDelByIndex(interfaceIndex)
 	if err != nil {
 		log.Warning("[Driver] Interface %s Del failed", interfaceIndex.Name)
 	}
 
-	pbrAclRemove(objVrf.Name)
+	v.pbrAclRemove(objVrf.Name)
+
+	if err = v.bgpVrfRemove(objVrf.Name); err != nil {
+		log.Warning("[Driver] BGP instance of vrf %s remove failed %v\n", objVrf.Name, err)
+	}
 
 	return nil
 }
 
-func (v vrfAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v vrfAPI) AddObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objVrf := obj.(tai.VrfObj)
 
 	vrfIndex := cdb.VrfIndex{
 		Name: objVrf.Name,
 	}
-	_, err := cdb.VrfGetByIndex(vrfIndex)
+	tableVrf, err := v.db().VrfGetByIndex(vrfIndex)
 	if err != nil {
 		log.Warning("[Driver] Vrf %+v not exist\n", vrfIndex)
 		return nil
@@ -163,18 +174,23 @@ func (v vrfAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 
 	for attr, attrValue := range attrs {
 		switch attr {
+		case tai.VrfAttrL3vni:
+			if err := v.vrfL3vniSet(tableVrf, attrs.GetInt(tai.VrfAttrL3vni)); err != nil {
+				log.Warning("[Driver] Vrf %s l3vni set failed %v\n", objVrf.Name, err)
+				return err
+			}
 		case tai.VrfAttrTunnel:
 			if tunnelName, ok := attrValue.(string); ok {
 				tunnelIndex := cdb.TunnelIndex{
 					Name: tunnelName,
 				}
-				tunnel, err := cdb.TunnelGetByIndex(tunnelIndex)
+				tunnel, err := v.db().TunnelGetByIndex(tunnelIndex)
 				if err != nil {
 					log.Warning("[Driver] Vrf %+v update tunnel %s not exist\n", vrfIndex, tunnelName)
 					return nil
 				}
 
-				cdb.VrfSetField(vrfIndex, cdb.VrfFieldTunnel, []libovsdb.UUID{{GoUUID: tunnel.UUID}})
+				v.db().VrfSetField(vrfIndex, cdb.VrfFieldTunnel, []libovsdb.UUID{{GoUUID: tunnel.UUID}})
 			}
 		}
 	}
@@ -182,17 +198,37 @@ func (v vrfAPI) AddObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v vrfAPI) DelObjectAttr(interface{}, map[interface{}]interface{}) error {
+// vrfL3vniSet l3vni of vrf and RD and RT of its bgp instance, vrf is
+// created with l3vni of its name
+func (v vrfAPI) vrfL3vniSet(tableVrf cdb.TableVrf, vni int) error {
+	if vni < cdb.VrfL3vniMin || vni > cdb.VrfL3vniMax {
+		return nil
+	}
+	if len(tableVrf.L3vni) != 1 || tableVrf.L3vni[0] != vni {
+		vrfIndex := cdb.VrfIndex{
+			Name: tableVrf.Name,
+		}
+		if err := v.db().VrfSetField(vrfIndex, cdb.VrfFieldL3vni, []int{vni}); err != nil {
+			return err
+		}
+	}
+	if err := v.bgpVrfRdRtSet(tableVrf.Name, vni); err != nil {
+		log.Warning("[Driver] BGP instance of vrf %s RD and RT set failed %v\n", tableVrf.Name, err)
+	}
+	return nil
+}
+
+func (v vrfAPI) DelObjectAttr(interface{}, tai.Attrs) error {
 	return nil
 }
 
-func (v vrfAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}) error {
+func (v vrfAPI) SetObjectAttr(obj interface{}, attrs tai.Attrs) error {
 	objVrf := obj.(tai.VrfObj)
 
 	vrfIndex := cdb.VrfIndex{
 		Name: objVrf.Name,
 	}
-	_, err := cdb.VrfGetByIndex(vrfIndex)
+	tableVrf, err := v.db().VrfGetByIndex(vrfIndex)
 	if err != nil {
 		log.Warning("[Driver] Vrf %+v not exist\n", vrfIndex)
 		return nil
@@ -200,18 +236,23 @@ func (v vrfAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 
 	for attr, attrValue := range attrs {
 		switch attr {
+		case tai.VrfAttrL3vni:
+			if err := v.vrfL3vniSet(tableVrf, attrs.GetInt(tai.VrfAttrL3vni)); err != nil {
+				log.Warning("[Driver] Vrf %s l3vni set failed %v\n", objVrf.Name, err)
+				return err
+			}
 		case tai.VrfAttrTunnel:
 			if tunnelName, ok := attrValue.(string); ok {
 				tunnelIndex := cdb.TunnelIndex{
 					Name: tunnelName,
 				}
-				tunnel, err := cdb.TunnelGetByIndex(tunnelIndex)
+				tunnel, err := v.db().TunnelGetByIndex(tunnelIndex)
 				if err != nil {
 					log.Warning("[Driver] Vrf %+v update tunnel %s not exist\n", vrfIndex, tunnelName)
 					return nil
 				}
 
-				cdb.VrfSetField(vrfIndex, cdb.VrfFieldTunnel, []libovsdb.UUID{{GoUUID: tunnel.UUID}})
+				v.db().VrfSetField(vrfIndex, cdb.VrfFieldTunnel, []libovsdb.UUID{{GoUUID: tunnel.UUID}})
 			}
 		}
 	}
@@ -219,10 +260,36 @@ func (v vrfAPI) SetObjectAttr(obj interface{}, attrs map[interface{}]interface{}
 	return nil
 }
 
-func (v vrfAPI) GetObjectAttr(interface{}, []interface{}) (map[interface{}]interface{}, error) {
+func (v vrfAPI) GetObjectAttr(interface{}, []tai.ObjAttrID) (tai.Attrs, error) {
 	return nil, nil
 }
 
+// driverVrfs vrfs created by driver, named "Vrf"+vni
+func (d *unosDriver) driverVrfs() []cdb.TableVrf {
+	var vrfs []cdb.TableVrf
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: vtepdb.InvalidUUID}))
+	rows, _ := d.db().VrfGet(conditions)
+	for _, row := range rows {
+		tableVrf := cdb.ConvertRowToVrf(row)
+		if !strings.HasPrefix(tableVrf.Name, "Vrf") {
+			continue
+		}
+		vni := getVniByVrfName(tableVrf.Name)
+		if vni < cdb.VrfL3vniMin || vni > cdb.VrfL3vniMax {
+			continue
+		}
+		vrfs = append(vrfs, tableVrf)
+	}
+	return vrfs
+}
+
+// ListObject vrfs created by driver
 func (v vrfAPI) ListObject() ([]interface{}, error) {
-	return nil, nil
+	var objs []interface{}
+	for _, tableVrf := range v.driverVrfs() {
+		objs = append(objs, tai.VrfObj{Name: tableVrf.Name})
+	}
+	return objs, nil
 }
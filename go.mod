@@ -4,5 +4,9 @@ go 1.14
 
 require (
 	github.com/ebay/libovsdb v0.2.0
+	github.com/google/nftables v0.1.0
 	github.com/google/uuid v1.2.0
+	github.com/vishvananda/netlink v1.3.1
+	github.com/vishvananda/netns v0.0.5
+	golang.org/x/sys v0.10.0
 )
@@ -153,12 +153,6 @@ func aclCreate(row libovsdb.Row) error {
 		vtepACL.Stage = vtepdb.ACLStageEgress
 	}
 
-	_, err = vtepdb.ACLAdd(vtepACL)
-	if err != nil {
-		log.Warning("ACL %s create in vtepdb failed\n", vtepACL.Name)
-		return nil
-	}
-
 	// convert match to vtepdb ACL
 	var vtepACLRule vtepdb.TableACLRule
 	for key, val := range aclRuleField {
@@ -231,6 +225,21 @@ func aclCreate(row libovsdb.Row) error {
 	vtepACLIndex := vtepdb.ACLIndex{
 		Name: tableACL.Name[0],
 	}
+	dbACL, err := vtepdb.ACLGetByIndex(vtepACLIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.ACL, dbACL.UUID)
+		log.Info("ACL %s already exist\n", vtepACL.Name)
+		if err := aclReconcile(dbACL, vtepACL, vtepACLRule); err != nil {
+			log.Warning("ACL %s reconcile failed %v\n", vtepACL.Name, err)
+		}
+		return nil
+	}
+
+	_, err = vtepdb.ACLAdd(vtepACL)
+	if err != nil {
+		log.Warning("ACL %s create in vtepdb failed\n", vtepACL.Name)
+		return nil
+	}
 
 	err = vtepdb.ACLUpdateAddACLRules(vtepACLIndex, vtepACLRule)
 	if err != nil {
@@ -240,6 +249,42 @@ func aclCreate(row libovsdb.Row) error {
 	return nil
 }
 
+// aclReconcile update ACL and its rule kept by warm restart to ACL derived again
+func aclReconcile(dbACL vtepdb.TableACL, vtepACL vtepdb.TableACL, vtepACLRule vtepdb.TableACLRule) error {
+	aclIndex := vtepdb.ACLIndex{
+		Name: vtepACL.Name,
+	}
+	err := warmRestartReconcile("ACL "+vtepACL.Name, dbACL, vtepACL, vtepdb.ACLFieldMapToColumn,
+		[]string{"Ports", "Stage"},
+		func(column string, value interface{}) error {
+			return vtepdb.ACLSetField(aclIndex, column, value)
+		})
+	if err != nil {
+		return err
+	}
+
+	ruleIndex := vtepdb.ACLRuleIndex{
+		ACLName:  vtepACLRule.ACLName,
+		Sequence: vtepACLRule.Sequence,
+	}
+	dbRule, err := vtepdb.ACLRuleGetByIndex(ruleIndex)
+	if err != nil {
+		// priority changed, rules of other priority are replaced
+		if len(dbACL.ACLRules) != 0 {
+			if err := vtepdb.ACLUpdateACLRulesDelvalue(aclIndex, dbACL.ACLRules); err != nil {
+				return err
+			}
+		}
+		return vtepdb.ACLUpdateAddACLRules(aclIndex, vtepACLRule)
+	}
+	return warmRestartReconcile("ACL rule "+vtepACL.Name, dbRule, vtepACLRule, vtepdb.ACLRuleFieldMapToColumn,
+		[]string{"Action", "Ethertype", "Protocol", "SourceMac", "DestMac", "SourceIP", "DestIP",
+			"DestPortMin", "DestPortMax"},
+		func(column string, value interface{}) error {
+			return vtepdb.ACLRuleSetField(ruleIndex, column, value)
+		})
+}
+
 func aclRemove(row libovsdb.Row) error {
 	tableACL := ovnnb.ConvertRowToACL(row.Fields)
 
@@ -2,6 +2,7 @@ package govtep
 
 import (
 	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
 
 	"github.com/ebay/libovsdb"
 )
@@ -11,13 +12,17 @@ type ovnNbNotifier struct {
 }
 
 func (notify ovnNbNotifier) Update(context interface{}, updates libovsdb.TableUpdates) {
+	vtepProcessMutex.Lock()
+	defer vtepProcessMutex.Unlock()
 	notify.onbi.ovnNbNotifyUpdate(updates)
 }
 
-func (notify ovnNbNotifier) Locked([]interface{}) {
+func (notify ovnNbNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
 
-func (notify ovnNbNotifier) Stolen([]interface{}) {
+func (notify ovnNbNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 
 func (notify ovnNbNotifier) Echo([]interface{}) {
@@ -35,13 +40,17 @@ type ovnSbNotifier struct {
 }
 
 func (notify ovnSbNotifier) Update(context interface{}, updates libovsdb.TableUpdates) {
+	vtepProcessMutex.Lock()
+	defer vtepProcessMutex.Unlock()
 	notify.osbi.ovnSbNotifyUpdate(updates)
 }
 
-func (notify ovnSbNotifier) Locked([]interface{}) {
+func (notify ovnSbNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
 
-func (notify ovnSbNotifier) Stolen([]interface{}) {
+func (notify ovnSbNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 
 func (notify ovnSbNotifier) Echo([]interface{}) {
@@ -59,13 +68,17 @@ type vtepDbNotifier struct {
 }
 
 func (notify vtepDbNotifier) Update(context interface{}, updates libovsdb.TableUpdates) {
+	vtepProcessMutex.Lock()
+	defer vtepProcessMutex.Unlock()
 	notify.vdbi.vtepDbNotifyUpdate(updates)
 }
 
-func (notify vtepDbNotifier) Locked([]interface{}) {
+func (notify vtepDbNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
 
-func (notify vtepDbNotifier) Stolen([]interface{}) {
+func (notify vtepDbNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 
 func (notify vtepDbNotifier) Echo([]interface{}) {
@@ -85,10 +98,12 @@ type ovnNbLibNotifier struct {
 func (notify ovnNbLibNotifier) Update(context interface{}, updates libovsdb.TableUpdates) {
 }
 
-func (notify ovnNbLibNotifier) Locked([]interface{}) {
+func (notify ovnNbLibNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
 
-func (notify ovnNbLibNotifier) Stolen([]interface{}) {
+func (notify ovnNbLibNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 
 func (notify ovnNbLibNotifier) Echo([]interface{}) {
@@ -106,10 +121,12 @@ type ovnSbLibNotifier struct {
 func (notify ovnSbLibNotifier) Update(context interface{}, updates libovsdb.TableUpdates) {
 }
 
-func (notify ovnSbLibNotifier) Locked([]interface{}) {
+func (notify ovnSbLibNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
 
-func (notify ovnSbLibNotifier) Stolen([]interface{}) {
+func (notify ovnSbLibNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 
 func (notify ovnSbLibNotifier) Echo([]interface{}) {
@@ -1,6 +1,8 @@
 package govtep
 
 import (
+	"fmt"
+
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
@@ -65,17 +67,26 @@ func remoteFdbCreate(fdbs []RemoteFdb) error {
 			Bridge: fdb.Bridge,
 			Mac:    fdb.Mac,
 		}
-		_, err := vtepdb.RemoteFdbGetByIndex(fdbIndex)
-		if err == nil {
-			log.Info("fdb %+v already exist", fdbIndex)
-			continue
-		}
-
 		tableFdb := vtepdb.TableRemoteFdb{
 			Bridge:        fdb.Bridge,
 			Mac:           fdb.Mac,
 			RemoteLocator: fdb.RemoteLocator,
 		}
+		dbFdb, err := vtepdb.RemoteFdbGetByIndex(fdbIndex)
+		if err == nil {
+			warmRestartClaim(vtepdb.RemoteFdb, dbFdb.UUID)
+			log.Info("fdb %+v already exist", fdbIndex)
+			err = warmRestartReconcile(fmt.Sprintf("fdb %+v", fdbIndex), dbFdb, tableFdb,
+				vtepdb.RemoteFdbFieldMapToColumn, []string{"RemoteLocator"},
+				func(column string, value interface{}) error {
+					return vtepdb.RemoteFdbSetField(fdbIndex, column, value)
+				})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		bdIndex := vtepdb.BridgeDomainIndex{
 			Name: fdb.Bridge,
 		}
@@ -0,0 +1,425 @@
+package govtep
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+
+	"github.com/ebay/libovsdb"
+)
+
+// Gateway group HA. Members of a gateway group share the group chassis
+// and the anycast encap ip, every member owns a SB HA_Chassis row in
+// HA_Chassis_Group named by the group system id. Member holds SB lock of
+// its HA_Chassis while its local locator is ready, SB releases the lock
+// when session of the member closed or its echo timed out in
+// GatewayDeadInterval. Live members request the locks of the others, the
+// one granted lock of a member drops it from
+// Chassis.gateway_chassis_members and sets its HA_Chassis priority to 0
+// with the lock asserted, member restores both once it holds its lock
+// again.
+var (
+	GatewayHelloInterval time.Duration = 1 * time.Second
+	GatewayDeadInterval  time.Duration = 3 * time.Second
+)
+
+// GatewayPriorityDefault HA_Chassis priority of member without
+// Physical_Switch other_config gateway_priority
+const GatewayPriorityDefault int = 100
+
+// HA_Chassis external_ids and Physical_Switch other_config keys of
+// gateway group
+const (
+	GatewayMemberKey   string = "gateway-member"
+	GatewayPriorityKey string = "gateway_priority"
+)
+
+// gatewayMember member of gateway group seen by local member, dead once
+// local member got its lock and demoted it
+type gatewayMember struct {
+	dead bool
+}
+
+// gatewayGroup local physical switch in gateway group
+type gatewayGroup struct {
+	psUUID   string
+	psName   string
+	systemID string
+	priority int
+	members  map[string]*gatewayMember
+	stop     chan struct{}
+	// done closed when run returned and locks released
+	done chan struct{}
+}
+
+// gatewayGroups local gateway group members keyed by Physical_Switch
+// name, member locks are requested on SB session shared by them.
+// stopping holds channel closed once HA_Chassis of stopped member
+// removed, member restarted meanwhile waits for it
+var gatewayGroups = struct {
+	mutex    sync.Mutex
+	groups   map[string]*gatewayGroup
+	stopping map[string]chan struct{}
+	session  *odbc.LockSession
+}{
+	groups:   make(map[string]*gatewayGroup),
+	stopping: make(map[string]chan struct{}),
+}
+
+// gatewayGroupStart start member lock and monitor of local physical
+// switch in gateway group, restarted if priority changed
+func gatewayGroupStart(tablePS vtepdb.TablePhysicalSwitch) {
+	priority := GatewayPriorityDefault
+	if value, ok := tablePS.OtherConfig[GatewayPriorityKey].(string); ok {
+		if p, err := strconv.Atoi(value); err == nil && p >= ovnsb.HaChassisPriorityMin && p <= ovnsb.HaChassisPriorityMax {
+			priority = p
+		} else {
+			log.Warning("Physical Switch %s invalid %s %s, use %d\n", tablePS.Name, GatewayPriorityKey, value, priority)
+		}
+	}
+
+	gatewayGroups.mutex.Lock()
+	defer gatewayGroups.mutex.Unlock()
+	// restarted group waits for the old one releasing its locks
+	var prev chan struct{}
+	if group, ok := gatewayGroups.groups[tablePS.Name]; ok {
+		if group.psUUID == tablePS.UUID && group.systemID == tablePS.SystemID && group.priority == priority {
+			return
+		}
+		close(group.stop)
+		prev = group.done
+	} else {
+		prev = gatewayGroups.stopping[tablePS.Name]
+	}
+
+	group := &gatewayGroup{
+		psUUID:   tablePS.UUID,
+		psName:   tablePS.Name,
+		systemID: tablePS.SystemID,
+		priority: priority,
+		members:  make(map[string]*gatewayMember),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	gatewayGroups.groups[tablePS.Name] = group
+	if gatewayGroups.session == nil {
+		gatewayGroups.session = odbc.NewLockSession("gateway group",
+			func() string { return odbc.OvnsbAddr }, nil, GatewayHelloInterval, GatewayDeadInterval)
+	}
+	log.Warning("Physical Switch %s gateway group %s HA start, priority %d\n", tablePS.Name, tablePS.SystemID, priority)
+	go group.run(gatewayGroups.session, prev)
+}
+
+// gatewayGroupStop stop HA of local physical switch, its HA_Chassis is
+// removed in background once its locks released, so vtepdb notifier is
+// not blocked by SB transactions
+func gatewayGroupStop(tablePS vtepdb.TablePhysicalSwitch) {
+	gatewayGroups.mutex.Lock()
+	defer gatewayGroups.mutex.Unlock()
+	group, ok := gatewayGroups.groups[tablePS.Name]
+	if !ok {
+		return
+	}
+	close(group.stop)
+	delete(gatewayGroups.groups, tablePS.Name)
+
+	removed := make(chan struct{})
+	gatewayGroups.stopping[tablePS.Name] = removed
+	go func() {
+		<-group.done
+		group.remove()
+		close(removed)
+		gatewayGroups.mutex.Lock()
+		if gatewayGroups.stopping[group.psName] == removed {
+			delete(gatewayGroups.stopping, group.psName)
+		}
+		gatewayGroups.mutex.Unlock()
+	}()
+}
+
+// remove HA_Chassis of stopped member, group is removed with its last
+// member
+func (group *gatewayGroup) remove() {
+	groupIndex := ovnsb.HaChassisGroupIndex{Name: group.systemID}
+	tableGroup, err := ovnsb.HaChassisGroupGetByIndex(groupIndex)
+	if err != nil {
+		return
+	}
+	if self, _ := group.haChassis(tableGroup); self != nil {
+		err = ovnsb.HaChassisGroupUpdateHaChassisDelvalue(groupIndex, []libovsdb.UUID{{GoUUID: self.UUID}})
+		if err != nil {
+			log.Warning("Gateway group %s remove member %s failed %v\n", group.systemID, group.psName, err)
+		}
+		// members joining meanwhile keep the group, zero timeout is
+		// omitted by libovsdb and means wait forever so 1ms is used
+		empty, _ := libovsdb.NewOvsSet([]libovsdb.UUID{})
+		condition := libovsdb.NewCondition(ovnsb.HaChassisGroupFieldName, "==", group.systemID)
+		_, err = ovnsb.Transact(libovsdb.Operation{
+			Op:      odbc.OpWait,
+			Table:   ovnsb.HaChassisGroup,
+			Where:   []interface{}{condition},
+			Columns: []string{ovnsb.HaChassisGroupFieldHaChassis},
+			Until:   "==",
+			Rows:    []map[string]interface{}{{ovnsb.HaChassisGroupFieldHaChassis: empty}},
+			Timeout: 1,
+		}, libovsdb.Operation{
+			Op:    odbc.OpDelete,
+			Table: ovnsb.HaChassisGroup,
+			Where: []interface{}{condition},
+		})
+		if err != nil {
+			log.Info("Gateway group %s kept by other members\n", group.systemID)
+		}
+	}
+	log.Warning("Physical Switch %s gateway group %s HA stop\n", group.psName, group.systemID)
+}
+
+// gatewayMemberLock SB lock held by live member
+func gatewayMemberLock(member string) string {
+	return GatewayMemberKey + "-" + member
+}
+
+func (group *gatewayGroup) run(session *odbc.LockSession, prev chan struct{}) {
+	defer close(group.done)
+	if prev != nil {
+		<-prev
+	}
+	ticker := time.NewTicker(GatewayHelloInterval)
+	defer ticker.Stop()
+	defer group.release(session)
+	for {
+		select {
+		case <-group.stop:
+			return
+		case <-ticker.C:
+			// standby instance of the member leaves its lock to active one
+			if !odbc.IsActive() {
+				group.release(session)
+				continue
+			}
+			if OvnCentralConnected == false {
+				continue
+			}
+			if err := group.hello(session); err != nil {
+				log.Warning("Gateway group %s member %s hello failed %v\n", group.systemID, group.psName, err)
+			}
+		}
+	}
+}
+
+// release locks of member and the ones requested for other members
+func (group *gatewayGroup) release(session *odbc.LockSession) {
+	for _, member := range append([]string{group.psUUID}, group.memberNames()...) {
+		if lockID := gatewayMemberLock(member); session.Requested(lockID) {
+			session.Unlock(lockID)
+		}
+	}
+	group.members = make(map[string]*gatewayMember)
+}
+
+func (group *gatewayGroup) memberNames() []string {
+	names := make([]string, 0, len(group.members))
+	for member := range group.members {
+		names = append(names, member)
+	}
+	return names
+}
+
+// haChassis HA_Chassis rows of group, own row and the others by member
+func (group *gatewayGroup) haChassis(tableGroup ovnsb.TableHaChassisGroup) (*ovnsb.TableHaChassis, map[string]ovnsb.TableHaChassis) {
+	var self *ovnsb.TableHaChassis
+	others := make(map[string]ovnsb.TableHaChassis)
+	for _, uuid := range tableGroup.HaChassis {
+		tableHA, err := ovnsb.HaChassisGetByUUID(uuid.GoUUID)
+		if err != nil {
+			continue
+		}
+		member, _ := tableHA.ExternalIds[GatewayMemberKey].(string)
+		if member == group.psUUID {
+			self = &tableHA
+		} else if member != "" {
+			others[member] = tableHA
+		}
+	}
+	return self, others
+}
+
+// hello hold lock of local member while its locator is ready and check
+// the others
+func (group *gatewayGroup) hello(session *odbc.LockSession) error {
+	chassisIndex := ovnsb.ChassisIndex{Name: group.systemID}
+	tableChassis, err := ovnsb.ChassisGetByIndex(chassisIndex)
+	if err != nil {
+		return fmt.Errorf("chassis not found")
+	}
+
+	groupIndex := ovnsb.HaChassisGroupIndex{Name: group.systemID}
+	tableGroup, err := ovnsb.HaChassisGroupGetByIndex(groupIndex)
+	if err != nil {
+		_, err = ovnsb.HaChassisGroupAdd(ovnsb.TableHaChassisGroup{Name: group.systemID})
+		if err != nil {
+			// added by other member at the same time
+			return fmt.Errorf("add HA chassis group failed %v", err)
+		}
+		if tableGroup, err = ovnsb.HaChassisGroupGetByIndex(groupIndex); err != nil {
+			return err
+		}
+	}
+
+	self, others := group.haChassis(tableGroup)
+	// lock released until local locator ready, other members drop it
+	selfLock := gatewayMemberLock(group.psUUID)
+	if !gatewayReady(group.systemID) {
+		if session.Requested(selfLock) {
+			log.Warning("Gateway group %s member %s not ready, leave\n", group.systemID, group.psName)
+			if err = session.Unlock(selfLock); err != nil {
+				return err
+			}
+		}
+	} else if !session.Requested(selfLock) {
+		if _, err = session.Lock(selfLock); err != nil {
+			return err
+		}
+	}
+	if session.Held(selfLock) {
+		if err = group.join(session, tableGroup, tableChassis, self); err != nil {
+			return err
+		}
+	}
+
+	for member, tableHA := range others {
+		state, ok := group.members[member]
+		if !ok {
+			state = &gatewayMember{}
+			group.members[member] = state
+		}
+		lockID := gatewayMemberLock(member)
+		if state.dead {
+			// member restores its priority once it holds its lock again
+			if tableHA.Priority == 0 {
+				continue
+			}
+			log.Warning("Gateway group %s member %s alive\n", group.systemID, member)
+			state.dead = false
+		}
+		if !session.Requested(lockID) {
+			if _, err := session.Lock(lockID); err != nil {
+				log.Warning("Gateway group %s member %s lock failed %v\n", group.systemID, member, err)
+				continue
+			}
+		}
+		if !session.Held(lockID) {
+			continue
+		}
+
+		log.Warning("Gateway group %s member %s dead, its lock is released\n", group.systemID, member)
+		if err := group.demote(session, tableChassis, tableHA, member); err != nil {
+			log.Warning("Gateway group %s update member %s failed %v\n", group.systemID, member, err)
+			continue
+		}
+		state.dead = true
+		session.Unlock(lockID)
+	}
+	for member := range group.members {
+		if _, ok := others[member]; !ok {
+			delete(group.members, member)
+			if lockID := gatewayMemberLock(member); session.Requested(lockID) {
+				session.Unlock(lockID)
+			}
+		}
+	}
+	return nil
+}
+
+// demote member whose lock is held by local member, so it can't rejoin
+// meanwhile
+func (group *gatewayGroup) demote(session *odbc.LockSession, tableChassis ovnsb.TableChassis,
+	tableHA ovnsb.TableHaChassis, member string) error {
+	var ops []libovsdb.Operation
+	for _, m := range tableChassis.GatewayChassisMembers {
+		if m == member {
+			ops = append(ops, gatewayMembersOp(group.systemID, odbc.OpDelete, member))
+			break
+		}
+	}
+	if tableHA.Priority != 0 {
+		ops = append(ops, libovsdb.Operation{
+			Op:    odbc.OpUpdate,
+			Table: ovnsb.HaChassis,
+			Row:   map[string]interface{}{ovnsb.HaChassisFieldPriority: 0},
+			Where: []interface{}{libovsdb.NewCondition("_uuid", "==", libovsdb.UUID{GoUUID: tableHA.UUID})},
+		})
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	_, err := session.Transact(odbc.OVNSB, gatewayMemberLock(member), ops...)
+	return err
+}
+
+// join add HA_Chassis of local member holding its lock, priority and
+// gateway_chassis_members dropped by other members are restored
+func (group *gatewayGroup) join(session *odbc.LockSession, tableGroup ovnsb.TableHaChassisGroup,
+	tableChassis ovnsb.TableChassis, self *ovnsb.TableHaChassis) error {
+	var ops []libovsdb.Operation
+	if self == nil {
+		insertOp, err := ovnsb.HaChassisAddOp(ovnsb.TableHaChassis{
+			Chassis:     []libovsdb.UUID{{GoUUID: tableChassis.UUID}},
+			Priority:    group.priority,
+			ExternalIds: map[interface{}]interface{}{GatewayMemberKey: group.psUUID},
+		})
+		if err != nil {
+			return err
+		}
+		set, _ := libovsdb.NewOvsSet([]libovsdb.UUID{{GoUUID: insertOp.UUIDName}})
+		ops = append(ops, insertOp, libovsdb.Operation{
+			Op:        odbc.OpMutate,
+			Table:     ovnsb.HaChassisGroup,
+			Mutations: []interface{}{libovsdb.NewMutation(ovnsb.HaChassisGroupFieldHaChassis, odbc.OpInsert, set)},
+			Where:     []interface{}{libovsdb.NewCondition("_uuid", "==", libovsdb.UUID{GoUUID: tableGroup.UUID})},
+		})
+	} else if self.Priority != group.priority {
+		ops = append(ops, libovsdb.Operation{
+			Op:    odbc.OpUpdate,
+			Table: ovnsb.HaChassis,
+			Row:   map[string]interface{}{ovnsb.HaChassisFieldPriority: group.priority},
+			Where: []interface{}{libovsdb.NewCondition("_uuid", "==", libovsdb.UUID{GoUUID: self.UUID})},
+		})
+	}
+
+	joined := false
+	for _, member := range tableChassis.GatewayChassisMembers {
+		if member == group.psUUID {
+			joined = true
+			break
+		}
+	}
+	if !joined {
+		log.Warning("Gateway group %s member %s rejoin\n", group.systemID, group.psName)
+		ops = append(ops, gatewayMembersOp(group.systemID, odbc.OpInsert, group.psUUID))
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	_, err := session.Transact(odbc.OVNSB, gatewayMemberLock(group.psUUID), ops...)
+	return err
+}
+
+// gatewayMembersOp insert or delete member of gateway_chassis_members of
+// group chassis
+func gatewayMembersOp(systemID string, mutator string, member string) libovsdb.Operation {
+	set, _ := libovsdb.NewOvsSet([]string{member})
+	return libovsdb.Operation{
+		Op:        odbc.OpMutate,
+		Table:     ovnsb.Chassis,
+		Mutations: []interface{}{libovsdb.NewMutation(ovnsb.ChassisFieldGatewayChassisMembers, mutator, set)},
+		Where:     []interface{}{libovsdb.NewCondition(ovnsb.ChassisFieldName, "==", systemID)},
+	}
+}
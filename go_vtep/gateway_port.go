@@ -0,0 +1,285 @@
+package govtep
+
+import (
+	"fmt"
+
+	ovnnb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnnorthbound"
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// Gateway port types, l3gateway ports are the two ends of gateway router
+// pinned to a chassis, chassisredirect port is distributed gateway port
+// redirected to its active gateway chassis, external port is bare-metal
+// port attached to active chassis of its HA_Chassis_Group
+const (
+	PortTypeL3Gateway       = "l3gateway"
+	PortTypeChassisRedirect = "chassisredirect"
+	PortTypeExternal        = "external"
+)
+
+// Port binding gateway options
+const (
+	PbOptionL3GatewayChassis = "l3gateway-chassis"
+	PbOptionDistributedPort  = "distributed-port"
+)
+
+// gatewayPortChassis chassis name hosting gateway router port, distributed
+// gateway port or external port, active chassis of Gateway_Chassis or
+// HA_Chassis_Group is the one with highest priority. HA chassis with
+// priority 0 is a dead gateway group member.
+func gatewayPortChassis(tablePB ovnsb.TablePortBinding) string {
+	switch tablePB.Type {
+	case PortTypeL3Gateway:
+		chassisName, _ := tablePB.Options[PbOptionL3GatewayChassis].(string)
+		return chassisName
+	case PortTypeChassisRedirect, PortTypeExternal:
+	default:
+		return ""
+	}
+
+	var chassisName string
+	priority := -1
+	for _, gwUUID := range tablePB.GatewayChassis {
+		tableGC, err := ovnsb.GatewayChassisGetByUUID(gwUUID.GoUUID)
+		if err != nil || len(tableGC.Chassis) == 0 || tableGC.Priority <= priority {
+			continue
+		}
+		tableChassis, err := ovnsb.ChassisGetByUUID(tableGC.Chassis[0].GoUUID)
+		if err != nil {
+			continue
+		}
+		chassisName, priority = tableChassis.Name, tableGC.Priority
+	}
+
+	if len(tablePB.HaChassisGroup) == 1 {
+		tableGroup, err := ovnsb.HaChassisGroupGetByUUID(tablePB.HaChassisGroup[0].GoUUID)
+		if err != nil {
+			return chassisName
+		}
+		for _, haUUID := range tableGroup.HaChassis {
+			tableHA, err := ovnsb.HaChassisGetByUUID(haUUID.GoUUID)
+			if err != nil || len(tableHA.Chassis) == 0 || tableHA.Priority <= 0 || tableHA.Priority <= priority {
+				continue
+			}
+			tableChassis, err := ovnsb.ChassisGetByUUID(tableHA.Chassis[0].GoUUID)
+			if err != nil {
+				continue
+			}
+			chassisName, priority = tableChassis.Name, tableHA.Priority
+		}
+	}
+	return chassisName
+}
+
+// gatewayPortBind bind gateway port to local chassis hosting it, ports of
+// software chassis are bound by ovn-controller
+func gatewayPortBind(port PortInfo) error {
+	if port.Location != LocationLocal {
+		return nil
+	}
+
+	pbIndex := ovnsb.PortBindingIndex1{
+		LogicalPort: port.LogicalPort,
+	}
+	tablePB, err := ovnsb.PortBindingGetByIndex(pbIndex)
+	if err != nil {
+		return fmt.Errorf("Get port binding %v failed", port.LogicalPort)
+	}
+	chassisName := gatewayPortChassis(tablePB)
+	chassisIndex := ovnsb.ChassisIndex{
+		Name: chassisName,
+	}
+	tableChassis, err := ovnsb.ChassisGetByIndex(chassisIndex)
+	if err != nil {
+		return fmt.Errorf("Get chassis %v failed", chassisName)
+	}
+	if len(tablePB.Chassis) == 1 && tablePB.Chassis[0].GoUUID == tableChassis.UUID {
+		return nil
+	}
+	return ovnsb.PortBindingSetField(pbIndex, ovnsb.PortBindingFieldChassis, libovsdb.UUID{GoUUID: tableChassis.UUID})
+}
+
+// gatewayPortRelease clear chassis of gateway port bound by local chassis
+// which no longer hosts it, active chassis of its group claims it again
+func gatewayPortRelease(port PortInfo) error {
+	pbIndex := ovnsb.PortBindingIndex1{
+		LogicalPort: port.LogicalPort,
+	}
+	tablePB, err := ovnsb.PortBindingGetByIndex(pbIndex)
+	if err != nil || len(tablePB.Chassis) == 0 {
+		return nil
+	}
+	tableChassis, err := ovnsb.ChassisGetByUUID(tablePB.Chassis[0].GoUUID)
+	if err != nil || !gatewayReady(tableChassis.Name) {
+		return nil
+	}
+	if gatewayPortChassis(tablePB) == tableChassis.Name {
+		return nil
+	}
+	return ovnsb.PortBindingUpdateChassisDelvalue(pbIndex, tablePB.Chassis)
+}
+
+// gatewayPortSync gateway ports are located again when Gateway_Chassis,
+// HA_Chassis or HA_Chassis_Group changed, ports moved to or from local
+// chassis are re-created by their branch
+func gatewayPortSync() {
+	for pbUUID, port := range portInfoMap {
+		if port.Type != PortTypeL3Gateway && port.Type != PortTypeChassisRedirect &&
+			port.Type != PortTypeExternal {
+			continue
+		}
+
+		var conditions []interface{}
+		conditions = append(conditions, libovsdb.
+			NewCondition(ovnsb.PortBindingFieldLogicalPort, "==", port.LogicalPort))
+		rows, num := ovnsb.PortBindingGet(conditions)
+		if num == 0 {
+			continue
+		}
+		portbindingUpdatePortBranch(libovsdb.Row{Fields: rows[0]}, pbUUID)
+	}
+}
+
+// gatewayPortLocal gateway ports of logical router are all hosted by local
+// chassis, router without gateway port is distributed
+func gatewayPortLocal(lrUUID string) bool {
+	for pbUUID, port := range portInfoMap {
+		if port.LnName != lrUUID {
+			continue
+		}
+		if portType[pbUUID] != LRPL3Gateway && portType[pbUUID] != LRPChassisRedirect {
+			continue
+		}
+		if port.Location != LocationLocal {
+			return false
+		}
+	}
+	return true
+}
+
+// gatewayRouteSet external routes of logical router by static routes out
+// of its gateway port lrpName, routes are forwarded by local outputPort or
+// to remote gateway chassis
+func gatewayRouteSet(port PortInfo, outputPort string, lrpName string) []Route {
+	tableLR, err := ovnnb.LogicalRouterGetByUUID(port.LnName)
+	if err != nil {
+		log.Warning("Logical router %s of gateway port %s not found\n", port.LnName, port.LogicalPort)
+		return nil
+	}
+
+	var rts []Route
+	for _, srUUID := range tableLR.StaticRoutes {
+		tableSR, err := ovnnb.LogicalRouterStaticRouteGetByUUID(srUUID.GoUUID)
+		if err != nil {
+			continue
+		}
+		// routes out of other router ports are not external
+		if len(tableSR.OutputPort) == 1 && tableSR.OutputPort[0] != lrpName {
+			continue
+		}
+
+		rt := Route{
+			UUID:     "",
+			Vrf:      port.Vrf,
+			IPPrefix: tableSR.IPPrefix,
+			Policy:   RoutePolicyDefault,
+		}
+		if len(tableSR.Policy) == 1 {
+			rt.Policy = tableSR.Policy[0]
+		}
+		if port.Location == LocationLocal {
+			rt.Nexthop = tableSR.Nexthop
+			rt.OutputPort = outputPort
+		} else {
+			rt.RemoteLocator = port.Locator
+			if err = routeLocatorNexthop(&rt); err != nil {
+				log.Warning("%v\n", err)
+			}
+		}
+		rts = append(rts, rt)
+	}
+	return rts
+}
+
+// chassisRedirectCreate distributed gateway port hosted by local chassis
+// routes external traffic by its L3Port and does NAT of router, otherwise
+// external traffic is routed to remote gateway chassis
+func chassisRedirectCreate(port PortInfo) error {
+	var outputPort string
+	if port.Location == LocationLocal {
+		var conditions []interface{}
+		conditions = append(conditions, libovsdb.
+			NewCondition(ovnsb.PortBindingFieldLogicalPort, "==", port.DistributedPort))
+		rows, num := ovnsb.PortBindingGet(conditions)
+		if num == 0 {
+			return fmt.Errorf("Distributed port %s of %s not found", port.DistributedPort, port.LogicalPort)
+		}
+		// L3Port is owned by patch branch of distributed port, it's created
+		// here in case distributed port is not processed yet
+		dgp := PortbindingParser(libovsdb.Row{Fields: rows[0]})
+		if err := l3portCreate(dgp); err != nil {
+			return err
+		}
+		outputPort = dgp.Name
+	}
+
+	routeSetCreate(gatewayRouteSet(port, outputPort, port.DistributedPort))
+	logicalRouterNatSync(port.LnName, port.Location == LocationLocal)
+	return nil
+}
+
+// gatewayPortCreate gateway ports of branches LSPL3Gateway, LRPL3Gateway,
+// LRPChassisRedirect and external ports of branch LSPExternal
+func gatewayPortCreate(branch int, port PortInfo) error {
+	if err := gatewayPortBind(port); err != nil {
+		log.Warning("Bind gateway port %s failed %v\n", port.LogicalPort, err)
+	}
+
+	switch branch {
+	case LSPL3Gateway, LSPExternal:
+		return l2PortCreate(port)
+	case LRPL3Gateway:
+		// gateway router hosted by remote chassis is not programmed
+		if port.Location == LocationLocal {
+			if err := l3portCreate(port); err != nil {
+				return err
+			}
+			routeSetCreate(gatewayRouteSet(port, port.Name, port.LogicalPort))
+		}
+		logicalRouterNatSync(port.LnName, port.Location == LocationLocal)
+	case LRPChassisRedirect:
+		return chassisRedirectCreate(port)
+	}
+	return nil
+}
+
+// gatewayPortRemove port moved off local chassis is released, NATs of
+// router are distributed again after its gateway port removed
+func gatewayPortRemove(branch int, port PortInfo) error {
+	if err := gatewayPortRelease(port); err != nil {
+		log.Warning("Release gateway port %s failed %v\n", port.LogicalPort, err)
+	}
+
+	var err error
+
+	switch branch {
+	case LSPL3Gateway, LSPExternal:
+		return l2PortRemove(port)
+	case LRPL3Gateway:
+		if port.Location == LocationLocal {
+			routeSetRemove(gatewayRouteSet(port, "", port.LogicalPort))
+			err = l3portRemove(port)
+		}
+	case LRPChassisRedirect:
+		routeSetRemove(gatewayRouteSet(port, "", port.DistributedPort))
+	}
+
+	if port.Location != LocationLocal {
+		logicalRouterNatSync(port.LnName, true)
+	}
+	return err
+}
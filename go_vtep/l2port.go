@@ -32,14 +32,13 @@ func l2PortCreate(port PortInfo) error {
 	if !bdIsExist(port.Bd) {
 		return errors.New("l2port Create failed, because bd not found")
 	}
-
-	l2portIndex := vtepdb.L2portIndex1{
-		Name: port.Name,
+	if err := physicalParentPortCheck(port); err != nil {
+		return err
 	}
-	_, err := vtepdb.L2portGetByIndex(l2portIndex)
-	if err == nil {
-		log.Info("l2port %s already exist", l2portIndex.Name)
-		return nil
+	if port.Type == "" || port.Type == PortTypeExternal {
+		if err := trunkMemberAdd(port); err != nil {
+			return err
+		}
 	}
 
 	bdIndex := vtepdb.BridgeDomainIndex{
@@ -52,17 +51,56 @@ func l2PortCreate(port PortInfo) error {
 		Peertype:      port.PeerLtype,
 		LogicalPort:   port.LogicalPort,
 		PhyparentPort: port.PhyParentPort,
+		Physwitch:     port.PhySwitch,
 		Type:          port.Type,
 	}
 	if port.VlanTag != 0 {
 		tableL2port.Vlantag = []int{port.VlanTag}
 	}
-	err = vtepdb.BridgeDomainUpdateAddL2ports(bdIndex, tableL2port)
+	if port.VlanTag != 0 && port.InnerVlanTag != 0 {
+		tableL2port.InnerVlantag = []int{port.InnerVlanTag}
+	}
+	tableL2port.VlanTransparent = port.VlanTransparent
 
-	return err
+	l2portIndex := vtepdb.L2portIndex1{
+		Name: port.Name,
+	}
+	dbL2port, err := vtepdb.L2portGetByIndex(l2portIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.L2port, dbL2port.UUID)
+		if dbL2port.Bd == port.Bd {
+			log.Info("l2port %s already exist", l2portIndex.Name)
+			return l2PortReconcile(dbL2port, tableL2port)
+		}
+		// moved to another bd, the row is garbage collected once removed
+		err = vtepdb.BridgeDomainUpdateL2portsDelvalue(vtepdb.BridgeDomainIndex{Name: dbL2port.Bd},
+			[]libovsdb.UUID{{GoUUID: dbL2port.UUID}})
+		if err != nil {
+			return fmt.Errorf("l2port %s remove from bd %s failed: %v", port.Name, dbL2port.Bd, err)
+		}
+	}
+
+	return vtepdb.BridgeDomainUpdateAddL2ports(bdIndex, tableL2port)
+}
+
+// l2PortReconcile update l2port row kept by warm restart to port derived again
+func l2PortReconcile(dbL2port vtepdb.TableL2port, tableL2port vtepdb.TableL2port) error {
+	l2portIndex := vtepdb.L2portIndex1{
+		Name: tableL2port.Name,
+	}
+	return warmRestartReconcile("l2port "+tableL2port.Name, dbL2port, tableL2port, vtepdb.L2portFieldMapToColumn,
+		[]string{"Peerport", "Peertype", "LogicalPort", "PhyparentPort", "Physwitch", "Type",
+			"Vlantag", "InnerVlantag", "VlanTransparent"},
+		func(column string, value interface{}) error {
+			return vtepdb.L2portSetField(l2portIndex, column, value)
+		})
 }
 
 func l2PortRemove(port PortInfo) error {
+	physicalParentPortClear(port.LogicalPort)
+	if port.Type == "" || port.Type == PortTypeExternal {
+		defer trunkMemberRetry(trunkMemberRemove(port))
+	}
 	if !bdIsExist(port.Bd) {
 		return errors.New("l2port delete failed, because bd not found")
 	}
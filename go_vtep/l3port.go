@@ -40,14 +40,8 @@ func l3portCreate(port PortInfo) error {
 	if !vrfIsExist(port.Vrf) {
 		return errors.New("l3port Create fail, because vrf not found")
 	}
-
-	l3portIndex := vtepdb.L3portIndex1{
-		Name: port.Name,
-	}
-	_, err := vtepdb.L3portGetByIndex(l3portIndex)
-	if err == nil {
-		log.Info("l3port %s already exist", l3portIndex.Name)
-		return nil
+	if err := physicalParentPortCheck(port); err != nil {
+		return err
 	}
 
 	vrfIndex := vtepdb.VrfIndex{
@@ -62,18 +56,52 @@ func l3portCreate(port PortInfo) error {
 		Peertype:      port.PeerLtype,
 		LogicalPort:   port.LogicalPort,
 		PhyparentPort: port.PhyParentPort,
+		Physwitch:     port.PhySwitch,
 		Type:          port.Type,
 	}
 	if port.VlanTag != 0 {
 		tableL3port.Vlantag = []int{port.VlanTag}
 	}
+	if port.VlanTag != 0 && port.InnerVlanTag != 0 {
+		tableL3port.InnerVlantag = []int{port.InnerVlanTag}
+	}
 
-	err = vtepdb.VrfUpdateAddL3ports(vrfIndex, tableL3port)
+	l3portIndex := vtepdb.L3portIndex1{
+		Name: port.Name,
+	}
+	dbL3port, err := vtepdb.L3portGetByIndex(l3portIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.L3port, dbL3port.UUID)
+		if dbL3port.Vrf == port.Vrf {
+			log.Info("l3port %s already exist", l3portIndex.Name)
+			return l3portReconcile(dbL3port, tableL3port)
+		}
+		// moved to another vrf, the row is garbage collected once removed
+		err = vtepdb.VrfUpdateL3portsDelvalue(vtepdb.VrfIndex{Name: dbL3port.Vrf},
+			[]libovsdb.UUID{{GoUUID: dbL3port.UUID}})
+		if err != nil {
+			return fmt.Errorf("l3port %s remove from vrf %s failed: %v", port.Name, dbL3port.Vrf, err)
+		}
+	}
 
-	return err
+	return vtepdb.VrfUpdateAddL3ports(vrfIndex, tableL3port)
+}
+
+// l3portReconcile update l3port row kept by warm restart to port derived again
+func l3portReconcile(dbL3port vtepdb.TableL3port, tableL3port vtepdb.TableL3port) error {
+	l3portIndex := vtepdb.L3portIndex1{
+		Name: tableL3port.Name,
+	}
+	return warmRestartReconcile("l3port "+tableL3port.Name, dbL3port, tableL3port, vtepdb.L3portFieldMapToColumn,
+		[]string{"Ipv4addr", "Ipv6addr", "Peerport", "Peertype", "LogicalPort", "PhyparentPort",
+			"Physwitch", "Type", "Vlantag", "InnerVlantag"},
+		func(column string, value interface{}) error {
+			return vtepdb.L3portSetField(l3portIndex, column, value)
+		})
 }
 
 func l3portRemove(port PortInfo) error {
+	physicalParentPortClear(port.LogicalPort)
 	vrfIndex := vtepdb.VrfIndex{
 		Name: port.Vrf,
 	}
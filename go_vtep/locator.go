@@ -2,6 +2,7 @@ package govtep
 
 import (
 	"fmt"
+	"sync"
 
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
@@ -12,8 +13,50 @@ import (
 	"github.com/ebay/libovsdb"
 )
 
-// GatewayInitDone identify wheather vnet process can be done
-var GatewayInitDone bool
+// localGateways chassis names of physical switches whose local locator is
+// ready, vnet process can be done once any local gateway is ready
+var localGateways = struct {
+	mutex sync.Mutex
+	ready map[string]bool
+}{
+	ready: make(map[string]bool),
+}
+
+// gatewayInitDone identify wheather vnet process can be done
+func gatewayInitDone() bool {
+	localGateways.mutex.Lock()
+	defer localGateways.mutex.Unlock()
+	return len(localGateways.ready) != 0
+}
+
+// gatewayReady check local locator of chassis ready
+func gatewayReady(chassisName string) bool {
+	localGateways.mutex.Lock()
+	defer localGateways.mutex.Unlock()
+	return localGateways.ready[chassisName]
+}
+
+// gatewayUp local locator of chassis ready, return true for the first
+// ready gateway
+func gatewayUp(chassisName string) bool {
+	localGateways.mutex.Lock()
+	defer localGateways.mutex.Unlock()
+	first := len(localGateways.ready) == 0
+	localGateways.ready[chassisName] = true
+	return first
+}
+
+// gatewayDown local locator of chassis removed, return true when no
+// gateway is ready anymore
+func gatewayDown(chassisName string) bool {
+	localGateways.mutex.Lock()
+	defer localGateways.mutex.Unlock()
+	if !localGateways.ready[chassisName] {
+		return false
+	}
+	delete(localGateways.ready, chassisName)
+	return len(localGateways.ready) == 0
+}
 
 // Locator in vtep db
 type Locator struct {
@@ -89,8 +132,9 @@ func locatorCreate(chassis libovsdb.Row) error {
 	}
 	dbLocator, err := vtepdb.LocatorGetByIndex(locatorIndex)
 	if err == nil {
+		warmRestartClaim(vtepdb.Locator, dbLocator.UUID)
 		if dbLocator.LocalLocator == true {
-			GatewayInitDone = true
+			gatewayUp(dbLocator.ChassisName)
 		}
 
 		log.Info("Locator for chassis %s already exist", tableChassis.Name)
@@ -177,8 +221,8 @@ func locatorCreate(chassis libovsdb.Row) error {
 	_, err = vtepdb.LocatorAdd(tableLocator)
 
 	if err == nil {
-		if tableLocator.LocalLocator == true {
-			GatewayInitDone = true
+		// vnets are processed once, later gateways get them from vtepdb
+		if tableLocator.LocalLocator == true && gatewayUp(tableLocator.ChassisName) {
 			vnetProcessAll()
 		}
 	}
@@ -234,8 +278,9 @@ func locatorRemove(chassis libovsdb.Row) error {
 	if err == nil {
 		if tableLocator.LocalLocator == true {
 			log.Warning("LocalLocator %s remove\n", tableLocator.ChassisName)
-			GatewayInitDone = false
-			vnetRemoveAll()
+			if gatewayDown(tableLocator.ChassisName) {
+				vnetRemoveAll()
+			}
 		}
 	}
 
@@ -292,6 +337,9 @@ func locatorUpdateEncap(newrow libovsdb.Row, oldValue interface{}) error {
 		if tableLocator.RouteMac != tableEncap.RouterMac {
 			tableLocator.RouteMac = tableEncap.RouterMac
 			err = vtepdb.LocatorSetField(locatorIndex, vtepdb.LocatorFieldRouteMac, tableEncap.RouterMac)
+
+			// symmetric routes to chassis use its new router mac
+			routeRmacUpdateForLocator(tableLocator.UUID, tableEncap.RouterMac)
 		}
 	}
 
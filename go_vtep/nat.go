@@ -62,7 +62,27 @@ func logicalRouterUpdateNat(lrRow libovsdb.Row, oldValue interface{}, UUID strin
 	return nil
 }
 
+// natPolicyBasedRoute PBR of NAT in vrf of its logical router
+func natPolicyBasedRoute(vrf string, tableNat ovnnb.TableNat) PolicyBasedRoute {
+	return PolicyBasedRoute{
+		Vrf:        vrf,
+		IP:         tableNat.ExternalIP,
+		LogicalIPs: []string{tableNat.LogicalIP},
+		Type:       tableNat.Type,
+	}
+}
+
+// logicalRouterAddNat NAT of router with gateway port is done only if its
+// gateway chassis is local
 func logicalRouterAddNat(tableLR ovnnb.TableLogicalRouter, nat libovsdb.UUID) error {
+	if !gatewayPortLocal(tableLR.UUID) {
+		log.Info("LR %s gateway is remote, nat %s ignored\n", tableLR.Name, nat.GoUUID)
+		return nil
+	}
+	return natAdd(tableLR, nat)
+}
+
+func natAdd(tableLR ovnnb.TableLogicalRouter, nat libovsdb.UUID) error {
 	tableNat, err := ovnnb.NatGetByUUID(nat.GoUUID)
 	if err != nil {
 		return fmt.Errorf("nat %s not found", nat.GoUUID)
@@ -73,13 +93,7 @@ func logicalRouterAddNat(tableLR ovnnb.TableLogicalRouter, nat libovsdb.UUID) er
 		return fmt.Errorf("LR %s vtepdb.vrf not found", tableLR.Name)
 	}
 
-	pbr := PolicyBasedRoute{
-		Vrf:        vrf,
-		IP:         tableNat.ExternalIP,
-		LogicalIPs: []string{tableNat.LogicalIP},
-		Type:       tableNat.Type,
-	}
-
+	pbr := natPolicyBasedRoute(vrf, tableNat)
 	err = policyBasedRouteAdd(pbr)
 	if err != nil {
 		log.Warning("policyBasedRoute %+v Add failed\n", pbr)
@@ -152,13 +166,7 @@ func natRemove(lbRow libovsdb.Row, UUID string) error {
 		return nil
 	}
 
-	pbr := PolicyBasedRoute{
-		Vrf:        vrf,
-		IP:         tableNat.ExternalIP,
-		LogicalIPs: []string{tableNat.LogicalIP},
-		Type:       tableNat.Type,
-	}
-
+	pbr := natPolicyBasedRoute(vrf, tableNat)
 	err := policyBasedRouteDel(pbr)
 	if err != nil {
 		log.Warning("policyBasedRoute %+v del failed\n", pbr)
@@ -166,3 +174,34 @@ func natRemove(lbRow libovsdb.Row, UUID string) error {
 
 	return nil
 }
+
+// logicalRouterNatSync add NATs of logical router if its gateway is local,
+// otherwise they are removed and done by remote gateway chassis
+func logicalRouterNatSync(lrUUID string, local bool) {
+	tableLR, err := ovnnb.LogicalRouterGetByUUID(lrUUID)
+	if err != nil {
+		return
+	}
+	tableLR.UUID = lrUUID
+
+	vrf, err := getVrfFromLR(lrUUID)
+	if err != nil {
+		log.Warning("LR %s vtepdb.vrf not found\n", tableLR.Name)
+		return
+	}
+
+	for _, nat := range tableLR.Nat {
+		if local {
+			err = natAdd(tableLR, nat)
+		} else {
+			var tableNat ovnnb.TableNat
+			tableNat, err = ovnnb.NatGetByUUID(nat.GoUUID)
+			if err == nil {
+				err = policyBasedRouteDel(natPolicyBasedRoute(vrf, tableNat))
+			}
+		}
+		if err != nil {
+			log.Warning("LR %s sync nat %s failed %v\n", tableLR.Name, nat.GoUUID, err)
+		}
+	}
+}
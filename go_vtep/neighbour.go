@@ -36,8 +36,9 @@ func remoteNeighCreate(rns []RemoteNeigh) error {
 			Ipaddr: rn.Ipaddr,
 			Mac:    rn.Mac,
 		}
-		_, err := vtepdb.RemoteNeighGetByIndex(neighIndex)
+		dbNeigh, err := vtepdb.RemoteNeighGetByIndex(neighIndex)
 		if err == nil {
+			warmRestartClaim(vtepdb.RemoteNeigh, dbNeigh.UUID)
 			log.Info("neighbour %+v already exist", neighIndex)
 			continue
 		}
@@ -72,12 +73,6 @@ func remoteNeighCreate(rns []RemoteNeigh) error {
 			log.Warning("Neighbour %s vrf %s not exist", tableNeigh.Ipaddr, vrfIndex.Name)
 			continue
 		}
-		tableLocator, err := vtepdb.LocatorGetByUUID(rn.RemoteLocator)
-		if err != nil {
-			log.Warning("Locator for neighbour %s not exist yet", tableNeigh.Ipaddr)
-			continue
-		}
-
 		rt := Route{
 			IPPrefix:      rn.Ipaddr + "/32",
 			Vrf:           tableVrf.Name,
@@ -86,9 +81,9 @@ func remoteNeighCreate(rns []RemoteNeigh) error {
 			Policy:        RoutePolicyDefault,
 			RemoteLocator: rn.RemoteLocator,
 		}
-		// locator first ip as route nexthop for now
-		if len(tableLocator.Ipaddr) > 0 {
-			rt.Nexthop = tableLocator.Ipaddr[0]
+		if err = routeLocatorNexthop(&rt); err != nil {
+			log.Warning("Locator for neighbour %s not exist yet", tableNeigh.Ipaddr)
+			continue
 		}
 
 		err = routeCreate(rt)
@@ -0,0 +1,127 @@
+package govtep
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// invalidParentPort local port whose physical parent port is not in
+// Physical_Port inventory of its switch, created again once the port shows
+// up in inventory
+type invalidParentPort struct {
+	port   PortInfo
+	psName string
+	fault  string
+}
+
+// invalidParentPorts invalid ports keyed by logical port
+var invalidParentPorts = struct {
+	mutex sync.Mutex
+	ports map[string]invalidParentPort
+}{
+	ports: make(map[string]invalidParentPort),
+}
+
+// portPhysicalSwitch Physical_Switch port is bound to, by port option or
+// by chassis of port binding
+func portPhysicalSwitch(port PortInfo) (vtepdb.TablePhysicalSwitch, error) {
+	if port.PhySwitch != "" {
+		return vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex{Name: port.PhySwitch})
+	}
+	tableChassis, err := ovnsb.ChassisGetByUUID(port.Chassis)
+	if err != nil {
+		return vtepdb.TablePhysicalSwitch{}, err
+	}
+	return vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex1{SystemID: tableChassis.Name})
+}
+
+// physicalParentPortCheck check physical parent port of local port is in
+// Physical_Port inventory of its switch, fault is added to the switch if
+// not. Switch without inventory reported by driver is not checked.
+func physicalParentPortCheck(port PortInfo) error {
+	if port.PhyParentPort == "" {
+		return nil
+	}
+	tablePS, err := portPhysicalSwitch(port)
+	if err != nil || len(tablePS.Ports) == 0 {
+		return nil
+	}
+	for _, uuid := range tablePS.Ports {
+		tablePort, err := vtepdb.PhysicalPortGetByUUID(uuid.GoUUID)
+		if err == nil && tablePort.Name == port.PhyParentPort {
+			physicalParentPortClear(port.LogicalPort)
+			return nil
+		}
+	}
+
+	fault := fmt.Sprintf("port %s physical_parent_port %s not found", port.LogicalPort, port.PhyParentPort)
+	invalidParentPorts.mutex.Lock()
+	old, ok := invalidParentPorts.ports[port.LogicalPort]
+	invalidParentPorts.ports[port.LogicalPort] = invalidParentPort{port: port, psName: tablePS.Name, fault: fault}
+	invalidParentPorts.mutex.Unlock()
+	if ok && (old.psName != tablePS.Name || old.fault != fault) {
+		physicalParentPortFault(old.psName, old.fault, false)
+	}
+	physicalParentPortFault(tablePS.Name, fault, true)
+	return errors.New(fault)
+}
+
+// physicalParentPortClear clear fault of invalid port, called when port is
+// valid or removed
+func physicalParentPortClear(logicalPort string) {
+	invalidParentPorts.mutex.Lock()
+	invalid, ok := invalidParentPorts.ports[logicalPort]
+	delete(invalidParentPorts.ports, logicalPort)
+	invalidParentPorts.mutex.Unlock()
+	if ok {
+		physicalParentPortFault(invalid.psName, invalid.fault, false)
+	}
+}
+
+func physicalParentPortFault(psName string, fault string, add bool) {
+	var err error
+	psIndex := vtepdb.PhysicalSwitchIndex{Name: psName}
+	if add {
+		err = vtepdb.PhysicalSwitchUpdateSwitchFaultStatusAddvalue(psIndex, []string{fault})
+	} else {
+		err = vtepdb.PhysicalSwitchUpdateSwitchFaultStatusDelvalue(psIndex, []string{fault})
+	}
+	if err != nil {
+		log.Warning("Physical Switch %s update fault %s failed %v\n", psName, fault, err)
+	}
+}
+
+// physicalParentPortRetry create invalid ports of switch again after its
+// Physical_Port inventory changed
+func physicalParentPortRetry(psName string) {
+	var ports []PortInfo
+	invalidParentPorts.mutex.Lock()
+	for _, invalid := range invalidParentPorts.ports {
+		if invalid.psName == psName {
+			ports = append(ports, invalid.port)
+		}
+	}
+	invalidParentPorts.mutex.Unlock()
+
+	for _, port := range ports {
+		var err error
+		if port.LnType == DatapathTypeLS {
+			if err = l2PortCreate(port); err == nil {
+				autoGatewayConfTableUpdate(port)
+			}
+		} else {
+			err = l3portCreate(port)
+		}
+		if err != nil {
+			log.Info("Port %s still invalid: %v\n", port.LogicalPort, err)
+			continue
+		}
+		log.Warning("Port %s created after Physical_Port inventory update\n", port.LogicalPort)
+	}
+}
@@ -60,14 +60,6 @@ type Encap struct {
 	ChassisName string
 }
 
-const (
-	localChassisNo = iota
-	localChassisGw
-	localChassisGwGroup
-)
-
-var localChassisStatus int = localChassisNo
-
 // SwitchConfFile default switch configure file path and name
 var SwitchConfFile = "/etc/sonic/govtep/switch.conf"
 
@@ -89,6 +81,10 @@ func writeStringAtFileTail(filename string, data string) error {
 func checkPhysicalSwitchValid(psIndex vtepdb.PhysicalSwitchIndex, tablePS vtepdb.TablePhysicalSwitch) error {
 	var err error
 
+	if err = tablePS.Validate(); err != nil {
+		vtepdb.PhysicalSwitchSetField(psIndex, vtepdb.PhysicalSwitchFieldSwitchFaultStatus, []string{err.Error()})
+		return err
+	}
 	if tablePS.EncapType != "vxlan" {
 		vtepdb.PhysicalSwitchSetField(psIndex, vtepdb.PhysicalSwitchFieldSwitchFaultStatus, []string{"unsuppoted encap type"})
 		return fmt.Errorf("encap type must be vxlan")
@@ -134,16 +130,17 @@ func deleteOldChassisRecord(ps vtepdb.TablePhysicalSwitch) {
 
 // PhysicalSwitchInit process phsical switch (group)s in vtep DB
 func PhysicalSwitchInit() {
+	if !odbc.IsActive() {
+		return
+	}
+
 	var conditions []interface{}
 	conditions = append(conditions, libovsdb.
 		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: vtepdb.InvalidUUID}))
 	rows, num := vtepdb.PhysicalSwitchGet(conditions)
 	if num > 0 {
 		for _, row := range rows {
-			rowPS := libovsdb.Row{
-				Fields: row,
-			}
-			physicalSwitchCreate(rowPS)
+			physicalSwitchCreate(vtepdb.ConvertRowToPhysicalSwitch(row))
 		}
 	}
 
@@ -153,25 +150,41 @@ func PhysicalSwitchInit() {
 	return
 }
 
-func physicalSwitchNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate) {
+// physicalSwitchNotifyReady physical switch updates are processed only
+// by active instance connected to ovn central
+func physicalSwitchNotifyReady() bool {
+	if !odbc.IsActive() {
+		return false
+	}
 	if OvnCentralConnected == false {
 		log.Warning("Ovn central connection not established, process phsical switch update later\n")
-		return
+		return false
 	}
+	return true
+}
 
-	switch op {
-	case odbc.OpInsert:
-		physicalSwitchCreate(rowUpdate.New)
-	case odbc.OpDelete:
-		physicalSwitchRemove(rowUpdate.Old)
-	case odbc.OpUpdate:
-		physicalSwitchUpdate(rowUpdate.New, rowUpdate.Old)
+// OnPhysicalSwitchInsert vtepdb.PhysicalSwitchHandler
+func (h *vtepdbHandler) OnPhysicalSwitchInsert(newPS vtepdb.TablePhysicalSwitch) {
+	if h.ready() && physicalSwitchNotifyReady() {
+		physicalSwitchCreate(newPS)
 	}
 }
 
-func physicalSwitchCreate(row libovsdb.Row) {
-	tablePS := vtepdb.ConvertRowToPhysicalSwitch(row.Fields)
+// OnPhysicalSwitchUpdate vtepdb.PhysicalSwitchHandler
+func (h *vtepdbHandler) OnPhysicalSwitchUpdate(oldPS, newPS vtepdb.TablePhysicalSwitch, changed []string) {
+	if h.ready() && physicalSwitchNotifyReady() {
+		physicalSwitchUpdate(oldPS, newPS, changed)
+	}
+}
+
+// OnPhysicalSwitchDelete vtepdb.PhysicalSwitchHandler
+func (h *vtepdbHandler) OnPhysicalSwitchDelete(oldPS vtepdb.TablePhysicalSwitch) {
+	if h.ready() && physicalSwitchNotifyReady() {
+		physicalSwitchRemove(oldPS)
+	}
+}
 
+func physicalSwitchCreate(tablePS vtepdb.TablePhysicalSwitch) {
 	psIndex := vtepdb.PhysicalSwitchIndex{
 		Name: tablePS.Name,
 	}
@@ -226,6 +239,9 @@ func physicalSwitchCreate(row libovsdb.Row) {
 				vtepdb.LocatorDelByIndex(locatorIndex)
 			}
 		}
+		if tablePS.GatewayGroup == true {
+			gatewayGroupStart(tablePS)
+		}
 
 		return
 
@@ -274,17 +290,23 @@ func physicalSwitchCreate(row libovsdb.Row) {
 		log.Warning("%v\n", err)
 		return
 	}
+	if tablePS.GatewayGroup == true {
+		gatewayGroupStart(tablePS)
+	}
 }
 
-func physicalSwitchRemove(row libovsdb.Row) {
+func physicalSwitchRemove(tablePS vtepdb.TablePhysicalSwitch) {
 	var err error
 
-	tablePS := vtepdb.ConvertRowToPhysicalSwitch(row.Fields)
 	if tablePS.SystemID == "" {
 		log.Error("Physical Switch Group %s System ID not configured, ignored chassis update\n", tablePS.Name)
 		return
 	}
 
+	if tablePS.GatewayGroup == true {
+		gatewayGroupStop(tablePS)
+	}
+
 	chassisIndex := ovnsb.ChassisIndex{
 		Name: tablePS.SystemID,
 	}
@@ -330,26 +352,36 @@ func physicalSwitchRemove(row libovsdb.Row) {
 
 		if err == nil {
 			vtepdb.LocatorDelByIndex(locatorIndex)
-			GatewayInitDone = false
-			vnetRemoveAll()
+			if gatewayDown(tablePS.SystemID) {
+				vnetRemoveAll()
+			}
 		}
 	}
 
 	return
 }
 
-func physicalSwitchUpdate(newrow libovsdb.Row, oldrow libovsdb.Row) {
+func physicalSwitchUpdate(oldPS, newPS vtepdb.TablePhysicalSwitch, changed []string) {
 	var err error
+	var oldValue interface{}
 
-	for field, oldValue := range oldrow.Fields {
+	for _, field := range changed {
 		switch field {
 		case vtepdb.PhysicalSwitchFieldSystemID:
 			// invalid systemID should not changed
-			err = physicalSwitchUpdateSystemID(newrow, oldValue.(string))
+			oldValue = oldPS.SystemID
+			err = physicalSwitchUpdateSystemID(newPS, oldPS.SystemID)
 		case vtepdb.PhysicalSwitchFieldEncapIP:
-			err = physicalSwitchUpdateEncapIP(newrow, oldValue.(string))
+			oldValue = oldPS.EncapIP
+			err = physicalSwitchUpdateEncapIP(newPS, oldPS.EncapIP)
 		case vtepdb.PhysicalSwitchFieldRouterMac:
-			err = physicalSwitchUpdateRouteMac(newrow, oldValue.(string))
+			oldValue = oldPS.RouterMac
+			err = physicalSwitchUpdateRouteMac(newPS, oldPS.RouterMac)
+		case vtepdb.PhysicalSwitchFieldOtherConfig:
+			oldValue = oldPS.OtherConfig
+			err = physicalSwitchUpdateOtherConfig(newPS)
+		case vtepdb.PhysicalSwitchFieldPorts:
+			physicalParentPortRetry(newPS.Name)
 		default:
 			// Don't care about other field update
 			continue
@@ -473,18 +505,15 @@ func chassisDelete(chassis Chassis) error {
 	return nil
 }
 
-func physicalSwitchUpdateSystemID(newrow libovsdb.Row, oldValue string) error {
+func physicalSwitchUpdateSystemID(tablePS vtepdb.TablePhysicalSwitch, oldValue string) error {
 	// 1. Get chassis, if not exist, then ignore EncapIP update.
 	// no need to store encapIP to SB, when update ps systemID create chassis
 	// the EncapIP will also carried in newRow
-	systemID, ok := newrow.Fields["system_id"].(string)
-	if !ok {
-		return fmt.Errorf("Physical Switch system_id get failed")
-	}
+	systemID := tablePS.SystemID
 
 	if len(oldValue) == 0 {
 		// add system_id
-		physicalSwitchCreate(newrow)
+		physicalSwitchCreate(tablePS)
 	} else if len(systemID) == 0 {
 		// delete system_id
 		chassisName := oldValue
@@ -503,16 +532,14 @@ func physicalSwitchUpdateSystemID(newrow libovsdb.Row, oldValue string) error {
 	return nil
 }
 
-func physicalSwitchUpdateEncapIP(newrow libovsdb.Row, oldValue string) error {
-	tablePS := vtepdb.ConvertRowToPhysicalSwitch(newrow.Fields)
-
+func physicalSwitchUpdateEncapIP(tablePS vtepdb.TablePhysicalSwitch, oldValue string) error {
 	// 1. Get chassis, if not exist, then ignore EncapIP update.
 	// no need to store encapIP to SB, when update ps systemID create chassis
 	// the EncapIP will also carried in newRow
 	if len(tablePS.SystemID) == 0 {
 		// if the old configured invalid ip, create new chassis
 		if net.ParseIP(oldValue) == nil {
-			physicalSwitchCreate(newrow)
+			physicalSwitchCreate(tablePS)
 			return nil
 		}
 
@@ -563,16 +590,14 @@ func physicalSwitchUpdateEncapIP(newrow libovsdb.Row, oldValue string) error {
 	return nil
 }
 
-func physicalSwitchUpdateRouteMac(newrow libovsdb.Row, oldValue string) error {
-	tablePS := vtepdb.ConvertRowToPhysicalSwitch(newrow.Fields)
-
+func physicalSwitchUpdateRouteMac(tablePS vtepdb.TablePhysicalSwitch, oldValue string) error {
 	// 1. Get chassis, if not exist, then ignore EncapIP update.
 	// no need to store encapIP to SB, when update ps systemID create chassis
 	// the EncapIP will also carried in newRow
 	if len(tablePS.SystemID) == 0 {
 		_, err := net.ParseMAC(oldValue)
 		if err != nil {
-			physicalSwitchCreate(newrow)
+			physicalSwitchCreate(tablePS)
 			return nil
 		}
 
@@ -620,3 +645,17 @@ func physicalSwitchUpdateRouteMac(newrow libovsdb.Row, oldValue string) error {
 
 	return nil
 }
+
+// physicalSwitchUpdateOtherConfig gateway priority in other_config of
+// gateway group member changed
+func physicalSwitchUpdateOtherConfig(tablePS vtepdb.TablePhysicalSwitch) error {
+	if tablePS.GatewayGroup == false || len(tablePS.SystemID) == 0 {
+		return nil
+	}
+
+	if _, err := ovnsb.ChassisGetByIndex(ovnsb.ChassisIndex{Name: tablePS.SystemID}); err != nil {
+		return fmt.Errorf("Chassis %s not exist", tablePS.SystemID)
+	}
+	gatewayGroupStart(tablePS)
+	return nil
+}
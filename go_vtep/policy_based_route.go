@@ -2,6 +2,7 @@ package govtep
 
 import (
 	"fmt"
+	"sort"
 
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 
@@ -38,6 +39,8 @@ func getNexthopForLogicalIP(ip string, vrf string) (string, error) {
 	return nexthopIP, nil
 }
 
+// getNexthopGroupRemote remote vtep ips known by local locators, local
+// locators of all physical switches are merged
 func getNexthopGroupRemote() []string {
 	var nhs []string
 	var conditions []interface{}
@@ -49,12 +52,14 @@ func getNexthopGroupRemote() []string {
 			dbLocator := vtepdb.ConvertRowToLocator(row)
 			if dbLocator.LocalLocator == true {
 				for ip := range dbLocator.RmacMap {
-					nhs = append(nhs, ip.(string))
+					if !pbrNhGroupContains(nhs, ip.(string)) {
+						nhs = append(nhs, ip.(string))
+					}
 				}
-				break
 			}
 		}
 	}
+	sort.Strings(nhs)
 
 	return nhs
 }
@@ -157,10 +162,28 @@ func policyBasedRouteAdd(pbr PolicyBasedRoute) error {
 	}
 
 	log.Info("tablePBR %+v\n", tablePBR)
+	pbrIndex := vtepdb.PolicyBasedRouteIndex{
+		Vrf:      tablePBR.Vrf,
+		IP:       tablePBR.IP,
+		Port:     pbr.Port,
+		Protocol: tablePBR.Protocol[0],
+		Type:     tablePBR.Type,
+	}
+	dbPBR, err := vtepdb.PolicyBasedRouteGetByIndex(pbrIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.PolicyBasedRoute, dbPBR.UUID)
+		log.Info("PBR %+v already existed", tablePBR)
+		return warmRestartReconcile(fmt.Sprintf("PBR %+v", pbrIndex), dbPBR, tablePBR,
+			vtepdb.PolicyBasedRouteFieldMapToColumn, []string{"NhVrf", "LogicalIps", "NhGroup"},
+			func(column string, value interface{}) error {
+				return vtepdb.PolicyBasedRouteSetField(pbrIndex, column, value)
+			})
+	}
+
 	vrfIndex := vtepdb.VrfIndex{
 		Name: pbr.Vrf,
 	}
-	err := vtepdb.VrfUpdateAddPbr(vrfIndex, tablePBR)
+	err = vtepdb.VrfUpdateAddPbr(vrfIndex, tablePBR)
 	if err != nil {
 		log.Info("PBR %+v already existed", tablePBR)
 		return nil
@@ -3,6 +3,7 @@ package govtep
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
@@ -40,6 +41,7 @@ const (
 	PbOptionPhysicalSwitch      = "physical_switch"
 	PbOptionPhysicalSwitchGroup = "physical_switch_group"
 	PbOptionPhysicalParentPort  = "physical_parent_port"
+	PbOptionInnerVlanTag        = "inner_vlantag"
 )
 
 // Logical port type with location
@@ -54,6 +56,11 @@ const (
 	LRPACRemote
 	LRPPatchLSP
 	LRPPatchLRP
+	LSPL3Gateway
+	LRPL3Gateway
+	LRPChassisRedirect
+	LSPExternal
+	LSPVirtual
 )
 
 // PortInfo attributes
@@ -63,12 +70,15 @@ type PortInfo struct {
 	LnType            string // logical_switch or logical_router
 	VnetTunnelKey     int    // SB.Datapath_Binding.tunnel_key
 	LogicalParentPort string
-	VlanTag           int
+	VlanTag           int // outer tag of QinQ port
+	InnerVlanTag      int
+	VlanTransparent   bool     // physical parent port passing all tags
 	Ipv4addr          []string // SB.Port_Binding.mac
 	Ipv6addr          []string // SB.Port_Binding.mac
 	Mac               []string // SB.Port_Binding.mac
 	Nat               []string // SB.Port_Binding.nat_addresses
 	Type              string   // logical port type, SB.Port_Binding.type, eg: "patch","localnet","vtep","localport"...
+	DistributedPort   string   // distributed gateway port of chassisredirect port
 	Peer              string   // patch peer,logical port name
 	PeerLtype         string
 	PeerPort          string
@@ -89,39 +99,23 @@ type PortInfo struct {
 	FailureReason     string
 }
 
-func isBelong(ip, cidr string) bool {
-	ipAddr := strings.Split(ip, `.`)
-	if len(ipAddr) < 4 {
+// cidrsContain ipv4 address belongs to one of space separated cidrs,
+// cidrs of other families are skipped
+func cidrsContain(cidrs string, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.To4() == nil {
 		return false
 	}
-	cidrArr := strings.Split(cidr, `/`)
-	if len(cidrArr) < 2 {
-		return false
-	}
-	addrInt1 := strconv.FormatInt(ipAddrToInt(ip), 2)
-	addrInt2 := strconv.FormatInt(ipAddrToInt(cidrArr[0]), 2)
-	mask, _ := strconv.Atoi(cidrArr[1])
-	for i := 0; i < len(addrInt1)+mask-32; i++ {
-		if addrInt1[i] != addrInt2[i] {
-			return false
+	for _, cidr := range strings.Fields(cidrs) {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		if ipNet.Contains(addr) {
+			return true
 		}
 	}
-	return true
-}
-
-func ipAddrToInt(ipAddr string) int64 {
-	bits := strings.Split(ipAddr, ".")
-	b0, _ := strconv.Atoi(bits[0])
-	b1, _ := strconv.Atoi(bits[1])
-	b2, _ := strconv.Atoi(bits[2])
-	b3, _ := strconv.Atoi(bits[3])
-	var sum int64
-	sum += int64(b0) << 24
-	sum += int64(b1) << 16
-	sum += int64(b2) << 8
-	sum += int64(b3)
-
-	return sum
+	return false
 }
 
 var portFailureChains map[interface{}]portFailureChain
@@ -176,7 +170,7 @@ func portProcFailureChain(chain int) {
 }
 
 func portbindingNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate, pbUUID string) {
-	if false == GatewayInitDone {
+	if !gatewayInitDone() {
 		log.Info("Gateway not init yet")
 		return
 	}
@@ -184,6 +178,10 @@ func portbindingNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate, pbUUID str
 	switch op {
 	case odbc.OpInsert:
 		portbindingCreate(rowUpdate.New, pbUUID)
+		if pType, _ := rowUpdate.New.Fields[ovnsb.PortBindingFieldType].(string); pType == PortTypeVtep {
+			// bind vtep port of local physical switch
+			vlanBindingSync()
+		}
 	case odbc.OpDelete:
 		portbindingRemove(rowUpdate.Old, pbUUID)
 	case odbc.OpUpdate:
@@ -191,6 +189,36 @@ func portbindingNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate, pbUUID str
 	}
 }
 
+// portbindingStandbyUpdate standby instance keeps port info of port
+// bindings without writing vtepdb, ports changed or removed before takeover
+// are processed with the cached info
+func portbindingStandbyUpdate(op string, rowUpdate libovsdb.RowUpdate, pbUUID string) {
+	switch op {
+	case odbc.OpInsert, odbc.OpUpdate:
+		port := PortbindingParser(rowUpdate.New)
+		portInfoMap[pbUUID] = port
+		portType[pbUUID] = definePortProcBranch(port)
+	case odbc.OpDelete:
+		delete(portType, pbUUID)
+		delete(portInfoMap, pbUUID)
+	}
+}
+
+// portbindingStandbySync cached port info of standby instance replaced by
+// port bindings of snapshot
+func portbindingStandbySync(rows map[string]libovsdb.RowUpdate) {
+	for pbUUID := range portInfoMap {
+		if _, ok := rows[pbUUID]; !ok {
+			delete(portType, pbUUID)
+			delete(portInfoMap, pbUUID)
+		}
+	}
+	for pbUUID, rowUpdate := range rows {
+		odbc.Float64ToInt(rowUpdate.New)
+		portbindingStandbyUpdate(odbc.OpInsert, rowUpdate, pbUUID)
+	}
+}
+
 func definePortProcBranch(port PortInfo) int {
 	if port.LnType == DatapathTypeLS {
 		if port.Type == "" {
@@ -216,9 +244,51 @@ func definePortProcBranch(port PortInfo) int {
 		if port.Type == "localnet" {
 			return LSPLocalNET
 		}
+
+		// gateway router port of remote chassis is reached like remote AC
+		if port.Type == PortTypeL3Gateway {
+			if port.Location == LocationLocal {
+				return LSPL3Gateway
+			}
+
+			if port.Location == LocationRemote {
+				return LSPACRemote
+			}
+		}
+
+		// external port of bare-metal is attached to active HA chassis
+		if port.Type == PortTypeExternal {
+			if port.Location == LocationLocal {
+				return LSPExternal
+			}
+
+			if port.Location == LocationRemote {
+				return LSPACRemote
+			}
+		}
+
+		if port.Type == PortTypeVirtual {
+			if port.Location == LocationLocal || port.Location == LocationRemote {
+				return LSPVirtual
+			}
+		}
+
+		if port.Type == PortTypeLocalport {
+			log.Info("Port %s of type localport is local to every chassis, skipped\n", port.LogicalPort)
+		}
 	}
 
 	if port.LnType == DatapathTypeLR {
+		if port.Type == PortTypeL3Gateway || port.Type == PortTypeChassisRedirect {
+			if port.Location == LocationLocal || port.Location == LocationRemote {
+				if port.Type == PortTypeL3Gateway {
+					return LRPL3Gateway
+				}
+				return LRPChassisRedirect
+			}
+			return 0
+		}
+
 		//if port.Type == "" {
 		if port.Location == LocationLocal {
 			return LRPACLocal
@@ -243,13 +313,70 @@ func definePortProcBranch(port PortInfo) int {
 	return 0
 }
 
+// portTypeCreate ports of OVN port types other than patch and localnet
+func portTypeCreate(branch int, port PortInfo) error {
+	switch branch {
+	case LSPVirtual:
+		return virtualPortCreate(port)
+	}
+	return gatewayPortCreate(branch, port)
+}
+
+func portTypeRemove(branch int, port PortInfo) error {
+	switch branch {
+	case LSPVirtual:
+		return virtualPortRemove(port)
+	}
+	return gatewayPortRemove(branch, port)
+}
+
+// portVlanTags outer and inner vlan tag of port, inner tag is option
+// inner_vlantag of tagged port, or tag of port nested in a tagged parent
+// port. Nested port without physical parent port is on that of its parent.
+func portVlanTags(tablePB ovnsb.TablePortBinding, phyParentPort string) (int, int, string) {
+	var vlantag int
+	if len(tablePB.Tag) == 1 {
+		vlantag = tablePB.Tag[0]
+	}
+	if vlantag == 0 {
+		return 0, 0, phyParentPort
+	}
+
+	if tag, ok := tablePB.Options[PbOptionInnerVlanTag].(string); ok {
+		inner, err := strconv.Atoi(tag)
+		if err == nil && inner >= vtepdb.L2portInnerVlantagMin && inner <= vtepdb.L2portInnerVlantagMax {
+			return vlantag, inner, phyParentPort
+		}
+		log.Warning("Port %s invalid inner vlan tag %s ignored\n", tablePB.LogicalPort, tag)
+	}
+
+	if len(tablePB.ParentPort) == 1 {
+		parentIndex := ovnsb.PortBindingIndex1{
+			LogicalPort: tablePB.ParentPort[0],
+		}
+		tableParent, err := ovnsb.PortBindingGetByIndex(parentIndex)
+		if err != nil {
+			log.Warning("Parent port %s of %s not found\n", parentIndex.LogicalPort, tablePB.LogicalPort)
+			return vlantag, 0, phyParentPort
+		}
+		if phyParentPort == "" {
+			phyParentPort, _ = tableParent.Options[PbOptionPhysicalParentPort].(string)
+		}
+		if len(tableParent.Tag) == 1 {
+			return tableParent.Tag[0], vlantag, phyParentPort
+		}
+	}
+	return vlantag, 0, phyParentPort
+}
+
 // PortbindingParser OVNSB logical port binding table row TO PortInfo struct
 func PortbindingParser(row libovsdb.Row) PortInfo {
 	var (
 		logicalPort, datapath            string
 		lnType, lnName, pType            string
 		vnetTunnelKey, portTunnelKey     int
-		vlantag                          int
+		vlantag, innerVlantag            int
+		vlanTransparent                  bool
 		peer, peerLtype, peerPort        string
 		peerPtype, peerBd, peerVrf       string
 		location                         = LocationUnknown
@@ -258,6 +385,7 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 		macaddr, ipv4addr, ipv6addr, nat []string
 		irb                              string
 		locator, logicalParentPort       string
+		distributedPort                  string
 	)
 
 	tablePortBinding := ovnsb.ConvertRowToPortBinding(libovsdb.ResultRow(row.Fields))
@@ -306,8 +434,9 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 		}
 	}
 
-	// do phsical switch chassis binding port
-	if phySwitch != "" && len(tablePortBinding.Chassis) == 0 {
+	// do phsical switch chassis binding port, standby parses port bindings
+	// for its caches only and binds nothing
+	if phySwitch != "" && len(tablePortBinding.Chassis) == 0 && odbc.IsActive() {
 		phsicalSwitchIndex := vtepdb.PhysicalSwitchIndex{
 			Name: phySwitch,
 		}
@@ -335,13 +464,44 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 		}
 	}
 
-	// Only support one tag in ovn for now
-	if len(tablePortBinding.Tag) == 1 {
-		vlantag = tablePortBinding.Tag[0]
+	// gateway router port and distributed gateway port are located by
+	// their gateway chassis, local gateway binds them when port created
+	if gwChassis := gatewayPortChassis(tablePortBinding); gwChassis != "" {
+		location = LocationRemote
+		locator, _ = getLocatorUUID(gwChassis)
+		if gatewayReady(gwChassis) {
+			location = LocationLocal
+		}
 	}
+	distributedPort, _ = options[PbOptionDistributedPort].(string)
 
-	if (pType == "") && (location == LocationLocal) {
-		name = getSubportName(phyParentPort, []int{vlantag})
+	// virtual port is located by its virtual parent answering for the VIP
+	if pType == PortTypeVirtual {
+		location, locator, macaddr = virtualPortParent(tablePortBinding)
+		if vip, _ := options[PbOptionVirtualIP].(string); len(ipv4addr) == 0 && len(ipv6addr) == 0 {
+			_, ipv4addr, ipv6addr = addressParser([]string{vip})
+		}
+	}
+
+	vlantag, innerVlantag, phyParentPort = portVlanTags(tablePortBinding, phyParentPort)
+	// vlan transparent port is untagged physical parent port of trunk
+	if transparent, ok := options[PbOptionVlanTransparent].(string); ok {
+		vlanTransparent, _ = strconv.ParseBool(transparent)
+	}
+	if vlanTransparent {
+		vlantag, innerVlantag = 0, 0
+	}
+
+	if (pType == "" || pType == PortTypeExternal) && (location == LocationLocal) {
+		// untagged native vlan port is physical parent port itself
+		var tags []int
+		if vlantag != 0 {
+			tags = append(tags, vlantag)
+		}
+		if innerVlantag != 0 {
+			tags = append(tags, innerVlantag)
+		}
+		name = getSubportName(phyParentPort, tags)
 	}
 
 	if peer, ok := options["peer"].(string); ok {
@@ -356,7 +516,7 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 			peerVrf = _v
 		}
 
-		if pType == "patch" {
+		if pType == "patch" || pType == PortTypeL3Gateway {
 			if (lnType == DatapathTypeLR) && (peerLtype == PortLtypeLSP) {
 				name = getBdifportName(peerBd)
 			} else {
@@ -365,7 +525,8 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 		}
 	}
 
-	if (lnType == DatapathTypeLS) && (pType == "") {
+	if (lnType == DatapathTypeLS) && (pType == "" || pType == PortTypeL3Gateway ||
+		pType == PortTypeExternal || pType == PortTypeVirtual) {
 		irb = getBdifportName(bd)
 	}
 
@@ -376,11 +537,14 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 		VnetTunnelKey:     vnetTunnelKey,
 		LogicalParentPort: logicalParentPort,
 		VlanTag:           vlantag,
+		InnerVlanTag:      innerVlantag,
+		VlanTransparent:   vlanTransparent,
 		Ipv4addr:          ipv4addr,
 		Ipv6addr:          ipv6addr,
 		Mac:               macaddr,
 		Nat:               nat,
 		Type:              pType,
+		DistributedPort:   distributedPort,
 		Peer:              peer,
 		PeerLtype:         peerLtype,
 		PeerPort:          peerPort,
@@ -402,9 +566,19 @@ func PortbindingParser(row libovsdb.Row) PortInfo {
 	return port
 }
 
+// portbindLocalNET bind localnet port to chassis of its physical switch,
+// or to the first local locator when port names no physical switch
 func portbindLocalNET(port PortInfo) error {
 	var conditions []interface{}
 	conditions = append(conditions, libovsdb.NewCondition("local_locator", "==", true))
+	if port.PhySwitch != "" {
+		tablePS, err := vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex{Name: port.PhySwitch})
+		if err != nil {
+			log.Error("Physical switch %s of port %s not found.\n", port.PhySwitch, port.LogicalPort)
+			return err
+		}
+		conditions = append(conditions, libovsdb.NewCondition("chassis_name", "==", tablePS.SystemID))
+	}
 	row, _ := vtepdb.LocatorGet(conditions)
 	if len(row) == 0 {
 		log.Error("Get the value of local_locator failed.\n")
@@ -455,10 +629,14 @@ func clearAutoGatewayConfBdValue(port PortInfo) error {
 		Vrf: tableAGC.Vrf,
 	}
 
+	// uplinks from configure are kept, addresses of localnet are cleared
 	tableAutoGatewayConf := vtepdb.TableAutoGatewayConf{
 		UUID:         tableAGC.UUID,
 		PhysicalPort: tableAGC.PhysicalPort,
 		Vrf:          tableAGC.Vrf,
+		Mtu:          tableAGC.Mtu,
+		Nexthops:     tableAGC.Nexthops,
+		Uplinks:      tableAGC.Uplinks,
 	}
 
 	err := vtepdb.AutoGatewayConfSet(agcIndex, tableAutoGatewayConf)
@@ -531,16 +709,25 @@ func addBdToAutoGatewayConf(port PortInfo) error {
 		return err
 	}
 
+	var tableLsps []ovnnb.TableLogicalSwitchPort
+	var networkName string
 	for _, portUUID := range tableLs.Ports {
 		tableLsp, err := ovnnb.LogicalSwitchPortGetByUUID(portUUID.GoUUID)
 		if err != nil {
 			log.Error("Get logical switch port table failed.\n")
 			return err
 		}
+		if tableLsp.Type == "localnet" {
+			networkName, _ = tableLsp.Options["network_name"].(string)
+		}
+		tableLsps = append(tableLsps, tableLsp)
+	}
+
+	for _, tableLsp := range tableLsps {
 		_, cidrIsExisted := tableLsp.ExternalIds["neutron:cidrs"]
 		if cidrIsExisted {
 			ipaddress := (tableLsp.ExternalIds["neutron:cidrs"]).(string)
-			if isBelong(port.Ipv4addr[0], ipaddress) {
+			if cidrsContain(ipaddress, port.Ipv4addr[0]) {
 				Vrfname, errVrf := getexternalVrf(port)
 				if errVrf != nil {
 					log.Warning("Vrf did't exist.\n")
@@ -559,7 +746,11 @@ func addBdToAutoGatewayConf(port PortInfo) error {
 
 				tableAGC.Bdname = port.Bd
 				tableAGC.Vlan = port.VlanTag
-				tableAGC.IP = ipaddress
+				tableAGC.IP, tableAGC.Ipv6 = gatewayAddrs(ipaddress)
+				// uplink of provider network overrides uplink of vrf
+				if conf, ok := uplinkConfGet(networkName, Vrfname); ok {
+					conf.apply(&tableAGC)
+				}
 
 				errSet := vtepdb.AutoGatewayConfSet(agcIndex, tableAGC)
 				if errSet != nil {
@@ -685,6 +876,9 @@ func portGenRouteSet(port PortInfo) []Route {
 			RemoteLocator: port.Locator,
 			Policy:        RoutePolicyDefault,
 		}
+		if err = routeLocatorNexthop(&rt); err != nil {
+			log.Warning("%v\n", err)
+		}
 		rts = append(rts, rt)
 	}
 	for _, ipv6addr := range port.Ipv6addr {
@@ -702,6 +896,9 @@ func portGenRouteSet(port PortInfo) []Route {
 			RemoteLocator: port.Locator,
 			Policy:        RoutePolicyDefault,
 		}
+		if err = routeLocatorNexthop(&rt); err != nil {
+			log.Warning("%v\n", err)
+		}
 		rts = append(rts, rt)
 	}
 	return rts
@@ -793,6 +990,13 @@ func portbindingCreate(row libovsdb.Row, pbUUID string) {
 			portAddToFailureChain(LRPPatchLRP, port)
 			return
 		}
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		err := portTypeCreate(procBranch, port)
+		if err != nil {
+			log.Warning("portTypeCreate failed %v\n", err)
+			portAddToFailureChain(procBranch, port)
+			return
+		}
 	default:
 		return
 	}
@@ -834,6 +1038,8 @@ func portbindingRemove(row libovsdb.Row, pbUUID string) {
 		err = l3portRemove(port)
 	case LRPPatchLRP:
 		err = l3portRemove(port)
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		err = portTypeRemove(procBranch, port)
 	}
 
 	if err != nil {
@@ -847,6 +1053,7 @@ func portbindingRemove(row libovsdb.Row, pbUUID string) {
 
 func portbindingUpdate(newrow libovsdb.Row, oldrow libovsdb.Row, pbUUID string) {
 	var err error
+	chassisChanged := false
 
 	for field, oldValue := range oldrow.Fields {
 		log.Info("update field %s old-value %v\n", field, oldValue)
@@ -855,21 +1062,30 @@ func portbindingUpdate(newrow libovsdb.Row, oldrow libovsdb.Row, pbUUID string)
 			err = portbindingUpdateType(newrow, oldValue, pbUUID)
 		case ovnsb.PortBindingFieldMac:
 			err = portbindingUpdateMac(newrow, oldValue, pbUUID)
-		case ovnsb.PortBindingFieldChassis:
-			err = portbindingUpdateChassis(newrow, oldValue, pbUUID)
+		case ovnsb.PortBindingFieldChassis, ovnsb.PortBindingFieldVirtualParent:
+			// VIP of virtual port moves with its virtual parent like port
+			// moves with chassis, processed once for both fields
+			chassisChanged = true
+			continue
 		case ovnsb.PortBindingFieldOptions:
 			oldOptions := oldValue.(libovsdb.OvsMap).GoMap
 			err = portbindingUpdateOptions(newrow, oldOptions)
 		default:
 			continue
 		}
+		if err != nil {
+			log.Error("portbindingUpdate field %s failed %v\n", field, err)
+		}
 	}
 
-	portbindingUpdatePortBranch(newrow, pbUUID)
-
-	if err != nil {
-		log.Error("portbindingUpdate failed %v\n", err)
+	if chassisChanged {
+		err = portbindingUpdateChassis(newrow, oldrow.Fields[ovnsb.PortBindingFieldChassis], pbUUID)
+		if err != nil {
+			log.Error("portbindingUpdate chassis failed %v\n", err)
+		}
 	}
+
+	portbindingUpdatePortBranch(newrow, pbUUID)
 }
 
 func portbindingUpdatePortBranch(newrow libovsdb.Row, pbUUID string) error {
@@ -877,7 +1093,11 @@ func portbindingUpdatePortBranch(newrow libovsdb.Row, pbUUID string) error {
 	port := PortbindingParser(newrow)
 
 	procBranch := definePortProcBranch(port)
-	if portType[pbUUID] == procBranch {
+	// port is re-created when its interface changes with vlan tags, or it
+	// moves to another chassis in the same branch as gateway ports do
+	if portType[pbUUID] == procBranch && portInfoMap[pbUUID].Name == port.Name &&
+		portInfoMap[pbUUID].VlanTransparent == port.VlanTransparent &&
+		portInfoMap[pbUUID].Location == port.Location && portInfoMap[pbUUID].Locator == port.Locator {
 		return nil
 	}
 
@@ -902,6 +1122,8 @@ func portbindingUpdatePortBranch(newrow libovsdb.Row, pbUUID string) error {
 		l3portRemove(portInfoMap[pbUUID])
 	case LRPPatchLRP:
 		l3portRemove(portInfoMap[pbUUID])
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		portTypeRemove(portType[pbUUID], portInfoMap[pbUUID])
 	}
 
 	portType[pbUUID] = procBranch
@@ -954,6 +1176,11 @@ func portbindingUpdatePortBranch(newrow libovsdb.Row, pbUUID string) error {
 		if err != nil {
 			portAddToFailureChain(LRPPatchLRP, port)
 		}
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		err := portTypeCreate(procBranch, port)
+		if err != nil {
+			portAddToFailureChain(procBranch, port)
+		}
 	}
 
 	return nil
@@ -969,10 +1196,14 @@ func portbindingUpdateChassis(newrow libovsdb.Row, oldValue interface{}, pbUUID
 
 	// get new port branch
 	port := PortbindingParser(newrow)
+	oldPort := portInfoMap[pbUUID]
 	portInfoMap[pbUUID] = port
 
 	procBranch := definePortProcBranch(port)
 	if portType[pbUUID] == procBranch {
+		if procBranch == LSPVirtual {
+			return portbindingUpdateVirtualParent(oldPort, port)
+		}
 		log.Info("portbindingUpdateChassis proc branch not changed, ignore\n")
 		return nil
 	}
@@ -998,6 +1229,8 @@ func portbindingUpdateChassis(newrow libovsdb.Row, oldValue interface{}, pbUUID
 		l3portRemove(port)
 	case LRPPatchLRP:
 		l3portRemove(port)
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		portTypeRemove(portType[pbUUID], port)
 	}
 
 	portType[pbUUID] = procBranch
@@ -1048,6 +1281,11 @@ func portbindingUpdateChassis(newrow libovsdb.Row, oldValue interface{}, pbUUID
 		if err != nil {
 			portAddToFailureChain(LRPPatchLRP, port)
 		}
+	case LSPL3Gateway, LRPL3Gateway, LRPChassisRedirect, LSPExternal, LSPVirtual:
+		err := portTypeCreate(procBranch, port)
+		if err != nil {
+			portAddToFailureChain(procBranch, port)
+		}
 	}
 
 	return nil
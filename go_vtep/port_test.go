@@ -0,0 +1,28 @@
+package govtep
+
+import "testing"
+
+func TestCidrsContain(t *testing.T) {
+	tests := []struct {
+		cidrs string
+		ip    string
+		want  bool
+	}{
+		{"10.0.0.0/24", "10.0.0.5", true},
+		{"10.0.0.0/24", "10.0.1.5", false},
+		{"10.0.0.0/24 2001:db8::/64", "10.0.0.5", true},
+		{"2001:db8::/64 10.0.0.0/24", "10.0.0.5", true},
+		{"2001:db8::/64 10.0.0.0/24", "10.0.1.5", false},
+		{"2001:db8::/64", "10.0.0.5", false},
+		{"10.0.0.0/24 2001:db8::/64", "2001:db8::5", false},
+		{"192.168.0.0/16 10.0.0.0/8", "10.1.2.3", true},
+		{"invalid 10.0.0.0/8", "10.1.2.3", true},
+		{"", "10.1.2.3", false},
+		{"10.0.0.0/8", "", false},
+	}
+	for _, test := range tests {
+		if got := cidrsContain(test.cidrs, test.ip); got != test.want {
+			t.Errorf("cidrsContain(%q, %q) = %v, want %v", test.cidrs, test.ip, got, test.want)
+		}
+	}
+}
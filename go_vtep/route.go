@@ -20,6 +20,7 @@ type Route struct {
 	OutputPort    string //VTEP DB L3Port name
 	RemoteLocator string //VTEP DB Locator uuid
 	Policy        string //either dst−ip or src−ip
+	RouteMac      string //Remote locator router mac, inner dmac of symmetric IRB
 }
 
 // route policy type
@@ -53,6 +54,46 @@ func routeNhUpdateForLocator(locator string, nh string) error {
 	return err
 }
 
+// routeRmacUpdateForLocator update route mac of routes to remote locator
+// after router mac of its chassis changed
+func routeRmacUpdateForLocator(locator string, rmac string) error {
+	var err error
+
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition(vtepdb.RouteFieldRemoteLocator, "==", locator))
+	rows, num := vtepdb.RouteGet(conditions)
+	for i := 0; i < num; i++ {
+		tableRoute := vtepdb.ConvertRowToRoute(rows[i])
+		if tableRoute.RouteMac == rmac {
+			continue
+		}
+		rtIndex := vtepdb.RouteUUIDIndex{
+			UUID: tableRoute.UUID,
+		}
+		err = vtepdb.RouteSetField(rtIndex, vtepdb.RouteFieldRouteMac, rmac)
+		if err != nil {
+			log.Error("Route %s update route mac %s failed\n", tableRoute.IPPrefix, rmac)
+		}
+	}
+
+	return err
+}
+
+// routeLocatorNexthop set nexthop and route mac of route to remote
+// locator, locator first ip is the nexthop
+func routeLocatorNexthop(route *Route) error {
+	tableLocator, err := vtepdb.LocatorGetByUUID(route.RemoteLocator)
+	if err != nil {
+		return fmt.Errorf("Locator %s of route %s not exist", route.RemoteLocator, route.IPPrefix)
+	}
+	if len(tableLocator.Ipaddr) > 0 {
+		route.Nexthop = tableLocator.Ipaddr[0]
+	}
+	route.RouteMac = tableLocator.RouteMac
+	return nil
+}
+
 func routeCreate(route Route) error {
 	var err error
 
@@ -69,10 +110,22 @@ func routeCreate(route Route) error {
 		Vrf:      route.Vrf,
 		IPPrefix: route.IPPrefix,
 	}
-	_, err = vtepdb.RouteGetByIndex(rtIndex)
+	dbRoute, err := vtepdb.RouteGetByIndex(rtIndex)
 	if err == nil {
+		warmRestartClaim(vtepdb.Route, dbRoute.UUID)
 		log.Info("Route %+v already exist\n", rtIndex)
-		return nil
+		// encap of remote locator may be changed since last run
+		if route.RemoteLocator == "" {
+			return nil
+		}
+		dbRouteIndex := vtepdb.RouteUUIDIndex{UUID: dbRoute.UUID}
+		if dbRoute.Nexthop != route.Nexthop {
+			err = vtepdb.RouteSetField(dbRouteIndex, vtepdb.RouteFieldNexthop, route.Nexthop)
+		}
+		if err == nil && dbRoute.RouteMac != route.RouteMac {
+			err = vtepdb.RouteSetField(dbRouteIndex, vtepdb.RouteFieldRouteMac, route.RouteMac)
+		}
+		return err
 	}
 
 	tableRoute := vtepdb.TableRoute{
@@ -83,6 +136,7 @@ func routeCreate(route Route) error {
 		OutputPort:    route.OutputPort,
 		RemoteLocator: route.RemoteLocator,
 		Policy:        route.Policy,
+		RouteMac:      route.RouteMac,
 	}
 
 	err = vtepdb.VrfUpdateAddRoute(vrfIndex, tableRoute)
@@ -0,0 +1,240 @@
+package govtep
+
+import (
+	"fmt"
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// Port binding trunk options, vlan transparent port passes frames of all
+// tags of its physical parent port into its logical switch
+const (
+	PbOptionVlanTransparent = "vlan_transparent"
+)
+
+// trunkKey physical parent port of physical switch
+type trunkKey struct {
+	phySwitch string
+	port      string
+}
+
+// trunkVlan vlan tags of trunk member, outer tag 0 is the native vlan
+// member receiving untagged frames
+type trunkVlan struct {
+	vlantag      int
+	innerVlantag int
+}
+
+// overlap vlans can't be members of the same trunk, sub port of single tag
+// is the outer tag sub port of QinQ on the same vlan
+func (v trunkVlan) overlap(other trunkVlan) bool {
+	return v.vlantag == other.vlantag &&
+		(v.innerVlantag == other.innerVlantag || v.innerVlantag == 0 || other.innerVlantag == 0)
+}
+
+// trunkPending port conflicting with members of trunk and its fault on
+// physical parent port
+type trunkPending struct {
+	port  PortInfo
+	fault string
+}
+
+// trunkPort logical ports sharing a physical parent port, every tagged
+// member is in its own logical switch. Vlan transparent member is the
+// only member of its trunk. Pending ports are created again once a member
+// is removed.
+type trunkPort struct {
+	transparent string
+	members     map[trunkVlan]string
+	pending     map[string]trunkPending
+}
+
+// trunkPorts trunk of physical parent ports, members are added and removed
+// with l2port of local logical port
+var trunkPorts = struct {
+	mutex sync.Mutex
+	ports map[trunkKey]*trunkPort
+}{
+	ports: make(map[trunkKey]*trunkPort),
+}
+
+// conflict error of port conflicting with other members of trunk
+func (trunk *trunkPort) conflict(port PortInfo) error {
+	vlan := trunkVlan{vlantag: port.VlanTag, innerVlantag: port.InnerVlanTag}
+	if trunk.transparent != "" && trunk.transparent != port.LogicalPort {
+		return fmt.Errorf("port %s trunk %s is vlan transparent to port %s",
+			port.LogicalPort, port.PhyParentPort, trunk.transparent)
+	}
+	for member, lp := range trunk.members {
+		if lp == port.LogicalPort {
+			continue
+		}
+		if port.VlanTransparent {
+			return fmt.Errorf("port %s vlan transparent trunk %s has member %s",
+				port.LogicalPort, port.PhyParentPort, lp)
+		}
+		if member.overlap(vlan) {
+			return fmt.Errorf("port %s vlan %d/%d of trunk %s used by port %s",
+				port.LogicalPort, vlan.vlantag, vlan.innerVlantag, port.PhyParentPort, lp)
+		}
+	}
+	return nil
+}
+
+// has logical port is a member or pending port of trunk
+func (trunk *trunkPort) has(lp string) bool {
+	if _, ok := trunk.pending[lp]; ok {
+		return true
+	}
+	for _, member := range trunk.members {
+		if member == lp {
+			return true
+		}
+	}
+	return false
+}
+
+func trunkFault(port string, fault string, add bool) {
+	var err error
+	portIndex := vtepdb.PhysicalPortIndex{Name: port}
+	if add {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusAddvalue(portIndex, []string{fault})
+	} else {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusDelvalue(portIndex, []string{fault})
+	}
+	if err != nil {
+		log.Info("Physical_Port %s update fault %s failed %v\n", port, fault, err)
+	}
+}
+
+// trunkKeyGet trunk of port on its physical switch, switch of port bound
+// by chassis is got by chassis system id
+func trunkKeyGet(port PortInfo) trunkKey {
+	key := trunkKey{phySwitch: port.PhySwitch, port: port.PhyParentPort}
+	if tablePS, err := portPhysicalSwitch(port); err == nil {
+		key.phySwitch = tablePS.Name
+	}
+	return key
+}
+
+// trunkMemberAdd add logical port to trunk of its physical parent port,
+// port conflicting with other members is pending until they're removed
+func trunkMemberAdd(port PortInfo) error {
+	if port.PhyParentPort == "" {
+		return nil
+	}
+
+	trunkPorts.mutex.Lock()
+	defer trunkPorts.mutex.Unlock()
+
+	key := trunkKeyGet(port)
+	trunk, ok := trunkPorts.ports[key]
+	if !ok {
+		trunk = &trunkPort{
+			members: make(map[trunkVlan]string),
+			pending: make(map[string]trunkPending),
+		}
+		trunkPorts.ports[key] = trunk
+	}
+
+	old, pending := trunk.pending[port.LogicalPort]
+	err := trunk.conflict(port)
+	if pending && (err == nil || old.fault != err.Error()) {
+		trunkFault(port.PhyParentPort, old.fault, false)
+		delete(trunk.pending, port.LogicalPort)
+	}
+	if err != nil {
+		if _, ok := trunk.pending[port.LogicalPort]; !ok {
+			trunkFault(port.PhyParentPort, err.Error(), true)
+		}
+		trunk.pending[port.LogicalPort] = trunkPending{port: port, fault: err.Error()}
+		return err
+	}
+
+	for member, lp := range trunk.members {
+		if lp == port.LogicalPort {
+			delete(trunk.members, member)
+		}
+	}
+	vlan := trunkVlan{vlantag: port.VlanTag, innerVlantag: port.InnerVlanTag}
+	if port.VlanTransparent {
+		trunk.transparent = port.LogicalPort
+	}
+	trunk.members[vlan] = port.LogicalPort
+	log.Info("Trunk %s/%s add member %s vlan %d/%d transparent %v\n", key.phySwitch,
+		key.port, port.LogicalPort, vlan.vlantag, vlan.innerVlantag, port.VlanTransparent)
+	return nil
+}
+
+// trunkMemberRemove remove logical port from trunk of its physical parent
+// port, return pending ports of trunk to be created again
+func trunkMemberRemove(port PortInfo) []PortInfo {
+	if port.PhyParentPort == "" {
+		return nil
+	}
+
+	trunkPorts.mutex.Lock()
+	defer trunkPorts.mutex.Unlock()
+
+	// switch of port may be gone, trunk is found by its member
+	var key trunkKey
+	var trunk *trunkPort
+	for k, t := range trunkPorts.ports {
+		if k.port == port.PhyParentPort && t.has(port.LogicalPort) {
+			key, trunk = k, t
+			break
+		}
+	}
+	if trunk == nil {
+		return nil
+	}
+	if old, ok := trunk.pending[port.LogicalPort]; ok {
+		trunkFault(port.PhyParentPort, old.fault, false)
+		delete(trunk.pending, port.LogicalPort)
+	}
+	removed := false
+	for member, lp := range trunk.members {
+		if lp == port.LogicalPort {
+			delete(trunk.members, member)
+			removed = true
+		}
+	}
+	if trunk.transparent == port.LogicalPort {
+		trunk.transparent = ""
+	}
+	if len(trunk.members) == 0 && len(trunk.pending) == 0 {
+		delete(trunkPorts.ports, key)
+	}
+
+	if !removed {
+		return nil
+	}
+	var ports []PortInfo
+	for _, pending := range trunk.pending {
+		ports = append(ports, pending.port)
+	}
+	return ports
+}
+
+// trunkMemberRetry create pending ports of trunk again after a member
+// removed, pending vlan bindings are added again by vlan binding sync
+func trunkMemberRetry(ports []PortInfo) {
+	bindings := false
+	for _, port := range ports {
+		if port.Type == PortTypeVtep {
+			bindings = true
+			continue
+		}
+		if err := l2PortCreate(port); err != nil {
+			log.Info("Port %s still pending on trunk: %v\n", port.LogicalPort, err)
+			continue
+		}
+		log.Warning("Port %s created after trunk %s member removed\n", port.LogicalPort, port.PhyParentPort)
+	}
+	if bindings {
+		vlanBindingSync()
+	}
+}
@@ -0,0 +1,183 @@
+package govtep
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// UplinkConfFile default uplink configure file path and name, one external
+// network per line:
+//
+//	<network> [port=<port|lag>] [vlan=<vlan>] [mtu=<mtu>]
+//	          [nexthop=<ip>[,<ip>...]] [uplink=<interface>=<cidr>[,<cidr>...]]...
+//
+// network is provider network name of localnet, vrf name, or * for all
+// vrfs. port is the uplink with address of localnet, vlan 0 makes it a
+// routed port. uplink adds more routed port (<port>) or sub interface
+// (<port>.<vlan>) with its own addresses, default routes of vrf are ECMP
+// over all nexthops. Lines start with # are comments. File is loaded once
+// at first use, changes take effect after restart.
+var UplinkConfFile = "/etc/sonic/govtep/uplink.conf"
+
+// uplinkConfAll network name matching every vrf
+const uplinkConfAll = "*"
+
+// uplinkConf uplink configure of an external network
+type uplinkConf struct {
+	port     string
+	vlan     int // -1 vlan of localnet
+	mtu      int
+	nexthops []string
+	uplinks  map[string]string
+}
+
+func parseUplinkConf(fields []string) (uplinkConf, error) {
+	conf := uplinkConf{
+		vlan:    -1,
+		uplinks: make(map[string]string),
+	}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return conf, fmt.Errorf("invalid %q", field)
+		}
+		var err error
+		switch kv[0] {
+		case "port":
+			conf.port = kv[1]
+		case "vlan":
+			conf.vlan, err = strconv.Atoi(kv[1])
+			if err == nil && (conf.vlan < 0 || conf.vlan > 4095) {
+				err = fmt.Errorf("vlan %d out of range", conf.vlan)
+			}
+		case "mtu":
+			conf.mtu, err = strconv.Atoi(kv[1])
+			if err == nil && (conf.mtu < vtepdb.AutoGatewayConfMtuMin || conf.mtu > vtepdb.AutoGatewayConfMtuMax) {
+				err = fmt.Errorf("mtu %d out of range", conf.mtu)
+			}
+		case "nexthop":
+			for _, nh := range strings.Split(kv[1], ",") {
+				if net.ParseIP(nh) == nil {
+					return conf, fmt.Errorf("invalid nexthop %q", nh)
+				}
+				conf.nexthops = append(conf.nexthops, nh)
+			}
+		case "uplink":
+			uplink := strings.SplitN(kv[1], "=", 2)
+			if len(uplink) != 2 {
+				return conf, fmt.Errorf("uplink %q without address", kv[1])
+			}
+			for _, cidr := range strings.Split(uplink[1], ",") {
+				if _, _, err = net.ParseCIDR(cidr); err != nil {
+					return conf, err
+				}
+			}
+			conf.uplinks[uplink[0]] = uplink[1]
+		default:
+			return conf, fmt.Errorf("unknown key %q", kv[0])
+		}
+		if err != nil {
+			return conf, err
+		}
+	}
+	return conf, nil
+}
+
+// uplinkConfLoad uplink configures by network name, uplink configure file
+// may not exist
+func uplinkConfLoad() (map[string]uplinkConf, error) {
+	file, err := os.Open(UplinkConfFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	confs := make(map[string]uplinkConf)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		conf, err := parseUplinkConf(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", UplinkConfFile, lineNo, err)
+		}
+		confs[fields[0]] = conf
+	}
+	return confs, scanner.Err()
+}
+
+// uplinkConfs uplink configures loaded from UplinkConfFile
+var uplinkConfs struct {
+	once  sync.Once
+	confs map[string]uplinkConf
+}
+
+// uplinkConfGet uplink configure of the first network names configured,
+// configure for all vrfs at last
+func uplinkConfGet(names ...string) (uplinkConf, bool) {
+	uplinkConfs.once.Do(func() {
+		confs, err := uplinkConfLoad()
+		if err != nil {
+			log.Warning("Uplink configure load failed %v\n", err)
+		}
+		uplinkConfs.confs = confs
+	})
+	for _, name := range append(names, uplinkConfAll) {
+		if conf, ok := uplinkConfs.confs[name]; ok && name != "" {
+			return conf, true
+		}
+	}
+	return uplinkConf{}, false
+}
+
+// apply set uplink configure to auto gateway conf of vrf
+func (conf uplinkConf) apply(tableAGC *vtepdb.TableAutoGatewayConf) {
+	if conf.port != "" {
+		tableAGC.PhysicalPort = conf.port
+	}
+	if conf.vlan >= 0 {
+		tableAGC.Vlan = conf.vlan
+	}
+	tableAGC.Mtu = nil
+	if conf.mtu != 0 {
+		tableAGC.Mtu = []int{conf.mtu}
+	}
+	tableAGC.Nexthops = conf.nexthops
+	tableAGC.Uplinks = make(map[interface{}]interface{}, len(conf.uplinks))
+	for name, ips := range conf.uplinks {
+		tableAGC.Uplinks[name] = ips
+	}
+}
+
+// gatewayAddrs ipv4 and ipv6 gateway address of space separated cidrs
+func gatewayAddrs(cidrs string) (string, string) {
+	var ipv4, ipv6 string
+	for _, cidr := range strings.Fields(cidrs) {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ip.To4() != nil {
+			if ipv4 == "" {
+				ipv4 = cidr
+			}
+		} else if ipv6 == "" {
+			ipv6 = cidr
+		}
+	}
+	return ipv4, ipv6
+}
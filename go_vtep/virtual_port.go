@@ -0,0 +1,70 @@
+package govtep
+
+import (
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// Port types of VIP and chassis local port, virtual port VIP is claimed by
+// one of its virtual parents, localport is present on every chassis and
+// never bound
+const (
+	PortTypeVirtual   = "virtual"
+	PortTypeLocalport = "localport"
+)
+
+// Port binding virtual port options
+const (
+	PbOptionVirtualIP = "virtual-ip"
+)
+
+// virtualPortParent location, locator and mac of virtual parent of virtual
+// port, VIP is answered by virtual parent with its own mac. Location is
+// unknown before VIP claimed by a virtual parent.
+func virtualPortParent(tablePB ovnsb.TablePortBinding) (string, string, []string) {
+	if len(tablePB.VirtualParent) == 0 || tablePB.VirtualParent[0] == "" {
+		return LocationUnknown, "", nil
+	}
+
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition(ovnsb.PortBindingFieldLogicalPort, "==", tablePB.VirtualParent[0]))
+	rows, num := ovnsb.PortBindingGet(conditions)
+	if num == 0 {
+		log.Warning("Virtual parent %s of %s not found\n", tablePB.VirtualParent[0], tablePB.LogicalPort)
+		return LocationUnknown, "", nil
+	}
+	parent := PortbindingParser(libovsdb.Row{Fields: rows[0]})
+	return parent.Location, parent.Locator, parent.Mac
+}
+
+// virtualPortCreate VIP of virtual parent on remote chassis is a remote
+// neighbour with mac of virtual parent, VIP of local virtual parent is
+// learned from its ARP
+func virtualPortCreate(port PortInfo) error {
+	if port.Location != LocationRemote || len(port.Mac) == 0 {
+		return nil
+	}
+	return remoteNeighCreate(portGenRneighSet(port))
+}
+
+func virtualPortRemove(port PortInfo) error {
+	if port.Location != LocationRemote || len(port.Mac) == 0 {
+		return nil
+	}
+	return remoteNeighRemove(portGenRneighSet(port))
+}
+
+// portbindingUpdateVirtualParent VIP moved to another virtual parent in
+// branch LSPVirtual, VIP claimed or released changes branch of port
+func portbindingUpdateVirtualParent(oldPort PortInfo, port PortInfo) error {
+	log.Info("portbindingUpdateVirtualParent PortInfo %+v\n", port)
+
+	if err := virtualPortRemove(oldPort); err != nil {
+		log.Warning("virtualPortRemove failed %v\n", err)
+	}
+	return virtualPortCreate(port)
+}
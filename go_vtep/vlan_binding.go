@@ -0,0 +1,388 @@
+package govtep
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+	ovnnb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnnorthbound"
+	ovnsb "github.com/cn-pmlabs/govtep/lib/odbapi/ovnsouthbound"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+
+	"github.com/ebay/libovsdb"
+)
+
+// hardware_vtep workflow, Physical_Port.vlan_bindings binds vlan of port
+// to vtep Logical_Switch named as the OVN Logical_Switch. Every binding is
+// a L2Port in Bridge_Domain of the OVN switch, and every physical switch
+// with bindings of a logical switch is a vtep Logical_Switch_Port of it in
+// OVN bound to chassis of the physical switch.
+const (
+	PortTypeVtep               = "vtep"
+	PbOptionVtepPhysicalSwitch = "vtep-physical-switch"
+	PbOptionVtepLogicalSwitch  = "vtep-logical-switch"
+)
+
+// vlanBinding vlan of physical port bound to logical switch
+type vlanBinding struct {
+	psName string
+	port   string
+	vlan   int
+	lsName string
+}
+
+// vtepPort OVN vtep port of physical switch in logical switch
+type vtepPort struct {
+	psName string
+	lsName string
+}
+
+func (port vtepPort) name() string {
+	return fmt.Sprintf("vtep-%s-%s", port.psName, port.lsName)
+}
+
+// vlanBindings bindings programmed into vtepdb and OVN, binding failed is
+// retried on next sync
+var vlanBindings = struct {
+	mutex  sync.Mutex
+	l2port map[vlanBinding]string
+	faults map[vlanBinding]string
+	ports  map[vtepPort]bool
+}{
+	l2port: make(map[vlanBinding]string),
+	faults: make(map[vlanBinding]string),
+	ports:  make(map[vtepPort]bool),
+}
+
+func ovsdbInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// vlanBindingsConfigured bindings of Physical_Port vlan_bindings of every
+// local physical switch
+func vlanBindingsConfigured() map[vlanBinding]bool {
+	bindings := make(map[vlanBinding]bool)
+	vtepdb.PhysicalSwitchIterator(func(tablePS vtepdb.TablePhysicalSwitch) {
+		for _, uuid := range tablePS.Ports {
+			tablePort, err := vtepdb.PhysicalPortGetByUUID(uuid.GoUUID)
+			if err != nil {
+				continue
+			}
+			for key, value := range tablePort.VlanBindings {
+				vlan, ok := ovsdbInt(key)
+				lsUUID, _ := value.(libovsdb.UUID)
+				if !ok {
+					continue
+				}
+				tableLS, err := vtepdb.LogicalSwitchGetByUUID(lsUUID.GoUUID)
+				if err != nil {
+					continue
+				}
+				bindings[vlanBinding{
+					psName: tablePS.Name,
+					port:   tablePort.Name,
+					vlan:   vlan,
+					lsName: tableLS.Name,
+				}] = true
+			}
+		}
+	})
+	return bindings
+}
+
+// vlanBindingSync program bindings of vtepdb, called on vtepdb binding
+// changes, OVN logical switch creation or removal and vtep port binding
+// creation
+func vlanBindingSync() {
+	if !odbc.IsActive() || OvnCentralConnected == false {
+		return
+	}
+
+	vlanBindings.mutex.Lock()
+	defer vlanBindings.mutex.Unlock()
+
+	configured := vlanBindingsConfigured()
+	for binding, name := range vlanBindings.l2port {
+		if configured[binding] {
+			continue
+		}
+		if err := vlanBindingRemove(binding, name); err != nil {
+			log.Warning("Vlan binding %+v remove failed %v\n", binding, err)
+		}
+		delete(vlanBindings.l2port, binding)
+	}
+	for binding, fault := range vlanBindings.faults {
+		if !configured[binding] {
+			vlanBindingFault(binding, fault, false)
+			vlanBindingTrunkRemove(binding)
+			delete(vlanBindings.faults, binding)
+		}
+	}
+
+	ports := make(map[vtepPort]bool)
+	for binding := range configured {
+		// L2Port removed with its Bridge_Domain is added again
+		if name, ok := vlanBindings.l2port[binding]; ok {
+			if _, err := vtepdb.L2portGetByIndex(vtepdb.L2portIndex1{Name: name}); err != nil {
+				log.Info("Vlan binding %+v l2port %s removed, add again\n", binding, name)
+				delete(vlanBindings.l2port, binding)
+			}
+		}
+		if _, ok := vlanBindings.l2port[binding]; !ok {
+			name, err := vlanBindingAdd(binding)
+			fault := fmt.Sprintf("vlan %d: %v", binding.vlan, err)
+			if old, ok := vlanBindings.faults[binding]; ok && (err == nil || old != fault) {
+				vlanBindingFault(binding, old, false)
+				delete(vlanBindings.faults, binding)
+			}
+			if err != nil {
+				if _, ok := vlanBindings.faults[binding]; !ok {
+					log.Warning("Vlan binding %+v add failed %v\n", binding, err)
+					vlanBindingFault(binding, fault, true)
+					vlanBindings.faults[binding] = fault
+				}
+				continue
+			}
+			vlanBindings.l2port[binding] = name
+		}
+		ports[vtepPort{psName: binding.psName, lsName: binding.lsName}] = true
+	}
+
+	for port := range vlanBindings.ports {
+		if ports[port] {
+			continue
+		}
+		if err := vtepPortRemove(port); err != nil {
+			log.Warning("Vtep port %s remove failed %v\n", port.name(), err)
+		}
+		delete(vlanBindings.ports, port)
+	}
+	for port := range ports {
+		if err := vtepPortAdd(port); err != nil {
+			log.Warning("Vtep port %s add failed %v\n", port.name(), err)
+			continue
+		}
+		vlanBindings.ports[port] = true
+	}
+}
+
+func vlanBindingFault(binding vlanBinding, fault string, add bool) {
+	var err error
+	portIndex := vtepdb.PhysicalPortIndex{Name: binding.port}
+	if add {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusAddvalue(portIndex, []string{fault})
+	} else {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusDelvalue(portIndex, []string{fault})
+	}
+	if err != nil {
+		log.Info("Physical_Port %s update fault %s failed %v\n", binding.port, fault, err)
+	}
+}
+
+// logicalSwitchByName OVN Logical_Switch of name
+func logicalSwitchByName(name string) (ovnnb.TableLogicalSwitch, error) {
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.NewCondition("name", "==", name))
+	rows, num := ovnnb.LogicalSwitchGet(conditions)
+	if num != 1 {
+		return ovnnb.TableLogicalSwitch{}, fmt.Errorf("logical switch %s not found", name)
+	}
+	return ovnnb.ConvertRowToLogicalSwitch(rows[0]), nil
+}
+
+// logicalSwitchTunnelKey tunnel key of OVN logical switch datapath
+func logicalSwitchTunnelKey(lsUUID string) (int, error) {
+	tunnelKey := 0
+	ovnsb.DatapathBindingIterator(func(tableDp ovnsb.TableDatapathBinding) {
+		if name, _ := tableDp.ExternalIds[DatapathTypeLS].(string); name == lsUUID {
+			tunnelKey = tableDp.TunnelKey
+		}
+	})
+	if tunnelKey == 0 {
+		return 0, fmt.Errorf("datapath of logical switch %s not found", lsUUID)
+	}
+	return tunnelKey, nil
+}
+
+// vlanBindingAdd add L2Port of binding into Bridge_Domain of its logical
+// switch, return name of L2Port. Tunnel key of vtep Logical_Switch is set
+// to the OVN one.
+func vlanBindingAdd(binding vlanBinding) (string, error) {
+	tableLS, err := logicalSwitchByName(binding.lsName)
+	if err != nil {
+		return "", err
+	}
+	tunnelKey, err := logicalSwitchTunnelKey(tableLS.UUID)
+	if err != nil {
+		return "", err
+	}
+	bd := getBdNameByVni(tunnelKey)
+	if !bdIsExist(bd) {
+		return "", fmt.Errorf("bridge domain %s not found", bd)
+	}
+
+	lsIndex := vtepdb.LogicalSwitchIndex{Name: binding.lsName}
+	if tableVtepLS, err := vtepdb.LogicalSwitchGetByIndex(lsIndex); err == nil &&
+		(len(tableVtepLS.TunnelKey) != 1 || tableVtepLS.TunnelKey[0] != tunnelKey) {
+		if err = vtepdb.LogicalSwitchSetField(lsIndex, vtepdb.LogicalSwitchFieldTunnelKey, tunnelKey); err != nil {
+			log.Warning("Logical_Switch %s set tunnel key %d failed %v\n", binding.lsName, tunnelKey, err)
+		}
+	}
+
+	// vlan 0 binds untagged traffic of port
+	var vlantag []int
+	if binding.vlan != 0 {
+		vlantag = []int{binding.vlan}
+	}
+	name := getSubportName(binding.port, vlantag)
+	lspName := vtepPort{psName: binding.psName, lsName: binding.lsName}.name()
+	dbL2port, dbErr := vtepdb.L2portGetByIndex(vtepdb.L2portIndex1{Name: name})
+	if dbErr == nil && dbL2port.LogicalPort != lspName {
+		return "", fmt.Errorf("l2port %s used by port %s", name, dbL2port.LogicalPort)
+	}
+	if err = trunkMemberAdd(vlanBindingTrunkPort(binding)); err != nil {
+		return "", err
+	}
+	if dbErr == nil {
+		warmRestartClaim(vtepdb.L2port, dbL2port.UUID)
+		return name, nil
+	}
+
+	tableL2port := vtepdb.TableL2port{
+		Name:          name,
+		Bd:            bd,
+		LogicalPort:   lspName,
+		PhyparentPort: binding.port,
+		Physwitch:     binding.psName,
+		Vlantag:       vlantag,
+	}
+	if err = vtepdb.BridgeDomainUpdateAddL2ports(vtepdb.BridgeDomainIndex{Name: bd}, tableL2port); err != nil {
+		vlanBindingTrunkRemove(binding)
+		return "", err
+	}
+	log.Info("Vlan binding %+v add l2port %s in %s\n", binding, name, bd)
+	return name, nil
+}
+
+// vlanBindingTrunkPort trunk member of binding on its physical port, it's
+// named by its L2Port as bindings of a physical switch share vtep port
+func vlanBindingTrunkPort(binding vlanBinding) PortInfo {
+	var vlantag []int
+	if binding.vlan != 0 {
+		vlantag = []int{binding.vlan}
+	}
+	return PortInfo{
+		LogicalPort:   getSubportName(binding.port, vlantag),
+		Type:          PortTypeVtep,
+		PhySwitch:     binding.psName,
+		PhyParentPort: binding.port,
+		VlanTag:       binding.vlan,
+	}
+}
+
+// vlanBindingTrunkRemove remove binding from trunk of its physical port,
+// pending bindings are added again by the sync in progress
+func vlanBindingTrunkRemove(binding vlanBinding) {
+	var ports []PortInfo
+	for _, port := range trunkMemberRemove(vlanBindingTrunkPort(binding)) {
+		if port.Type != PortTypeVtep {
+			ports = append(ports, port)
+		}
+	}
+	trunkMemberRetry(ports)
+}
+
+func vlanBindingRemove(binding vlanBinding, name string) error {
+	vlanBindingTrunkRemove(binding)
+	tableL2port, err := vtepdb.L2portGetByIndex(vtepdb.L2portIndex1{Name: name})
+	if err != nil {
+		return nil
+	}
+	return vtepdb.BridgeDomainUpdateL2portsDelvalue(vtepdb.BridgeDomainIndex{Name: tableL2port.Bd},
+		[]libovsdb.UUID{{GoUUID: tableL2port.UUID}})
+}
+
+// vtepPortAdd add vtep Logical_Switch_Port of physical switch to OVN and
+// bind its Port_Binding to chassis of the switch once created by northd
+func vtepPortAdd(port vtepPort) error {
+	tablePS, err := vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex{Name: port.psName})
+	if err != nil {
+		return err
+	}
+	if _, err = ovnnb.LogicalSwitchPortGetByIndex(ovnnb.LogicalSwitchPortIndex{Name: port.name()}); err != nil {
+		tableLS, err := logicalSwitchByName(port.lsName)
+		if err != nil {
+			return err
+		}
+		tableLSP := ovnnb.TableLogicalSwitchPort{
+			Name:      port.name(),
+			Type:      PortTypeVtep,
+			Addresses: []string{"unknown"},
+			Options: map[interface{}]interface{}{
+				PbOptionVtepPhysicalSwitch: port.psName,
+				PbOptionVtepLogicalSwitch:  port.lsName,
+			},
+		}
+		err = ovnnb.LogicalSwitchUpdateAddPorts(ovnnb.LogicalSwitchUUIDIndex{UUID: tableLS.UUID}, tableLSP)
+		if err != nil {
+			return err
+		}
+		log.Warning("Vtep port %s added to logical switch %s\n", port.name(), port.lsName)
+	}
+
+	chassisIndex := ovnsb.ChassisIndex{Name: tablePS.SystemID}
+	tableChassis, err := ovnsb.ChassisGetByIndex(chassisIndex)
+	if err != nil {
+		return fmt.Errorf("chassis %s not found", tablePS.SystemID)
+	}
+	bound := false
+	for _, ls := range tableChassis.VtepLogicalSwitches {
+		bound = bound || ls == port.lsName
+	}
+	if !bound {
+		if err = ovnsb.ChassisUpdateVtepLogicalSwitchesAddvalue(chassisIndex, []string{port.lsName}); err != nil {
+			return err
+		}
+	}
+
+	pbIndex := ovnsb.PortBindingIndex1{LogicalPort: port.name()}
+	tablePB, err := ovnsb.PortBindingGetByIndex(pbIndex)
+	if err != nil {
+		// bound when northd created it
+		return nil
+	}
+	if len(tablePB.Chassis) == 1 && tablePB.Chassis[0].GoUUID == tableChassis.UUID {
+		return nil
+	}
+	return ovnsb.PortBindingSetField(pbIndex, ovnsb.PortBindingFieldChassis, libovsdb.UUID{GoUUID: tableChassis.UUID})
+}
+
+func vtepPortRemove(port vtepPort) error {
+	if tablePS, err := vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex{Name: port.psName}); err == nil {
+		chassisIndex := ovnsb.ChassisIndex{Name: tablePS.SystemID}
+		if err = ovnsb.ChassisUpdateVtepLogicalSwitchesDelvalue(chassisIndex, []string{port.lsName}); err != nil {
+			log.Warning("Chassis %s remove vtep logical switch %s failed %v\n", tablePS.SystemID, port.lsName, err)
+		}
+	}
+
+	tableLSP, err := ovnnb.LogicalSwitchPortGetByIndex(ovnnb.LogicalSwitchPortIndex{Name: port.name()})
+	if err != nil {
+		return nil
+	}
+	tableLS, err := logicalSwitchByName(port.lsName)
+	if err != nil {
+		return errors.New("logical switch of vtep port not found")
+	}
+	log.Warning("Vtep port %s removed from logical switch %s\n", port.name(), port.lsName)
+	return ovnnb.LogicalSwitchUpdatePortsDelvalue(ovnnb.LogicalSwitchUUIDIndex{UUID: tableLS.UUID},
+		[]libovsdb.UUID{{GoUUID: tableLSP.UUID}})
+}
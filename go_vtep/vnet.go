@@ -15,10 +15,12 @@ import (
 const (
 	DatapathTypeLS = "logical-switch"
 	DatapathTypeLR = "logical-router"
-
-	AutoGatewayConfDefaultPhysicalPort string = "ep60"
 )
 
+// AutoGatewayConfDefaultPhysicalPort uplink of vrf external network not
+// configured in uplink configure file
+var AutoGatewayConfDefaultPhysicalPort = "ep60"
+
 // Vnet type string
 const (
 	VnetTypeBD  = "bd"
@@ -26,7 +28,7 @@ const (
 )
 
 func datapathNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate, dpuuid string) {
-	if false == GatewayInitDone {
+	if !gatewayInitDone() {
 		log.Info("Gateway not init yet")
 		return
 	}
@@ -118,7 +120,9 @@ func vnetCreate(row libovsdb.Row, dpuuid string) {
 	tableDatapathBinding.UUID = dpuuid
 
 	if tableDatapathBinding.ExternalIds[DatapathTypeLS] != nil {
-		bridgeDomainAdd(tableDatapathBinding)
+		if bridgeDomainAdd(tableDatapathBinding) == nil {
+			vlanBindingSync()
+		}
 	} else if tableDatapathBinding.ExternalIds[DatapathTypeLR] != nil {
 		vrfAdd(tableDatapathBinding)
 	}
@@ -130,6 +134,8 @@ func vnetRemove(row libovsdb.Row) {
 
 	if tableDatapathBinding.ExternalIds[DatapathTypeLS] != nil {
 		bridgeDomainDel(vni)
+		// L2Ports of bindings are removed with the bridge domain
+		vlanBindingSync()
 	} else if tableDatapathBinding.ExternalIds[DatapathTypeLR] != nil {
 		vrfDel(vni)
 	}
@@ -143,18 +149,23 @@ func bridgeDomainAdd(tableDp ovnsb.TableDatapathBinding) error {
 	bdIndex := vtepdb.BridgeDomainIndex{
 		Name: getBdNameByVni(tableDp.TunnelKey),
 	}
-	_, err := vtepdb.BridgeDomainGetByIndex(bdIndex)
-	if err == nil {
-		log.Info("BridgeDomain %s already exist", bdIndex.Name)
-		return nil
-	}
-
 	tableBridgeDomain := vtepdb.TableBridgeDomain{
 		L2vni:    tableDp.TunnelKey,
 		Name:     getBdNameByVni(tableDp.TunnelKey),
 		Datapath: tableDp.UUID,
 		Lsname:   tableDp.ExternalIds[DatapathTypeLS].(string),
 	}
+	dbBd, err := vtepdb.BridgeDomainGetByIndex(bdIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.BridgeDomain, dbBd.UUID)
+		log.Info("BridgeDomain %s already exist", bdIndex.Name)
+		return warmRestartReconcile("BridgeDomain "+bdIndex.Name, dbBd, tableBridgeDomain,
+			vtepdb.BridgeDomainFieldMapToColumn, []string{"Datapath", "Lsname"},
+			func(column string, value interface{}) error {
+				return vtepdb.BridgeDomainSetField(bdIndex, column, value)
+			})
+	}
+
 	_, err = vtepdb.BridgeDomainAdd(tableBridgeDomain)
 	if err != nil {
 		log.Error("BridgeDomainAdd %s failed : %v", tableBridgeDomain.Name, err)
@@ -177,18 +188,23 @@ func vrfAdd(tableDp ovnsb.TableDatapathBinding) error {
 	vrfIndex := vtepdb.VrfIndex{
 		Name: getVrfNameByVni(tableDp.TunnelKey),
 	}
-	_, err := vtepdb.VrfGetByIndex(vrfIndex)
-	if err == nil {
-		log.Info("Vrf %s already exist", vrfIndex.Name)
-		return nil
-	}
-
 	tableVrf := vtepdb.TableVrf{
 		L3vni:    tableDp.TunnelKey,
 		Name:     getVrfNameByVni(tableDp.TunnelKey),
 		Datapath: tableDp.UUID,
 		Lrname:   tableDp.ExternalIds[DatapathTypeLR].(string),
 	}
+	dbVrf, err := vtepdb.VrfGetByIndex(vrfIndex)
+	if err == nil {
+		warmRestartClaim(vtepdb.Vrf, dbVrf.UUID)
+		log.Info("Vrf %s already exist", vrfIndex.Name)
+		return warmRestartReconcile("Vrf "+vrfIndex.Name, dbVrf, tableVrf,
+			vtepdb.VrfFieldMapToColumn, []string{"Datapath", "Lrname"},
+			func(column string, value interface{}) error {
+				return vtepdb.VrfSetField(vrfIndex, column, value)
+			})
+	}
+
 	_, err = vtepdb.VrfAdd(tableVrf)
 	if err != nil {
 		log.Error("VrfAdd %s failed : %v", tableVrf.Name, err)
@@ -198,6 +214,9 @@ func vrfAdd(tableDp ovnsb.TableDatapathBinding) error {
 		PhysicalPort: AutoGatewayConfDefaultPhysicalPort,
 		Vrf:          tableVrf.Name,
 	}
+	if conf, ok := uplinkConfGet(tableVrf.Name); ok {
+		conf.apply(&tableAutoGatewayConf)
+	}
 
 	err = vtepdb.VrfUpdateAddGatewayConf(vrfIndex, tableAutoGatewayConf)
 	if err != nil {
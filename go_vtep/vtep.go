@@ -2,6 +2,7 @@ package govtep
 
 import (
 	"math"
+	"sync"
 	"time"
 
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
@@ -20,6 +21,10 @@ var (
 	OvnCentralConnected bool = false
 )
 
+// vtepProcessMutex serializes processing of db updates, recompute and role
+// takeover, they share the port caches
+var vtepProcessMutex sync.Mutex
+
 type ovsdbc struct {
 	odbc.OvsdbC
 }
@@ -55,6 +60,9 @@ var sbDBClient = ovsdbc{
 			ovnsb.DatapathBinding,
 			ovnsb.PortBinding,
 			ovnsb.MacBinding,
+			ovnsb.GatewayChassis,
+			ovnsb.HaChassis,
+			ovnsb.HaChassisGroup,
 		},
 	},
 }
@@ -70,12 +78,16 @@ var vtepDBClient = ovsdbc{
 		MonitorTables: []string{
 			vtepdb.Global,
 			vtepdb.PhysicalSwitch,
+			vtepdb.PhysicalPort,
+			vtepdb.LogicalSwitch,
 		},
 	},
 }
 
 // NewVtepDbClient connect to VTEP DB
 func NewVtepDbClient() {
+	odbc.RegisterRoleHandler("govtep", vtepRoleChange)
+
 	// init vtepdb lib, disconnect handler todo
 	vtepdb.InitControllervtep(odbc.VtepdbAddr)
 
@@ -88,7 +100,9 @@ func NewVtepDbClient() {
 		log.Warning("Connect ovsdb %s successed\n", vtepDBClient.Db)
 		initial, _ := vtepDBClient.MonitorDbTables(vtepDBClient.Db, vtepDBClient.
 			MonitorAll, vtepDBClient.MonitorTables, "")
+		vtepProcessMutex.Lock()
 		vtepDBClient.vtepDbNotifyUpdate(*initial)
+		vtepProcessMutex.Unlock()
 		notifier := vtepDbNotifier{&vtepDBClient}
 		vtepDBClient.Client.Register(notifier)
 	}
@@ -109,10 +123,12 @@ func (c *ovsdbc) vtepDBReConnect() {
 			if err == nil && client != nil {
 				log.Warning("Reconnect ovsdb %s successed\n", c.Db)
 				c.Client = client
-				vtepdb.ControllervtepClient.Client = client
+				vtepdb.RegisterControllervtepClient(client)
 
 				initial, _ := c.MonitorDbTables(c.Db, c.MonitorAll, c.MonitorTables, "")
+				vtepProcessMutex.Lock()
 				c.vtepDbNotifyUpdate(*initial)
+				vtepProcessMutex.Unlock()
 				notifier := vtepDbNotifier{c}
 				c.Client.Register(notifier)
 
@@ -131,34 +147,77 @@ func (c *ovsdbc) vtepDBReConnect() {
 	}
 }
 
-func (c *ovsdbc) vtepDbNotifyUpdate(updates libovsdb.TableUpdates) {
-	var op string
-	for table, tableupdate := range updates.Updates {
-		for uuid, rowUpdate := range tableupdate.Rows {
-			rowUpdate = odbc.RowUpdateOptimize(rowUpdate, uuid)
-			op = odbc.GetRowUpdateOp(rowUpdate)
+// vtepdbHandler typed handler of vtepdb tables processed by controller,
+// it lives for one table updates
+type vtepdbHandler struct {
+	vlanBindingChanged bool
+}
 
-			log.Warning(">>> Vtep Table %s rowuuid %s op %s\n", table, uuid, op)
+// ready tables other than Global are not processed before ovn target set
+func (h *vtepdbHandler) ready() bool {
+	if OvnCentralSet == false {
+		log.Warning("Ovn central not connected, ignore vtepdb modification\n")
+		return false
+	}
+	return true
+}
 
-			// not process tables before Global ovn target set
-			if OvnCentralSet == false && table != vtepdb.Global {
-				log.Warning("Ovn central not connected, ignore vtepdb modification\n")
-				continue
-			}
+// OnPhysicalPortInsert vtepdb.PhysicalPortHandler, vlan bindings are
+// synced once for the whole update
+func (h *vtepdbHandler) OnPhysicalPortInsert(vtepdb.TablePhysicalPort) {
+	h.vlanBindingChanged = h.ready()
+}
 
-			switch table {
-			case vtepdb.Global:
-				vtepGlobalNotifyUpdate(op, rowUpdate)
-			case vtepdb.PhysicalSwitch:
-				physicalSwitchNotifyUpdate(op, rowUpdate)
-			default:
-				continue
-			}
-		}
+// OnPhysicalPortUpdate vtepdb.PhysicalPortHandler
+func (h *vtepdbHandler) OnPhysicalPortUpdate(_, _ vtepdb.TablePhysicalPort, _ []string) {
+	h.vlanBindingChanged = h.ready()
+}
+
+// OnPhysicalPortDelete vtepdb.PhysicalPortHandler
+func (h *vtepdbHandler) OnPhysicalPortDelete(vtepdb.TablePhysicalPort) {
+	h.vlanBindingChanged = h.ready()
+}
+
+// OnLogicalSwitchInsert vtepdb.LogicalSwitchHandler
+func (h *vtepdbHandler) OnLogicalSwitchInsert(vtepdb.TableLogicalSwitch) {
+	h.vlanBindingChanged = h.ready()
+}
+
+// OnLogicalSwitchUpdate vtepdb.LogicalSwitchHandler
+func (h *vtepdbHandler) OnLogicalSwitchUpdate(_, _ vtepdb.TableLogicalSwitch, _ []string) {
+	h.vlanBindingChanged = h.ready()
+}
+
+// OnLogicalSwitchDelete vtepdb.LogicalSwitchHandler
+func (h *vtepdbHandler) OnLogicalSwitchDelete(vtepdb.TableLogicalSwitch) {
+	h.vlanBindingChanged = h.ready()
+}
+
+// vtepDbNotifyUpdate dispatch vtepdb updates to typed handlers, Global
+// first as ovn target set by it enables other tables
+func (c *ovsdbc) vtepDbNotifyUpdate(updates libovsdb.TableUpdates) {
+	for table, tableupdate := range updates.Updates {
+		log.Info(">>> Vtep Table %s %d rows updated\n", table, len(tableupdate.Rows))
+	}
+
+	handler := &vtepdbHandler{}
+	dispatcher := vtepdb.NewDispatcher()
+	dispatcher.SetOrder(vtepdb.Global, vtepdb.PhysicalSwitch)
+	if err := dispatcher.Register(handler); err != nil {
+		log.Error("vtepdb handler register failed %v\n", err)
+		return
+	}
+	dispatcher.Dispatch(updates)
+	if handler.vlanBindingChanged {
+		vlanBindingSync()
 	}
 }
 
 func (c *ovsdbc) ovnNbNotifyUpdate(updates libovsdb.TableUpdates) {
+	if !odbc.IsActive() {
+		return
+	}
+
 	var op string
 	for table, tableupdate := range updates.Updates {
 		for uuid, rowUpdate := range tableupdate.Rows {
@@ -221,6 +280,14 @@ func (c *ovsdbc) ovnSbReComputeAll() {
 	// consistency
 }
 
+// ovnNbReComputeAll process current NB rows as initial update
+func (c *ovsdbc) ovnNbReComputeAll() {
+	if c == nil || c.Client == nil {
+		return
+	}
+	c.ovnNbNotifyUpdate(c.TableSnapshot(c.MonitorTables))
+}
+
 func (c *ovsdbc) ovnSbReComputeSchedule() {
 	cycleTime := time.NewTimer(time.Second * 5)
 	for {
@@ -251,7 +318,9 @@ func (c *ovsdbc) ovnSbReComputeSchedule() {
 				}
 				tableUpdates.Updates[tableName] = tableUpdate
 			}
+			vtepProcessMutex.Lock()
 			c.ovnSbProcessInitial(tableUpdates)
+			vtepProcessMutex.Unlock()
 			// consistency
 
 			cycleTime.Reset(time.Second * 5)
@@ -260,6 +329,12 @@ func (c *ovsdbc) ovnSbReComputeSchedule() {
 }
 
 func (c *ovsdbc) ovnSbProcessInitial(updates libovsdb.TableUpdates) {
+	if !odbc.IsActive() {
+		// standby caches port bindings of snapshot for takeover
+		portbindingStandbySync(updates.Updates[ovnsb.PortBinding].Rows)
+		return
+	}
+
 	var op string
 
 	// process phsical network infomation first
@@ -318,7 +393,16 @@ func (c *ovsdbc) ovnSbProcessInitial(updates libovsdb.TableUpdates) {
 }
 
 func (c *ovsdbc) ovnSbNotifyUpdate(updates libovsdb.TableUpdates) {
+	if !odbc.IsActive() {
+		for uuid, rowUpdate := range updates.Updates[ovnsb.PortBinding].Rows {
+			rowUpdate = odbc.RowUpdateOptimize(rowUpdate, uuid)
+			portbindingStandbyUpdate(odbc.GetRowUpdateOp(rowUpdate), rowUpdate, uuid)
+		}
+		return
+	}
+
 	var op string
+	gatewayChanged := false
 	for table, tableupdate := range updates.Updates {
 		for uuid, rowUpdate := range tableupdate.Rows {
 			// missing json number conversion in libovsdb, convert float64 to int
@@ -338,11 +422,17 @@ func (c *ovsdbc) ovnSbNotifyUpdate(updates libovsdb.TableUpdates) {
 				locatorNotifyUpdate(op, rowUpdate)
 			case ovnsb.Encap:
 				encapNotifyUpdate(op, rowUpdate)
+			case ovnsb.GatewayChassis, ovnsb.HaChassis, ovnsb.HaChassisGroup:
+				// gateway ports are located again once for the whole update
+				gatewayChanged = true
 			default:
 				continue
 			}
 		}
 	}
+	if gatewayChanged {
+		gatewayPortSync()
+	}
 
 	sbDBClient.ovnSbReComputeAll()
 }
@@ -372,7 +462,10 @@ func (c *ovsdbc) ovnSbReConnect() {
 				PhysicalSwitchInit()
 
 				initial, _ := c.MonitorDbTables(c.Db, c.MonitorAll, c.MonitorTables, "")
+				vtepProcessMutex.Lock()
 				c.ovnSbProcessInitial(*initial)
+				vtepProcessMutex.Unlock()
+				warmRestartReady(c.Db)
 				notifier := ovnSbNotifier{c}
 				c.Client.Register(notifier)
 
@@ -410,7 +503,10 @@ func (c *ovsdbc) ovnNbReConnect() {
 
 				c.Client = client
 				initial, _ := c.MonitorDbTables(c.Db, c.MonitorAll, c.MonitorTables, "")
+				vtepProcessMutex.Lock()
 				c.ovnNbNotifyUpdate(*initial)
+				vtepProcessMutex.Unlock()
+				warmRestartReady(c.Db)
 				notifier := ovnNbNotifier{c}
 				c.Client.Register(notifier)
 
@@ -439,10 +535,10 @@ func OvnSbLibReConnect() {
 			client, err := libovsdb.Connect(odbc.OvnsbAddr, nil)
 			if err == nil && client != nil {
 				log.Warning("Reconnect ovnsb lib successed\n")
-				ovnsb.OvnsouthboundClient.Client = client
+				ovnsb.RegisterOvnsouthboundClient(client)
 
-				notifier := ovnSbLibNotifier{ovnsb.OvnsouthboundClient.Client}
-				ovnsb.OvnsouthboundClient.Client.Register(notifier)
+				notifier := ovnSbLibNotifier{client}
+				client.Register(notifier)
 
 				cycleTime.Stop()
 				return
@@ -469,10 +565,10 @@ func OvnNbLibReConnect() {
 			client, err := libovsdb.Connect(odbc.OvnnbAddr, nil)
 			if err == nil && client != nil {
 				log.Warning("Reconnect ovnnb lib successed\n")
-				ovnnb.OvnnorthboundClient.Client = client
+				ovnnb.RegisterOvnnorthboundClient(client)
 
-				notifier := ovnNbLibNotifier{ovnnb.OvnnorthboundClient.Client}
-				ovnnb.OvnnorthboundClient.Client.Register(notifier)
+				notifier := ovnNbLibNotifier{client}
+				client.Register(notifier)
 
 				cycleTime.Stop()
 				return
@@ -500,7 +596,10 @@ func NewNbDbClient() {
 		log.Warning("Connect ovsdb %s successed\n", nbDBClient.Db)
 		initial, _ := nbDBClient.MonitorDbTables(nbDBClient.Db, nbDBClient.
 			MonitorAll, nbDBClient.MonitorTables, "")
+		vtepProcessMutex.Lock()
 		nbDBClient.ovnNbNotifyUpdate(*initial)
+		vtepProcessMutex.Unlock()
+		warmRestartReady(nbDBClient.Db)
 		notifier := ovnNbNotifier{&nbDBClient}
 		nbDBClient.Client.Register(notifier)
 	}
@@ -521,7 +620,10 @@ func NewSbDbClient() {
 
 		initial, _ := sbDBClient.MonitorDbTables(sbDBClient.Db, sbDBClient.
 			MonitorAll, sbDBClient.MonitorTables, "")
+		vtepProcessMutex.Lock()
 		sbDBClient.ovnSbProcessInitial(*initial)
+		vtepProcessMutex.Unlock()
+		warmRestartReady(sbDBClient.Db)
 		notifier := ovnSbNotifier{&sbDBClient}
 		sbDBClient.Client.Register(notifier)
 	}
@@ -534,15 +636,17 @@ func NewOvnLibClient() {
 	if ovnsb.InitOvnsouthbound(odbc.OvnsbAddr) != nil {
 		go OvnSbLibReConnect()
 	} else {
-		notifier := ovnSbLibNotifier{ovnsb.OvnsouthboundClient.Client}
-		ovnsb.OvnsouthboundClient.Client.Register(notifier)
+		client := ovnsb.OvnsouthboundClient.Conn()
+		notifier := ovnSbLibNotifier{client}
+		client.Register(notifier)
 	}
 
 	if ovnnb.InitOvnnorthbound(odbc.OvnnbAddr) != nil {
 		go OvnNbLibReConnect()
 	} else {
-		notifier := ovnNbLibNotifier{ovnnb.OvnnorthboundClient.Client}
-		ovnnb.OvnnorthboundClient.Client.Register(notifier)
+		client := ovnnb.OvnnorthboundClient.Conn()
+		notifier := ovnNbLibNotifier{client}
+		client.Register(notifier)
 	}
 }
 
@@ -550,6 +654,8 @@ func ovnCentralConnectJob() {
 	for {
 		time.Sleep(10 * time.Millisecond)
 		if OvnCentralSet == true {
+			// mark rows of last run stale before deriving them from ovn
+			warmRestartMark()
 			// start ovn lib connection
 			NewOvnLibClient()
 			// Start OVN SB connection and update Notifier
@@ -561,25 +667,55 @@ func ovnCentralConnectJob() {
 	}
 }
 
+// vtepRoleChange standby instance keeps OVN and vtepdb monitored but writes
+// nothing, on takeover vtepdb is derived again from current OVN rows and
+// rows of previous active instance are claimed by warm restart. Takeover
+// holds vtepProcessMutex so no update is processed half standby.
+func vtepRoleChange(active bool) {
+	if !active {
+		return
+	}
+	go func() {
+		vtepProcessMutex.Lock()
+		defer vtepProcessMutex.Unlock()
+
+		warmRestartMark()
+		if !OvnCentralConnected {
+			// derived when ovn central connected
+			return
+		}
+
+		PhysicalSwitchInit()
+		sbDBClient.ovnSbReComputeAll()
+		warmRestartReady(sbDBClient.Db)
+		if nbDBClient.Client != nil {
+			nbDBClient.ovnNbReComputeAll()
+			warmRestartReady(nbDBClient.Db)
+		}
+	}()
+}
+
 // OvnCentralConnect vtep controller connect to ovn sb and nb
 func OvnCentralConnect() {
 	go ovnCentralConnectJob()
 }
 
-func vtepGlobalNotifyUpdate(op string, rowUpdate libovsdb.RowUpdate) {
-	switch op {
-	case odbc.OpInsert:
-		vtepGlobalCreate(rowUpdate.New)
-	case odbc.OpDelete:
-		//vtepGlobalRemove(rowUpdate.Old)
-	case odbc.OpUpdate:
-		//vtepGlobalUpdate(rowUpdate.New, rowUpdate.Old)
-	}
+// OnGlobalInsert vtepdb.GlobalHandler
+func (h *vtepdbHandler) OnGlobalInsert(newGlobal vtepdb.TableGlobal) {
+	vtepGlobalCreate(newGlobal)
 }
 
-func vtepGlobalCreate(row libovsdb.Row) {
-	tableGlobal := vtepdb.ConvertRowToGlobal(libovsdb.ResultRow(row.Fields))
+// OnGlobalUpdate vtepdb.GlobalHandler
+func (h *vtepdbHandler) OnGlobalUpdate(_, _ vtepdb.TableGlobal, _ []string) {
+	//vtepGlobalUpdate(rowUpdate.New, rowUpdate.Old)
+}
+
+// OnGlobalDelete vtepdb.GlobalHandler
+func (h *vtepdbHandler) OnGlobalDelete(vtepdb.TableGlobal) {
+	//vtepGlobalRemove(rowUpdate.Old)
+}
 
+func vtepGlobalCreate(tableGlobal vtepdb.TableGlobal) {
 	log.Info("ovn target set northbound: %s southbound: %s\n",
 		tableGlobal.OvnnbTarget, tableGlobal.OvnsbTarget)
 
@@ -617,8 +753,8 @@ func vtepGlobalUpdateNb(row libovsdb.Row) error {
 
 	odbc.OvnnbAddr = tableGlobal.OvnnbTarget
 	// disconnect origin connection then auto reconnect
-	if nil != ovnnb.OvnnorthboundClient.Client {
-		ovnnb.OvnnorthboundClient.Client.Disconnect()
+	if client := ovnnb.OvnnorthboundClient.Conn(); nil != client {
+		client.Disconnect()
 	}
 	if nil != nbDBClient.Client {
 		nbDBClient.Client.Disconnect()
@@ -634,8 +770,8 @@ func vtepGlobalUpdateSb(row libovsdb.Row) error {
 
 	odbc.OvnsbAddr = tableGlobal.OvnsbTarget
 	// disconnect origin connection then auto reconnect
-	if nil != ovnsb.OvnsouthboundClient.Client {
-		ovnsb.OvnsouthboundClient.Client.Disconnect()
+	if client := ovnsb.OvnsouthboundClient.Conn(); nil != client {
+		client.Disconnect()
 	}
 	if nil != sbDBClient.Client {
 		sbDBClient.Client.Disconnect()
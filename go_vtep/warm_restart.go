@@ -0,0 +1,218 @@
+package govtep
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+
+	"github.com/ebay/libovsdb"
+)
+
+// WarmRestart keep vtepdb rows of last run instead of rebuilding them, rows
+// not derived again from OVN are swept WarmRestartTimer after OVN initial
+// updates processed
+var (
+	WarmRestart      bool          = false
+	WarmRestartTimer time.Duration = 180 * time.Second
+)
+
+// warmRestartTables vtepdb tables derived from OVN in sweep order. Rows of
+// non-root tables are removed from column of their parent, vtepdb garbage
+// collects them then. ACL rules and auto gateway confs go with their
+// parent.
+var warmRestartTables = []struct {
+	table  string
+	parent string
+	column string
+}{
+	{vtepdb.RemoteNeigh, vtepdb.L3port, vtepdb.L3portFieldNeighbour},
+	{vtepdb.RemoteFdb, vtepdb.BridgeDomain, vtepdb.BridgeDomainFieldUnicastfdb},
+	{vtepdb.Route, vtepdb.Vrf, vtepdb.VrfFieldRoute},
+	{vtepdb.PolicyBasedRoute, vtepdb.Vrf, vtepdb.VrfFieldPbr},
+	{vtepdb.L2port, vtepdb.BridgeDomain, vtepdb.BridgeDomainFieldL2ports},
+	{vtepdb.L3port, vtepdb.Vrf, vtepdb.VrfFieldL3ports},
+	{vtepdb.ACL, "", ""},
+	{vtepdb.BridgeDomain, "", ""},
+	{vtepdb.Vrf, "", ""},
+	{vtepdb.Locator, "", ""},
+}
+
+// warmRestartState stale rows of tables by uuid, rows are claimed when
+// derived again from OVN. Sweep timer starts once initial updates of both
+// OVN databases are processed.
+var warmRestartState = struct {
+	mutex sync.Mutex
+	stale map[string]map[string]bool
+	ready map[string]bool
+	timer *time.Timer
+}{
+	stale: make(map[string]map[string]bool),
+	ready: make(map[string]bool),
+}
+
+// warmRestartMark mark every existing row of derived tables stale, called
+// before OVN updates processed by active instance at start or takeover.
+// Pending sweep of previous mark is cancelled.
+func warmRestartMark() {
+	if !WarmRestart || !odbc.IsActive() {
+		return
+	}
+
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	if warmRestartState.timer != nil {
+		warmRestartState.timer.Stop()
+		warmRestartState.timer = nil
+	}
+	warmRestartState.ready = make(map[string]bool)
+
+	var conditions []interface{}
+	conditions = append(conditions, libovsdb.
+		NewCondition("_uuid", "!=", libovsdb.UUID{GoUUID: vtepdb.InvalidUUID}))
+	for _, t := range warmRestartTables {
+		rows, _ := vtepdb.SelectRows(t.table, conditions)
+		stale := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			if UUID, ok := row["_uuid"].(libovsdb.UUID); ok {
+				stale[UUID.GoUUID] = true
+			}
+		}
+		warmRestartState.stale[t.table] = stale
+		log.Warning("Warm restart mark %d %s rows stale\n", len(stale), t.table)
+	}
+}
+
+// warmRestartClaim claim existing row derived again from OVN
+func warmRestartClaim(table string, uuid string) {
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	delete(warmRestartState.stale[table], uuid)
+}
+
+// warmRestartReady initial updates of OVN database processed, sweep timer
+// starts when both OVN databases are ready
+func warmRestartReady(db string) {
+	if !WarmRestart || !odbc.IsActive() {
+		return
+	}
+
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	if warmRestartState.ready[db] {
+		return
+	}
+	warmRestartState.ready[db] = true
+	if len(warmRestartState.ready) < 2 || warmRestartState.timer != nil {
+		return
+	}
+
+	log.Warning("Warm restart sweep stale rows after %v\n", WarmRestartTimer)
+	warmRestartState.timer = time.AfterFunc(WarmRestartTimer, warmRestartSweep)
+}
+
+// warmRestartSweep remove rows still stale. Sweep is postponed while local
+// gateway not ready, every row would be stale then.
+func warmRestartSweep() {
+	if !odbc.IsActive() {
+		log.Warning("Warm restart sweep skipped, instance is %s\n", odbc.RoleStandby)
+		return
+	}
+
+	if !gatewayInitDone() {
+		log.Warning("Warm restart gateway not init yet, sweep after %v\n", WarmRestartTimer)
+		warmRestartState.mutex.Lock()
+		if warmRestartState.timer != nil {
+			warmRestartState.timer.Reset(WarmRestartTimer)
+		}
+		warmRestartState.mutex.Unlock()
+		return
+	}
+
+	warmRestartState.mutex.Lock()
+	stale := warmRestartState.stale
+	warmRestartState.stale = make(map[string]map[string]bool)
+	warmRestartState.mutex.Unlock()
+
+	for _, t := range warmRestartTables {
+		for uuid := range stale[t.table] {
+			log.Warning("Warm restart sweep stale %s %s\n", t.table, uuid)
+			if err := warmRestartRemove(t.table, t.parent, t.column, uuid); err != nil {
+				log.Warning("Warm restart sweep %s %s failed: %v\n", t.table, uuid, err)
+			}
+		}
+	}
+	log.Warning("Warm restart done\n")
+}
+
+func warmRestartRemove(table string, parent string, column string, uuid string) error {
+	if parent == "" {
+		condition := libovsdb.NewCondition("_uuid", "==", libovsdb.UUID{GoUUID: uuid})
+		vtepdb.DeleteRows(table, []interface{}{condition})
+		return nil
+	}
+
+	set, err := libovsdb.NewOvsSet([]libovsdb.UUID{{GoUUID: uuid}})
+	if err != nil {
+		return err
+	}
+	mutation := libovsdb.NewMutation(column, "delete", set)
+	condition := libovsdb.NewCondition(column, "includes", set)
+	vtepdb.MutateRows(parent, []interface{}{mutation}, []interface{}{condition})
+	return nil
+}
+
+// warmRestartReconcile set fields of claimed row differing from the row
+// derived again from OVN. Fields are Go field names of the table struct,
+// setField sets column of them on the claimed row.
+func warmRestartReconcile(name string, dbTable interface{}, table interface{}, fieldMap map[string]string,
+	fields []string, setField func(column string, value interface{}) error) error {
+	dbValue := reflect.ValueOf(dbTable)
+	value := reflect.ValueOf(table)
+	for _, field := range fields {
+		old := dbValue.FieldByName(field)
+		derived := value.FieldByName(field)
+		if warmRestartEqual(old, derived) {
+			continue
+		}
+		log.Warning("Warm restart %s %s changed %v => %v\n", name, field, old.Interface(), derived.Interface())
+		if err := setField(fieldMap[field], derived.Interface()); err != nil {
+			return fmt.Errorf("%s set %s failed: %v", name, field, err)
+		}
+	}
+	return nil
+}
+
+// warmRestartEqual compare field values, slices are sets of column and
+// empty slice or map equals nil
+func warmRestartEqual(a reflect.Value, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < b.Len(); i++ {
+			found := false
+			for j := 0; j < a.Len(); j++ {
+				if reflect.DeepEqual(a.Index(j).Interface(), b.Index(i).Interface()) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -3,7 +3,11 @@ package ovsdbclient
 import (
 	"crypto/tls"
 	"fmt"
+	"math"
 	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
 
 	"github.com/ebay/libovsdb"
 )
@@ -25,6 +29,25 @@ type OvsdbC struct {
 	//disconnectCB OVNDisconnectedCallback
 }
 
+// Reconnect connect db of addr after connection lost, connect is retried
+// after 1, 2, 4 and then every 8 seconds until succeeded
+func Reconnect(db string, addr string, tlsConfig *tls.Config) *libovsdb.OvsdbClient {
+	retryCnt := 0
+	for {
+		time.Sleep(time.Second * time.Duration(math.Exp2(float64(retryCnt))))
+		client, err := libovsdb.Connect(addr, tlsConfig)
+		if err == nil && client != nil {
+			log.Warning("Reconnect ovsdb %s[%s] successed\n", db, addr)
+			return client
+		}
+		if retryCnt <= 2 {
+			retryCnt++
+		}
+		log.Info("Try to connect ovsdb %s[%s] failed, retry after %v seconds\n",
+			db, addr, math.Exp2(float64(retryCnt)))
+	}
+}
+
 // UpdateRows update db.table row's field with updates
 // return updated number
 func (c *OvsdbC) UpdateRows(db string, table string,
@@ -99,12 +122,52 @@ func (c *OvsdbC) SelectRows(db string, table string,
 	return []libovsdb.ResultRow{}, 0
 }
 
+// TableSnapshot rows of tables as initial table updates, all tables of db
+// if tables is empty
+func (c *OvsdbC) TableSnapshot(tables []string) libovsdb.TableUpdates {
+	var tableUpdates libovsdb.TableUpdates
+	tableUpdates.Updates = make(map[string]libovsdb.TableUpdate)
+
+	if len(tables) == 0 {
+		for table := range c.Client.Schema[c.Db].Tables {
+			tables = append(tables, table)
+		}
+	}
+
+	for _, table := range tables {
+		var conditions []interface{}
+		rows, num := c.SelectRows(c.Db, table, conditions)
+		if num == 0 {
+			continue
+		}
+
+		var tableUpdate libovsdb.TableUpdate
+		tableUpdate.Rows = make(map[string]libovsdb.RowUpdate)
+		for _, row := range rows {
+			if UUID, ok := row["_uuid"].(libovsdb.UUID); ok {
+				var rowUpdate libovsdb.RowUpdate
+				rowUpdate.New.Fields = row
+				tableUpdate.Rows[UUID.GoUUID] = rowUpdate
+			}
+		}
+		tableUpdates.Updates[table] = tableUpdate
+	}
+	return tableUpdates
+}
+
 // Transact with mutex and error check
 func (c *OvsdbC) Transact(db string, ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
 	// Only support one trans at same time now.
 	c.Tranmutex.Lock()
 	defer c.Tranmutex.Unlock()
-	reply, err := c.Client.Transact(db, ops...)
+	var reply []libovsdb.OperationResult
+	var err error
+	if ControllerLock != "" && db == VTEPDB && writeTransaction(ops) {
+		// writes of standby instance are refused by ovsdb
+		reply, err = LockTransact(db, ops...)
+	} else {
+		reply, err = c.Client.Transact(db, ops...)
+	}
 	if err != nil {
 		return reply, err
 	}
@@ -127,6 +190,16 @@ func (c *OvsdbC) Transact(db string, ops ...libovsdb.Operation) ([]libovsdb.Oper
 	return reply, nil
 }
 
+// writeTransaction transaction has operations other than select and wait
+func writeTransaction(ops []libovsdb.Operation) bool {
+	for _, op := range ops {
+		if op.Op != OpSelect && op.Op != OpWait {
+			return true
+		}
+	}
+	return false
+}
+
 // MonitorDbTables for specific tables monitor
 func (c *OvsdbC) MonitorDbTables(db string, all bool, tables []string,
 	jsonContext string) (*libovsdb.TableUpdates, error) {
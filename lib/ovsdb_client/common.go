@@ -23,6 +23,8 @@ const (
 	OpDelete string = "delete"
 	OpSelect string = "select"
 	OpUpdate string = "update"
+	OpWait   string = "wait"
+	OpAssert string = "assert"
 )
 
 // DB name
@@ -0,0 +1,382 @@
+package ovsdbclient
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// controller roles, instance is active when lock election not configured
+const (
+	RoleActive  string = "active"
+	RoleStandby string = "standby"
+)
+
+// ControllerLock name of lock on vtepdb for active/standby election, empty
+// to run without election
+var ControllerLock string = ""
+
+// role active/standby of controller instance, handlers are called on role
+// changes
+var role = struct {
+	mutex    sync.Mutex
+	active   bool
+	lockID   string
+	handlers map[string]func(active bool)
+}{
+	active:   true,
+	handlers: make(map[string]func(active bool)),
+}
+
+// lockConn lock session connection, write transactions of locked db are
+// sent on it with the lock asserted
+var lockConn = struct {
+	mutex   sync.Mutex
+	send    func(msg interface{}) error
+	nextID  int
+	pending map[string]chan lockMessage
+}{
+	pending: make(map[string]chan lockMessage),
+}
+
+// IsActive controller instance holds the lock, only active instance
+// processes updates and writes
+func IsActive() bool {
+	role.mutex.Lock()
+	defer role.mutex.Unlock()
+	return role.active
+}
+
+// Role current role of controller instance
+func Role() string {
+	if IsActive() {
+		return RoleActive
+	}
+	return RoleStandby
+}
+
+// RegisterRoleHandler handler called on role changes
+func RegisterRoleHandler(name string, handler func(active bool)) {
+	role.mutex.Lock()
+	defer role.mutex.Unlock()
+	role.handlers[name] = handler
+}
+
+func setActive(active bool) {
+	role.mutex.Lock()
+	if role.active == active {
+		role.mutex.Unlock()
+		return
+	}
+	role.active = active
+	var names []string
+	for name := range role.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	handlers := make([]func(active bool), 0, len(names))
+	for _, name := range names {
+		handlers = append(handlers, role.handlers[name])
+	}
+	role.mutex.Unlock()
+
+	if active {
+		log.Warning("Controller role change to %s\n", RoleActive)
+	} else {
+		log.Warning("Controller role change to %s\n", RoleStandby)
+	}
+	for _, handler := range handlers {
+		handler(active)
+	}
+}
+
+// LockNotify locked/stolen notification of election lock received from
+// ovsdb connection
+func LockNotify(params []interface{}, locked bool) {
+	role.mutex.Lock()
+	lockID := role.lockID
+	role.mutex.Unlock()
+
+	if lockID == "" || len(params) == 0 || params[0] != lockID {
+		return
+	}
+	setActive(locked)
+}
+
+// LockElection elect active instance by ovsdb lock lockID on db of addr.
+// Instance is standby until lock granted and falls back to standby when lock
+// is stolen or connection lost, connection is retried forever.
+func LockElection(addr string, tlsConfig *tls.Config, lockID string) {
+	role.mutex.Lock()
+	role.active = false
+	role.lockID = lockID
+	role.mutex.Unlock()
+	log.Warning("Controller role %s, wait for lock %s on %s\n", RoleStandby, lockID, addr)
+
+	go func() {
+		retryCnt := 0
+		for {
+			err := lockSession(addr, tlsConfig, lockID)
+			setActive(false)
+			log.Warning("Lock %s session on %s closed: %v, retry after %v seconds\n",
+				lockID, addr, err, math.Exp2(float64(retryCnt)))
+
+			time.Sleep(time.Second * time.Duration(math.Exp2(float64(retryCnt))))
+			if retryCnt <= 2 {
+				retryCnt++
+			}
+		}
+	}()
+}
+
+// lockMessage json-rpc request, response or notification of ovsdb
+type lockMessage struct {
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  interface{}     `json:"error,omitempty"`
+	ID     interface{}     `json:"id"`
+}
+
+// lockSession lock on dedicated connection, ovsdb releases the lock when
+// connection closed. Returns when connection lost.
+func lockSession(addr string, tlsConfig *tls.Config, lockID string) error {
+	conn, err := lockDial(addr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var mutex sync.Mutex
+	encoder := json.NewEncoder(conn)
+	send := func(msg interface{}) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return encoder.Encode(msg)
+	}
+	lockConn.mutex.Lock()
+	lockConn.send = send
+	lockConn.mutex.Unlock()
+	defer lockConnClose()
+
+	if err := send(map[string]interface{}{
+		"method": "lock",
+		"params": []interface{}{lockID},
+		"id":     "lock",
+	}); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var msg lockMessage
+		if err := decoder.Decode(&msg); err != nil {
+			return err
+		}
+
+		switch msg.Method {
+		case "echo":
+			var params []interface{}
+			json.Unmarshal(msg.Params, &params)
+			if err := send(map[string]interface{}{
+				"result": params,
+				"error":  nil,
+				"id":     msg.ID,
+			}); err != nil {
+				return err
+			}
+		case "locked", "stolen":
+			var params []interface{}
+			json.Unmarshal(msg.Params, &params)
+			if len(params) == 0 || params[0] != lockID {
+				continue
+			}
+			// lock request stays queued after stolen, locked is notified
+			// again when the stealer releases it
+			setActive(msg.Method == "locked")
+		case "":
+			if id, ok := msg.ID.(string); ok && strings.HasPrefix(id, "transact-") {
+				lockConnReply(id, msg)
+				continue
+			}
+			if msg.ID != "lock" {
+				continue
+			}
+			if msg.Error != nil {
+				return fmt.Errorf("lock %s failed: %v", lockID, msg.Error)
+			}
+			var result struct {
+				Locked bool `json:"locked"`
+			}
+			json.Unmarshal(msg.Result, &result)
+			if result.Locked {
+				setActive(true)
+			} else {
+				log.Warning("Lock %s held by other instance, wait as %s\n", lockID, RoleStandby)
+			}
+		}
+	}
+}
+
+// lockConnReply reply of transaction sent on lock session
+func lockConnReply(id string, msg lockMessage) {
+	lockConn.mutex.Lock()
+	reply, ok := lockConn.pending[id]
+	delete(lockConn.pending, id)
+	lockConn.mutex.Unlock()
+	if ok {
+		reply <- msg
+	}
+}
+
+// lockConnClose lock session closed, pending transactions fail
+func lockConnClose() {
+	lockConn.mutex.Lock()
+	defer lockConn.mutex.Unlock()
+	lockConn.send = nil
+	for id, reply := range lockConn.pending {
+		close(reply)
+		delete(lockConn.pending, id)
+	}
+}
+
+// LockTransact transaction on lock session with the lock asserted first,
+// ovsdb fails the transaction of instance not holding the lock
+func LockTransact(db string, ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	role.mutex.Lock()
+	lockID := role.lockID
+	role.mutex.Unlock()
+
+	lockConn.mutex.Lock()
+	send := lockConn.send
+	if send == nil {
+		lockConn.mutex.Unlock()
+		return nil, fmt.Errorf("lock %s session not connected", lockID)
+	}
+	lockConn.nextID++
+	id := fmt.Sprintf("transact-%d", lockConn.nextID)
+	reply := make(chan lockMessage, 1)
+	lockConn.pending[id] = reply
+	lockConn.mutex.Unlock()
+
+	params := []interface{}{db, map[string]interface{}{"op": OpAssert, "lock": lockID}}
+	for _, op := range ops {
+		params = append(params, op)
+	}
+	if err := send(map[string]interface{}{
+		"method": "transact",
+		"params": params,
+		"id":     id,
+	}); err != nil {
+		lockConn.mutex.Lock()
+		delete(lockConn.pending, id)
+		lockConn.mutex.Unlock()
+		return nil, err
+	}
+
+	msg, ok := <-reply
+	if !ok {
+		return nil, fmt.Errorf("lock %s session closed", lockID)
+	}
+	if msg.Error != nil {
+		return nil, fmt.Errorf("transact on lock %s session failed: %v", lockID, msg.Error)
+	}
+	var results []libovsdb.OperationResult
+	if err := json.Unmarshal(msg.Result, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("lock %s assert without result", lockID)
+	}
+	if results[0].Error != "" {
+		return nil, fmt.Errorf("lock %s not owned: %v %v", lockID, results[0].Error, results[0].Details)
+	}
+	return results[1:], nil
+}
+
+// LockTransactor transaction of db asserting election lock, set as lock
+// transact of generated clients of election db
+func LockTransactor(db string) func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	return func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+		return LockTransact(db, ops...)
+	}
+}
+
+// Follow lock session echo interval and timeout
+const (
+	lockFollowInterval time.Duration = 5 * time.Second
+	lockFollowTimeout  time.Duration = 15 * time.Second
+)
+
+// LockFollow hold election lock on db of addr too while instance is
+// active and release it on standby, return transaction of db asserting
+// it. Writes of instance losing the election are refused by db once the
+// new active instance holds the lock there.
+func LockFollow(db string, addr func() string) func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	role.mutex.Lock()
+	lockID := role.lockID
+	role.mutex.Unlock()
+
+	session := NewLockSession(db, addr, nil, lockFollowInterval, lockFollowTimeout)
+	RegisterRoleHandler("lock "+db, func(active bool) {
+		go func() {
+			var err error
+			if active {
+				_, err = session.Lock(lockID)
+			} else {
+				err = session.Unlock(lockID)
+			}
+			if err != nil {
+				log.Warning("Lock %s on %s follow role failed %v\n", lockID, db, err)
+			}
+		}()
+	})
+
+	return func(ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+		// requested locks are lost on reconnect of session
+		if IsActive() && !session.Requested(lockID) {
+			if _, err := session.Lock(lockID); err != nil {
+				return nil, err
+			}
+		}
+		return session.Transact(db, lockID, ops...)
+	}
+}
+
+// lockDial dial endpoints of ovsdb addr, tcp:host:port, ssl:host:port or
+// unix:path
+func lockDial(addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	err := fmt.Errorf("no endpoint in %s", addr)
+	for _, endpoint := range strings.Split(addr, ",") {
+		var conn net.Conn
+		fields := strings.SplitN(endpoint, ":", 2)
+		if len(fields) != 2 {
+			err = fmt.Errorf("invalid endpoint %s", endpoint)
+			continue
+		}
+		switch fields[0] {
+		case "unix":
+			conn, err = net.Dial("unix", fields[1])
+		case "tcp":
+			conn, err = net.Dial("tcp", fields[1])
+		case "ssl":
+			conn, err = tls.Dial("tcp", fields[1], tlsConfig)
+		default:
+			err = fmt.Errorf("unknown network protocol %s", fields[0])
+		}
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,311 @@
+package ovsdbclient
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// LockSession ovsdb connection holding several locks, ovsdb releases the
+// locks when the connection is closed, so a lock held by a session tells
+// its owner is alive. The session echoes ovsdb every interval and is
+// reconnected when nothing received in timeout, requested locks are lost
+// on reconnect.
+type LockSession struct {
+	name      string
+	addr      func() string
+	tlsConfig *tls.Config
+	interval  time.Duration
+	timeout   time.Duration
+
+	mutex   sync.Mutex
+	send    func(msg interface{}) error
+	nextID  int
+	pending map[string]chan lockMessage
+	// requested locks, true when held
+	locks map[string]bool
+	stop  chan struct{}
+}
+
+// NewLockSession start lock session on ovsdb of addr, addr is got again
+// at every reconnect
+func NewLockSession(name string, addr func() string, tlsConfig *tls.Config,
+	interval time.Duration, timeout time.Duration) *LockSession {
+	s := &LockSession{
+		name:      name,
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		interval:  interval,
+		timeout:   timeout,
+		pending:   make(map[string]chan lockMessage),
+		locks:     make(map[string]bool),
+		stop:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Close session, locks are released by ovsdb
+func (s *LockSession) Close() {
+	close(s.stop)
+}
+
+func (s *LockSession) run() {
+	retryCnt := 0
+	for {
+		err := s.session()
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		log.Warning("Lock session %s closed: %v, retry after %v seconds\n",
+			s.name, err, math.Exp2(float64(retryCnt)))
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(time.Second * time.Duration(math.Exp2(float64(retryCnt)))):
+		}
+		if retryCnt <= 2 {
+			retryCnt++
+		}
+	}
+}
+
+// session serve one connection until lost, stopped or echo timed out
+func (s *LockSession) session() error {
+	conn, err := lockDial(s.addr(), s.tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var sendMutex sync.Mutex
+	encoder := json.NewEncoder(conn)
+	send := func(msg interface{}) error {
+		sendMutex.Lock()
+		defer sendMutex.Unlock()
+		return encoder.Encode(msg)
+	}
+	s.mutex.Lock()
+	s.send = send
+	s.mutex.Unlock()
+	defer s.closed()
+
+	received := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case <-s.stop:
+				conn.Close()
+				return
+			case <-received:
+				last = time.Now()
+			case <-ticker.C:
+				if time.Since(last) > s.timeout {
+					log.Warning("Lock session %s nothing received in %v\n", s.name, s.timeout)
+					conn.Close()
+					return
+				}
+				send(map[string]interface{}{
+					"method": "echo",
+					"params": []interface{}{},
+					"id":     "echo",
+				})
+			}
+		}
+	}()
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var msg lockMessage
+		if err := decoder.Decode(&msg); err != nil {
+			return err
+		}
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+
+		switch msg.Method {
+		case "echo":
+			var params []interface{}
+			json.Unmarshal(msg.Params, &params)
+			if err := send(map[string]interface{}{
+				"result": params,
+				"error":  nil,
+				"id":     msg.ID,
+			}); err != nil {
+				return err
+			}
+		case "locked", "stolen":
+			var params []interface{}
+			json.Unmarshal(msg.Params, &params)
+			if len(params) == 0 {
+				continue
+			}
+			lockID, _ := params[0].(string)
+			s.mutex.Lock()
+			if _, ok := s.locks[lockID]; ok {
+				s.locks[lockID] = msg.Method == "locked"
+			}
+			s.mutex.Unlock()
+		case "":
+			if id, ok := msg.ID.(string); ok {
+				s.reply(id, msg)
+			}
+		}
+	}
+}
+
+func (s *LockSession) reply(id string, msg lockMessage) {
+	s.mutex.Lock()
+	reply, ok := s.pending[id]
+	delete(s.pending, id)
+	s.mutex.Unlock()
+	if ok {
+		reply <- msg
+	}
+}
+
+// closed connection lost, pending requests fail and locks are released
+func (s *LockSession) closed() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.send = nil
+	for id, reply := range s.pending {
+		close(reply)
+		delete(s.pending, id)
+	}
+	s.locks = make(map[string]bool)
+}
+
+// request send json-rpc request on session and wait for its reply
+func (s *LockSession) request(method string, params []interface{}) (lockMessage, error) {
+	s.mutex.Lock()
+	send := s.send
+	if send == nil {
+		s.mutex.Unlock()
+		return lockMessage{}, fmt.Errorf("lock session %s not connected", s.name)
+	}
+	s.nextID++
+	id := fmt.Sprintf("%s-%d", method, s.nextID)
+	reply := make(chan lockMessage, 1)
+	s.pending[id] = reply
+	s.mutex.Unlock()
+
+	if err := send(map[string]interface{}{
+		"method": method,
+		"params": params,
+		"id":     id,
+	}); err != nil {
+		s.mutex.Lock()
+		delete(s.pending, id)
+		s.mutex.Unlock()
+		return lockMessage{}, err
+	}
+
+	msg, ok := <-reply
+	if !ok {
+		return lockMessage{}, fmt.Errorf("lock session %s closed", s.name)
+	}
+	if msg.Error != nil {
+		return msg, fmt.Errorf("%s on lock session %s failed: %v", method, s.name, msg.Error)
+	}
+	return msg, nil
+}
+
+// Lock request lock, return true if granted at once. Request not granted
+// stays queued and the lock is held once its owner releases it.
+func (s *LockSession) Lock(lockID string) (bool, error) {
+	s.mutex.Lock()
+	s.locks[lockID] = false
+	s.mutex.Unlock()
+
+	msg, err := s.request("lock", []interface{}{lockID})
+	if err != nil {
+		s.mutex.Lock()
+		delete(s.locks, lockID)
+		s.mutex.Unlock()
+		return false, err
+	}
+	var result struct {
+		Locked bool `json:"locked"`
+	}
+	json.Unmarshal(msg.Result, &result)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if result.Locked {
+		s.locks[lockID] = true
+	}
+	return s.locks[lockID], nil
+}
+
+// Unlock release lock or cancel its queued request
+func (s *LockSession) Unlock(lockID string) error {
+	s.mutex.Lock()
+	delete(s.locks, lockID)
+	s.mutex.Unlock()
+	_, err := s.request("unlock", []interface{}{lockID})
+	return err
+}
+
+// Requested lock is requested on current connection
+func (s *LockSession) Requested(lockID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.locks[lockID]
+	return ok
+}
+
+// Held lock is held by session
+func (s *LockSession) Held(lockID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.locks[lockID]
+}
+
+// Transact transaction on session with lock asserted first, ovsdb fails
+// the transaction if session doesn't hold the lock
+func (s *LockSession) Transact(db string, lockID string, ops ...libovsdb.Operation) ([]libovsdb.OperationResult, error) {
+	params := []interface{}{db, map[string]interface{}{"op": OpAssert, "lock": lockID}}
+	for _, op := range ops {
+		params = append(params, op)
+	}
+	msg, err := s.request("transact", params)
+	if err != nil {
+		return nil, err
+	}
+	var results []libovsdb.OperationResult
+	if err := json.Unmarshal(msg.Result, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("lock %s assert without result", lockID)
+	}
+	if results[0].Error != "" {
+		return nil, fmt.Errorf("lock %s not owned: %v %v", lockID, results[0].Error, results[0].Details)
+	}
+	for i, result := range results[1:] {
+		if result.Error != "" {
+			return nil, fmt.Errorf("operation %d failed: %v %v", i, result.Error, result.Details)
+		}
+	}
+	return results[1:], nil
+}
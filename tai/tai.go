@@ -2,8 +2,11 @@ package tai
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +20,11 @@ import (
 
 type taiDriver struct {
 	activeDriver  string
-	handlers      map[string]DriverHandler
+	shadowDrivers []string
+	driverInits   map[string]DriverInit
+	instances     map[string]*driverInstance
+	switches      map[string]*taiSwitch
+	capability    Capability
 	handlersMutex *sync.Mutex
 }
 
@@ -36,6 +43,8 @@ var taiDBClient = ovsdbc{
 
 // NewTaiDbClient connect and subscribe vtep DB
 func NewTaiDbClient() {
+	odbc.RegisterRoleHandler("tai", taiRoleChange)
+
 	taiDBClient.Addr = odbc.VtepdbAddr
 	err := taiDBClient.NewOvsDbClient()
 	if err != nil {
@@ -84,48 +93,124 @@ func (c *ovsdbc) reConnect() {
 	}
 }
 
+// taiRoleChange standby instance keeps vtepdb monitored but programs
+// nothing, on takeover current vtepdb rows are programmed as initial update
+// and objects of previous active instance are claimed by warm restart
+func taiRoleChange(active bool) {
+	if !active {
+		return
+	}
+	go func() {
+		if taiDBClient.Client == nil {
+			// initial update programmed when connected
+			return
+		}
+		warmRestartRearm()
+		taiDBClient.taiProcessInitial(taiDBClient.TableSnapshot(nil))
+	}()
+}
+
 var tai = taiDriver{
-	handlers:      make(map[string]DriverHandler),
+	driverInits:   make(map[string]DriverInit),
+	instances:     make(map[string]*driverInstance),
+	switches:      make(map[string]*taiSwitch),
 	handlersMutex: &sync.Mutex{},
 }
 
-// DriverHandler interface
+// DriverHandler interface. TAI calls between TaiBegin and TaiCommit are
+// one batch, driver may program them at once in TaiCommit and drop them in
+// TaiAbort. Drivers programming every call immediately can't roll back
+// and treat batch calls as no-op.
 type DriverHandler interface {
+	TaiBegin() error
+	TaiCommit() error
+	TaiAbort()
 	TaiCreateObject(ObjID, interface{}) error
 	TaiRemoveObject(ObjID, interface{}) error
-	TaiAddObjectAttr(ObjID, interface{}, map[interface{}]interface{}) error
-	TaiDelObjectAttr(ObjID, interface{}, map[interface{}]interface{}) error
-	TaiSetObjectAttr(ObjID, interface{}, map[interface{}]interface{}) error
-	TaiGetObjectAttr(ObjID, interface{}, []interface{}) (map[interface{}]interface{}, error)
+	TaiAddObjectAttr(ObjID, interface{}, Attrs) error
+	TaiDelObjectAttr(ObjID, interface{}, Attrs) error
+	TaiSetObjectAttr(ObjID, interface{}, Attrs) error
+	TaiGetObjectAttr(ObjID, interface{}, []ObjAttrID) (Attrs, error)
 	TaiListObject(ObjID) ([]interface{}, error)
+	TaiGetCapability() Capability
 }
 
-// RegisterTaiDriverHandler register tai handler
-func RegisterTaiDriverHandler(name string, handler DriverHandler) {
-	tai.handlersMutex.Lock()
-	defer tai.handlersMutex.Unlock()
-	tai.handlers[name] = handler
-	tai.activeDriver = name
+// DriverConfig config of driver instance. Switch is the Physical_Switch
+// name of instance, empty for default instance. Addr is the address
+// instance programs, eg config db of UNOS, empty for driver default.
+type DriverConfig struct {
+	Switch string
+	Addr   string
+}
+
+// DriverInit init driver instance when it's selected, return handler of
+// instance. Driver is initialized once for default instance and once more
+// for every attached switch it programs.
+type DriverInit func(DriverConfig) (DriverHandler, error)
+
+// driverName driver names are case insensitive
+func driverName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
 }
 
-// UnRegisterTaiDriverHandler unregister tai handler
-func UnRegisterTaiDriverHandler(name string, handler DriverHandler) {
+// RegisterDriver register driver init by name, drivers register
+// themselves in package init, driver isn't initialized until selected by
+// InitDrivers
+func RegisterDriver(name string, init DriverInit) {
 	tai.handlersMutex.Lock()
 	defer tai.handlersMutex.Unlock()
-	delete(tai.handlers, name)
+	if _, ok := tai.driverInits[driverName(name)]; ok {
+		panic(fmt.Sprintf("tai: driver %s registered twice", name))
+	}
+	tai.driverInits[driverName(name)] = init
 }
 
-func activeTaiDriver() (DriverHandler, error) {
-	var err error
+// RegisteredDrivers sorted names of registered drivers
+func RegisteredDrivers() []string {
 	tai.handlersMutex.Lock()
 	defer tai.handlersMutex.Unlock()
-	if len(tai.activeDriver) == 0 {
-		return nil, errors.New("NULL ActiveDriver")
+	names := make([]string, 0, len(tai.driverInits))
+	for name := range tai.driverInits {
+		names = append(names, name)
 	}
-	if handler, ok := tai.handlers[tai.activeDriver]; ok {
-		return handler, err
+	sort.Strings(names)
+	return names
+}
+
+// InitDrivers validate and init the primary driver and shadow drivers of
+// default instance, instances of switches get the same drivers unless
+// switch configures its own. Every TAI call goes to the primary driver first, then is mirrored
+// to shadow drivers in order with the same arguments even if primary
+// failed. Result of primary driver always wins and is returned to TAI,
+// shadow results and panics are only logged when they diverge from
+// primary.
+func InitDrivers(primary string, shadows []string) error {
+	names, err := checkDriverNames(append([]string{primary}, shadows...))
+	if err != nil {
+		return err
 	}
-	return nil, errors.New("NULL ActiveDriver Register")
+
+	inst, err := newDriverInstance("", names, "")
+	if err != nil {
+		return err
+	}
+
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	tai.activeDriver = names[0]
+	tai.shadowDrivers = names[1:]
+	tai.instances[inst.name] = inst
+	// objects beyond capability of primary driver are skipped and fault
+	// marked, shadow drivers follow the primary
+	tai.capability = inst.capability
+	log.Info("[TAI] driver %s active, shadow drivers %v\n", tai.activeDriver, tai.shadowDrivers)
+	log.Info("[TAI] driver %s capability:\n%s\n", tai.activeDriver, tai.capability)
+	return nil
+}
+
+type namedHandler struct {
+	name    string
+	handler DriverHandler
 }
 
 // getObjIDByTblName get switch Obj id from vtep DB table name
@@ -167,9 +252,11 @@ func getObjIDByTblName(tblName string) (ObjID, error) {
 	return taiObj, err
 }
 
-func rowToObj(objID ObjID, row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+// rowToObj convert vtepdb row to TAI object, attrs are converted to
+// declared types and invalid attrs are dropped
+func rowToObj(objID ObjID, row libovsdb.Row) (interface{}, Attrs) {
 	var obj interface{}
-	var attrs map[interface{}]interface{}
+	var attrs Attrs
 	switch objID {
 	case ObjectIDBridge:
 		obj, attrs = rowToBridgeObj(row)
@@ -198,124 +285,119 @@ func rowToObj(objID ObjID, row libovsdb.Row) (interface{}, map[interface{}]inter
 	case ObjectIDAutoGatewayConf:
 		obj, attrs = rowToAutoGatewayConfObj(row)
 	}
-	return obj, attrs
-}
 
-func rowToAttrs(objID ObjID, row libovsdb.Row) map[interface{}]interface{} {
-	var attrs map[interface{}]interface{}
-	switch objID {
-	case ObjectIDBridge:
-		attrs = rowToBridgeAttrs(row)
-	case ObjectIDVrf:
-		attrs = rowToVrfAttrs(row)
-	case ObjectIDL2Port:
-		attrs = rowToL2portAttrs(row)
-	case ObjectIDL3Port:
-		attrs = rowToL3portAttrs(row)
-	case ObjectIDRoute:
-		attrs = rowToRouteAttrs(row)
-	case ObjectIDTunnel:
-		attrs = rowToTunnelAttrs(row)
-	case ObjectIDFDB:
-		attrs = rowToFdbAttrs(row)
-	case ObjectIDNeighbour:
-		attrs = rowToNeighbourAttrs(row)
-	case ObjectIDACL:
-		attrs = rowToACLAttrs(row)
-	case ObjectIDACLRule:
-		attrs = rowToACLRuleAttrs(row)
-	case ObjectIDMcastFDB:
-		attrs = rowToMcastfdbAttrs(row)
-	case ObjectIDPBR:
-		attrs = rowToPBRAttrs(row)
-	case ObjectIDAutoGatewayConf:
-		attrs = rowToAutoGatewayConfAttrs(row)
+	typed := make(Attrs, len(attrs))
+	for id, value := range attrs {
+		if err := typed.Set(id, value); err != nil {
+			log.Warning("[TAI] %s %+v %v, attr ignored\n", ObjectOrder[objID], obj, err)
+		}
 	}
-	return attrs
+	return obj, typed
 }
 
+// taiProcessInitial program initial rows one batch per row, batching
+// whole initial update would resend all pending operations for every row
 func (c *ovsdbc) taiProcessInitial(updates libovsdb.TableUpdates) {
-	for table, tableupdate := range updates.Updates {
-		objID, err := getObjIDByTblName(table)
-		if err != nil {
-			continue
-		}
-		if objID != ObjectIDTunnel {
-			continue
-		}
-
-		for _, rowUpdate := range tableupdate.Rows {
-			odbc.Float64ToInt(rowUpdate.New)
-			odbc.Float64ToInt(rowUpdate.Old)
-			op := odbc.GetRowUpdateOp(rowUpdate)
+	if !odbc.IsActive() {
+		log.Info("[TAI] %s instance, initial update not programmed\n", odbc.RoleStandby)
+		return
+	}
 
-			switch op {
-			case odbc.OpInsert:
-				taiCreateObj(objID, rowUpdate.New)
-			case odbc.OpDelete:
-				taiRemoveObj(objID, rowUpdate.Old)
-			case odbc.OpUpdate:
-				taiUpdateObj(objID, rowUpdate.New, rowUpdate.Old)
-			}
-		}
+	if warmRestartMark() {
+		defer func() {
+			log.Warning("[TAI] warm restart sweep stale objects after %v\n", WarmRestartTimer)
+			time.AfterFunc(WarmRestartTimer, warmRestartSweep)
+		}()
 	}
 
-	for table, tableupdate := range updates.Updates {
-		objID, err := getObjIDByTblName(table)
-		if err != nil {
-			continue
-		}
-		if (objID != ObjectIDBridge && objID != ObjectIDVrf) || objID == ObjectIDTunnel {
-			continue
-		}
+	initialRows(updates, func(objID ObjID, rowUpdate libovsdb.RowUpdate) {
+		op := odbc.GetRowUpdateOp(rowUpdate)
+		_ = taiBatch(func() error {
+			return taiRowUpdate(objID, op, rowUpdate)
+		})
+	})
+	// every row is programmed on switches attached by initial update
+	switchReplayDone()
+}
 
-		log.Info("[TAI] >>> table %v tableupdate %+v\n", table, tableupdate)
+// initialRows call fn for rows of initial update, tunnels first, then
+// bridges and vrfs they refer, then the others
+func initialRows(updates libovsdb.TableUpdates, fn func(ObjID, libovsdb.RowUpdate)) {
+	for pass := 0; pass < 3; pass++ {
+		for table, tableupdate := range updates.Updates {
+			objID, err := getObjIDByTblName(table)
+			if err != nil || initialPass(objID) != pass {
+				continue
+			}
 
-		for _, rowUpdate := range tableupdate.Rows {
-			odbc.Float64ToInt(rowUpdate.New)
-			odbc.Float64ToInt(rowUpdate.Old)
-			op := odbc.GetRowUpdateOp(rowUpdate)
+			if objID != ObjectIDTunnel {
+				log.Info("[TAI] >>> table %v tableupdate %+v\n", table, tableupdate)
+			}
 
-			switch op {
-			case odbc.OpInsert:
-				taiCreateObj(objID, rowUpdate.New)
-			case odbc.OpDelete:
-				taiRemoveObj(objID, rowUpdate.Old)
-			case odbc.OpUpdate:
-				taiUpdateObj(objID, rowUpdate.New, rowUpdate.Old)
+			for _, rowUpdate := range tableupdate.Rows {
+				odbc.Float64ToInt(rowUpdate.New)
+				odbc.Float64ToInt(rowUpdate.Old)
+				fn(objID, rowUpdate)
 			}
 		}
 	}
+}
 
-	for table, tableupdate := range updates.Updates {
-		objID, err := getObjIDByTblName(table)
-		if err != nil {
-			continue
-		}
-		if objID == ObjectIDBridge || objID == ObjectIDVrf || objID == ObjectIDTunnel {
-			continue
-		}
-
-		log.Info("[TAI] >>> table %v tableupdate %+v\n", table, tableupdate)
+func initialPass(objID ObjID) int {
+	switch objID {
+	case ObjectIDTunnel:
+		return 0
+	case ObjectIDBridge, ObjectIDVrf:
+		return 1
+	}
+	return 2
+}
 
-		for _, rowUpdate := range tableupdate.Rows {
-			odbc.Float64ToInt(rowUpdate.New)
-			odbc.Float64ToInt(rowUpdate.Old)
-			op := odbc.GetRowUpdateOp(rowUpdate)
+// taiNotifyUpdate program table updates of one vtepdb transaction in one
+// batch. Batch is aborted if a row fails, rows are programmed again one
+// batch per row so an object and its attributes are programmed together
+// or not at all. Rows failed on ordering, referring rows coming later in
+// the same update, are retried once in their own batch. Switches attached
+// by the update get current vtepdb programmed afterwards.
+func (c *ovsdbc) taiNotifyUpdate(updates libovsdb.TableUpdates) {
+	if !odbc.IsActive() {
+		return
+	}
 
-			switch op {
-			case odbc.OpInsert:
-				taiCreateObj(objID, rowUpdate.New)
-			case odbc.OpDelete:
-				taiRemoveObj(objID, rowUpdate.Old)
-			case odbc.OpUpdate:
-				taiUpdateObj(objID, rowUpdate.New, rowUpdate.Old)
+	rows := tableRows(updates)
+	var rowErr error
+	err := taiBatch(func() error {
+		for _, row := range rows {
+			if rowErr = taiRowUpdate(row.objID, row.op, row.rowUpdate); rowErr != nil {
+				return fmt.Errorf("%s %s row %s failed %v", ObjectOrder[row.objID], row.op, row.uuid, rowErr)
 			}
 		}
+		return nil
+	})
+	if rowErr != nil {
+		log.Warning("[TAI] table updates batch aborted, %v, program rows one by one\n", err)
+		failed := taiRowsBatch(rows)
+		for _, row := range taiRowsBatch(failed) {
+			log.Warning("[TAI] %s %s row %+v retry failed %v\n", ObjectOrder[row.objID], row.op, row.rowUpdate, row.err)
+		}
+	} else if err != nil {
+		log.Warning("[TAI] table updates commit failed %v\n", err)
 	}
+	switchReplay()
 }
 
-func (c *ovsdbc) taiNotifyUpdate(updates libovsdb.TableUpdates) {
+// tableRow row of table update with its TAI object
+type tableRow struct {
+	objID     ObjID
+	op        string
+	uuid      string
+	rowUpdate libovsdb.RowUpdate
+	err       error
+}
+
+// tableRows rows of table updates of TAI objects
+func tableRows(updates libovsdb.TableUpdates) []tableRow {
+	var rows []tableRow
 	for table, tableupdate := range updates.Updates {
 		objID, err := getObjIDByTblName(table)
 		if err != nil {
@@ -327,148 +409,305 @@ func (c *ovsdbc) taiNotifyUpdate(updates libovsdb.TableUpdates) {
 		for uuid, rowUpdate := range tableupdate.Rows {
 			rowUpdate = odbc.RowUpdateOptimize(rowUpdate, uuid)
 			op := odbc.GetRowUpdateOp(rowUpdate)
+			rows = append(rows, tableRow{objID: objID, op: op, uuid: uuid, rowUpdate: rowUpdate})
+		}
+	}
+	return rows
+}
 
-			switch op {
-			case odbc.OpInsert:
-				taiCreateObj(objID, rowUpdate.New)
-			case odbc.OpDelete:
-				taiRemoveObj(objID, rowUpdate.Old)
-			case odbc.OpUpdate:
-				taiUpdateObj(objID, rowUpdate.New, rowUpdate.Old)
-			}
+// taiRowsBatch program rows one batch per row, return rows failed
+func taiRowsBatch(rows []tableRow) []tableRow {
+	var failed []tableRow
+	for _, row := range rows {
+		row.err = taiBatch(func() error {
+			return taiRowUpdate(row.objID, row.op, row.rowUpdate)
+		})
+		if row.err != nil {
+			log.Info("[TAI] %s %s row %s failed %v\n", ObjectOrder[row.objID], row.op, row.uuid, row.err)
+			failed = append(failed, row)
 		}
 	}
+	return failed
+}
+
+func taiRowUpdate(objID ObjID, op string, rowUpdate libovsdb.RowUpdate) error {
+	switch op {
+	case odbc.OpInsert:
+		return taiCreateObj(objID, rowUpdate.New)
+	case odbc.OpDelete:
+		return taiRemoveObj(objID, rowUpdate.Old)
+	case odbc.OpUpdate:
+		return taiUpdateObj(objID, rowUpdate.New, rowUpdate.Old)
+	}
+	return nil
+}
+
+// taiBatch run TAI calls of fn in one driver batch on every driver
+// instance, see taiBatchOn
+func taiBatch(fn func() error) error {
+	return taiBatchOn(allInstances(), fn)
 }
 
-func taiCreateObj(objID ObjID, row libovsdb.Row) {
+// taiBatchOn run TAI calls of fn in one driver batch per instance, batches
+// are committed if fn succeeds and aborted otherwise. Calls are still done
+// one by one on instance whose batch can't begin or which is created by
+// fn. Objects of aborted batch are not programmed until vtepdb updates
+// them again, driver state out of switch, eg ids allocated, is not rolled
+// back.
+func taiBatchOn(insts []*driverInstance, fn func() error) error {
+	var begun []*driverInstance
+	for _, inst := range insts {
+		if err := taiBegin(inst); err != nil {
+			log.Warning("[TAI] begin batch on %s failed %v, program without batch\n", inst, err)
+			continue
+		}
+		begun = append(begun, inst)
+	}
+	if err := fn(); err != nil {
+		for _, inst := range begun {
+			taiAbort(inst)
+		}
+		return err
+	}
+
+	var err error
+	for _, inst := range begun {
+		if commitErr := taiCommit(inst); commitErr != nil {
+			log.Warning("[TAI] commit batch on %s failed %v\n", inst, commitErr)
+			err = commitErr
+		}
+	}
+	return err
+}
+
+func taiCreateObj(objID ObjID, row libovsdb.Row) error {
 	obj, attrs := rowToObj(objID, row)
 
 	if obj == nil {
 		log.Warning("[TAI] taiCreateObj convert obj %v failed\n", objID)
-		return
+		return nil
 	}
 	log.Info("[TAI] obj %v attrs %v\n", obj, attrs)
 
-	err := taiCreateObject(objID, obj)
+	if objID == ObjectIDTunnel {
+		systemID := vtepdb.ConvertRowToLocator(libovsdb.ResultRow(row.Fields)).ChassisName
+		if _, err := switchAttach(systemID, obj.(TunnelObj).Name); err != nil {
+			log.Warning("[TAI] attach switch %s failed %v\n", systemID, err)
+			index := vtepdb.PhysicalSwitchIndex1{SystemID: systemID}
+			_ = vtepdb.PhysicalSwitchUpdateSwitchFaultStatusAddvalue(index, []string{err.Error()})
+			return nil
+		}
+	}
+
+	if reason := capabilityCheck(objID, obj, attrs); reason != "" {
+		faultMark(objID, obj, reason)
+		return nil
+	}
+	capabilityCheckAttrs(objID, obj, attrs)
+
+	for _, inst := range objectInstances(objID, row) {
+		if err := taiCreateObjOn(inst, objID, obj, attrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taiCreateObjOn create object with attrs on driver instance
+func taiCreateObjOn(inst *driverInstance, objID ObjID, obj interface{}, attrs Attrs) error {
+	attrs = instanceAttrs(inst, objID, attrs)
+
+	err := taiCreateObject(inst, objID, obj)
 	if err != nil {
-		log.Warning("[TAI] taiCreateObj %s failed\n", ObjectOrder[objID])
-		return
+		log.Warning("[TAI] taiCreateObj %s on %s failed\n", ObjectOrder[objID], inst)
+		return err
 	}
 
 	if len(attrs) != 0 {
-		_ = taiAddObjectAttr(objID, obj, attrs)
+		if err = taiAddObjectAttr(inst, objID, obj, attrs); err != nil {
+			log.Warning("[TAI] taiCreateObj %s on %s add attrs failed %v\n", ObjectOrder[objID], inst, err)
+			return err
+		}
 	}
+	warmRestartClaim(inst, objID, obj)
+	return nil
 }
 
-func taiRemoveObj(objID ObjID, row libovsdb.Row) {
+func taiRemoveObj(objID ObjID, row libovsdb.Row) error {
 	obj, attrs := rowToObj(objID, row)
-	// Do we need delete object attr? just remove object can work either
-	_ = taiDelObjectAttr(objID, obj, attrs)
-
+	if obj != nil && capabilityRelease(objID, obj) {
+		return nil
+	}
 	if obj == nil {
 		log.Warning("[TAI] taiRemoveObj convert obj %v failed\n", objID)
-		return
+		return nil
 	}
-	err := taiRemoveObject(objID, obj)
-	if err != nil {
-		log.Warning("[TAI] taiRemoveObj failed %v\n", err)
-		return
+
+	for _, inst := range objectInstances(objID, row) {
+		// Do we need delete object attr? just remove object can work either
+		_ = taiDelObjectAttr(inst, objID, obj, instanceAttrs(inst, objID, attrs))
+
+		err := taiRemoveObject(inst, objID, obj)
+		if err != nil {
+			log.Warning("[TAI] taiRemoveObj on %s failed %v\n", inst, err)
+			return err
+		}
+	}
+
+	if objID == ObjectIDTunnel {
+		switchDetach(vtepdb.ConvertRowToLocator(libovsdb.ResultRow(row.Fields)).ChassisName)
 	}
+	return nil
 }
 
-func taiUpdateObj(objID ObjID, newrow libovsdb.Row, oldrow libovsdb.Row) {
+func taiUpdateObj(objID ObjID, newrow libovsdb.Row, oldrow libovsdb.Row) error {
+	// old row of update only has changed columns, unchanged columns are
+	// taken from new row
+	fullOldrow := libovsdb.Row{Fields: make(map[string]interface{}, len(newrow.Fields))}
+	for column, value := range newrow.Fields {
+		fullOldrow.Fields[column] = value
+	}
+	for column, value := range oldrow.Fields {
+		fullOldrow.Fields[column] = value
+	}
+	_, oldattrs := rowToObj(objID, fullOldrow)
 	newobj, newattrs := rowToObj(objID, newrow)
-	oldattrs := rowToAttrs(objID, oldrow)
 
 	if newobj == nil {
 		log.Warning("[TAI] taiUpdateObj convert obj %v failed\n", objID)
-		return
+		return nil
+	}
+	if capabilitySkipped(objID, newobj) {
+		return nil
 	}
 
-	attrsAdd := make(map[interface{}]interface{})
-	attrsDel := make(map[interface{}]interface{})
-	attrsSet := make(map[interface{}]interface{})
-
-	for k, v1 := range newattrs {
-		if v2, ok := oldattrs[k]; ok {
-			if !reflect.DeepEqual(v1, v2) {
-				attrsSet[k] = v1
+	attrsAdd, attrsDel, attrsSet := DiffAttrs(oldattrs, newattrs)
+	for _, inst := range objectInstances(objID, newrow) {
+		if len(attrsAdd) != 0 {
+			if err := taiAddObjectAttr(inst, objID, newobj, instanceAttrs(inst, objID, attrsAdd)); err != nil {
+				return err
+			}
+		}
+		if len(attrsDel) != 0 {
+			if err := taiDelObjectAttr(inst, objID, newobj, instanceAttrs(inst, objID, attrsDel)); err != nil {
+				return err
+			}
+		}
+		if len(attrsSet) != 0 {
+			if err := taiSetObjectAttr(inst, objID, newobj, instanceAttrs(inst, objID, attrsSet)); err != nil {
+				return err
 			}
-		} else {
-			attrsAdd[k] = v1
 		}
 	}
+	return nil
+}
 
-	for k, v := range oldattrs {
-		if _, ok := oldattrs[k]; ok == false {
-			attrsDel[k] = v
+// shadowCall call shadow driver, panic of shadow driver is recovered so
+// it never breaks primary driver
+func shadowCall(shadow namedHandler, op string, call func(DriverHandler) (interface{}, error)) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("shadow driver %s %s panic: %v", shadow.name, op, r)
 		}
+	}()
+	return call(shadow.handler)
+}
+
+// taiCall call primary driver of instance and mirror call to shadow
+// drivers, result of primary driver wins. Shadow result is compared only
+// if shadow returns a non-nil result, recording drivers return nil.
+func taiCall(inst *driverInstance, objID ObjID, op string, call func(DriverHandler) (interface{}, error)) (interface{}, error) {
+	if inst == nil {
+		return nil, errors.New("NULL driver instance")
 	}
 
-	_ = taiAddObjectAttr(objID, newobj, attrsAdd)
-	_ = taiDelObjectAttr(objID, newobj, attrsDel)
-	_ = taiSetObjectAttr(objID, newobj, attrsSet)
+	result, err := call(inst.primary.handler)
+	for _, shadow := range inst.shadows {
+		shadowResult, shadowErr := shadowCall(shadow, op, call)
+		if (shadowErr == nil) != (err == nil) {
+			log.Warning("[TAI] %s shadow driver %s %s %v diverged, primary err %v, shadow err %v\n",
+				inst, shadow.name, op, ObjectOrder[objID], err, shadowErr)
+			continue
+		}
+		if !reflect.ValueOf(shadowResult).IsValid() || reflect.ValueOf(shadowResult).IsNil() {
+			continue
+		}
+		if !reflect.DeepEqual(result, shadowResult) {
+			log.Warning("[TAI] %s shadow driver %s %s %v diverged, primary %+v, shadow %+v\n",
+				inst, shadow.name, op, ObjectOrder[objID], result, shadowResult)
+		}
+	}
+	return result, err
 }
 
-func taiCreateObject(objID ObjID, obj interface{}) error {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return err
-	}
-	err = handler.TaiCreateObject(objID, obj)
+func taiBegin(inst *driverInstance) error {
+	_, err := taiCall(inst, 0, "begin", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiBegin()
+	})
 	return err
 }
 
-func taiRemoveObject(objID ObjID, obj interface{}) error {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return err
-	}
-	err = handler.TaiRemoveObject(objID, obj)
+func taiCommit(inst *driverInstance) error {
+	_, err := taiCall(inst, 0, "commit", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiCommit()
+	})
 	return err
 }
 
-func taiAddObjectAttr(objID ObjID, obj interface{}, attrs map[interface{}]interface{}) error {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return err
-	}
-	err = handler.TaiAddObjectAttr(objID, obj, attrs)
+func taiAbort(inst *driverInstance) {
+	_, _ = taiCall(inst, 0, "abort", func(handler DriverHandler) (interface{}, error) {
+		handler.TaiAbort()
+		return nil, nil
+	})
+}
+
+func taiCreateObject(inst *driverInstance, objID ObjID, obj interface{}) error {
+	_, err := taiCall(inst, objID, "create", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiCreateObject(objID, obj)
+	})
 	return err
 }
 
-func taiDelObjectAttr(objID ObjID, obj interface{}, attrs map[interface{}]interface{}) error {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return err
-	}
-	err = handler.TaiDelObjectAttr(objID, obj, attrs)
+func taiRemoveObject(inst *driverInstance, objID ObjID, obj interface{}) error {
+	_, err := taiCall(inst, objID, "remove", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiRemoveObject(objID, obj)
+	})
 	return err
 }
 
-func taiSetObjectAttr(objID ObjID, obj interface{}, attrs map[interface{}]interface{}) error {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return err
-	}
-	err = handler.TaiSetObjectAttr(objID, obj, attrs)
+func taiAddObjectAttr(inst *driverInstance, objID ObjID, obj interface{}, attrs Attrs) error {
+	_, err := taiCall(inst, objID, "add attr", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiAddObjectAttr(objID, obj, attrs)
+	})
 	return err
 }
 
-func taiGetObjectAttr(objID ObjID, obj interface{}, attrIDs []interface{}) (map[interface{}]interface{}, error) {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return nil, err
-	}
-	attrlist, err := handler.TaiGetObjectAttr(objID, obj, attrIDs)
+func taiDelObjectAttr(inst *driverInstance, objID ObjID, obj interface{}, attrs Attrs) error {
+	_, err := taiCall(inst, objID, "del attr", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiDelObjectAttr(objID, obj, attrs)
+	})
+	return err
+}
+
+func taiSetObjectAttr(inst *driverInstance, objID ObjID, obj interface{}, attrs Attrs) error {
+	_, err := taiCall(inst, objID, "set attr", func(handler DriverHandler) (interface{}, error) {
+		return nil, handler.TaiSetObjectAttr(objID, obj, attrs)
+	})
+	return err
+}
+
+func taiGetObjectAttr(inst *driverInstance, objID ObjID, obj interface{}, attrIDs []ObjAttrID) (Attrs, error) {
+	result, err := taiCall(inst, objID, "get attr", func(handler DriverHandler) (interface{}, error) {
+		return handler.TaiGetObjectAttr(objID, obj, attrIDs)
+	})
+	attrlist, _ := result.(Attrs)
 	return attrlist, err
 }
 
-func taiGetObject(objID ObjID) ([]interface{}, error) {
-	handler, err := activeTaiDriver()
-	if err != nil {
-		return nil, err
-	}
-	objs, err := handler.TaiListObject(objID)
+func taiGetObject(inst *driverInstance, objID ObjID) ([]interface{}, error) {
+	result, err := taiCall(inst, objID, "list", func(handler DriverHandler) (interface{}, error) {
+		return handler.TaiListObject(objID)
+	})
+	objs, _ := result.([]interface{})
 	return objs, err
 }
@@ -10,19 +10,28 @@ import (
 
 // ACLAttrName ...
 const (
-	ACLAttrName  = "acl_name"
-	ACLAttrPorts = "acl_ports"
-	ACLAttrStage = "acl_stage"
-	ACLAttrType  = "acl_type"
-	ACLAttrRules = "acl_rules"
+	ACLAttrName  ObjAttrID = "acl_name"
+	ACLAttrPorts ObjAttrID = "acl_ports"
+	ACLAttrStage ObjAttrID = "acl_stage"
+	ACLAttrType  ObjAttrID = "acl_type"
+	ACLAttrRules ObjAttrID = "acl_rules"
 )
 
+// aclAttrTypes value types of ACL attrs
+var aclAttrTypes = map[ObjAttrID]AttrType{
+	ACLAttrName:  AttrTypeString,
+	ACLAttrPorts: AttrTypeString,
+	ACLAttrStage: AttrTypeString,
+	ACLAttrType:  AttrTypeString,
+	ACLAttrRules: AttrTypeIntList,
+}
+
 // ACLObj ...
 type ACLObj struct {
 	ACLName string
 }
 
-func rowToACLObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToACLObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableACL := vtepdb.ConvertRowToACL(libovsdb.ResultRow(row.Fields))
 
 	obj := ACLObj{
@@ -41,7 +50,7 @@ func rowToACLObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
 		}
 	}
 
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		ACLAttrName:  tableACL.Name,
 		ACLAttrPorts: tableACL.Ports,
 		ACLAttrStage: tableACL.Stage,
@@ -50,34 +59,3 @@ func rowToACLObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
 	}
 	return obj, attrs
 }
-
-func rowToACLAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		// acl field name & type & stage can't be updated
-		case vtepdb.ACLFieldPorts:
-			if port, ok := value.(string); ok {
-				attrs[ACLAttrPorts] = port
-			}
-		case vtepdb.ACLFieldACLRules:
-			if aclRules, ok := value.([]libovsdb.UUID); ok {
-				var aclRulesSequence []int
-				if len(aclRules) != 0 {
-					for _, aclRuleUUID := range aclRules {
-						tableACLRule, err := vtepdb.ACLRuleGetByUUID(aclRuleUUID.GoUUID)
-						if err != nil {
-							log.Warning("ACLRule %s not exist", aclRuleUUID.GoUUID)
-							continue
-						}
-						aclRulesSequence = append(aclRulesSequence, tableACLRule.Sequence)
-					}
-				}
-				attrs[ACLAttrRules] = aclRulesSequence
-			}
-		}
-	}
-
-	return attrs
-}
@@ -8,39 +8,60 @@ import (
 
 // ACLRuleAttr ...
 const (
-	ACLRuleAttrMatchSRCMAC       = "aclrule_match_srcmac"
-	ACLRuleAttrMatchDSTMAC       = "aclrule_match_dstmac"
-	ACLRuleAttrMatchETHERTYPE    = "aclrule_match_ethertype"
-	ACLRuleAttrMatchSRCIP        = "aclrule_match_srcip"
-	ACLRuleAttrMatchSRCMASK      = "aclrule_match_srcmask"
-	ACLRuleAttrMatchDSTIP        = "aclrule_match_dstip"
-	ACLRuleAttrMatchDSTMASK      = "aclrule_match_dstmask"
-	ACLRuleAttrMatchPROTOCOL     = "aclrule_match_protocol"
-	ACLRuleAttrMatchSRCPORTMIN   = "aclrule_match_srcportmin"
-	ACLRuleAttrMatchSRCPORTMAX   = "aclrule_match_srcportmax"
-	ACLRuleAttrMatchDSTPORTMIN   = "aclrule_match_dstportmin"
-	ACLRuleAttrMatchDSTPORTMAX   = "aclrule_match_dstportmax"
-	ACLRuleAttrMatchTCPFLAGS     = "aclrule_match_tcpflags"
-	ACLRuleAttrMatchTCPFLAGSMASK = "aclrule_match_tcpflagmask"
-	ACLRuleAttrMatchICMPTYPE     = "aclrule_match_icmptype"
-	ACLRuleAttrMatchICMPCODE     = "aclrule_match_icmpcode"
-	ACLRuleAttrAction            = "aclrule_action"
+	ACLRuleAttrMatchSRCMAC       ObjAttrID = "aclrule_match_srcmac"
+	ACLRuleAttrMatchDSTMAC       ObjAttrID = "aclrule_match_dstmac"
+	ACLRuleAttrMatchETHERTYPE    ObjAttrID = "aclrule_match_ethertype"
+	ACLRuleAttrMatchSRCIP        ObjAttrID = "aclrule_match_srcip"
+	ACLRuleAttrMatchSRCMASK      ObjAttrID = "aclrule_match_srcmask"
+	ACLRuleAttrMatchDSTIP        ObjAttrID = "aclrule_match_dstip"
+	ACLRuleAttrMatchDSTMASK      ObjAttrID = "aclrule_match_dstmask"
+	ACLRuleAttrMatchPROTOCOL     ObjAttrID = "aclrule_match_protocol"
+	ACLRuleAttrMatchSRCPORTMIN   ObjAttrID = "aclrule_match_srcportmin"
+	ACLRuleAttrMatchSRCPORTMAX   ObjAttrID = "aclrule_match_srcportmax"
+	ACLRuleAttrMatchDSTPORTMIN   ObjAttrID = "aclrule_match_dstportmin"
+	ACLRuleAttrMatchDSTPORTMAX   ObjAttrID = "aclrule_match_dstportmax"
+	ACLRuleAttrMatchTCPFLAGS     ObjAttrID = "aclrule_match_tcpflags"
+	ACLRuleAttrMatchTCPFLAGSMASK ObjAttrID = "aclrule_match_tcpflagmask"
+	ACLRuleAttrMatchICMPTYPE     ObjAttrID = "aclrule_match_icmptype"
+	ACLRuleAttrMatchICMPCODE     ObjAttrID = "aclrule_match_icmpcode"
+	ACLRuleAttrAction            ObjAttrID = "aclrule_action"
 )
 
+// aclRuleAttrTypes value types of ACL rule attrs
+var aclRuleAttrTypes = map[ObjAttrID]AttrType{
+	ACLRuleAttrMatchSRCMAC:       AttrTypeStringList,
+	ACLRuleAttrMatchDSTMAC:       AttrTypeStringList,
+	ACLRuleAttrMatchETHERTYPE:    AttrTypeStringList,
+	ACLRuleAttrMatchSRCIP:        AttrTypeStringList,
+	ACLRuleAttrMatchSRCMASK:      AttrTypeStringList,
+	ACLRuleAttrMatchDSTIP:        AttrTypeStringList,
+	ACLRuleAttrMatchDSTMASK:      AttrTypeStringList,
+	ACLRuleAttrMatchPROTOCOL:     AttrTypeIntList,
+	ACLRuleAttrMatchSRCPORTMIN:   AttrTypeIntList,
+	ACLRuleAttrMatchSRCPORTMAX:   AttrTypeIntList,
+	ACLRuleAttrMatchDSTPORTMIN:   AttrTypeIntList,
+	ACLRuleAttrMatchDSTPORTMAX:   AttrTypeIntList,
+	ACLRuleAttrMatchTCPFLAGS:     AttrTypeIntList,
+	ACLRuleAttrMatchTCPFLAGSMASK: AttrTypeIntList,
+	ACLRuleAttrMatchICMPTYPE:     AttrTypeIntList,
+	ACLRuleAttrMatchICMPCODE:     AttrTypeIntList,
+	ACLRuleAttrAction:            AttrTypeString,
+}
+
 // ACLRuleObj ...
 type ACLRuleObj struct {
 	ACLName  string
 	Sequence int
 }
 
-func rowToACLRuleObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToACLRuleObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableACLRule := vtepdb.ConvertRowToACLRule(libovsdb.ResultRow(row.Fields))
 
 	obj := ACLRuleObj{
 		ACLName:  tableACLRule.ACLName,
 		Sequence: tableACLRule.Sequence,
 	}
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		ACLRuleAttrMatchSRCMAC:       tableACLRule.SourceMac,
 		ACLRuleAttrMatchDSTMAC:       tableACLRule.DestMac,
 		ACLRuleAttrMatchETHERTYPE:    tableACLRule.Ethertype,
@@ -61,22 +82,3 @@ func rowToACLRuleObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}
 	}
 	return obj, attrs
 }
-
-func rowToACLRuleAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.ACLRuleFieldSourceMac:
-			if srcMac, ok := value.(string); ok {
-				attrs[ACLRuleAttrMatchSRCMAC] = srcMac
-			}
-		case vtepdb.ACLRuleFieldDestMac:
-			if dstMac, ok := value.(string); ok {
-				attrs[ACLRuleAttrMatchDSTMAC] = dstMac
-			}
-		}
-	}
-
-	return attrs
-}
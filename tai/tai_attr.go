@@ -0,0 +1,367 @@
+package tai
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ebay/libovsdb"
+)
+
+// AttrType value type of TAI attr
+type AttrType int
+
+// TAI attr value types, list and map attrs are never passed to driver as
+// single value
+const (
+	_ AttrType = iota
+	AttrTypeString
+	AttrTypeInt
+	AttrTypeBool
+	AttrTypeStringList
+	AttrTypeIntList
+	AttrTypeStringMap
+)
+
+var attrTypeNames = []string{
+	AttrTypeString:     "string",
+	AttrTypeInt:        "int",
+	AttrTypeBool:       "bool",
+	AttrTypeStringList: "[]string",
+	AttrTypeIntList:    "[]int",
+	AttrTypeStringMap:  "map[string]string",
+}
+
+func (t AttrType) String() string {
+	if t <= 0 || int(t) >= len(attrTypeNames) {
+		return fmt.Sprintf("AttrType(%d)", int(t))
+	}
+	return attrTypeNames[t]
+}
+
+// objAttrTypes declared attrs of every TAI object, attrs not declared
+// here are rejected by Attrs
+var objAttrTypes = map[ObjID]map[ObjAttrID]AttrType{
+	ObjectIDBridge:          bridgeAttrTypes,
+	ObjectIDVrf:             vrfAttrTypes,
+	ObjectIDL2Port:          l2portAttrTypes,
+	ObjectIDL3Port:          l3portAttrTypes,
+	ObjectIDFDB:             fdbAttrTypes,
+	ObjectIDNeighbour:       neighbourAttrTypes,
+	ObjectIDRoute:           routeAttrTypes,
+	ObjectIDTunnel:          tunnelAttrTypes,
+	ObjectIDMcastFDB:        mcastfdbAttrTypes,
+	ObjectIDACL:             aclAttrTypes,
+	ObjectIDACLRule:         aclRuleAttrTypes,
+	ObjectIDPBR:             pbrAttrTypes,
+	ObjectIDAutoGatewayConf: autoGatewayConfAttrTypes,
+}
+
+// attrObjIDs object of attr, attr ids are unique among objects
+var attrObjIDs = func() map[ObjAttrID]ObjID {
+	objIDs := make(map[ObjAttrID]ObjID)
+	for objID, attrTypes := range objAttrTypes {
+		for id := range attrTypes {
+			if _, ok := objIDs[id]; ok {
+				panic(fmt.Sprintf("tai: attr %s declared twice", id))
+			}
+			objIDs[id] = objID
+		}
+	}
+	return objIDs
+}()
+
+// ObjAttrIDs sorted attr ids of object
+func ObjAttrIDs(objID ObjID) []ObjAttrID {
+	ids := make([]ObjAttrID, 0, len(objAttrTypes[objID]))
+	for id := range objAttrTypes[objID] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ObjID object of attr, 0 if attr is not declared
+func (id ObjAttrID) ObjID() ObjID {
+	return attrObjIDs[id]
+}
+
+// Type declared value type of attr, 0 if attr is not declared
+func (id ObjAttrID) Type() AttrType {
+	return objAttrTypes[attrObjIDs[id]][id]
+}
+
+// Attrs attr values of TAI object. Values set by Set or NewAttrs are
+// normalized to the declared type of attr: string, int, bool, []string,
+// []int or map[string]string.
+type Attrs map[ObjAttrID]interface{}
+
+// NewAttrs build attrs of object from raw values, values of ovsdb rows
+// (float number, uuid, set and map) are converted to declared type
+func NewAttrs(objID ObjID, values map[ObjAttrID]interface{}) (Attrs, error) {
+	attrs := make(Attrs, len(values))
+	for id, value := range values {
+		if id.ObjID() != objID {
+			return attrs, fmt.Errorf("tai: attr %s not declared for %s", id, ObjectOrder[objID])
+		}
+		if err := attrs.Set(id, value); err != nil {
+			return attrs, err
+		}
+	}
+	return attrs, nil
+}
+
+// Set normalize value to declared type of attr and set it
+func (a Attrs) Set(id ObjAttrID, value interface{}) error {
+	attrType := id.Type()
+	if attrType == 0 {
+		return fmt.Errorf("tai: unknown attr %s", id)
+	}
+	v, ok := attrValue(attrType, value)
+	if !ok {
+		return fmt.Errorf("tai: attr %s expects %s, got %T", id, attrType, value)
+	}
+	a[id] = v
+	return nil
+}
+
+// Validate check all attrs are declared for object with value of
+// declared type
+func (a Attrs) Validate(objID ObjID) error {
+	for id, value := range a {
+		if id.ObjID() != objID {
+			return fmt.Errorf("tai: attr %s not declared for %s", id, ObjectOrder[objID])
+		}
+		if !attrTypeOf(id.Type(), value) {
+			return fmt.Errorf("tai: attr %s expects %s, got %T", id, id.Type(), value)
+		}
+	}
+	return nil
+}
+
+// Has check whether attr is set with non empty value
+func (a Attrs) Has(id ObjAttrID) bool {
+	value, ok := a[id]
+	return ok && !emptyAttr(value)
+}
+
+// IDs sorted ids of attrs
+func (a Attrs) IDs() []ObjAttrID {
+	ids := make([]ObjAttrID, 0, len(a))
+	for id := range a {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// GetString get string attr, "" if not set
+func (a Attrs) GetString(id ObjAttrID) string {
+	v, _ := attrValue(AttrTypeString, a[id])
+	s, _ := v.(string)
+	return s
+}
+
+// GetInt get int attr, 0 if not set
+func (a Attrs) GetInt(id ObjAttrID) int {
+	v, _ := attrValue(AttrTypeInt, a[id])
+	i, _ := v.(int)
+	return i
+}
+
+// GetBool get bool attr, false if not set
+func (a Attrs) GetBool(id ObjAttrID) bool {
+	v, _ := attrValue(AttrTypeBool, a[id])
+	b, _ := v.(bool)
+	return b
+}
+
+// GetStrings get string list attr, single string value is taken as list
+func (a Attrs) GetStrings(id ObjAttrID) []string {
+	v, _ := attrValue(AttrTypeStringList, a[id])
+	s, _ := v.([]string)
+	return s
+}
+
+// GetInts get int list attr, single int value is taken as list
+func (a Attrs) GetInts(id ObjAttrID) []int {
+	v, _ := attrValue(AttrTypeIntList, a[id])
+	i, _ := v.([]int)
+	return i
+}
+
+// GetStringMap get string map attr
+func (a Attrs) GetStringMap(id ObjAttrID) map[string]string {
+	v, _ := attrValue(AttrTypeStringMap, a[id])
+	m, _ := v.(map[string]string)
+	return m
+}
+
+// DiffAttrs compare attrs of object before and after update. Attrs only
+// in new are added, attrs only in old are deleted with old value and
+// attrs changed are set. Empty value is taken as not set.
+func DiffAttrs(oldAttrs Attrs, newAttrs Attrs) (add Attrs, del Attrs, set Attrs) {
+	add, del, set = make(Attrs), make(Attrs), make(Attrs)
+	for id, value := range newAttrs {
+		oldSet, newSet := oldAttrs.Has(id), newAttrs.Has(id)
+		switch {
+		case newSet && !oldSet:
+			add[id] = value
+		case !newSet && oldSet:
+			del[id] = oldAttrs[id]
+		case newSet && !reflect.DeepEqual(oldAttrs[id], value):
+			set[id] = value
+		}
+	}
+	for id, value := range oldAttrs {
+		if _, ok := newAttrs[id]; !ok && oldAttrs.Has(id) {
+			del[id] = value
+		}
+	}
+	return add, del, set
+}
+
+// attrTypeOf check whether value is of normalized attr type
+func attrTypeOf(attrType AttrType, value interface{}) bool {
+	switch value.(type) {
+	case string:
+		return attrType == AttrTypeString
+	case int:
+		return attrType == AttrTypeInt
+	case bool:
+		return attrType == AttrTypeBool
+	case []string:
+		return attrType == AttrTypeStringList
+	case []int:
+		return attrType == AttrTypeIntList
+	case map[string]string:
+		return attrType == AttrTypeStringMap
+	}
+	return false
+}
+
+// attrValue convert value to attr type. ovsdb returns single element set
+// as the element and float number for integer, nil is zero value.
+func attrValue(attrType AttrType, value interface{}) (interface{}, bool) {
+	switch attrType {
+	case AttrTypeString:
+		switch v := value.(type) {
+		case nil:
+			return "", true
+		case string:
+			return v, true
+		case libovsdb.UUID:
+			return v.GoUUID, true
+		}
+		if list, ok := attrValue(AttrTypeStringList, value); ok && len(list.([]string)) <= 1 {
+			if len(list.([]string)) == 0 {
+				return "", true
+			}
+			return list.([]string)[0], true
+		}
+	case AttrTypeInt:
+		switch v := value.(type) {
+		case nil:
+			return 0, true
+		case int:
+			return v, true
+		case float64:
+			return int(v), true
+		}
+		if list, ok := attrValue(AttrTypeIntList, value); ok && len(list.([]int)) <= 1 {
+			if len(list.([]int)) == 0 {
+				return 0, true
+			}
+			return list.([]int)[0], true
+		}
+	case AttrTypeBool:
+		switch v := value.(type) {
+		case nil:
+			return false, true
+		case bool:
+			return v, true
+		}
+	case AttrTypeStringList:
+		switch v := value.(type) {
+		case nil:
+			return []string(nil), true
+		case string:
+			if v == "" {
+				return []string(nil), true
+			}
+			return []string{v}, true
+		case libovsdb.UUID:
+			return []string{v.GoUUID}, true
+		case []string:
+			return v, true
+		case []libovsdb.UUID:
+			list := make([]string, 0, len(v))
+			for _, uuid := range v {
+				list = append(list, uuid.GoUUID)
+			}
+			return list, true
+		case libovsdb.OvsSet:
+			return attrValue(attrType, v.GoSet)
+		case *libovsdb.OvsSet:
+			return attrValue(attrType, v.GoSet)
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, elem := range v {
+				s, ok := attrValue(AttrTypeString, elem)
+				if !ok || elem == nil {
+					return nil, false
+				}
+				list = append(list, s.(string))
+			}
+			return list, true
+		}
+	case AttrTypeIntList:
+		switch v := value.(type) {
+		case nil:
+			return []int(nil), true
+		case int:
+			return []int{v}, true
+		case float64:
+			return []int{int(v)}, true
+		case []int:
+			return v, true
+		case libovsdb.OvsSet:
+			return attrValue(attrType, v.GoSet)
+		case *libovsdb.OvsSet:
+			return attrValue(attrType, v.GoSet)
+		case []interface{}:
+			list := make([]int, 0, len(v))
+			for _, elem := range v {
+				i, ok := attrValue(AttrTypeInt, elem)
+				if !ok || elem == nil {
+					return nil, false
+				}
+				list = append(list, i.(int))
+			}
+			return list, true
+		}
+	case AttrTypeStringMap:
+		switch v := value.(type) {
+		case nil:
+			return map[string]string(nil), true
+		case map[string]string:
+			return v, true
+		case libovsdb.OvsMap:
+			return attrValue(attrType, v.GoMap)
+		case *libovsdb.OvsMap:
+			return attrValue(attrType, v.GoMap)
+		case map[interface{}]interface{}:
+			m := make(map[string]string, len(v))
+			for key, elem := range v {
+				k, ok1 := attrValue(AttrTypeString, key)
+				e, ok2 := attrValue(AttrTypeString, elem)
+				if !ok1 || !ok2 {
+					return nil, false
+				}
+				m[k.(string)] = e.(string)
+			}
+			return m, true
+		}
+	}
+	return nil, false
+}
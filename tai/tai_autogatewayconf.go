@@ -1,6 +1,10 @@
 package tai
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
@@ -10,12 +14,28 @@ import (
 
 // AutoGatewayConfObj attr list
 const (
-	AutoGatewayConfAttrBdname       = "autogatewayconf_bdname"
-	AutoGatewayConfAttrVlan         = "autogatewayconf_vlan"
-	AutoGatewayConfAttrIP           = "autogatewayconf_ip"
-	AutoGatewayConfAttrPhysicalPort = "autogatewayconf_physicalport"
+	AutoGatewayConfAttrBdname       ObjAttrID = "autogatewayconf_bdname"
+	AutoGatewayConfAttrVlan         ObjAttrID = "autogatewayconf_vlan"
+	AutoGatewayConfAttrIP           ObjAttrID = "autogatewayconf_ip"
+	AutoGatewayConfAttrPhysicalPort ObjAttrID = "autogatewayconf_physicalport"
+	AutoGatewayConfAttrIpv6         ObjAttrID = "autogatewayconf_ipv6"
+	AutoGatewayConfAttrMtu          ObjAttrID = "autogatewayconf_mtu"
+	AutoGatewayConfAttrUplinks      ObjAttrID = "autogatewayconf_uplinks"
+	AutoGatewayConfAttrNexthops     ObjAttrID = "autogatewayconf_nexthops"
 )
 
+// autoGatewayConfAttrTypes value types of auto gateway conf attrs
+var autoGatewayConfAttrTypes = map[ObjAttrID]AttrType{
+	AutoGatewayConfAttrBdname:       AttrTypeString,
+	AutoGatewayConfAttrVlan:         AttrTypeInt,
+	AutoGatewayConfAttrIP:           AttrTypeString,
+	AutoGatewayConfAttrPhysicalPort: AttrTypeString,
+	AutoGatewayConfAttrIpv6:         AttrTypeString,
+	AutoGatewayConfAttrMtu:          AttrTypeInt,
+	AutoGatewayConfAttrUplinks:      AttrTypeStringMap,
+	AutoGatewayConfAttrNexthops:     AttrTypeStringList,
+}
+
 // AutoGatewayConfObj ...
 type AutoGatewayConfObj struct {
 	Bdname       string
@@ -25,7 +45,7 @@ type AutoGatewayConfObj struct {
 	Vrf          string
 }
 
-func rowToAutoGatewayConfObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToAutoGatewayConfObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableAutoGatewayConf := vtepdb.ConvertRowToAutoGatewayConf(libovsdb.ResultRow(row.Fields))
 	log.Info("Table autogatewayconf : %+v.\n", tableAutoGatewayConf)
 
@@ -36,37 +56,74 @@ func rowToAutoGatewayConfObj(row libovsdb.Row) (interface{}, map[interface{}]int
 		PhysicalPort: tableAutoGatewayConf.PhysicalPort,
 		Vrf:          tableAutoGatewayConf.Vrf,
 	}
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		AutoGatewayConfAttrBdname:       tableAutoGatewayConf.Bdname,
 		AutoGatewayConfAttrVlan:         tableAutoGatewayConf.Vlan,
 		AutoGatewayConfAttrIP:           tableAutoGatewayConf.IP,
 		AutoGatewayConfAttrPhysicalPort: tableAutoGatewayConf.PhysicalPort,
+		AutoGatewayConfAttrIpv6:         tableAutoGatewayConf.Ipv6,
+		AutoGatewayConfAttrUplinks:      tableAutoGatewayConf.Uplinks,
+		AutoGatewayConfAttrNexthops:     tableAutoGatewayConf.Nexthops,
+	}
+	if len(tableAutoGatewayConf.Mtu) > 0 {
+		attrs[AutoGatewayConfAttrMtu] = tableAutoGatewayConf.Mtu[0]
 	}
 	return obj, attrs
 }
 
-func rowToAutoGatewayConfAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
+// AutoGatewayUplink l3 interface of vrf external network, physical port or
+// LAG is a routed port without vlan, otherwise sub interface <port>.<vlan>
+type AutoGatewayUplink struct {
+	Name string
+	Port string
+	Vlan int
+	IPs  []string
+}
+
+// parseAutoGatewayUplink uplink of interface name with comma separated
+// addresses
+func parseAutoGatewayUplink(name string, ips string) AutoGatewayUplink {
+	uplink := AutoGatewayUplink{
+		Name: name,
+		Port: name,
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		if vlan, err := strconv.Atoi(name[i+1:]); err == nil {
+			uplink.Port, uplink.Vlan = name[:i], vlan
+		}
+	}
+	for _, ip := range strings.Split(ips, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			uplink.IPs = append(uplink.IPs, ip)
+		}
+	}
+	return uplink
+}
+
+// AutoGatewayUplinks uplinks of auto gateway conf, the first one is
+// physical port with vlan and addresses of localnet, the others are
+// configured uplinks with their own addresses sorted by name. There is no
+// uplink before vrf is attached to external network by bd of localnet.
+func AutoGatewayUplinks(tableAGC vtepdb.TableAutoGatewayConf) []AutoGatewayUplink {
+	if tableAGC.Bdname == "" || tableAGC.PhysicalPort == "" {
+		return nil
+	}
+	name := tableAGC.PhysicalPort
+	if tableAGC.Vlan != 0 {
+		name += "." + strconv.Itoa(tableAGC.Vlan)
+	}
+	uplinks := []AutoGatewayUplink{parseAutoGatewayUplink(name, tableAGC.IP+","+tableAGC.Ipv6)}
 
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.AutoGatewayConfFieldBdname:
-			if bdname, ok := value.(string); ok {
-				attrs[AutoGatewayConfAttrBdname] = bdname
-			}
-		case vtepdb.AutoGatewayConfFieldVlan:
-			if vlan, ok := value.(string); ok {
-				attrs[AutoGatewayConfAttrVlan] = vlan
-			}
-		case vtepdb.AutoGatewayConfFieldIP:
-			if ip, ok := value.(string); ok {
-				attrs[AutoGatewayConfAttrIP] = ip
-			}
-		case vtepdb.AutoGatewayConfFieldPhysicalPort:
-			if physicalPort, ok := value.(string); ok {
-				attrs[AutoGatewayConfAttrPhysicalPort] = physicalPort
-			}
+	var names []string
+	for key := range tableAGC.Uplinks {
+		if name, ok := key.(string); ok && name != uplinks[0].Name {
+			names = append(names, name)
 		}
 	}
-	return attrs
+	sort.Strings(names)
+	for _, name := range names {
+		ips, _ := tableAGC.Uplinks[name].(string)
+		uplinks = append(uplinks, parseAutoGatewayUplink(name, ips))
+	}
+	return uplinks
 }
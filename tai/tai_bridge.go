@@ -8,41 +8,32 @@ import (
 
 // TAI bridge attr list
 const (
-	BridgeAttrL2vni       = "bridge_l2vni"
-	BridgeAttrVxlanTunnel = "bridge_vxlan_tunnel"
+	BridgeAttrL2vni       ObjAttrID = "bridge_l2vni"
+	BridgeAttrVxlanTunnel ObjAttrID = "bridge_vxlan_tunnel"
 )
 
+// bridgeAttrTypes value types of Bridge attrs
+var bridgeAttrTypes = map[ObjAttrID]AttrType{
+	BridgeAttrL2vni:       AttrTypeInt,
+	BridgeAttrVxlanTunnel: AttrTypeString,
+}
+
 // BridgeObj ...
 type BridgeObj struct {
 	Name string //"Bd"+str(vni)
 	Vni  int
 }
 
-func rowToBridgeObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToBridgeObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableBridgeDomain := vtepdb.ConvertRowToBridgeDomain(libovsdb.ResultRow(row.Fields))
 
 	obj := BridgeObj{
 		Name: tableBridgeDomain.Name,
 		Vni:  tableBridgeDomain.L2vni,
 	}
-	attrs := map[interface{}]interface{}{
-		BridgeAttrVxlanTunnel: LocalPhsicalSwitchTunnelName,
+	// tunnel is set per driver instance
+	attrs := Attrs{
+		BridgeAttrVxlanTunnel: "",
 	}
 	return obj, attrs
 }
-
-func rowToBridgeAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.BridgeDomainFieldL2vni:
-			// TODO: for old value is nil ovsset
-			if l2Vni, ok := value.(string); ok {
-				attrs[BridgeAttrL2vni] = l2Vni
-			}
-		}
-	}
-
-	return attrs
-}
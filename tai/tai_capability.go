@@ -0,0 +1,340 @@
+package tai
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// driver limits
+const (
+	LimitBridge     = "max_bridge"
+	LimitVrf        = "max_vrf"
+	LimitACLRule    = "max_acl_rule"
+	LimitECMPMember = "max_ecmp_member"
+)
+
+// objectLimits limit of object count
+var objectLimits = map[ObjID]string{
+	ObjectIDBridge:  LimitBridge,
+	ObjectIDVrf:     LimitVrf,
+	ObjectIDACLRule: LimitACLRule,
+}
+
+// Capability of a TAI driver, queried once after driver init. Objects
+// not in Objects are not supported by driver, nil attr list means all
+// attrs of the object are supported. Limits not set are unlimited.
+type Capability struct {
+	Objects map[ObjID][]ObjAttrID
+	Limits  map[string]int
+}
+
+// FullCapability capability of driver supporting all objects and attrs
+// without limit
+func FullCapability() Capability {
+	capability := Capability{
+		Objects: make(map[ObjID][]ObjAttrID),
+	}
+	for objID := range ObjectOrder {
+		if objID != 0 {
+			capability.Objects[ObjID(objID)] = nil
+		}
+	}
+	return capability
+}
+
+// ObjectSupported check whether object is supported
+func (c Capability) ObjectSupported(objID ObjID) bool {
+	_, ok := c.Objects[objID]
+	return ok
+}
+
+// AttrSupported check whether attr of object is supported
+func (c Capability) AttrSupported(objID ObjID, attr ObjAttrID) bool {
+	attrs, ok := c.Objects[objID]
+	if !ok {
+		return false
+	}
+	if attrs == nil {
+		return true
+	}
+	for _, a := range attrs {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit get limit by name, 0 means unlimited
+func (c Capability) Limit(name string) int {
+	return c.Limits[name]
+}
+
+// capabilityIntersect capability supported by both, the lower limit wins
+func capabilityIntersect(a Capability, b Capability) Capability {
+	capability := Capability{
+		Objects: make(map[ObjID][]ObjAttrID),
+		Limits:  make(map[string]int),
+	}
+	for objID, attrsA := range a.Objects {
+		attrsB, ok := b.Objects[objID]
+		if !ok {
+			continue
+		}
+		switch {
+		case attrsA == nil:
+			capability.Objects[objID] = attrsB
+		case attrsB == nil:
+			capability.Objects[objID] = attrsA
+		default:
+			attrs := []ObjAttrID{}
+			for _, attr := range attrsA {
+				if b.AttrSupported(objID, attr) {
+					attrs = append(attrs, attr)
+				}
+			}
+			capability.Objects[objID] = attrs
+		}
+	}
+	for name, limit := range a.Limits {
+		capability.Limits[name] = limit
+	}
+	for name, limit := range b.Limits {
+		if current, ok := capability.Limits[name]; !ok || current == 0 || (limit != 0 && limit < current) {
+			capability.Limits[name] = limit
+		}
+	}
+	return capability
+}
+
+// capabilityUpdate capability of TAI after switch attached or detached,
+// objects are checked against driver instances with switch attached.
+// Mutex must be held.
+func capabilityUpdate() {
+	insts := attachedInstances()
+	if len(insts) == 0 {
+		return
+	}
+	capability := insts[0].capability
+	for _, inst := range insts[1:] {
+		capability = capabilityIntersect(capability, inst.capability)
+	}
+	tai.capability = capability
+}
+
+// String capability in sorted lines for debugging
+func (c Capability) String() string {
+	var lines []string
+	for objID, name := range ObjectOrder {
+		if name == "" {
+			continue
+		}
+		attrs, ok := c.Objects[ObjID(objID)]
+		switch {
+		case !ok:
+			lines = append(lines, fmt.Sprintf("%s: unsupported", name))
+		case attrs == nil:
+			lines = append(lines, fmt.Sprintf("%s: all attrs", name))
+		default:
+			sorted := make([]string, 0, len(attrs))
+			for _, attr := range attrs {
+				sorted = append(sorted, string(attr))
+			}
+			sort.Strings(sorted)
+			lines = append(lines, fmt.Sprintf("%s: %s", name, strings.Join(sorted, " ")))
+		}
+	}
+
+	limits := make([]string, 0, len(c.Limits))
+	for name := range c.Limits {
+		limits = append(limits, name)
+	}
+	sort.Strings(limits)
+	for _, name := range limits {
+		lines = append(lines, fmt.Sprintf("%s: %d", name, c.Limits[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DriverCapability capability of active driver, common capability of
+// driver instances with switch attached
+func DriverCapability() Capability {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	return tai.capability
+}
+
+// unrealised vtepdb objects skipped for driver capability, removing them
+// clears the fault instead of calling driver. Realised objects are kept
+// as set for limit check, initial update after reconnect creates them
+// again.
+var unrealised = struct {
+	mutex    sync.Mutex
+	objs     map[ObjID]map[interface{}]string
+	realised map[ObjID]map[interface{}]bool
+}{
+	objs:     make(map[ObjID]map[interface{}]string),
+	realised: make(map[ObjID]map[interface{}]bool),
+}
+
+// emptyAttr nil, zero or empty list value is not programmed by driver
+func emptyAttr(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	}
+	return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+}
+
+// capabilityCheck check object against active driver capability and
+// record it if realisable, return fault reason otherwise
+func capabilityCheck(objID ObjID, obj interface{}, attrs Attrs) string {
+	capability := DriverCapability()
+	if !capability.ObjectSupported(objID) {
+		return "object not supported by driver"
+	}
+
+	unrealised.mutex.Lock()
+	defer unrealised.mutex.Unlock()
+	if unrealised.realised[objID][obj] {
+		return ""
+	}
+
+	if limitName, ok := objectLimits[objID]; ok {
+		if limit := capability.Limit(limitName); limit != 0 && len(unrealised.realised[objID]) >= limit {
+			return fmt.Sprintf("exceed driver limit %s %d", limitName, limit)
+		}
+	}
+	if objID == ObjectIDPBR {
+		nhGroup := attrs.GetStrings(PBRAttrNexthopGroup)
+		if limit := capability.Limit(LimitECMPMember); limit != 0 && len(nhGroup) > limit {
+			return fmt.Sprintf("exceed driver limit %s %d", LimitECMPMember, limit)
+		}
+	}
+
+	if unrealised.realised[objID] == nil {
+		unrealised.realised[objID] = make(map[interface{}]bool)
+	}
+	unrealised.realised[objID][obj] = true
+	return ""
+}
+
+// capabilityRelease release object recorded by capabilityCheck, return
+// true if object was skipped and no driver call is needed
+func capabilityRelease(objID ObjID, obj interface{}) bool {
+	unrealised.mutex.Lock()
+	reason, skipped := unrealised.objs[objID][obj]
+	delete(unrealised.objs[objID], obj)
+	delete(unrealised.realised[objID], obj)
+	unrealised.mutex.Unlock()
+
+	if skipped {
+		faultClear(objID, obj, reason)
+	}
+	return skipped
+}
+
+// capabilitySkipped check whether object was skipped for capability
+func capabilitySkipped(objID ObjID, obj interface{}) bool {
+	unrealised.mutex.Lock()
+	defer unrealised.mutex.Unlock()
+	_, ok := unrealised.objs[objID][obj]
+	return ok
+}
+
+// capabilityCheckAttrs log attrs not supported by active driver, attrs
+// are still passed to driver as some drivers program the object itself
+// on any attr change
+func capabilityCheckAttrs(objID ObjID, obj interface{}, attrs Attrs) {
+	capability := DriverCapability()
+	for _, id := range attrs.IDs() {
+		if attrs.Has(id) && !capability.AttrSupported(objID, id) {
+			log.Info("[TAI] %s %+v attr %s not supported by driver, ignored\n",
+				ObjectOrder[objID], obj, id)
+		}
+	}
+}
+
+// faultMark skip object and write fault to vtepdb
+func faultMark(objID ObjID, obj interface{}, reason string) {
+	unrealised.mutex.Lock()
+	if unrealised.objs[objID] == nil {
+		unrealised.objs[objID] = make(map[interface{}]string)
+	}
+	unrealised.objs[objID][obj] = reason
+	unrealised.mutex.Unlock()
+
+	log.Warning("[TAI] %s, skipped\n", faultMessage(objID, obj, reason))
+	faultStatusUpdate(objID, obj, reason, true)
+}
+
+// faultClear clear fault written by faultMark, fault status of ACL and
+// ACL rule is removed with their row
+func faultClear(objID ObjID, obj interface{}, reason string) {
+	switch obj.(type) {
+	case ACLObj, ACLRuleObj:
+		return
+	}
+	faultStatusUpdate(objID, obj, reason, false)
+}
+
+// faultStatusUpdate add or del fault of object in vtepdb, ACL and ACL
+// rule have their own fault status column, other objects are reported
+// on local physical switch
+func faultStatusUpdate(objID ObjID, obj interface{}, reason string, add bool) {
+	var err error
+	switch o := obj.(type) {
+	case ACLObj:
+		index := vtepdb.ACLIndex{Name: o.ACLName}
+		if add {
+			err = vtepdb.ACLUpdateACLFaultStatusAddvalue(index, []string{reason})
+		} else {
+			err = vtepdb.ACLUpdateACLFaultStatusDelvalue(index, []string{reason})
+		}
+	case ACLRuleObj:
+		index := vtepdb.ACLRuleIndex{ACLName: o.ACLName, Sequence: o.Sequence}
+		if add {
+			err = vtepdb.ACLRuleUpdateAcleFaultStatusAddvalue(index, []string{reason})
+		} else {
+			err = vtepdb.ACLRuleUpdateAcleFaultStatusDelvalue(index, []string{reason})
+		}
+	default:
+		err = switchFaultStatusUpdate(faultMessage(objID, obj, reason), add)
+	}
+	if err != nil {
+		log.Warning("[TAI] update fault status of %s failed %v\n", ObjectOrder[objID], err)
+	}
+}
+
+// switchFaultStatusUpdate add or del fault of attached local physical
+// switches
+func switchFaultStatusUpdate(fault string, add bool) error {
+	var err error
+	for _, systemID := range attachedSystemIDs() {
+		index := vtepdb.PhysicalSwitchIndex1{SystemID: systemID}
+		if add {
+			err = vtepdb.PhysicalSwitchUpdateSwitchFaultStatusAddvalue(index, []string{fault})
+		} else {
+			err = vtepdb.PhysicalSwitchUpdateSwitchFaultStatusDelvalue(index, []string{fault})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func faultMessage(objID ObjID, obj interface{}, reason string) string {
+	return fmt.Sprintf("%s %+v: %s", ObjectOrder[objID], obj, reason)
+}
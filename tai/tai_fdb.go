@@ -10,26 +10,34 @@ import (
 
 // FDB attr list
 const (
-	FdbAttrLocator    = "fdb_locator"
-	FdbAttrTunnelName = "fdb_tunnel_name"
-	FdbAttrRemoteIP   = "fdb_remote_ip"
-	FdbAttrPort       = "fdb_port"
+	FdbAttrLocator    ObjAttrID = "fdb_locator"
+	FdbAttrTunnelName ObjAttrID = "fdb_tunnel_name"
+	FdbAttrRemoteIP   ObjAttrID = "fdb_remote_ip"
+	FdbAttrPort       ObjAttrID = "fdb_port"
 )
 
+// fdbAttrTypes value types of FDB attrs
+var fdbAttrTypes = map[ObjAttrID]AttrType{
+	FdbAttrLocator:    AttrTypeString,
+	FdbAttrTunnelName: AttrTypeString,
+	FdbAttrRemoteIP:   AttrTypeString,
+	FdbAttrPort:       AttrTypeString,
+}
+
 // FdbObj ...
 type FdbObj struct {
 	Bridge string //Bridge uuid
 	Mac    string
 }
 
-func rowToFdbObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToFdbObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableFdb := vtepdb.ConvertRowToRemoteFdb(libovsdb.ResultRow(row.Fields))
 
 	obj := FdbObj{
 		Bridge: tableFdb.Bridge,
 		Mac:    tableFdb.Mac,
 	}
-	attrs := make(map[interface{}]interface{})
+	attrs := make(Attrs)
 
 	tableLocator, err := vtepdb.LocatorGetByUUID(tableFdb.RemoteLocator)
 	if err != nil {
@@ -38,22 +46,8 @@ func rowToFdbObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
 	}
 
 	attrs[FdbAttrRemoteIP] = tableLocator.Ipaddr[0]
-	attrs[FdbAttrTunnelName] = LocalPhsicalSwitchTunnelName
+	// tunnel is set per driver instance
+	attrs[FdbAttrTunnelName] = ""
 
 	return obj, attrs
 }
-
-func rowToFdbAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.RemoteFdbFieldRemoteLocator:
-			if locator, ok := value.(string); ok {
-				attrs[FdbAttrLocator] = locator
-			}
-		}
-	}
-
-	return attrs
-}
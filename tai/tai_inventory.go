@@ -0,0 +1,146 @@
+package tai
+
+import (
+	"sync"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// Physical_Port other_config key and values of port type
+const (
+	PhysicalPortConfigType = "type"
+	PhysicalPortTypePort   = "port"
+	PhysicalPortTypeLag    = "lag"
+)
+
+// PhysicalPortFaultMissing port_fault_status of Physical_Port missing from
+// inventory of its switch
+const PhysicalPortFaultMissing = "missing from port inventory"
+
+// portInventories last port inventory reported by driver instances keyed
+// by instance name, written to switches attached later
+var portInventories = struct {
+	mutex       sync.Mutex
+	inventories map[string]PortInventoryNotification
+}{
+	inventories: make(map[string]PortInventoryNotification),
+}
+
+func portInventorySave(n PortInventoryNotification) {
+	portInventories.mutex.Lock()
+	defer portInventories.mutex.Unlock()
+	portInventories.inventories[n.Switch] = n
+}
+
+// portInventoryResend queue inventory of instance again for switch just
+// attached to it
+func portInventoryResend(instName string) {
+	portInventories.mutex.Lock()
+	n, ok := portInventories.inventories[instName]
+	portInventories.mutex.Unlock()
+	if ok {
+		Notify(n)
+	}
+}
+
+// instanceSystemIDs system ids of switches attached to instance of name
+func instanceSystemIDs(instName string) []string {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	inst, ok := tai.instances[instName]
+	if !ok {
+		return nil
+	}
+	systemIDs := make([]string, 0, len(inst.switches))
+	for _, sw := range inst.switches {
+		systemIDs = append(systemIDs, sw.systemID)
+	}
+	return systemIDs
+}
+
+func inventoryPortType(port InventoryPort) string {
+	if port.Lag {
+		return PhysicalPortTypeLag
+	}
+	return PhysicalPortTypePort
+}
+
+// portInventorySync make Physical_Port rows of switch the inventory. Rows
+// of ports missing from inventory are kept with their vlan bindings and
+// fault marked, fault is cleared once port is reported again.
+func portInventorySync(systemID string, ports []InventoryPort) error {
+	psIndex := vtepdb.PhysicalSwitchIndex1{SystemID: systemID}
+	tablePS, err := vtepdb.PhysicalSwitchGetByIndex(psIndex)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]vtepdb.TablePhysicalPort)
+	for _, uuid := range tablePS.Ports {
+		if tablePort, err := vtepdb.PhysicalPortGetByUUID(uuid.GoUUID); err == nil {
+			current[tablePort.Name] = tablePort
+		}
+	}
+
+	for _, port := range ports {
+		portType := inventoryPortType(port)
+		if tablePort, ok := current[port.Name]; ok {
+			delete(current, port.Name)
+			portIndex := vtepdb.PhysicalPortIndex{Name: port.Name}
+			if tablePort.Description != port.Description {
+				err = vtepdb.PhysicalPortSetField(portIndex, vtepdb.PhysicalPortFieldDescription, port.Description)
+			}
+			if err == nil && tablePort.OtherConfig[PhysicalPortConfigType] != portType {
+				err = vtepdb.PhysicalPortUpdateOtherConfigSetkey(portIndex,
+					map[interface{}]interface{}{PhysicalPortConfigType: portType})
+			}
+			if err == nil && portFaultMissing(tablePort) {
+				err = vtepdb.PhysicalPortUpdatePortFaultStatusDelvalue(portIndex, []string{PhysicalPortFaultMissing})
+				log.Info("[TAI] Physical_Port %s of %s back in inventory\n", port.Name, tablePS.Name)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// port names are unique in vtepdb
+		if _, err := vtepdb.PhysicalPortGetByIndex(vtepdb.PhysicalPortIndex{Name: port.Name}); err == nil {
+			log.Warning("[TAI] Physical_Port %s exists in other switch, not added to %s\n", port.Name, tablePS.Name)
+			continue
+		}
+		err = vtepdb.PhysicalSwitchUpdateAddPorts(psIndex, vtepdb.TablePhysicalPort{
+			Name:        port.Name,
+			Description: port.Description,
+			OtherConfig: map[interface{}]interface{}{PhysicalPortConfigType: portType},
+		})
+		if err != nil {
+			return err
+		}
+		log.Info("[TAI] Physical_Port %s %s added to %s\n", portType, port.Name, tablePS.Name)
+	}
+
+	for name, tablePort := range current {
+		if portFaultMissing(tablePort) {
+			continue
+		}
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusAddvalue(vtepdb.PhysicalPortIndex{Name: name},
+			[]string{PhysicalPortFaultMissing})
+		if err != nil {
+			return err
+		}
+		log.Warning("[TAI] Physical_Port %s missing from inventory of %s\n", name, tablePS.Name)
+	}
+	return nil
+}
+
+func portFaultMissing(tablePort vtepdb.TablePhysicalPort) bool {
+	for _, fault := range tablePort.PortFaultStatus {
+		if fault == PhysicalPortFaultMissing {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,20 @@ import (
 	"github.com/ebay/libovsdb"
 )
 
-// L2portObj attr list
+// L2portObj attr list, vlantag of QinQ port is outer tag followed by
+// inner tag. Vlan transparent port is the physical parent port passing
+// frames of all tags into its bridge.
 const (
-	L2portAttrVlanTag = "l2port_vlantag"
+	L2portAttrVlanTag         ObjAttrID = "l2port_vlantag"
+	L2portAttrVlanTransparent ObjAttrID = "l2port_vlan_transparent"
 )
 
+// l2portAttrTypes value types of L2port attrs
+var l2portAttrTypes = map[ObjAttrID]AttrType{
+	L2portAttrVlanTag:         AttrTypeIntList,
+	L2portAttrVlanTransparent: AttrTypeBool,
+}
+
 // L2portObj ...
 type L2portObj struct {
 	Name               string
@@ -18,7 +27,7 @@ type L2portObj struct {
 	PhysicalParentPort string
 }
 
-func rowToL2portObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToL2portObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableL2port := vtepdb.ConvertRowToL2port(libovsdb.ResultRow(row.Fields))
 
 	obj := L2portObj{
@@ -26,23 +35,17 @@ func rowToL2portObj(row libovsdb.Row) (interface{}, map[interface{}]interface{})
 		BridgeName:         tableL2port.Bd,
 		PhysicalParentPort: tableL2port.PhyparentPort,
 	}
-	attrs := map[interface{}]interface{}{
-		L2portAttrVlanTag: tableL2port.Vlantag,
+	attrs := Attrs{
+		L2portAttrVlanTag:         vlanTags(tableL2port.Vlantag, tableL2port.InnerVlantag),
+		L2portAttrVlanTransparent: tableL2port.VlanTransparent,
 	}
 	return obj, attrs
 }
 
-func rowToL2portAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.L2portFieldVlantag:
-			if vlanTag, ok := value.(int); ok {
-				attrs[L2portAttrVlanTag] = vlanTag
-			}
-		}
+// vlanTags vlan tags of port, inner tag is stacked only on outer tag
+func vlanTags(vlantag []int, innerVlantag []int) []int {
+	if len(vlantag) == 0 || len(innerVlantag) == 0 {
+		return vlantag
 	}
-
-	return attrs
+	return []int{vlantag[0], innerVlantag[0]}
 }
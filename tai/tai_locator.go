@@ -13,13 +13,19 @@ import (
 
 // TAI tunnel attr list
 const (
-	TunnelAttrTunnelKey = "tunnel_tunnel_key"
-	TunnelAttrIpaddr    = "tunnel_ipaddr"
-	TunnelAttrRmacMap   = "tunnel_rmac_map"
+	TunnelAttrTunnelKey ObjAttrID = "tunnel_tunnel_key"
+	TunnelAttrIpaddr    ObjAttrID = "tunnel_ipaddr"
+	TunnelAttrRmacMap   ObjAttrID = "tunnel_rmac_map"
+	TunnelAttrRouteMac  ObjAttrID = "tunnel_route_mac"
 )
 
-// LocalPhsicalSwitchTunnelName save tunnel name for phsical switch
-var LocalPhsicalSwitchTunnelName string
+// tunnelAttrTypes value types of tunnel attrs
+var tunnelAttrTypes = map[ObjAttrID]AttrType{
+	TunnelAttrTunnelKey: AttrTypeIntList,
+	TunnelAttrIpaddr:    AttrTypeString,
+	TunnelAttrRmacMap:   AttrTypeStringMap,
+	TunnelAttrRouteMac:  AttrTypeString,
+}
 
 // TunnelObj ...
 type TunnelObj struct {
@@ -38,7 +44,7 @@ func getTunnelName(chassisName string) string {
 	return tunnelName
 }
 
-func rowToTunnelObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToTunnelObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableLocator := vtepdb.ConvertRowToLocator(libovsdb.ResultRow(row.Fields))
 
 	if tableLocator.LocalLocator == false {
@@ -70,37 +76,14 @@ func rowToTunnelObj(row libovsdb.Row) (interface{}, map[interface{}]interface{})
 		}
 	}
 
-	LocalPhsicalSwitchTunnelName = tunnelName
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		TunnelAttrTunnelKey: tableLocator.TunnelKey,
 		TunnelAttrRmacMap:   tableLocator.RmacMap,
+		TunnelAttrRouteMac:  tableLocator.RouteMac,
 	}
 	return obj, attrs
 }
 
-func rowToTunnelAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.LocatorFieldTunnelKey:
-			if tunnelKey, ok := value.(int); ok {
-				attrs[TunnelAttrTunnelKey] = tunnelKey
-			}
-		case vtepdb.LocatorFieldIpaddr:
-			if ipaddr, ok := value.(string); ok {
-				attrs[TunnelAttrIpaddr] = ipaddr
-			}
-		case vtepdb.LocatorFieldRmacMap:
-			if rmacMap, ok := value.(map[interface{}]interface{}); ok {
-				attrs[TunnelAttrRmacMap] = rmacMap
-			}
-		}
-	}
-
-	return attrs
-}
-
 func taiGetLocatorObj(uuid string) (TunnelObj, error) {
 	var lctObj TunnelObj
 	condition := libovsdb.NewCondition("_uuid", "==", odbc.StringToGoUUID(uuid))
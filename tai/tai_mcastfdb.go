@@ -1,6 +1,7 @@
 package tai
 
 import (
+	"github.com/cn-pmlabs/govtep/lib/log"
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 
 	"github.com/ebay/libovsdb"
@@ -8,9 +9,15 @@ import (
 
 // mcastfdb attr list
 const (
-	McastFdbAttrLocators = "mcastfdb_locators"
+	McastFdbAttrLocators ObjAttrID = "mcastfdb_locators"
 )
 
+// mcastfdbAttrTypes value types of mcastfdb attrs, locators are remote
+// ips of the locator group
+var mcastfdbAttrTypes = map[ObjAttrID]AttrType{
+	McastFdbAttrLocators: AttrTypeStringList,
+}
+
 // McastFdbObj ...
 type McastFdbObj struct {
 	BridgeName     string
@@ -18,7 +25,7 @@ type McastFdbObj struct {
 	Mac            string
 }
 
-func rowToMcastfdbObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToMcastfdbObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableMcastFdb := vtepdb.ConvertRowToMcastMacsRemote(libovsdb.ResultRow(row.Fields))
 
 	obj := McastFdbObj{
@@ -26,25 +33,32 @@ func rowToMcastfdbObj(row libovsdb.Row) (interface{}, map[interface{}]interface{
 		Mac:        tableMcastFdb.Mac,
 		//IsolationGroup: ,
 	}
-	attrs := map[interface{}]interface{}{
-		McastFdbAttrLocators: tableMcastFdb.Locators,
+	attrs := Attrs{
+		McastFdbAttrLocators: locatorGroupIps(tableMcastFdb.Locators.GoUUID),
 	}
 	return obj, attrs
 }
 
-func rowToMcastfdbAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
+// locatorGroupIps remote ips of locator group, local locators are skipped
+func locatorGroupIps(group string) []string {
+	if group == "" {
+		return nil
+	}
 
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.McastMacsRemoteFieldLocators:
-			if locator, ok := value.(string); ok {
-				attrs[McastFdbAttrLocators] = locator
-			}
+	var ips []string
+	tableGroup, err := vtepdb.LocatorGroupGetByUUID(group)
+	if err != nil {
+		log.Warning("[TAI] Locator group %s not exist\n", group)
+		return nil
+	}
+	for _, locator := range tableGroup.Locators {
+		tableLocator, err := vtepdb.LocatorGetByUUID(locator.GoUUID)
+		if err != nil || len(tableLocator.Ipaddr) == 0 || tableLocator.LocalLocator {
+			continue
 		}
+		ips = append(ips, tableLocator.Ipaddr[0])
 	}
-
-	return attrs
+	return ips
 }
 
 func taiUpdateObjMcastFdb(objID ObjID, newrow libovsdb.Row, oldrow libovsdb.Row) {
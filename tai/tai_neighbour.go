@@ -10,26 +10,35 @@ import (
 
 // neighbour attr list
 const (
-	NeighbourAttrMacaddr  = "neighbour_macaddr"
-	NeighbourAttrBridge   = "neighbour_bridge"
-	NeighbourAttrOutPort  = "neighbour_outport"
-	NeighbourAttrRemoteIP = "neighbour_remoteip"
-	NeighbourAttrLocator  = "neighbour_locator"
+	NeighbourAttrMacaddr  ObjAttrID = "neighbour_macaddr"
+	NeighbourAttrBridge   ObjAttrID = "neighbour_bridge"
+	NeighbourAttrOutPort  ObjAttrID = "neighbour_outport"
+	NeighbourAttrRemoteIP ObjAttrID = "neighbour_remoteip"
+	NeighbourAttrLocator  ObjAttrID = "neighbour_locator"
 )
 
+// neighbourAttrTypes value types of neighbour attrs
+var neighbourAttrTypes = map[ObjAttrID]AttrType{
+	NeighbourAttrMacaddr:  AttrTypeString,
+	NeighbourAttrBridge:   AttrTypeString,
+	NeighbourAttrOutPort:  AttrTypeString,
+	NeighbourAttrRemoteIP: AttrTypeString,
+	NeighbourAttrLocator:  AttrTypeString,
+}
+
 // NeighbourObj ...
 type NeighbourObj struct {
 	Ipaddr string
 }
 
-func rowToNeighbourObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToNeighbourObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableNeighbour := vtepdb.ConvertRowToRemoteNeigh(libovsdb.ResultRow(row.Fields))
 
 	obj := NeighbourObj{
 		Ipaddr: tableNeighbour.Ipaddr,
 	}
 
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		NeighbourAttrMacaddr: tableNeighbour.Mac,
 		NeighbourAttrOutPort: tableNeighbour.OutL3port,
 	}
@@ -43,22 +52,3 @@ func rowToNeighbourObj(row libovsdb.Row) (interface{}, map[interface{}]interface
 
 	return obj, attrs
 }
-
-func rowToNeighbourAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.RemoteNeighFieldMac:
-			if mac, ok := value.(string); ok {
-				attrs[NeighbourAttrMacaddr] = mac
-			}
-		case vtepdb.RemoteNeighFieldRemoteLocator:
-			if locator, ok := value.(string); ok {
-				attrs[NeighbourAttrLocator] = locator
-			}
-		}
-	}
-
-	return attrs
-}
@@ -0,0 +1,163 @@
+package tai
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+)
+
+// PortOperNotification port oper status changed
+type PortOperNotification struct {
+	Port string
+	Up   bool
+}
+
+// FdbNotification mac learned or aged on local port
+type FdbNotification struct {
+	Bridge string
+	Mac    string
+	Port   string
+	Aged   bool
+}
+
+// NeighbourNotification neighbour learned or aged on local l3 port
+type NeighbourNotification struct {
+	Ipaddr string
+	Mac    string
+	Port   string
+	Aged   bool
+}
+
+// ObjectFaultNotification object programmed before is faulty in switch,
+// eg changed out of band, fault is removed when Cleared
+type ObjectFaultNotification struct {
+	ObjID   ObjID
+	Obj     interface{}
+	Fault   string
+	Cleared bool
+}
+
+// TableFullNotification switch table of object is full, objects of it
+// can't be created until Cleared
+type TableFullNotification struct {
+	ObjID   ObjID
+	Cleared bool
+}
+
+// PortInventoryNotification front panel ports and lags of switch driver
+// instance, whole inventory is sent on every change. Switch is the switch
+// of DriverConfig the instance was initialized with.
+type PortInventoryNotification struct {
+	Switch string
+	Ports  []InventoryPort
+}
+
+// InventoryPort port or lag of switch usable as physical parent port
+type InventoryPort struct {
+	Name        string
+	Description string
+	Lag         bool
+}
+
+// NotificationHandler controller callbacks of driver notifications,
+// called one by one in notification goroutine, driver is never blocked
+// by handlers
+type NotificationHandler interface {
+	PortOper(PortOperNotification)
+	Fdb(FdbNotification)
+	Neighbour(NeighbourNotification)
+	ObjectFault(ObjectFaultNotification)
+	TableFull(TableFullNotification)
+	PortInventory(PortInventoryNotification)
+}
+
+// notificationQueueSize notifications beyond queue size are dropped
+const notificationQueueSize = 4096
+
+var notifications = struct {
+	mutex    sync.Mutex
+	handlers map[string]NotificationHandler
+	queue    chan interface{}
+}{
+	handlers: make(map[string]NotificationHandler),
+	queue:    make(chan interface{}, notificationQueueSize),
+}
+
+func init() {
+	go notificationLoop()
+}
+
+// RegisterNotificationHandler register controller handler of driver
+// notifications, handlers are called in name order
+func RegisterNotificationHandler(name string, handler NotificationHandler) {
+	notifications.mutex.Lock()
+	defer notifications.mutex.Unlock()
+	notifications.handlers[name] = handler
+}
+
+// UnRegisterNotificationHandler unregister notification handler
+func UnRegisterNotificationHandler(name string) {
+	notifications.mutex.Lock()
+	defer notifications.mutex.Unlock()
+	delete(notifications.handlers, name)
+}
+
+// Notify called by driver to send notification to controller, it
+// never blocks, notification is dropped if queue is full
+func Notify(notification interface{}) {
+	select {
+	case notifications.queue <- notification:
+	default:
+		log.Warning("[TAI] notification queue full, drop %+v\n", notification)
+	}
+}
+
+func notificationLoop() {
+	for notification := range notifications.queue {
+		if n, ok := notification.(PortInventoryNotification); ok {
+			// kept by standby too, written when switch attached
+			portInventorySave(n)
+		}
+		// driver events are written to vtepdb by active instance only
+		if !odbc.IsActive() {
+			continue
+		}
+
+		notifications.mutex.Lock()
+		names := make([]string, 0, len(notifications.handlers))
+		for name := range notifications.handlers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		handlers := make([]NotificationHandler, 0, len(names))
+		for _, name := range names {
+			handlers = append(handlers, notifications.handlers[name])
+		}
+		notifications.mutex.Unlock()
+
+		for _, handler := range handlers {
+			notificationDispatch(handler, notification)
+		}
+	}
+}
+
+func notificationDispatch(handler NotificationHandler, notification interface{}) {
+	switch n := notification.(type) {
+	case PortOperNotification:
+		handler.PortOper(n)
+	case FdbNotification:
+		handler.Fdb(n)
+	case NeighbourNotification:
+		handler.Neighbour(n)
+	case ObjectFaultNotification:
+		handler.ObjectFault(n)
+	case TableFullNotification:
+		handler.TableFull(n)
+	case PortInventoryNotification:
+		handler.PortInventory(n)
+	default:
+		log.Warning("[TAI] unknown notification %T %+v\n", notification, notification)
+	}
+}
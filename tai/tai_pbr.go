@@ -4,16 +4,20 @@ import (
 	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
 
 	"github.com/cn-pmlabs/govtep/lib/log"
-	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
 
 	"github.com/ebay/libovsdb"
 )
 
 // route attr list
 const (
-	PBRAttrNexthopGroup = "pbr_nexthop_group"
+	PBRAttrNexthopGroup ObjAttrID = "pbr_nexthop_group"
 )
 
+// pbrAttrTypes value types of PBR attrs
+var pbrAttrTypes = map[ObjAttrID]AttrType{
+	PBRAttrNexthopGroup: AttrTypeStringList,
+}
+
 // PBRObj ...
 type PBRObj struct {
 	Type     string
@@ -23,7 +27,7 @@ type PBRObj struct {
 	Protocol string
 }
 
-func rowToPBRObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToPBRObj(row libovsdb.Row) (interface{}, Attrs) {
 	tablePBR := vtepdb.ConvertRowToPolicyBasedRoute(libovsdb.ResultRow(row.Fields))
 
 	if len(tablePBR.Port) != 1 || len(tablePBR.Protocol) != 1 {
@@ -38,28 +42,9 @@ func rowToPBRObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
 		Port:     tablePBR.Port[0],
 		Protocol: tablePBR.Protocol[0],
 	}
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		PBRAttrNexthopGroup: tablePBR.NhGroup,
 	}
 
 	return obj, attrs
 }
-
-func rowToPBRAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.PolicyBasedRouteFieldNhGroup:
-			switch value.(type) {
-			case string:
-				attrs[PBRAttrNexthopGroup] = []string{value.(string)}
-			case libovsdb.OvsSet:
-				attrs[PBRAttrNexthopGroup] = odbc.
-					ConvertGoSetToStringArray(value.(libovsdb.OvsSet))
-			}
-		}
-	}
-
-	return attrs
-}
@@ -8,12 +8,22 @@ import (
 
 // route attr list
 const (
-	RouteAttrNexthop    = "route_nexthop"
-	RouteAttrNhvrf      = "route_nhvrf"
-	RouteAttrOutputPort = "route_outputport"
-	RouteAttrPolicy     = "route_policy"
+	RouteAttrNexthop    ObjAttrID = "route_nexthop"
+	RouteAttrNhvrf      ObjAttrID = "route_nhvrf"
+	RouteAttrOutputPort ObjAttrID = "route_outputport"
+	RouteAttrPolicy     ObjAttrID = "route_policy"
+	RouteAttrRouteMac   ObjAttrID = "route_route_mac"
 )
 
+// routeAttrTypes value types of route attrs
+var routeAttrTypes = map[ObjAttrID]AttrType{
+	RouteAttrNexthop:    AttrTypeString,
+	RouteAttrNhvrf:      AttrTypeString,
+	RouteAttrOutputPort: AttrTypeString,
+	RouteAttrPolicy:     AttrTypeString,
+	RouteAttrRouteMac:   AttrTypeString,
+}
+
 // RouteObj ...
 type RouteObj struct {
 	Vrf        string
@@ -24,7 +34,7 @@ type RouteObj struct {
 	Policy     string
 }
 
-func rowToRouteObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToRouteObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableRoute := vtepdb.ConvertRowToRoute(libovsdb.ResultRow(row.Fields))
 
 	obj := RouteObj{
@@ -35,38 +45,16 @@ func rowToRouteObj(row libovsdb.Row) (interface{}, map[interface{}]interface{})
 		OutputPort: tableRoute.OutputPort,
 		Policy:     tableRoute.Policy,
 	}
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		RouteAttrNexthop:    tableRoute.Nexthop,
 		RouteAttrNhvrf:      tableRoute.NhVrf,
 		RouteAttrOutputPort: tableRoute.OutputPort,
 		RouteAttrPolicy:     tableRoute.Policy,
 	}
-	return obj, attrs
-}
-
-func rowToRouteAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.RouteFieldNexthop:
-			if nh, ok := value.(string); ok {
-				attrs[RouteAttrNexthop] = nh
-			}
-		case vtepdb.RouteFieldNhVrf:
-			if vrf, ok := value.(string); ok {
-				attrs[RouteAttrNhvrf] = vrf
-			}
-		case vtepdb.RouteFieldOutputPort:
-			if outPort, ok := value.(string); ok {
-				attrs[RouteAttrOutputPort] = outPort
-			}
-		case vtepdb.RouteFieldPolicy:
-			if policy, ok := value.(string); ok {
-				attrs[RouteAttrPolicy] = policy
-			}
-		}
+	// route to remote vtep is routed symmetrically by l3vni of vrf with
+	// router mac of remote vtep as inner dmac
+	if tableRoute.RouteMac != "" {
+		attrs[RouteAttrRouteMac] = tableRoute.RouteMac
 	}
-
-	return attrs
+	return obj, attrs
 }
@@ -0,0 +1,350 @@
+package tai
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+
+	"github.com/ebay/libovsdb"
+)
+
+// Physical_Switch other_config keys overriding driver and address of
+// driver instance of switch. Switch without them gets primary and shadow
+// drivers at their default address, switch setting only tai_driver_addr
+// gets them at its own address.
+const (
+	SwitchConfigDriver     = "tai_driver"
+	SwitchConfigDriverAddr = "tai_driver_addr"
+)
+
+// driverInstance primary and shadow drivers programming the switch of
+// its name, every attached switch has its own instance. Default instance
+// set up by InitDrivers programs objects while no switch attached.
+type driverInstance struct {
+	name       string
+	primary    namedHandler
+	shadows    []namedHandler
+	capability Capability
+	switches   map[string]*taiSwitch
+	// replay got switch attached, vtepdb rows programmed on other
+	// instances are programmed on it after current update
+	replay bool
+}
+
+func (inst *driverInstance) String() string {
+	if inst.name == "" {
+		return "default instance"
+	}
+	return "switch " + inst.name + " instance"
+}
+
+// taiSwitch local Physical_Switch programmed by TAI, attached to its
+// driver instance when its tunnel is created and detached when removed
+type taiSwitch struct {
+	name       string
+	systemID   string
+	tunnelName string
+	instance   *driverInstance
+}
+
+// tunnelAttrs attrs referring tunnel of switch, set to tunnel of driver
+// instance when programmed
+var tunnelAttrs = map[ObjID]ObjAttrID{
+	ObjectIDBridge: BridgeAttrVxlanTunnel,
+	ObjectIDVrf:    VrfAttrTunnel,
+	ObjectIDFDB:    FdbAttrTunnelName,
+}
+
+// checkDriverNames check drivers are registered and selected once,
+// return normalized names
+func checkDriverNames(names []string) ([]string, error) {
+	selected := make(map[string]bool)
+	checked := make([]string, len(names))
+	for i, name := range names {
+		checked[i] = driverName(name)
+		if checked[i] == "" {
+			return nil, errors.New("tai: driver name not set")
+		}
+		if selected[checked[i]] {
+			return nil, fmt.Errorf("tai: driver %s selected twice", checked[i])
+		}
+		selected[checked[i]] = true
+
+		tai.handlersMutex.Lock()
+		_, ok := tai.driverInits[checked[i]]
+		tai.handlersMutex.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("tai: unknown driver %s, registered drivers %v", checked[i], RegisteredDrivers())
+		}
+	}
+	return checked, nil
+}
+
+// newDriverInstance init drivers of instance, addr is passed to primary
+// driver only, shadow drivers use their default
+func newDriverInstance(name string, names []string, addr string) (*driverInstance, error) {
+	inst := &driverInstance{
+		name:     name,
+		switches: make(map[string]*taiSwitch),
+	}
+	for i, driver := range names {
+		tai.handlersMutex.Lock()
+		init := tai.driverInits[driver]
+		tai.handlersMutex.Unlock()
+
+		config := DriverConfig{Switch: name}
+		if i == 0 {
+			config.Addr = addr
+		}
+		handler, err := init(config)
+		if err != nil {
+			return nil, fmt.Errorf("tai: driver %s init failed: %v", driver, err)
+		}
+		if i == 0 {
+			inst.primary = namedHandler{driver, handler}
+		} else {
+			inst.shadows = append(inst.shadows, namedHandler{driver, handler})
+		}
+	}
+	inst.capability = inst.primary.handler.TaiGetCapability()
+	return inst, nil
+}
+
+// tunnelName tunnel bridges and vrfs of instance are bound to, tunnel of
+// its switch
+func (inst *driverInstance) tunnelName() string {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	if sw, ok := inst.switches[inst.name]; ok {
+		return sw.tunnelName
+	}
+	return ""
+}
+
+// switchAttach attach switch of system id with tunnel to its driver
+// instance, instance is created at first attach of switch
+func switchAttach(systemID string, tunnelName string) (*driverInstance, error) {
+	name := systemID
+	var driver, addr string
+	tablePS, err := vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex1{SystemID: systemID})
+	if err == nil {
+		name = tablePS.Name
+		driver, _ = tablePS.OtherConfig[SwitchConfigDriver].(string)
+		addr, _ = tablePS.OtherConfig[SwitchConfigDriverAddr].(string)
+	}
+
+	tai.handlersMutex.Lock()
+	inst := tai.instances[name]
+	names := append([]string{tai.activeDriver}, tai.shadowDrivers...)
+	tai.handlersMutex.Unlock()
+
+	if inst == nil {
+		if driver != "" {
+			names = []string{driver}
+		}
+		if names, err = checkDriverNames(names); err != nil {
+			return nil, err
+		}
+		if inst, err = newDriverInstance(name, names, addr); err != nil {
+			return nil, err
+		}
+		log.Warning("[TAI] %s created, driver %v addr %q\n", inst, names, addr)
+		warmRestartMarkInstance(inst)
+	}
+
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	tai.instances[inst.name] = inst
+	if len(inst.switches) == 0 {
+		inst.replay = true
+	}
+	sw := &taiSwitch{
+		name:       name,
+		systemID:   systemID,
+		tunnelName: tunnelName,
+		instance:   inst,
+	}
+	inst.switches[name] = sw
+	tai.switches[name] = sw
+	capabilityUpdate()
+	portInventoryResend(inst.name)
+	log.Warning("[TAI] switch %s system id %s attached to %s\n", name, systemID, inst)
+	return inst, nil
+}
+
+// switchDetach detach switch of system id, driver instance is kept for
+// switch attached again
+func switchDetach(systemID string) {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	for name, sw := range tai.switches {
+		if sw.systemID != systemID {
+			continue
+		}
+		delete(sw.instance.switches, name)
+		delete(tai.switches, name)
+		capabilityUpdate()
+		log.Warning("[TAI] switch %s detached from %s\n", name, sw.instance)
+	}
+}
+
+// attachedInstances instances with switch attached sorted by name,
+// default instance if no switch attached. Mutex must be held.
+func attachedInstances() []*driverInstance {
+	var insts []*driverInstance
+	for _, inst := range tai.instances {
+		if len(inst.switches) != 0 {
+			insts = append(insts, inst)
+		}
+	}
+	if len(insts) == 0 {
+		if inst, ok := tai.instances[""]; ok {
+			insts = append(insts, inst)
+		}
+	}
+	sort.Slice(insts, func(i, j int) bool { return insts[i].name < insts[j].name })
+	return insts
+}
+
+// allInstances every driver instance sorted by name
+func allInstances() []*driverInstance {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	insts := make([]*driverInstance, 0, len(tai.instances))
+	for _, inst := range tai.instances {
+		insts = append(insts, inst)
+	}
+	sort.Slice(insts, func(i, j int) bool { return insts[i].name < insts[j].name })
+	return insts
+}
+
+// objectInstances driver instances object of row is programmed on.
+// Tunnel goes to instance of its switch, port bound to a physical switch
+// goes to instance of that switch once attached, the others go to every
+// instance with switch attached.
+func objectInstances(objID ObjID, row libovsdb.Row) []*driverInstance {
+	var phySwitch string
+	switch objID {
+	case ObjectIDTunnel:
+		systemID := vtepdb.ConvertRowToLocator(libovsdb.ResultRow(row.Fields)).ChassisName
+		tai.handlersMutex.Lock()
+		defer tai.handlersMutex.Unlock()
+		for _, sw := range tai.switches {
+			if sw.systemID == systemID {
+				return []*driverInstance{sw.instance}
+			}
+		}
+		return nil
+	case ObjectIDL2Port:
+		phySwitch, _ = row.Fields[vtepdb.L2portFieldPhyswitch].(string)
+	case ObjectIDL3Port:
+		phySwitch, _ = row.Fields[vtepdb.L3portFieldPhyswitch].(string)
+	}
+
+	if phySwitch != "" {
+		tai.handlersMutex.Lock()
+		sw, ok := tai.switches[phySwitch]
+		tai.handlersMutex.Unlock()
+		if ok {
+			return []*driverInstance{sw.instance}
+		}
+		// programmed when the switch attached
+		if _, err := vtepdb.PhysicalSwitchGetByIndex(vtepdb.PhysicalSwitchIndex{Name: phySwitch}); err == nil {
+			return nil
+		}
+	}
+
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	return attachedInstances()
+}
+
+// instanceAttrs attrs of object on driver instance, tunnel attrs are set
+// to tunnel of instance
+func instanceAttrs(inst *driverInstance, objID ObjID, attrs Attrs) Attrs {
+	id, ok := tunnelAttrs[objID]
+	if !ok {
+		return attrs
+	}
+	if _, ok = attrs[id]; !ok {
+		return attrs
+	}
+
+	localized := make(Attrs, len(attrs))
+	for attrID, value := range attrs {
+		localized[attrID] = value
+	}
+	localized[id] = inst.tunnelName()
+	return localized
+}
+
+// switchReplay program current vtepdb rows on instances got first switch
+// attached by last update, tunnels are programmed by the update itself
+func switchReplay() {
+	var insts []*driverInstance
+	tai.handlersMutex.Lock()
+	for _, inst := range tai.instances {
+		if inst.replay {
+			inst.replay = false
+			insts = append(insts, inst)
+		}
+	}
+	tai.handlersMutex.Unlock()
+	if len(insts) == 0 || taiDBClient.Client == nil {
+		return
+	}
+
+	snapshot := taiDBClient.TableSnapshot(nil)
+	for _, inst := range insts {
+		log.Warning("[TAI] program vtepdb on %s\n", inst)
+		initialRows(snapshot, func(objID ObjID, rowUpdate libovsdb.RowUpdate) {
+			if objID == ObjectIDTunnel || !instanceIn(inst, objectInstances(objID, rowUpdate.New)) {
+				return
+			}
+			obj, attrs := rowToObj(objID, rowUpdate.New)
+			if obj == nil || capabilitySkipped(objID, obj) {
+				return
+			}
+			err := taiBatchOn([]*driverInstance{inst}, func() error {
+				return taiCreateObjOn(inst, objID, obj, attrs)
+			})
+			if err != nil {
+				log.Warning("[TAI] program %s %+v on %s failed %v\n", ObjectOrder[objID], obj, inst, err)
+			}
+		})
+	}
+}
+
+// switchReplayDone initial update programmed every row on every instance
+func switchReplayDone() {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	for _, inst := range tai.instances {
+		inst.replay = false
+	}
+}
+
+func instanceIn(inst *driverInstance, insts []*driverInstance) bool {
+	for _, i := range insts {
+		if i == inst {
+			return true
+		}
+	}
+	return false
+}
+
+// attachedSystemIDs system ids of attached switches
+func attachedSystemIDs() []string {
+	tai.handlersMutex.Lock()
+	defer tai.handlersMutex.Unlock()
+	systemIDs := make([]string, 0, len(tai.switches))
+	for _, sw := range tai.switches {
+		systemIDs = append(systemIDs, sw.systemID)
+	}
+	sort.Strings(systemIDs)
+	return systemIDs
+}
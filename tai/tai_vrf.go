@@ -8,40 +8,31 @@ import (
 
 // vrf attr list
 const (
-	VrfAttrL3vni  = "vrf_l3vni"
-	VrfAttrTunnel = "vrf_tunnel"
+	VrfAttrL3vni  ObjAttrID = "vrf_l3vni"
+	VrfAttrTunnel ObjAttrID = "vrf_tunnel"
 )
 
+// vrfAttrTypes value types of Vrf attrs
+var vrfAttrTypes = map[ObjAttrID]AttrType{
+	VrfAttrL3vni:  AttrTypeInt,
+	VrfAttrTunnel: AttrTypeString,
+}
+
 // VrfObj ...
 type VrfObj struct {
 	Name string //"Vrf"+vni
 }
 
-func rowToVrfObj(row libovsdb.Row) (interface{}, map[interface{}]interface{}) {
+func rowToVrfObj(row libovsdb.Row) (interface{}, Attrs) {
 	tableVrf := vtepdb.ConvertRowToVrf(libovsdb.ResultRow(row.Fields))
 
 	obj := VrfObj{
 		Name: tableVrf.Name,
 	}
-	attrs := map[interface{}]interface{}{
+	attrs := Attrs{
 		VrfAttrL3vni:  tableVrf.L3vni,
-		VrfAttrTunnel: LocalPhsicalSwitchTunnelName,
+		VrfAttrTunnel: "", // set per driver instance
 	}
 
 	return obj, attrs
 }
-
-func rowToVrfAttrs(row libovsdb.Row) map[interface{}]interface{} {
-	attrs := make(map[interface{}]interface{})
-
-	for attr, value := range row.Fields {
-		switch attr {
-		case vtepdb.VrfFieldL3vni:
-			if l3Vni, ok := value.(string); ok {
-				attrs[VrfAttrL3vni] = l3Vni
-			}
-		}
-	}
-
-	return attrs
-}
@@ -1,6 +1,8 @@
 package tai
 
 import (
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+
 	"github.com/ebay/libovsdb"
 )
 
@@ -12,9 +14,11 @@ func (notify vtepdbNotifier) Update(context interface{}, updates libovsdb.TableU
 	notify.tdbi.taiNotifyUpdate(updates)
 }
 
-func (notify vtepdbNotifier) Locked([]interface{}) {
+func (notify vtepdbNotifier) Locked(params []interface{}) {
+	odbc.LockNotify(params, true)
 }
-func (notify vtepdbNotifier) Stolen([]interface{}) {
+func (notify vtepdbNotifier) Stolen(params []interface{}) {
+	odbc.LockNotify(params, false)
 }
 func (notify vtepdbNotifier) Echo([]interface{}) {
 }
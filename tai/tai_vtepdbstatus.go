@@ -0,0 +1,122 @@
+package tai
+
+import (
+	"fmt"
+
+	vtepdb "github.com/cn-pmlabs/govtep/lib/odbapi/controllervtep"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+)
+
+// portOperDownFault Physical_Port fault status of oper down port
+const portOperDownFault = "oper_down"
+
+// VtepdbStatusHandler notification handler writing driver notifications
+// into vtepdb status columns and local fdb/neighbour tables
+var VtepdbStatusHandler NotificationHandler = vtepdbStatus{}
+
+type vtepdbStatus struct{}
+
+func (s vtepdbStatus) PortOper(n PortOperNotification) {
+	index := vtepdb.PhysicalPortIndex{Name: n.Port}
+	if _, err := vtepdb.PhysicalPortGetByIndex(index); err != nil {
+		log.Info("[TAI] Physical_Port %s not exist, oper status up=%v ignored\n", n.Port, n.Up)
+		return
+	}
+
+	var err error
+	if n.Up {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusDelvalue(index, []string{portOperDownFault})
+	} else {
+		err = vtepdb.PhysicalPortUpdatePortFaultStatusAddvalue(index, []string{portOperDownFault})
+	}
+	if err != nil {
+		log.Warning("[TAI] update Physical_Port %s oper status failed %v\n", n.Port, err)
+	}
+}
+
+func (s vtepdbStatus) Fdb(n FdbNotification) {
+	index := vtepdb.LocalFdbIndex{Bridge: n.Bridge, Mac: n.Mac}
+	_, err := vtepdb.LocalFdbGetByIndex(index)
+	exist := err == nil
+
+	switch {
+	case n.Aged && exist:
+		err = vtepdb.LocalFdbDelByIndex(index)
+	case n.Aged:
+		err = nil
+	case exist:
+		// mac moved to another port
+		err = vtepdb.LocalFdbSetField(index, vtepdb.LocalFdbFieldOutL2port, n.Port)
+	default:
+		_, err = vtepdb.LocalFdbAdd(vtepdb.TableLocalFdb{
+			Bridge:    n.Bridge,
+			Mac:       n.Mac,
+			OutL2port: n.Port,
+		})
+	}
+	if err != nil {
+		log.Warning("[TAI] update Local_Fdb %+v failed %v\n", n, err)
+	}
+}
+
+func (s vtepdbStatus) Neighbour(n NeighbourNotification) {
+	// neighbour mac changed or aged without mac, stale entries of the ip
+	// are removed
+	exist := false
+	vtepdb.LocalNeighIterator(func(table vtepdb.TableLocalNeigh) {
+		if table.Ipaddr != n.Ipaddr {
+			return
+		}
+		if !n.Aged && table.Mac == n.Mac {
+			exist = true
+			if table.OutL3port != n.Port {
+				err := vtepdb.LocalNeighSetField(vtepdb.LocalNeighIndex{Ipaddr: table.Ipaddr, Mac: table.Mac},
+					vtepdb.LocalNeighFieldOutL3port, n.Port)
+				if err != nil {
+					log.Warning("[TAI] update Local_Neigh %+v failed %v\n", n, err)
+				}
+			}
+			return
+		}
+		if n.Aged && n.Mac != "" && table.Mac != n.Mac {
+			return
+		}
+		if err := vtepdb.LocalNeighDelByUUID(table.UUID); err != nil {
+			log.Warning("[TAI] delete Local_Neigh %s %s failed %v\n", table.Ipaddr, table.Mac, err)
+		}
+	})
+	if n.Aged || exist {
+		return
+	}
+
+	_, err := vtepdb.LocalNeighAdd(vtepdb.TableLocalNeigh{
+		Ipaddr:    n.Ipaddr,
+		Mac:       n.Mac,
+		OutL3port: n.Port,
+	})
+	if err != nil {
+		log.Warning("[TAI] add Local_Neigh %+v failed %v\n", n, err)
+	}
+}
+
+func (s vtepdbStatus) ObjectFault(n ObjectFaultNotification) {
+	log.Warning("[TAI] %s, cleared=%v\n", faultMessage(n.ObjID, n.Obj, n.Fault), n.Cleared)
+	faultStatusUpdate(n.ObjID, n.Obj, n.Fault, !n.Cleared)
+}
+
+func (s vtepdbStatus) TableFull(n TableFullNotification) {
+	fault := fmt.Sprintf("%s: table full", ObjectOrder[n.ObjID])
+	log.Warning("[TAI] %s, cleared=%v\n", fault, n.Cleared)
+	if err := switchFaultStatusUpdate(fault, !n.Cleared); err != nil {
+		log.Warning("[TAI] update switch fault status %s failed %v\n", fault, err)
+	}
+}
+
+func (s vtepdbStatus) PortInventory(n PortInventoryNotification) {
+	for _, systemID := range instanceSystemIDs(n.Switch) {
+		if err := portInventorySync(systemID, n.Ports); err != nil {
+			log.Warning("[TAI] update Physical_Port of switch %s failed %v\n", systemID, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package tai
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/cn-pmlabs/govtep/lib/log"
+	odbc "github.com/cn-pmlabs/govtep/lib/ovsdb_client"
+)
+
+// WarmRestart keep objects of driver programmed by last run, objects
+// listed by driver but not created again from vtepdb are removed
+// WarmRestartTimer after initial vtepdb update processed
+var (
+	WarmRestart      bool          = false
+	WarmRestartTimer time.Duration = 180 * time.Second
+)
+
+// warmRestartState stale driver objects of last run per driver instance,
+// objects are claimed when created again from vtepdb. Instances created
+// for switches before the sweep are marked too.
+var warmRestartState = struct {
+	mutex   sync.Mutex
+	marked  bool
+	pending bool
+	stale   map[*driverInstance]map[ObjID]map[interface{}]bool
+}{
+	stale: make(map[*driverInstance]map[ObjID]map[interface{}]bool),
+}
+
+// warmRestartMark mark objects listed by drivers stale, only the first
+// initial update after start or takeover is marked. Drivers not listing
+// objects have nothing to sweep.
+func warmRestartMark() bool {
+	if !WarmRestart {
+		return false
+	}
+
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	if warmRestartState.marked {
+		return false
+	}
+	warmRestartState.marked = true
+	warmRestartState.pending = true
+
+	for _, inst := range allInstances() {
+		warmRestartState.stale[inst] = warmRestartList(inst)
+	}
+	return true
+}
+
+// warmRestartMarkInstance mark objects of driver instance created before
+// sweep
+func warmRestartMarkInstance(inst *driverInstance) {
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	if !warmRestartState.pending {
+		return
+	}
+	warmRestartState.stale[inst] = warmRestartList(inst)
+}
+
+// warmRestartList list objects of driver instance as stale
+func warmRestartList(inst *driverInstance) map[ObjID]map[interface{}]bool {
+	stales := make(map[ObjID]map[interface{}]bool)
+	for objID, name := range ObjectOrder {
+		if name == "" || !inst.capability.ObjectSupported(ObjID(objID)) {
+			continue
+		}
+		objs, err := taiGetObject(inst, ObjID(objID))
+		if err != nil {
+			log.Warning("[TAI] warm restart list %s on %s failed %v\n", name, inst, err)
+			continue
+		}
+		stale := make(map[interface{}]bool, len(objs))
+		for _, obj := range objs {
+			stale[obj] = true
+		}
+		stales[ObjID(objID)] = stale
+		log.Warning("[TAI] warm restart mark %d %s stale on %s\n", len(stale), name, inst)
+	}
+	return stales
+}
+
+// warmRestartRearm mark again on next initial update, instance taking over
+// sweeps objects left by previous active instance
+func warmRestartRearm() {
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	warmRestartState.marked = false
+}
+
+// warmRestartClaim claim object created again on driver instance. Drivers
+// list objects with fields they could recover from dataplane, zero fields
+// of listed object match any value of object created.
+func warmRestartClaim(inst *driverInstance, objID ObjID, obj interface{}) {
+	warmRestartState.mutex.Lock()
+	defer warmRestartState.mutex.Unlock()
+	stale := warmRestartState.stale[inst][objID]
+	if stale[obj] {
+		delete(stale, obj)
+		return
+	}
+	for listed := range stale {
+		if warmRestartMatch(listed, obj) {
+			delete(stale, listed)
+		}
+	}
+}
+
+// warmRestartMatch match non zero fields of listed object
+func warmRestartMatch(listed interface{}, obj interface{}) bool {
+	listedValue := reflect.ValueOf(listed)
+	value := reflect.ValueOf(obj)
+	if listedValue.Type() != value.Type() || listedValue.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < listedValue.NumField(); i++ {
+		field := listedValue.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		if field.Interface() != value.Field(i).Interface() {
+			return false
+		}
+	}
+	return true
+}
+
+// warmRestartSweep remove objects still stale, children first and tunnels
+// referred by bridges and vrfs last
+func warmRestartSweep() {
+	if !odbc.IsActive() {
+		log.Warning("[TAI] warm restart sweep skipped, instance is %s\n", odbc.RoleStandby)
+		return
+	}
+
+	warmRestartState.mutex.Lock()
+	stales := warmRestartState.stale
+	warmRestartState.stale = make(map[*driverInstance]map[ObjID]map[interface{}]bool)
+	warmRestartState.pending = false
+	warmRestartState.mutex.Unlock()
+
+	var order []ObjID
+	for objID := ObjID(len(ObjectOrder) - 1); objID > 0; objID-- {
+		if objID != ObjectIDTunnel {
+			order = append(order, objID)
+		}
+	}
+	order = append(order, ObjectIDTunnel)
+
+	for _, inst := range allInstances() {
+		stale := stales[inst]
+		for _, objID := range order {
+			for obj := range stale[objID] {
+				log.Warning("[TAI] warm restart sweep stale %s %+v on %s\n", ObjectOrder[objID], obj, inst)
+				err := taiBatchOn([]*driverInstance{inst}, func() error {
+					return taiRemoveObject(inst, objID, obj)
+				})
+				if err != nil {
+					log.Warning("[TAI] warm restart sweep %s %+v failed %v\n", ObjectOrder[objID], obj, err)
+				}
+			}
+		}
+	}
+	log.Warning("[TAI] warm restart done\n")
+}